@@ -22,6 +22,7 @@ import (
 	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/fetchdatasource"
 	"golang.org/x/pkgsite/internal/frontend"
+	"golang.org/x/pkgsite/internal/licenses"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/postgres"
@@ -38,12 +39,20 @@ var (
 	thirdPartyPath = flag.String("third_party", "third_party", "path to folder containing third-party libraries")
 	devMode        = flag.Bool("dev", false, "enable developer mode (reload templates on each page load, serve non-minified JS/CSS, etc.)")
 	disableCSP     = flag.Bool("nocsp", false, "disable Content Security Policy")
-	proxyURL       = flag.String("proxy_url", "https://proxy.golang.org", "Uses the module proxy referred to by this URL "+
-		"for direct proxy mode and frontend fetches")
+	proxyURL       = flag.String("proxy_url", "", "Uses the module proxy referred to by this URL, or the list of proxies "+
+		"in the format of GOPROXY, for direct proxy mode and frontend fetches; defaults to GO_MODULE_PROXY_URL")
 	directProxy = flag.Bool("direct_proxy", false, "if set to true, uses the module proxy referred to by this URL "+
 		"as a direct backend, bypassing the database")
-	bypassLicenseCheck = flag.Bool("bypass_license_check", false, "display all information, even for non-redistributable paths")
-	hostAddr           = flag.String("host", "localhost:8080", "Host address for the server")
+	bypassLicenseCheck   = flag.Bool("bypass_license_check", false, "display all information, even for non-redistributable paths")
+	hostAddr             = flag.String("host", "localhost:8080", "Host address for the server")
+	pageCacheSize        = flag.Int("page_cache_size", 0, "number of rendered unit pages to cache in memory; 0 disables the cache. Pages of modules inserted by the worker are refreshed only when they expire")
+	rateLimitQPS         = flag.Float64("rate_limit_qps", 0, "per-client requests per second allowed to search and documentation pages; 0 disables rate limiting")
+	rateLimitBurst       = flag.Int("rate_limit_burst", 20, "maximum burst of requests per client when rate limiting is enabled")
+	docTemplates         = flag.String("doc_templates", "", "path to folder with a doc subfolder of templates that override the documentation body templates")
+	onDemandFetchTimeout = flag.Duration("on_demand_fetch_timeout", 0, "how long a page for a path that has never been fetched waits for the path to be fetched; 0 disables fetching on page views")
+	onDemandFetchQPS     = flag.Float64("on_demand_fetch_qps", 0.1, "per-client fetches per second allowed on page views, when on_demand_fetch_timeout is set; 0 disables the limit")
+	onDemandFetchBurst   = flag.Int("on_demand_fetch_burst", 5, "maximum burst of fetches on page views per client, when on_demand_fetch_qps is set")
+	baseURL              = flag.String("base_url", "https://pkg.go.dev", "scheme and host of the site, for absolute URLs in the sitemap and the OpenSearch description; if empty, those of each request are used")
 )
 
 func main() {
@@ -54,6 +63,9 @@ func main() {
 		log.Fatal(ctx, err)
 	}
 	cfg.Dump(os.Stderr)
+	if len(cfg.RedistributableLicenses) > 0 {
+		licenses.SetRedistributableLicenseTypes(cfg.RedistributableLicenses)
+	}
 	if cfg.UseProfiler {
 		if err := profiler.Start(profiler.Config{}); err != nil {
 			log.Fatalf(ctx, "profiler.Start: %v", err)
@@ -63,6 +75,7 @@ func main() {
 	var (
 		dsg        func(context.Context) internal.DataSource
 		fetchQueue queue.Queue
+		pageCache  *frontend.PageCache
 	)
 	if *bypassLicenseCheck {
 		log.Info(ctx, "BYPASSING LICENSE CHECKING: DISPLAYING NON-REDISTRIBUTABLE INFORMATION")
@@ -72,7 +85,10 @@ func main() {
 	expg := cmdconfig.ExperimentGetter(ctx, cfg)
 	log.Infof(ctx, "cmd/frontend: initialized cmdconfig.ExperimentGetter")
 
-	proxyClient, err := proxy.New(*proxyURL)
+	if *proxyURL == "" {
+		*proxyURL = cfg.ProxyURL
+	}
+	proxyClient, err := proxy.NewFromGOPROXYWithNetrc(*proxyURL, cfg.ProxyNetrc)
 	if err != nil {
 		log.Fatal(ctx, err)
 	}
@@ -90,12 +106,19 @@ func main() {
 			log.Fatalf(ctx, "%v", err)
 		}
 		defer db.Close()
+		if *pageCacheSize > 0 {
+			pageCache, err = frontend.NewPageCache(*pageCacheSize)
+			if err != nil {
+				log.Fatalf(ctx, "frontend.NewPageCache: %v", err)
+			}
+			db.AddInsertModuleHook(pageCache.InvalidateModule)
+		}
 		dsg = func(context.Context) internal.DataSource { return db }
 		sourceClient := source.NewClient(config.SourceTimeout)
 		// The closure passed to queue.New is only used for testing and local
 		// execution, not in production. So it's okay that it doesn't use a
 		// per-request connection.
-		fetchQueue, err = queue.New(ctx, cfg, queueName, *workers, expg,
+		fetchQueue, err = queue.New(ctx, cfg, queueName, *workers, expg, db,
 			func(ctx context.Context, modulePath, version string) (int, error) {
 				return frontend.FetchAndUpdateState(ctx, modulePath, version, proxyClient, sourceClient, db)
 			})
@@ -109,19 +132,44 @@ func main() {
 	if err != nil {
 		log.Fatalf(ctx, "vuln.NewClient: %v", err)
 	}
+	var rateLimiter middleware.RateLimiter
+	if *rateLimitQPS > 0 {
+		rateLimiter, err = middleware.NewLocalRateLimiter(*rateLimitQPS, *rateLimitBurst, 10000)
+		if err != nil {
+			log.Fatalf(ctx, "middleware.NewLocalRateLimiter: %v", err)
+		}
+	}
+	var onDemandFetchRateLimiter middleware.RateLimiter
+	if *onDemandFetchTimeout > 0 && *onDemandFetchQPS > 0 {
+		onDemandFetchRateLimiter, err = middleware.NewLocalRateLimiter(*onDemandFetchQPS, *onDemandFetchBurst, 10000)
+		if err != nil {
+			log.Fatalf(ctx, "middleware.NewLocalRateLimiter: %v", err)
+		}
+	}
 	staticSource := template.TrustedSourceFromFlag(flag.Lookup("static").Value)
+	var docTemplateFS *template.TrustedFS
+	if *docTemplates != "" {
+		fsys := template.TrustedFSFromTrustedSource(template.TrustedSourceFromFlag(flag.Lookup("doc_templates").Value))
+		docTemplateFS = &fsys
+	}
 	server, err := frontend.NewServer(frontend.ServerConfig{
-		Config:               cfg,
-		DataSourceGetter:     dsg,
-		Queue:                fetchQueue,
-		TaskIDChangeInterval: config.TaskIDChangeIntervalFrontend,
-		TemplateFS:           template.TrustedFSFromTrustedSource(staticSource),
-		StaticFS:             os.DirFS(*staticFlag),
-		StaticPath:           *staticFlag,
-		ThirdPartyFS:         os.DirFS(*thirdPartyPath),
-		DevMode:              *devMode,
-		ReportingClient:      rc,
-		VulndbClient:         vc,
+		Config:                   cfg,
+		DataSourceGetter:         dsg,
+		Queue:                    fetchQueue,
+		TaskIDChangeInterval:     config.TaskIDChangeIntervalFrontend,
+		TemplateFS:               template.TrustedFSFromTrustedSource(staticSource),
+		StaticFS:                 os.DirFS(*staticFlag),
+		StaticPath:               *staticFlag,
+		ThirdPartyFS:             os.DirFS(*thirdPartyPath),
+		DevMode:                  *devMode,
+		ReportingClient:          rc,
+		VulndbClient:             vc,
+		PageCache:                pageCache,
+		RateLimiter:              rateLimiter,
+		DocTemplateFS:            docTemplateFS,
+		OnDemandFetchTimeout:     *onDemandFetchTimeout,
+		OnDemandFetchRateLimiter: onDemandFetchRateLimiter,
+		BaseURL:                  *baseURL,
 	})
 	if err != nil {
 		log.Fatalf(ctx, "frontend.NewServer: %v", err)
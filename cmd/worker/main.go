@@ -11,6 +11,7 @@ import (
 	"flag"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/profiler"
@@ -18,9 +19,12 @@ import (
 	"github.com/google/safehtml/template"
 	_ "github.com/jackc/pgx/v4/stdlib" // for pgx driver
 	"golang.org/x/pkgsite/cmd/internal/cmdconfig"
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/dcensus"
+	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/index"
+	"golang.org/x/pkgsite/internal/licenses"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/proxy"
@@ -36,6 +40,8 @@ var (
 	// flag used in call to safehtml/template.TrustedSourceFromFlag
 	_                  = flag.String("static", "static", "path to folder containing static files served")
 	bypassLicenseCheck = flag.Bool("bypass_license_check", false, "insert all data into the DB, even for non-redistributable paths")
+	healthCheckModule  = flag.String("health_check_module", worker.DefaultHealthCheckModule.String(),
+		"module@version whose info the readiness check requests from the proxy; it should be cached by every proxy")
 )
 
 func main() {
@@ -48,6 +54,9 @@ func main() {
 		log.Fatal(ctx, err)
 	}
 	cfg.Dump(os.Stdout)
+	if len(cfg.RedistributableLicenses) > 0 {
+		licenses.SetRedistributableLicenseTypes(cfg.RedistributableLicenses)
+	}
 
 	if cfg.UseProfiler {
 		if err := profiler.Start(profiler.Config{}); err != nil {
@@ -69,18 +78,26 @@ func main() {
 	if err != nil {
 		log.Fatal(ctx, err)
 	}
-	proxyClient, err := proxy.New(cfg.ProxyURL)
+	proxyClient, err := proxy.NewFromGOPROXYWithNetrc(cfg.ProxyURL, cfg.ProxyNetrc)
 	if err != nil {
 		log.Fatal(ctx, err)
 	}
 	sourceClient := source.NewClient(config.SourceTimeout)
+	var directGetter fetch.ModuleGetter
+	if cfg.VCSDir != "" {
+		directGetter, err = fetch.NewVCSModuleGetter(cfg.VCSDir, sourceClient)
+		if err != nil {
+			log.Fatal(ctx, err)
+		}
+	}
 	expg := cmdconfig.ExperimentGetter(ctx, cfg)
-	fetchQueue, err := queue.New(ctx, cfg, queueName, *workers, expg,
+	fetchQueue, err := queue.New(ctx, cfg, queueName, *workers, expg, db,
 		func(ctx context.Context, modulePath, version string) (int, error) {
 			f := &worker.Fetcher{
 				ProxyClient:  proxyClient,
 				SourceClient: sourceClient,
 				DB:           db,
+				DirectGetter: directGetter,
 			}
 			code, _, err := f.FetchAndUpdateState(ctx, modulePath, version, cfg.AppVersionLabel())
 			return code, err
@@ -93,17 +110,23 @@ func main() {
 	redisCacheClient := getCacheRedis(ctx, cfg)
 	redisBetaCacheClient := getBetaCacheRedis(ctx, cfg)
 	experimenter := cmdconfig.Experimenter(ctx, cfg, expg, reportingClient)
+	probePath, probeVersion, ok := strings.Cut(*healthCheckModule, "@")
+	if !ok || probePath == "" || probeVersion == "" {
+		log.Fatalf(ctx, "-health_check_module: want module@version, got %q", *healthCheckModule)
+	}
 	server, err := worker.NewServer(cfg, worker.ServerConfig{
 		DB:                   db,
 		IndexClient:          indexClient,
 		ProxyClient:          proxyClient,
 		SourceClient:         sourceClient,
+		DirectGetter:         directGetter,
 		RedisCacheClient:     redisCacheClient,
 		RedisBetaCacheClient: redisBetaCacheClient,
 		Queue:                fetchQueue,
 		ReportingClient:      reportingClient,
 		StaticPath:           template.TrustedSourceFromFlag(flag.Lookup("static").Value),
 		GetExperiments:       experimenter.Experiments,
+		HealthCheckModule:    internal.Modver{Path: probePath, Version: probeVersion},
 	})
 	if err != nil {
 		log.Fatal(ctx, err)
@@ -39,6 +39,7 @@ var (
 	keepGoing          = flag.Bool("keep_going", false, "continue on errors")
 	bypassLicenseCheck = flag.Bool("bypass_license_check", false,
 		"insert all data into the DB, even for non-redistributable paths")
+	localDirs = flag.String("dirs", "", "comma-separated list of local module directories to insert, without using the proxy")
 )
 
 func main() {
@@ -62,15 +63,15 @@ func main() {
 	}
 	defer db.Close()
 
-	if err := run(ctx, db, cfg.ProxyURL); err != nil {
+	if err := run(ctx, db, cfg.ProxyURL, cfg.ProxyNetrc); err != nil {
 		log.Fatal(ctx, err)
 	}
 }
 
-func run(ctx context.Context, db *database.DB, proxyURL string) error {
+func run(ctx context.Context, db *database.DB, proxyURL, proxyNetrc string) error {
 	start := time.Now()
 
-	proxyClient, err := proxy.New(proxyURL)
+	proxyClient, err := proxy.NewFromGOPROXYWithNetrc(proxyURL, proxyNetrc)
 	if err != nil {
 		return err
 	}
@@ -132,6 +133,15 @@ func run(ctx context.Context, db *database.DB, proxyURL string) error {
 	if len(errors) > 0 {
 		return errors
 	}
+	if *localDirs != "" {
+		for _, dir := range strings.Split(*localDirs, ",") {
+			m, v, err := f.FetchLocalModule(ctx, dir)
+			if err != nil {
+				return err
+			}
+			log.Infof(ctx, "Inserted %s@%s from %s", m, v, dir)
+		}
+	}
 	log.Infof(ctx, "Successfully fetched all modules: %v", time.Since(start))
 
 	// Print the time it took to fetch these modules.
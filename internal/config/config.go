@@ -119,7 +119,25 @@ type Config struct {
 	AuthValues []string
 
 	// Discovery environment variables
-	ProxyURL, IndexURL string
+	IndexURL string
+
+	// ProxyURL is the module proxy to use, or a list of them in the format
+	// of the GOPROXY environment variable. See proxy.NewFromGOPROXY.
+	ProxyURL string
+
+	// VCSDir is the directory where the worker clones the repositories of
+	// modules that it fetches directly, when ProxyURL ends in "direct". If it
+	// is empty, modules are not fetched directly.
+	VCSDir string
+
+	// ProxyNetrc is the path of a netrc file with credentials for the module
+	// proxy, if it requires authentication.
+	ProxyNetrc string
+
+	// RedistributableLicenses, if non-empty, replaces the default set of
+	// license types that allow redistribution. See
+	// licenses.SetRedistributableLicenseTypes.
+	RedistributableLicenses []string
 
 	// Ports used for hosting. 'DebugPort' is used for serving HTTP debug pages.
 	Port, DebugPort string
@@ -140,6 +158,12 @@ type Config struct {
 	// IAP that is gating access to the worker.
 	QueueAudience string
 
+	// QueueType selects the fetch queue. If it is QueueTypePostgres, fetches
+	// are queued in the fetch_queue table of the database, and processed by
+	// the processes that use the queue. Otherwise the Cloud Tasks queue is
+	// used on GCP, and an in-memory queue elsewhere.
+	QueueType string
+
 	// GoogleTagManagerID is the ID used for GoogleTagManager. It has the
 	// structure GTM-XXXX.
 	GoogleTagManagerID string
@@ -169,6 +193,8 @@ type Config struct {
 
 	Quota QuotaSettings
 
+	Search SearchSettings
+
 	// Minimum log level below which no logs will be printed.
 	// Possible values are [debug, info, error, fatal].
 	// In case of invalid/empty value, all logs will be printed.
@@ -246,6 +272,10 @@ const SourceTimeout = 1 * time.Minute
 // version can be re-enqueued to frontend tasks.
 const TaskIDChangeIntervalFrontend = 30 * time.Minute
 
+// QueueTypePostgres is the value of QueueType that selects the fetch queue
+// stored in the database.
+const QueueTypePostgres = "postgres"
+
 // DBConnInfo returns a PostgreSQL connection string constructed from
 // environment variables, using the primary database host.
 func (c *Config) DBConnInfo() string {
@@ -266,12 +296,14 @@ func (c *Config) DBSecondaryConnInfo() string {
 func (c *Config) dbConnInfo(host string) string {
 	// For the connection string syntax, see
 	// https://www.postgresql.org/docs/current/libpq-connect.html#LIBPQ-CONNSTRING.
-	// Set the statement_timeout config parameter for this session.
+	// Set the statement_timeout and default_text_search_config config
+	// parameters for this session.
 	// See https://www.postgresql.org/docs/current/runtime-config-client.html.
-	timeoutOption := fmt.Sprintf("-c statement_timeout=%d", StatementTimeout/time.Millisecond)
+	options := fmt.Sprintf("-c statement_timeout=%d -c default_text_search_config=%s",
+		StatementTimeout/time.Millisecond, c.Search.TextSearchConfiguration())
 	return fmt.Sprintf(
 		"user='%s' password='%s' host='%s' port=%s dbname='%s' sslmode='%s' options='%s'",
-		c.DBUser, c.DBPassword, host, c.DBPort, c.DBName, c.DBSSL, timeoutOption,
+		c.DBUser, c.DBPassword, host, c.DBPort, c.DBName, c.DBSSL, options,
 	)
 }
 
@@ -352,6 +384,38 @@ type QuotaSettings struct {
 	HMACKey    []byte `json:"-"` // key for obfuscating IPs
 }
 
+// SearchSettings configures how the text of search queries and of the
+// documents they search is split into words. The zero value removes stop
+// words and stems, as Postgres's english text search configuration does.
+//
+// The documents are split into words when they are inserted, so after a
+// change the search documents must be repopulated for queries to match them
+// consistently.
+type SearchSettings struct {
+	// KeepStopWords keeps common words like "the" and "of", which are
+	// otherwise ignored.
+	KeepStopWords bool
+	// DisableStemming matches words exactly, instead of matching all the
+	// words with the same stem, like "parsing" and "parse".
+	DisableStemming bool
+}
+
+// TextSearchConfiguration returns the name of the Postgres text search
+// configuration that implements s. The configurations other than the
+// built-in ones are created by the database migrations.
+func (s SearchSettings) TextSearchConfiguration() string {
+	switch {
+	case s.KeepStopWords && s.DisableStemming:
+		return "pg_catalog.simple"
+	case s.KeepStopWords:
+		return "english_keep_stopwords"
+	case s.DisableStemming:
+		return "english_nostem"
+	default:
+		return "pg_catalog.english"
+	}
+}
+
 // Init resolves all configuration values provided by the config package. It
 // must be called before any configuration values are used.
 func Init(ctx context.Context) (_ *Config, err error) {
@@ -362,8 +426,12 @@ func Init(ctx context.Context) (_ *Config, err error) {
 		AuthValues: parseCommaList(os.Getenv("GO_DISCOVERY_AUTH_VALUES")),
 		IndexURL:   GetEnv("GO_MODULE_INDEX_URL", "https://index.golang.org/index"),
 		ProxyURL:   GetEnv("GO_MODULE_PROXY_URL", "https://proxy.golang.org"),
+		ProxyNetrc: os.Getenv("GO_MODULE_PROXY_NETRC"),
+		VCSDir:     os.Getenv("GO_DISCOVERY_VCS_DIR"),
 		Port:       os.Getenv("PORT"),
 		DebugPort:  os.Getenv("DEBUG_PORT"),
+		// License policy
+		RedistributableLicenses: parseCommaList(os.Getenv("GO_DISCOVERY_REDISTRIBUTABLE_LICENSES")),
 		// Resolve AppEngine identifiers
 		ProjectID: os.Getenv("GOOGLE_CLOUD_PROJECT"),
 		ServiceID: GetEnv("GAE_SERVICE", os.Getenv("GO_DISCOVERY_SERVICE")),
@@ -375,6 +443,7 @@ func Init(ctx context.Context) (_ *Config, err error) {
 		GoogleTagManagerID: os.Getenv("GO_DISCOVERY_GOOGLE_TAG_MANAGER_ID"),
 		QueueURL:           os.Getenv("GO_DISCOVERY_QUEUE_URL"),
 		QueueAudience:      os.Getenv("GO_DISCOVERY_QUEUE_AUDIENCE"),
+		QueueType:          os.Getenv("GO_DISCOVERY_QUEUE_TYPE"),
 
 		// LocationID is essentially hard-coded until we figure out a good way to
 		// determine it programmatically, but we check an environment variable in
@@ -404,6 +473,10 @@ func Init(ctx context.Context) (_ *Config, err error) {
 			}(),
 			AuthValues: parseCommaList(os.Getenv("GO_DISCOVERY_AUTH_VALUES")),
 		},
+		Search: SearchSettings{
+			KeepStopWords:   os.Getenv("GO_DISCOVERY_SEARCH_KEEP_STOP_WORDS") == "true",
+			DisableStemming: os.Getenv("GO_DISCOVERY_SEARCH_DISABLE_STEMMING") == "true",
+		},
 		UseProfiler:           os.Getenv("GO_DISCOVERY_USE_PROFILER") == "true",
 		LogLevel:              os.Getenv("GO_DISCOVERY_LOG_LEVEL"),
 		ServeStats:            os.Getenv("GO_DISCOVERY_SERVE_STATS") == "true",
@@ -7,6 +7,7 @@ package config
 import (
 	"context"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -119,3 +120,23 @@ func TestEnvAndApp(t *testing.T) {
 		}
 	}
 }
+
+func TestTextSearchConfiguration(t *testing.T) {
+	for _, test := range []struct {
+		settings SearchSettings
+		want     string
+	}{
+		{SearchSettings{}, "pg_catalog.english"},
+		{SearchSettings{KeepStopWords: true}, "english_keep_stopwords"},
+		{SearchSettings{DisableStemming: true}, "english_nostem"},
+		{SearchSettings{KeepStopWords: true, DisableStemming: true}, "pg_catalog.simple"},
+	} {
+		if got := test.settings.TextSearchConfiguration(); got != test.want {
+			t.Errorf("%+v: got %q, want %q", test.settings, got, test.want)
+		}
+		cfg := &Config{Search: test.settings}
+		if got, want := cfg.DBConnInfo(), "default_text_search_config="+test.want; !strings.Contains(got, want) {
+			t.Errorf("%+v: DBConnInfo() = %q, want it to contain %q", test.settings, got, want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+	"sort"
+)
+
+// ContentHash returns a hash of the content of u that is shown to users:
+// its documentation, README, licenses and imports. Two units with the same
+// content have the same hash, regardless of the order of their
+// documentation, licenses and imports, so callers can compare hashes to
+// skip work, like invalidating caches or reindexing, when refetching a
+// module did not change a unit.
+//
+// The hash does not cover the unit's metadata, such as its version and
+// commit time, which change with every version.
+func (u *Unit) ContentHash() string {
+	h := sha256.New()
+
+	docs := append([]*Documentation(nil), u.Documentation...)
+	sort.Slice(docs, func(i, j int) bool {
+		if docs[i].GOOS != docs[j].GOOS {
+			return docs[i].GOOS < docs[j].GOOS
+		}
+		return docs[i].GOARCH < docs[j].GOARCH
+	})
+	writeHashInt(h, len(docs))
+	for _, d := range docs {
+		writeHashString(h, d.GOOS)
+		writeHashString(h, d.GOARCH)
+		writeHashString(h, d.Synopsis)
+		writeHashString(h, d.Usage)
+		writeHashBytes(h, d.Source)
+	}
+
+	if u.Readme == nil {
+		writeHashInt(h, 0)
+	} else {
+		writeHashInt(h, 1)
+		writeHashString(h, u.Readme.Filepath)
+		writeHashString(h, u.Readme.Contents)
+	}
+
+	// The license metadata is in u.Licenses, and the contents, when they
+	// have been read, in u.LicenseContents.
+	var lics []string
+	for _, l := range u.Licenses {
+		lics = append(lics, licenseKey(l.FilePath, l.Types, nil))
+	}
+	for _, l := range u.LicenseContents {
+		lics = append(lics, licenseKey(l.FilePath, l.Types, l.Contents))
+	}
+	sort.Strings(lics)
+	writeHashInt(h, len(lics))
+	for _, l := range lics {
+		writeHashString(h, l)
+	}
+
+	imports := append([]string(nil), u.Imports...)
+	sort.Strings(imports)
+	writeHashInt(h, len(imports))
+	for _, imp := range imports {
+		writeHashString(h, imp)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// licenseKey returns a string identifying a license for ContentHash.
+func licenseKey(filePath string, types []string, contents []byte) string {
+	h := sha256.New()
+	writeHashString(h, filePath)
+	writeHashInt(h, len(types))
+	for _, t := range types {
+		writeHashString(h, t)
+	}
+	writeHashBytes(h, contents)
+	return string(h.Sum(nil))
+}
+
+// The writeHash functions write values to a hash so that distinct sequences
+// of values produce distinct input: each string is preceded by its length.
+
+func writeHashInt(h hash.Hash, n int) {
+	var buf [binary.MaxVarintLen64]byte
+	h.Write(buf[:binary.PutUvarint(buf[:], uint64(n))])
+}
+
+func writeHashBytes(h hash.Hash, b []byte) {
+	writeHashInt(h, len(b))
+	h.Write(b)
+}
+
+func writeHashString(h hash.Hash, s string) {
+	writeHashBytes(h, []byte(s))
+}
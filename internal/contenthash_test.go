@@ -0,0 +1,63 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"testing"
+
+	"golang.org/x/pkgsite/internal/licenses"
+)
+
+func TestUnitContentHash(t *testing.T) {
+	newUnit := func() *Unit {
+		return &Unit{
+			UnitMeta: UnitMeta{
+				Path: "example.com/m/p",
+				ModuleInfo: ModuleInfo{
+					ModulePath: "example.com/m",
+					Version:    "v1.0.0",
+				},
+				Licenses: []*licenses.Metadata{{Types: []string{"MIT"}, FilePath: "LICENSE"}},
+			},
+			Documentation: []*Documentation{
+				{GOOS: "linux", GOARCH: "amd64", Synopsis: "Package p.", Source: []byte("source")},
+				{GOOS: "windows", GOARCH: "amd64", Synopsis: "Package p.", Source: []byte("source2")},
+			},
+			Readme:  &Readme{Filepath: "README.md", Contents: "readme"},
+			Imports: []string{"fmt", "strings"},
+		}
+	}
+	want := newUnit().ContentHash()
+
+	// Identical content hashes equally, whatever its order and the unit's
+	// version.
+	u := newUnit()
+	u.Documentation[0], u.Documentation[1] = u.Documentation[1], u.Documentation[0]
+	u.Imports = []string{"strings", "fmt"}
+	u.Version = "v1.0.1"
+	if got := u.ContentHash(); got != want {
+		t.Errorf("hash of identical content = %s, want %s", got, want)
+	}
+
+	for _, test := range []struct {
+		name   string
+		change func(*Unit)
+	}{
+		{"synopsis", func(u *Unit) { u.Documentation[0].Synopsis = "Package p does things." }},
+		{"source", func(u *Unit) { u.Documentation[1].Source = []byte("new source") }},
+		{"build context", func(u *Unit) { u.Documentation = u.Documentation[:1] }},
+		{"readme", func(u *Unit) { u.Readme.Contents = "new readme" }},
+		{"no readme", func(u *Unit) { u.Readme = nil }},
+		{"license", func(u *Unit) { u.Licenses[0].Types = []string{"BSD-3-Clause"} }},
+		{"imports", func(u *Unit) { u.Imports = append(u.Imports, "os") }},
+		{"import boundary", func(u *Unit) { u.Imports = []string{"fmtstrings"} }},
+	} {
+		u := newUnit()
+		test.change(u)
+		if got := u.ContentHash(); got == want {
+			t.Errorf("%s changed: hash did not change", test.name)
+		}
+	}
+}
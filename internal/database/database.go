@@ -67,6 +67,11 @@ func (db *DB) Ping() error {
 	return db.db.Ping()
 }
 
+// PingContext is like Ping, but gives up when ctx is done.
+func (db *DB) PingContext(ctx context.Context) error {
+	return db.db.PingContext(ctx)
+}
+
 func (db *DB) InTransaction() bool {
 	return db.tx != nil
 }
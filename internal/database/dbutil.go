@@ -184,6 +184,12 @@ func ResetDB(ctx context.Context, db *DB) error {
 		if _, err := tx.Exec(ctx, `TRUNCATE excluded_prefixes;`); err != nil {
 			return err
 		}
+		if _, err := tx.Exec(ctx, `TRUNCATE vulns; TRUNCATE withdrawn_vulns;`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `TRUNCATE fetch_queue;`); err != nil {
+			return err
+		}
 		return nil
 	}); err != nil {
 		return fmt.Errorf("error resetting test DB: %v", err)
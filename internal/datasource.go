@@ -25,6 +25,10 @@ type SearchOptions struct {
 
 	// SymbolFilter is the word in a search query with a # prefix.
 	SymbolFilter string
+
+	// If true, omit packages whose latest version is affected by a known
+	// vulnerability.
+	ExcludeVulnerable bool
 }
 
 // SearchResult represents a single search result from SearchDocuments.
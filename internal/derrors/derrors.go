@@ -25,6 +25,12 @@ var (
 	// NotFound indicates that a requested entity was not found (HTTP 404).
 	NotFound = errors.New("not found")
 
+	// ProxyGone indicates that a requested module version is no longer
+	// available, for example because it was disabled (HTTP 410). It wraps
+	// NotFound, so that code handling missing module versions handles gone
+	// ones too.
+	ProxyGone = fmt.Errorf("gone: %w", NotFound)
+
 	// NotFetched means that the proxy returned "not found" with the
 	// Disable-Module-Fetch header set. We don't know if the module really
 	// doesn't exist, or the proxy just didn't fetch it.
@@ -79,6 +85,12 @@ var (
 	// PackageDocumentationHTMLTooLarge indicates that the rendered documentation
 	// HTML size exceeded the specified limit for dochtml.RenderOptions.
 	PackageDocumentationHTMLTooLarge = errors.New("package documentation HTML is too large")
+	// PackageDocumentationRenderTimeout indicates that rendering the
+	// documentation of a package took longer than fetch.DocRenderTimeout.
+	PackageDocumentationRenderTimeout = errors.New("package documentation render timed out")
+	// PackageDocumentationRenderPanic indicates that rendering the
+	// documentation of a package panicked.
+	PackageDocumentationRenderPanic = errors.New("package documentation render panicked")
 	// PackageBadImportPath represents an error loading a package because its
 	// contents do not make up a valid package. This can happen, for
 	// example, if the .go files fail to parse or declare different package
@@ -118,6 +130,8 @@ var codes = []struct {
 	err  error
 	code int
 }{
+	// ProxyGone wraps NotFound, so it must come first.
+	{ProxyGone, http.StatusGone},
 	{NotFound, http.StatusNotFound},
 	{InvalidArgument, http.StatusBadRequest},
 	{Excluded, http.StatusForbidden},
@@ -152,6 +166,8 @@ var codes = []struct {
 	{PackageDocumentationHTMLTooLarge, 603},
 	{PackageInvalidContents, 604},
 	{PackageBadImportPath, 605},
+	{PackageDocumentationRenderTimeout, 606},
+	{PackageDocumentationRenderPanic, 607},
 }
 
 // FromStatus generates an error according for the given status code. It uses
@@ -51,6 +51,7 @@ func TestToHTTPStatus(t *testing.T) {
 		{nil, http.StatusOK},
 		{InvalidArgument, http.StatusBadRequest},
 		{NotFound, http.StatusNotFound},
+		{fmt.Errorf("wrapping: %w", ProxyGone), http.StatusGone},
 		{BadModule, 490},
 		{AlternativeModule, 491},
 		{Unknown, http.StatusInternalServerError},
@@ -0,0 +1,72 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"sort"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// A DirectoryNode is a directory in the tree of the packages of a module
+// version.
+type DirectoryNode struct {
+	// Path is the full path of the directory.
+	Path string
+	// Package is the package in the directory, or nil if there is none.
+	Package *PackageMeta
+	// Children are the subdirectories that contain packages, sorted by path.
+	Children []*DirectoryNode
+}
+
+// NewDirectoryTree returns the tree of the directories of pkgs, which must
+// be the packages of the module with the given path. The root of the tree is
+// the module root. A directory that is not itself a package is present only
+// if some package is below it.
+func NewDirectoryTree(modulePath string, pkgs []*PackageMeta) *DirectoryNode {
+	root := &DirectoryNode{Path: modulePath}
+	nodes := map[string]*DirectoryNode{modulePath: root}
+	// node returns the node for the directory with the given path relative
+	// to the module root, creating it and its ancestors if necessary.
+	var node func(rel string) *DirectoryNode
+	node = func(rel string) *DirectoryNode {
+		full := rel
+		if modulePath != stdlib.ModulePath {
+			full = modulePath + "/" + rel
+		}
+		if n := nodes[full]; n != nil {
+			return n
+		}
+		parent := root
+		if i := strings.LastIndexByte(rel, '/'); i >= 0 {
+			parent = node(rel[:i])
+		}
+		n := &DirectoryNode{Path: full}
+		parent.Children = append(parent.Children, n)
+		nodes[full] = n
+		return n
+	}
+	for _, p := range pkgs {
+		if p.Path == modulePath {
+			root.Package = p
+			continue
+		}
+		rel := p.Path
+		if modulePath != stdlib.ModulePath {
+			rel = strings.TrimPrefix(p.Path, modulePath+"/")
+		}
+		node(rel).Package = p
+	}
+	var sortChildren func(n *DirectoryNode)
+	sortChildren = func(n *DirectoryNode) {
+		sort.Slice(n.Children, func(i, j int) bool { return n.Children[i].Path < n.Children[j].Path })
+		for _, c := range n.Children {
+			sortChildren(c)
+		}
+	}
+	sortChildren(root)
+	return root
+}
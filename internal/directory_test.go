@@ -0,0 +1,67 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewDirectoryTree(t *testing.T) {
+	pkg := func(path string) *PackageMeta { return &PackageMeta{Path: path} }
+	dir := func(path string, pkg *PackageMeta, children ...*DirectoryNode) *DirectoryNode {
+		return &DirectoryNode{Path: path, Package: pkg, Children: children}
+	}
+
+	for _, test := range []struct {
+		name       string
+		modulePath string
+		pkgs       []*PackageMeta
+		want       *DirectoryNode
+	}{
+		{
+			name:       "nested",
+			modulePath: "example.com/m",
+			pkgs: []*PackageMeta{
+				pkg("example.com/m/z"),
+				pkg("example.com/m"),
+				pkg("example.com/m/a/b/c"),
+				pkg("example.com/m/a"),
+				pkg("example.com/m/a/b/d"),
+				pkg("example.com/m/x/y"),
+			},
+			want: dir("example.com/m", pkg("example.com/m"),
+				dir("example.com/m/a", pkg("example.com/m/a"),
+					dir("example.com/m/a/b", nil,
+						dir("example.com/m/a/b/c", pkg("example.com/m/a/b/c")),
+						dir("example.com/m/a/b/d", pkg("example.com/m/a/b/d")))),
+				dir("example.com/m/x", nil,
+					dir("example.com/m/x/y", pkg("example.com/m/x/y"))),
+				dir("example.com/m/z", pkg("example.com/m/z"))),
+		},
+		{
+			name:       "stdlib",
+			modulePath: "std",
+			pkgs:       []*PackageMeta{pkg("fmt"), pkg("encoding/json"), pkg("encoding")},
+			want: dir("std", nil,
+				dir("encoding", pkg("encoding"),
+					dir("encoding/json", pkg("encoding/json"))),
+				dir("fmt", pkg("fmt"))),
+		},
+		{
+			name:       "empty",
+			modulePath: "example.com/m",
+			want:       dir("example.com/m", nil),
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := NewDirectoryTree(test.modulePath, test.pkgs)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
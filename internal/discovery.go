@@ -51,6 +51,10 @@ type ModuleInfo struct {
 	// HasGoMod describes whether the module zip has a go.mod file.
 	HasGoMod   bool
 	SourceInfo *source.Info
+	// Commit describes the commit of the module version. It is nil if the
+	// module was neither fetched from its repository nor from a proxy that
+	// reports the origin of the version; the proxy reports only the hash.
+	Commit *Commit
 
 	// Deprecated describes whether the module is deprecated.
 	Deprecated bool
@@ -62,6 +66,15 @@ type ModuleInfo struct {
 	RetractionRationale string
 }
 
+// Commit describes the commit that a module version was built from.
+type Commit struct {
+	Hash           string
+	AuthorName     string
+	AuthorEmail    string
+	CommitterName  string
+	CommitterEmail string
+}
+
 // VersionMap holds metadata associated with module queries for a version.
 type VersionMap struct {
 	ModulePath       string
@@ -153,6 +166,47 @@ type Module struct {
 	// that may be contained in nested subdirectories.
 	Licenses []*licenses.License
 	Units    []*Unit
+	// Requirements holds the require directives of the module's go.mod file.
+	Requirements []*Requirement
+	// GoMod is the contents of the module's go.mod file, as served by the
+	// module proxy. It is nil for the standard library.
+	GoMod []byte
+	// Changelog is the changelog file at the root of the module, if any.
+	Changelog *Changelog
+	// Notice is the NOTICE file at the root of the module, if any.
+	Notice *Notice
+	// Workspace describes the go.work file at the root of the module, if
+	// any.
+	Workspace *Workspace
+	// LicenseConflict reports whether some of the module's license files
+	// permit redistribution and others do not.
+	LicenseConflict bool
+	// Keywords are the most frequent terms in the module's README and
+	// package synopses, used for search facets.
+	Keywords []string
+	// HasPackages reports whether the module contains any packages. A
+	// module may have only a go.mod file, and perhaps a README and licenses.
+	HasPackages bool
+}
+
+// A Requirement is a single require directive from a go.mod file.
+type Requirement struct {
+	ModulePath string
+	Version    string
+	// Indirect reports whether the directive is marked with an
+	// "// indirect" comment.
+	Indirect bool
+}
+
+// A Workspace describes a go.work file, which lists the modules of a
+// multi-module repository that are developed together.
+type Workspace struct {
+	// GoVersion is the version from the go directive, if any.
+	GoVersion string
+	// Dirs are the module directories of the use directives, relative to
+	// the directory of the go.work file, cleaned and in the order they
+	// appear in the file. For example, "." or "tools".
+	Dirs []string
 }
 
 // Packages returns all of the units for a module that are packages.
@@ -222,6 +276,10 @@ type ModuleVersionState struct {
 	// NumPackages it the number of packages that were processed as part of the
 	// module (regardless of whether the processing was successful).
 	NumPackages *int
+
+	// DocumentationPurged reports whether the documentation of this version
+	// was purged since it was last processed.
+	DocumentationPurged bool
 }
 
 // PackageVersionState holds a worker package version state. It is associated
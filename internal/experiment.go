@@ -6,15 +6,19 @@
 package internal
 
 const (
-	ExperimentEnableStdFrontendFetch = "enable-std-frontend-fetch"
-	ExperimentStyleGuide             = "styleguide"
+	ExperimentEnableStdFrontendFetch  = "enable-std-frontend-fetch"
+	ExperimentRedirectToLatestVersion = "redirect-to-latest-version"
+	ExperimentStoreDocFormats         = "store-doc-formats"
+	ExperimentStyleGuide              = "styleguide"
 )
 
 // Experiments represents all of the active experiments in the codebase and
 // a description of each experiment.
 var Experiments = map[string]string{
-	ExperimentEnableStdFrontendFetch: "Enable frontend fetching for module std.",
-	ExperimentStyleGuide:             "Enable the styleguide.",
+	ExperimentEnableStdFrontendFetch:  "Enable frontend fetching for module std.",
+	ExperimentRedirectToLatestVersion: "Redirect unversioned unit pages to the latest good version.",
+	ExperimentStoreDocFormats:         "Render and store the HTML and plain-text documentation of packages when they are fetched.",
+	ExperimentStyleGuide:              "Enable the styleguide.",
 }
 
 // Experiment holds data associated with an experimental feature for frontend
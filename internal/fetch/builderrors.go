@@ -0,0 +1,75 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// setBuildErrors sets the BuildErrors of each package in mod to the imports
+// of the package that cannot be resolved: those of packages within the module
+// that don't exist, and, if the module has a go.mod file, those not provided
+// by the standard library, the module or a required module.
+//
+// Since the dependencies of a module aren't available, setBuildErrors can't
+// tell whether an import from a required module exists, or type-check the
+// package.
+func setBuildErrors(mod *internal.Module) {
+	if mod.ModulePath == stdlib.ModulePath {
+		return
+	}
+	pkgs := map[string]bool{}
+	for _, u := range mod.Units {
+		if u.IsPackage() {
+			pkgs[u.Path] = true
+		}
+	}
+	for _, u := range mod.Units {
+		if !u.IsPackage() {
+			continue
+		}
+		u.BuildErrors = nil
+		for _, imp := range u.Imports {
+			if err := resolveImport(mod, pkgs, imp); err != "" {
+				u.BuildErrors = append(u.BuildErrors, err)
+			}
+		}
+	}
+}
+
+// resolveImport returns a description of why imp, imported by a package of
+// mod, can't be resolved, or the empty string if it can. pkgs holds the paths
+// of the packages in mod.
+func resolveImport(mod *internal.Module, pkgs map[string]bool, imp string) string {
+	if stdlib.Contains(imp) {
+		return ""
+	}
+	for _, r := range mod.Requirements {
+		if inModule(imp, r.ModulePath) {
+			return ""
+		}
+	}
+	if inModule(imp, mod.ModulePath) {
+		if pkgs[imp] {
+			return ""
+		}
+		return fmt.Sprintf("cannot find package %q in module %s", imp, mod.ModulePath)
+	}
+	if !mod.HasGoMod {
+		// Without a go.mod file, the module's dependencies are unknown.
+		return ""
+	}
+	return fmt.Sprintf("no required module provides package %q", imp)
+}
+
+// inModule reports whether importPath is the path of a package in the module
+// with the given path, ignoring nested modules.
+func inModule(importPath, modulePath string) bool {
+	return importPath == modulePath || strings.HasPrefix(importPath, modulePath+"/")
+}
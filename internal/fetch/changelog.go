@@ -0,0 +1,86 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// extractChangelog returns the changelog file at the root of contentDir, or
+// nil if there is none. If there are several, it prefers markdown files,
+// then CHANGELOG over CHANGES.
+//
+// Unlike READMEs, a changelog that is too large is ignored rather than
+// causing the fetch to fail, since changelogs of long-lived modules can grow
+// without bound.
+func extractChangelog(contentDir fs.FS) (_ *internal.Changelog, err error) {
+	defer derrors.Wrap(&err, "extractChangelog")
+
+	entries, err := fs.ReadDir(contentDir, ".")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) { // we can get NotExist on an empty FS
+			return nil, nil
+		}
+		return nil, err
+	}
+	var candidates []fs.DirEntry
+	for _, e := range entries {
+		if !e.IsDir() && changelogRank(e.Name()) >= 0 {
+			candidates = append(candidates, e)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return changelogRank(candidates[i].Name()) < changelogRank(candidates[j].Name())
+	})
+	for _, e := range candidates {
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() > MaxFileSize {
+			continue
+		}
+		c, err := readFSFile(contentDir, e.Name(), MaxFileSize)
+		if err != nil {
+			return nil, err
+		}
+		return &internal.Changelog{Filepath: e.Name(), Contents: string(c)}, nil
+	}
+	return nil, nil
+}
+
+// changelogRank returns the preference order of file as a changelog, lower
+// being better, or -1 if file is not a changelog. Matching is case
+// insensitive, and .go files are never changelogs.
+func changelogRank(file string) int {
+	ext := path.Ext(file)
+	if excludedReadmeExts[ext] {
+		return -1
+	}
+	rank := 0
+	switch strings.ToUpper(strings.TrimSuffix(file, ext)) {
+	case "CHANGELOG":
+	case "CHANGES":
+		rank = 1
+	default:
+		return -1
+	}
+	if !isMarkdownExt(ext) {
+		rank += 2
+	}
+	return rank
+}
+
+func isMarkdownExt(ext string) bool {
+	ext = strings.ToLower(ext)
+	return ext == ".md" || ext == ".markdown"
+}
@@ -0,0 +1,75 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestExtractChangelog(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		files map[string]string
+		want  *internal.Changelog
+	}{
+		{
+			name:  "none",
+			files: map[string]string{"README.md": "readme", "go.mod": "module m"},
+			want:  nil,
+		},
+		{
+			name:  "CHANGELOG.md",
+			files: map[string]string{"CHANGELOG.md": "# v1.0.0", "README.md": "readme"},
+			want:  &internal.Changelog{Filepath: "CHANGELOG.md", Contents: "# v1.0.0"},
+		},
+		{
+			name:  "prefer markdown",
+			files: map[string]string{"CHANGELOG": "text", "CHANGES.md": "markdown"},
+			want:  &internal.Changelog{Filepath: "CHANGES.md", Contents: "markdown"},
+		},
+		{
+			name:  "prefer CHANGELOG",
+			files: map[string]string{"CHANGES": "changes", "ChangeLog": "changelog"},
+			want:  &internal.Changelog{Filepath: "ChangeLog", Contents: "changelog"},
+		},
+		{
+			name:  "only at root",
+			files: map[string]string{"sub/CHANGELOG.md": "sub"},
+			want:  nil,
+		},
+		{
+			name:  "not Go files",
+			files: map[string]string{"changelog.go": "package changelog"},
+			want:  nil,
+		},
+		{
+			name: "too large",
+			files: map[string]string{
+				"CHANGELOG.md": strings.Repeat("x", int(MaxFileSize)+1),
+				"CHANGES.txt":  "small",
+			},
+			want: &internal.Changelog{Filepath: "CHANGES.txt", Contents: "small"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, contents := range test.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(contents)}
+			}
+			got, err := extractChangelog(fsys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
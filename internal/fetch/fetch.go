@@ -41,6 +41,9 @@ type FetchResult struct {
 	Error                error
 	Module               *internal.Module
 	PackageVersionStates []*internal.PackageVersionState
+	// ValidationReport lists problems with the module that did not prevent
+	// it from being processed. It is nil if there are none.
+	ValidationReport *ValidationReport
 }
 
 // FetchModule queries the proxy or the Go repo for the requested module
@@ -137,8 +140,10 @@ func fetchModule(ctx context.Context, fr *FetchResult, mg ModuleGetter) error {
 			return fmt.Errorf("%v: %w", err.Error(), derrors.BadModule)
 		}
 	}
+	setBuildErrors(mod)
 	fr.Module = mod
 	fr.PackageVersionStates = pvs
+	fr.ValidationReport = validateModule(mod)
 	for _, state := range fr.PackageVersionStates {
 		if state.Status != http.StatusOK {
 			fr.Status = derrors.ToStatus(derrors.HasIncompletePackages)
@@ -202,10 +207,29 @@ func processModuleContents(ctx context.Context, modulePath, resolvedVersion, req
 	if err != nil {
 		log.Infof(ctx, "error getting source info: %v", err)
 	}
+	var commit *internal.Commit
+	if cg, ok := mg.(CommitModuleGetter); ok {
+		commit, err = cg.Commit(ctx, modulePath, v)
+		if err != nil {
+			log.Infof(ctx, "error getting commit: %v", err)
+		}
+	}
 	readmes, err := extractReadmes(modulePath, resolvedVersion, contentDir)
 	if err != nil {
 		return nil, nil, err
 	}
+	changelog, err := extractChangelog(contentDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	notice, err := extractNotice(contentDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	workspace, err := extractWorkspace(ctx, contentDir)
+	if err != nil {
+		return nil, nil, err
+	}
 	logf := func(format string, args ...any) {
 		log.Infof(ctx, format, args...)
 	}
@@ -218,18 +242,29 @@ func processModuleContents(ctx context.Context, modulePath, resolvedVersion, req
 	if err != nil {
 		return nil, nil, err
 	}
+	if len(packages) == 0 && !hasGoModFile(contentDir) {
+		// Without a go.mod file or any packages, there is nothing to show.
+		return nil, nil, fmt.Errorf("%v: %w", ErrModuleContainsNoPackages.Error(), derrors.BadModule)
+	}
 	minfo := internal.ModuleInfo{
 		ModulePath:        modulePath,
 		Version:           resolvedVersion,
 		CommitTime:        commitTime,
 		IsRedistributable: d.ModuleIsRedistributable(),
 		SourceInfo:        sourceInfo,
+		Commit:            commit,
 		// HasGoMod is populated by the caller.
 	}
+	units := moduleUnits(modulePath, minfo, packages, readmes, d)
 	return &internal.Module{
-		ModuleInfo: minfo,
-		Licenses:   allLicenses,
-		Units:      moduleUnits(modulePath, minfo, packages, readmes, d),
+		ModuleInfo:  minfo,
+		Licenses:    allLicenses,
+		Units:       units,
+		Changelog:   changelog,
+		Notice:      notice,
+		Workspace:   workspace,
+		Keywords:    extractKeywords(modulePath, units),
+		HasPackages: len(packages) > 0,
 	}, packageVersionStates, nil
 }
 
@@ -246,7 +281,16 @@ func processGoModFile(goModBytes []byte, mod *internal.Module) (err error) {
 	if err != nil {
 		return err
 	}
+	mod.GoMod = goModBytes
 	mod.Deprecated, mod.DeprecationComment = extractDeprecatedComment(mf)
+	mod.Requirements = nil
+	for _, r := range mf.Require {
+		mod.Requirements = append(mod.Requirements, &internal.Requirement{
+			ModulePath: r.Mod.Path,
+			Version:    r.Mod.Version,
+			Indirect:   r.Indirect,
+		})
+	}
 	return nil
 }
 
@@ -12,6 +12,8 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -22,6 +24,7 @@ import (
 	"golang.org/x/mod/modfile"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/licenses"
@@ -29,6 +32,7 @@ import (
 	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 	"golang.org/x/pkgsite/internal/testing/sample"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
 )
 
 var testTimeout = 30 * time.Second
@@ -173,6 +177,901 @@ func validateDocumentationHTML(t *testing.T, got *internal.Module, want map[stri
 	}
 }
 
+func TestFetchModule_DocStats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/docstats",
+		Files: map[string]string{
+			"go.mod":  "module example.com/docstats",
+			"LICENSE": testhelper.MITLicense,
+			"p/p.go": `
+				// Package p is partially documented.
+				package p
+
+				// A is documented.
+				const A = 1
+
+				const (
+					// B is documented.
+					B = 2
+					C = 3
+				)
+
+				// Group is a documented group.
+				var (
+					D, E int
+				)
+
+				func F() {}
+
+				// T is documented.
+				type T struct{}
+
+				// NewT is documented.
+				func NewT() T { return T{} }
+
+				func (T) M() {}
+
+				func unexported() {}
+			`,
+			"empty/empty.go": "package empty",
+		},
+	}
+	for _, fetcher := range []struct {
+		name  string
+		fetch fetchFunc
+	}{
+		{name: "proxy", fetch: proxyFetcher},
+		{name: "local", fetch: localFetcher},
+	} {
+		t.Run(fetcher.name, func(t *testing.T) {
+			got, _ := fetcher.fetch(t, false, ctx, mod, "")
+			if got.Error != nil {
+				t.Fatal(got.Error)
+			}
+			want := map[string]internal.DocStats{
+				"example.com/docstats/p":     {NumExported: 9, NumDocumented: 6},
+				"example.com/docstats/empty": {},
+			}
+			for _, u := range got.Module.Units {
+				w, ok := want[u.Path]
+				if !ok {
+					continue
+				}
+				if u.DocStats != w {
+					t.Errorf("%s: got %+v, want %+v", u.Path, u.DocStats, w)
+				}
+				delete(want, u.Path)
+			}
+			if len(want) > 0 {
+				t.Errorf("missing units: %v", want)
+			}
+		})
+	}
+}
+
+func TestFetchModule_DocRenderTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	defer func(d time.Duration) { DocRenderTimeout = d }(DocRenderTimeout)
+	DocRenderTimeout = 10 * time.Millisecond
+	// The slow rendering stops when its context is canceled by the timeout.
+	stopped := make(chan struct{})
+	realDocInfo := docInfo
+	defer func() { docInfo = realDocInfo }()
+	docInfo = func(p *godoc.Package, ctx context.Context, innerPath string, si *source.Info, mi *godoc.ModuleInfo) (*godoc.DocInfo, error) {
+		if innerPath == "slow" {
+			<-ctx.Done()
+			close(stopped)
+			return nil, ctx.Err()
+		}
+		return realDocInfo(p, ctx, innerPath, si, mi)
+	}
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/render",
+		Files: map[string]string{
+			"go.mod":       "module example.com/render",
+			"LICENSE":      testhelper.MITLicense,
+			"slow/slow.go": "package slow\n\nfunc F() {}",
+			"fast/fast.go": "package fast\n\nfunc F() {}",
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	if want := derrors.ToStatus(derrors.HasIncompletePackages); got.Status != want {
+		t.Errorf("status: got %d, want %d", got.Status, want)
+	}
+	units := map[string]*internal.Unit{}
+	for _, u := range got.Module.Units {
+		units[u.Path] = u
+	}
+	slow := units["example.com/render/slow"]
+	if slow == nil || !slow.IsPackage() {
+		t.Fatal("slow package missing")
+	}
+	if slow.Documentation != nil {
+		t.Errorf("slow: got documentation %v, want nil", slow.Documentation)
+	}
+	if fast := units["example.com/render/fast"]; fast == nil || len(fast.Documentation) == 0 {
+		t.Error("fast: got no documentation")
+	}
+	for _, s := range got.PackageVersionStates {
+		if s.PackagePath == "example.com/render/slow" {
+			if want := derrors.ToStatus(derrors.PackageDocumentationRenderTimeout); s.Status != want {
+				t.Errorf("slow package status: got %d, want %d", s.Status, want)
+			}
+		}
+	}
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		t.Error("slow rendering did not stop")
+	}
+}
+
+func TestFetchModule_DocRenderPanic(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	realDocInfo := docInfo
+	defer func() { docInfo = realDocInfo }()
+	docInfo = func(p *godoc.Package, ctx context.Context, innerPath string, si *source.Info, mi *godoc.ModuleInfo) (*godoc.DocInfo, error) {
+		if innerPath == "bad" {
+			panic("malformed AST")
+		}
+		return realDocInfo(p, ctx, innerPath, si, mi)
+	}
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/render",
+		Files: map[string]string{
+			"go.mod":       "module example.com/render",
+			"LICENSE":      testhelper.MITLicense,
+			"bad/bad.go":   "package bad\n\nfunc F() {}",
+			"good/good.go": "package good\n\nfunc F() {}",
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	units := map[string]*internal.Unit{}
+	for _, u := range got.Module.Units {
+		units[u.Path] = u
+	}
+	bad := units["example.com/render/bad"]
+	if bad == nil || !bad.IsPackage() {
+		t.Fatal("bad package missing")
+	}
+	if bad.Documentation != nil {
+		t.Errorf("bad: got documentation %v, want nil", bad.Documentation)
+	}
+	if good := units["example.com/render/good"]; good == nil || len(good.Documentation) == 0 {
+		t.Error("good: got no documentation")
+	}
+	for _, s := range got.PackageVersionStates {
+		if s.PackagePath == "example.com/render/bad" {
+			if want := derrors.ToStatus(derrors.PackageDocumentationRenderPanic); s.Status != want {
+				t.Errorf("bad package status: got %d, want %d", s.Status, want)
+			}
+			if !strings.Contains(s.Error, "malformed AST") {
+				t.Errorf("bad package error %q does not mention the panic", s.Error)
+			}
+		}
+	}
+}
+
+func TestFetchModule_TestFiles(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/tests",
+		Files: map[string]string{
+			"go.mod":               "module example.com/tests",
+			"LICENSE":              testhelper.MITLicense,
+			"tested/a.go":          "package tested\n\nfunc F() int { return 1 }",
+			"tested/a_test.go":     "package tested\n\nimport \"testing\"\n\nfunc TestF(t *testing.T) {}",
+			"tested/b_test.go":     "package tested_test\n\nimport \"testing\"\n\nfunc TestG(t *testing.T) {}",
+			"untested/untested.go": "package untested\n\nfunc F() int { return 1 }",
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := map[string]int{
+		"example.com/tests/tested":   2,
+		"example.com/tests/untested": 0,
+	}
+	for _, u := range got.Module.Units {
+		w, ok := want[u.Path]
+		if !ok {
+			continue
+		}
+		if u.NumTestFiles != w || u.HasTests() != (w > 0) {
+			t.Errorf("%s: got NumTestFiles %d, HasTests %t; want %d, %t", u.Path, u.NumTestFiles, u.HasTests(), w, w > 0)
+		}
+		delete(want, u.Path)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing units: %v", want)
+	}
+}
+
+func TestFetchModule_NestedModule(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/parent",
+		Files: map[string]string{
+			"go.mod":                 "module example.com/parent",
+			"LICENSE":                testhelper.MITLicense,
+			"a/a.go":                 "package a",
+			"nested/go.mod":          "module example.com/parent/nested",
+			"nested/n.go":            "package nested",
+			"nested/sub/sub.go":      "package sub",
+			"notnested/go.mod/x":     "not a go.mod file",
+			"notnested/notnested.go": "package notnested",
+		},
+	}
+	for _, fetcher := range []struct {
+		name  string
+		fetch fetchFunc
+	}{
+		{name: "proxy", fetch: proxyFetcher},
+		{name: "local", fetch: localFetcher},
+	} {
+		t.Run(fetcher.name, func(t *testing.T) {
+			got, _ := fetcher.fetch(t, false, ctx, mod, "")
+			if got.Error != nil {
+				t.Fatal(got.Error)
+			}
+			var paths []string
+			for _, u := range got.Module.Units {
+				paths = append(paths, u.Path)
+			}
+			sort.Strings(paths)
+			want := []string{
+				"example.com/parent",
+				"example.com/parent/a",
+				"example.com/parent/notnested",
+			}
+			if diff := cmp.Diff(want, paths); diff != "" {
+				t.Errorf("unit paths mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFetchModule_DocFormats(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/formats",
+		Files: map[string]string{
+			"go.mod":  "module example.com/formats",
+			"LICENSE": testhelper.MITLicense,
+			"p/p.go":  "// Package p is documented.\npackage p\n\n// F is a function.\nfunc F() {}",
+		},
+	}
+	getDoc := func(ctx context.Context) *internal.Documentation {
+		t.Helper()
+		got, _ := proxyFetcher(t, false, ctx, mod, "")
+		if got.Error != nil {
+			t.Fatal(got.Error)
+		}
+		for _, u := range got.Module.Units {
+			if u.Path != "example.com/formats/p" {
+				continue
+			}
+			if len(u.Documentation) != 1 {
+				t.Fatalf("got %d documentations, want 1", len(u.Documentation))
+			}
+			return u.Documentation[0]
+		}
+		t.Fatal("package example.com/formats/p not found")
+		return nil
+	}
+
+	// The formats are only rendered with the experiment.
+	if doc := getDoc(ctx); doc.HTML != "" || doc.Text != "" {
+		t.Errorf("without experiment: got HTML %q and Text %q, want empty", doc.HTML, doc.Text)
+	}
+	doc := getDoc(experiment.NewContext(ctx, internal.ExperimentStoreDocFormats))
+	if !strings.Contains(doc.HTML, `id="F"`) {
+		t.Errorf("HTML does not document F:\n%s", doc.HTML)
+	}
+	if !strings.Contains(doc.Text, "F is a function.") {
+		t.Errorf("Text does not document F:\n%s", doc.Text)
+	}
+}
+
+func TestFetchModule_Examples(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/examples",
+		Files: map[string]string{
+			"go.mod":  "module example.com/examples",
+			"LICENSE": testhelper.MITLicense,
+			"p/p.go": `
+				package p
+
+				func F() {}
+
+				type T int
+
+				func (T) M() {}
+			`,
+			"p/example_test.go": `
+				package p_test
+
+				// Package example.
+				func Example() {}
+
+				// Package example with a suffix.
+				func Example_second() {
+					// Output: 2
+				}
+
+				func ExampleF() {}
+
+				func ExampleT_M_suffix() {}
+			`,
+			"q/q.go": "package q",
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := map[string]*internal.Examples{
+		"example.com/examples/p": {
+			Package: []*internal.Example{
+				{Doc: "Package example.\n"},
+				{Suffix: "second", Doc: "Package example with a suffix.\n", Output: "2\n"},
+			},
+			Symbol: []*internal.Example{
+				{Symbol: "F"},
+				{Symbol: "T.M", Suffix: "suffix"},
+			},
+		},
+		"example.com/examples/q": nil,
+	}
+	for _, u := range got.Module.Units {
+		w, ok := want[u.Path]
+		if !ok {
+			continue
+		}
+		if diff := cmp.Diff(w, u.Examples); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", u.Path, diff)
+		}
+		delete(want, u.Path)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing units: %v", want)
+	}
+}
+
+func TestFetchModule_BuildErrors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/builderrors",
+		Files: map[string]string{
+			"go.mod":  "module example.com/builderrors\n\nrequire example.com/dep v1.0.0",
+			"LICENSE": testhelper.MITLicense,
+			"ok/ok.go": `
+				// Package ok imports only resolvable packages.
+				package ok
+
+				import (
+					_ "example.com/builderrors/missing/sub"
+					_ "example.com/dep/pkg"
+					_ "fmt"
+				)
+			`,
+			"missing/sub/sub.go": "package sub",
+			"broken/broken.go": `
+				// Package broken has unresolved imports.
+				package broken
+
+				import (
+					_ "example.com/builderrors/nope"
+					_ "example.com/unknown"
+				)
+
+				// F is documented.
+				func F() {}
+			`,
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := map[string][]string{
+		"example.com/builderrors/ok": nil,
+		"example.com/builderrors/broken": {
+			`cannot find package "example.com/builderrors/nope" in module example.com/builderrors`,
+			`no required module provides package "example.com/unknown"`,
+		},
+	}
+	for _, u := range got.Module.Units {
+		w, ok := want[u.Path]
+		if !ok {
+			continue
+		}
+		if diff := cmp.Diff(w, u.BuildErrors); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", u.Path, diff)
+		}
+		if len(u.Documentation) == 0 || u.Documentation[0].Synopsis == "" {
+			t.Errorf("%s: no documentation", u.Path)
+		}
+		delete(want, u.Path)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing units: %v", want)
+	}
+}
+
+func TestFetchModule_ExamplesValid(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/examples",
+		Files: map[string]string{
+			"go.mod":  "module example.com/examples",
+			"LICENSE": testhelper.MITLicense,
+			"valid/valid.go": `
+				package valid
+
+				func F() int { return 1 }
+			`,
+			"valid/example_test.go": `
+				package valid_test
+
+				import (
+					"fmt"
+
+					"example.com/examples/valid"
+				)
+
+				func ExampleF() {
+					n := valid.F()
+					fmt.Println(n)
+					// Output: 1
+				}
+			`,
+			"broken/broken.go": `
+				package broken
+
+				func F() int { return 1 }
+			`,
+			"broken/example_test.go": `
+				package broken_test
+
+				import (
+					"fmt"
+
+					"example.com/examples/broken"
+				)
+
+				func ExampleF() {
+					fmt.Println(broken.Renamed())
+					// Output: 1
+				}
+			`,
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := map[string]bool{
+		"example.com/examples/valid":  true,
+		"example.com/examples/broken": false,
+	}
+	for _, u := range got.Module.Units {
+		w, ok := want[u.Path]
+		if !ok {
+			continue
+		}
+		if u.ExamplesValid != w {
+			t.Errorf("%s: got ExamplesValid %t, want %t", u.Path, u.ExamplesValid, w)
+		}
+		delete(want, u.Path)
+	}
+	if len(want) > 0 {
+		t.Errorf("missing units: %v", want)
+	}
+}
+
+func TestFetchModule_GoMod(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const goMod = "module example.com/gomod\n\ngo 1.19\n"
+	mod := &proxytest.Module{
+		ModulePath: "example.com/gomod",
+		Files: map[string]string{
+			"go.mod":  goMod,
+			"LICENSE": testhelper.MITLicense,
+			"p/p.go":  "package p",
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	if string(got.Module.GoMod) != goMod {
+		t.Errorf("got go.mod %q, want %q", got.Module.GoMod, goMod)
+	}
+}
+
+func TestFetchModule_Keywords(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/mux",
+		Files: map[string]string{
+			"go.mod":    "module example.com/mux",
+			"LICENSE":   testhelper.MITLicense,
+			"README.md": "# Mux\n\nMux is a fast HTTP router for Go. The router matches HTTP requests.\n",
+			"mux.go":    "// Package mux implements an HTTP router.\npackage mux",
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := []string{"http", "mux", "router"}
+	if kws := got.Module.Keywords; len(kws) < len(want) || !cmp.Equal(kws[:len(want)], want) {
+		t.Errorf("got keywords %q, want them to start with %q", kws, want)
+	}
+}
+
+func TestFetchModule_NoPackages(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "no.go/files",
+		Files: map[string]string{
+			"go.mod":    "module no.go/files",
+			"LICENSE":   testhelper.MITLicense,
+			"README.md": "This module has no packages.",
+		},
+	}
+	for _, fetcher := range []struct {
+		name  string
+		fetch fetchFunc
+	}{
+		{name: "proxy", fetch: proxyFetcher},
+		{name: "local", fetch: localFetcher},
+	} {
+		t.Run(fetcher.name, func(t *testing.T) {
+			got, _ := fetcher.fetch(t, false, ctx, mod, "")
+			if got.Error != nil {
+				t.Fatal(got.Error)
+			}
+			m := got.Module
+			if m.HasPackages {
+				t.Error("got HasPackages = true, want false")
+			}
+			if !got.HasGoMod {
+				t.Error("got HasGoMod = false, want true")
+			}
+			if len(m.Licenses) != 1 {
+				t.Errorf("got %d licenses, want 1", len(m.Licenses))
+			}
+			if len(m.Units) != 1 || m.Units[0].Path != mod.ModulePath || m.Units[0].IsPackage() {
+				t.Fatalf("got units %v, want only the module root directory", m.Units)
+			}
+			if r := m.Units[0].Readme; r == nil || r.Contents != "This module has no packages." {
+				t.Errorf("got README %+v, want the module's README", r)
+			}
+		})
+	}
+}
+
+func TestFetchModule_Command(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/cmds",
+		Files: map[string]string{
+			"go.mod":  "module example.com/cmds",
+			"LICENSE": testhelper.MITLicense,
+			"cmd/hello/main.go": `
+				// Hello prints a greeting. It has no exported symbols.
+				//
+				// Usage:
+				//
+				//	hello [-name name]
+				package main
+
+				import "fmt"
+
+				func main() { fmt.Println("hello") }
+			`,
+			"lib/lib.go": `
+				// Package lib is a library.
+				package lib
+
+				// F is a function.
+				func F() {}
+			`,
+		},
+	}
+	for _, fetcher := range []struct {
+		name  string
+		fetch fetchFunc
+	}{
+		{name: "proxy", fetch: proxyFetcher},
+		{name: "local", fetch: localFetcher},
+	} {
+		t.Run(fetcher.name, func(t *testing.T) {
+			got, _ := fetcher.fetch(t, false, ctx, mod, "")
+			if got.Error != nil {
+				t.Fatal(got.Error)
+			}
+			want := map[string]struct {
+				isCommand bool
+				synopsis  string
+				usage     string
+			}{
+				"example.com/cmds/cmd/hello": {true, "Hello prints a greeting.", "hello [-name name]"},
+				"example.com/cmds/lib":       {false, "Package lib is a library.", ""},
+			}
+			for _, u := range got.Module.Units {
+				w, ok := want[u.Path]
+				if !ok {
+					continue
+				}
+				if g := u.IsCommand(); g != w.isCommand {
+					t.Errorf("%s: IsCommand() = %t, want %t", u.Path, g, w.isCommand)
+				}
+				if len(u.Documentation) == 0 {
+					t.Fatalf("%s: no documentation", u.Path)
+				}
+				if g := u.Documentation[0].Synopsis; g != w.synopsis {
+					t.Errorf("%s: synopsis = %q, want %q", u.Path, g, w.synopsis)
+				}
+				if g := u.Documentation[0].Usage; g != w.usage {
+					t.Errorf("%s: usage = %q, want %q", u.Path, g, w.usage)
+				}
+				delete(want, u.Path)
+			}
+			if len(want) > 0 {
+				t.Errorf("missing units: %v", want)
+			}
+		})
+	}
+}
+
+func TestFetchModule_PackageDeprecated(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/deprecated",
+		Files: map[string]string{
+			"go.mod":  "module example.com/deprecated",
+			"LICENSE": testhelper.MITLicense,
+			"old/old.go": `
+				// Deprecated: Use package example.com/deprecated/new instead.
+				package old
+			`,
+			"new/new.go": `
+				// Package new replaces package old.
+				package new
+			`,
+		},
+	}
+	for _, fetcher := range []struct {
+		name  string
+		fetch fetchFunc
+	}{
+		{name: "proxy", fetch: proxyFetcher},
+		{name: "local", fetch: localFetcher},
+	} {
+		t.Run(fetcher.name, func(t *testing.T) {
+			got, _ := fetcher.fetch(t, false, ctx, mod, "")
+			if got.Error != nil {
+				t.Fatal(got.Error)
+			}
+			for _, u := range got.Module.Units {
+				var (
+					wantDep     bool
+					wantComment string
+				)
+				switch u.Path {
+				case "example.com/deprecated/old":
+					wantDep, wantComment = true, "Use package example.com/deprecated/new instead."
+				case "example.com/deprecated/new":
+				default:
+					continue
+				}
+				if u.PackageDeprecated != wantDep || u.PackageDeprecationComment != wantComment {
+					t.Errorf("%s: got %t, %q; want %t, %q", u.Path,
+						u.PackageDeprecated, u.PackageDeprecationComment, wantDep, wantComment)
+				}
+				// Package deprecation doesn't affect the module.
+				if u.Deprecated {
+					t.Errorf("%s: module is deprecated", u.Path)
+				}
+			}
+		})
+	}
+}
+
+func TestFetchModule_DeprecatedSymbols(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/depsym",
+		Files: map[string]string{
+			"go.mod":  "module example.com/depsym",
+			"LICENSE": testhelper.MITLicense,
+			"p.go": `
+				// Package p has a deprecated function.
+				package p
+
+				// Old does things.
+				//
+				// Deprecated: Use New instead.
+				func Old() {}
+
+				// New does things better.
+				func New() {}
+
+				type T int
+
+				// Deprecated: Do not use.
+				func (T) M() {}
+			`,
+		},
+	}
+	type flag struct {
+		Deprecated bool
+		Comment    string
+	}
+	want := map[string]flag{
+		"Old": {true, "Use New instead."},
+		"New": {},
+		"T":   {},
+		"T.M": {true, "Do not use."},
+	}
+	for _, fetcher := range []struct {
+		name  string
+		fetch fetchFunc
+	}{
+		{name: "proxy", fetch: proxyFetcher},
+		{name: "local", fetch: localFetcher},
+	} {
+		t.Run(fetcher.name, func(t *testing.T) {
+			got, _ := fetcher.fetch(t, false, ctx, mod, "")
+			if got.Error != nil {
+				t.Fatal(got.Error)
+			}
+			var u *internal.Unit
+			for _, gu := range got.Module.Units {
+				if gu.Path == "example.com/depsym" {
+					u = gu
+				}
+			}
+			if u == nil || len(u.Documentation) == 0 {
+				t.Fatal("no documentation for example.com/depsym")
+			}
+			gotFlags := map[string]flag{}
+			for _, s := range u.Documentation[0].API {
+				gotFlags[s.Name] = flag{s.Deprecated, s.DeprecationComment}
+				for _, c := range s.Children {
+					gotFlags[c.Name] = flag{c.Deprecated, c.DeprecationComment}
+				}
+			}
+			if diff := cmp.Diff(want, gotFlags); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFetchModule_RedistributableLicenseTypes(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// The Fair license is not redistributable by default.
+	const fairLicense = `Fair License
+
+Usage of the works is permitted provided that this instrument is retained with
+the works, so that any entity that uses the works is notified of this
+instrument.
+
+DISCLAIMER: THE WORKS ARE WITHOUT WARRANTY.
+`
+	mod := &proxytest.Module{
+		ModulePath: "example.com/fair",
+		Files: map[string]string{
+			"go.mod":  "module example.com/fair",
+			"LICENSE": fairLicense,
+			"p.go":    "// Package p is fair.\npackage p\n",
+		},
+	}
+	fetch := func() *FetchResult {
+		t.Helper()
+		got, _ := proxyFetcher(t, false, ctx, mod, "")
+		if got.Error != nil {
+			t.Fatal(got.Error)
+		}
+		return got
+	}
+	if fetch().Module.IsRedistributable {
+		t.Fatal("module is redistributable with the default license types")
+	}
+
+	defer licenses.SetRedistributableLicenseTypes(nil)
+	licenses.SetRedistributableLicenseTypes(append(licenses.DefaultRedistributableLicenseTypes(), "Fair"))
+	got := fetch()
+	if !got.Module.IsRedistributable {
+		t.Error("module is not redistributable with Fair added to the license types")
+	}
+	for _, u := range got.Module.Units {
+		if !u.IsRedistributable {
+			t.Errorf("%s is not redistributable", u.Path)
+		}
+		if u.IsPackage() && len(u.Documentation) == 0 {
+			t.Errorf("%s has no documentation", u.Path)
+		}
+	}
+}
+
+func TestFetchModule_Stdlib(t *testing.T) {
+	defer stdlib.WithTestData()()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	got, _ := proxyFetcher(t, false, ctx, &proxytest.Module{ModulePath: stdlib.ModulePath, Version: "v1.12.5"}, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	var errorsUnit *internal.Unit
+	for _, u := range got.Module.Units {
+		if u.Path == "errors" {
+			errorsUnit = u
+		}
+	}
+	if errorsUnit == nil {
+		t.Fatal("no errors package")
+	}
+	if got, want := errorsUnit.Name, "errors"; got != want {
+		t.Errorf("name: got %q, want %q", got, want)
+	}
+	if len(errorsUnit.Documentation) == 0 || errorsUnit.Documentation[0].Synopsis == "" {
+		t.Errorf("missing documentation: %+v", errorsUnit.Documentation)
+	}
+	file := path.Join(internal.Suffix(errorsUnit.Path, stdlib.ModulePath), "errors.go")
+	if got, want := errorsUnit.SourceInfo.FileURL(file), "https://cs.opensource.google/go/go/+/go1.12.5:src/errors/errors.go"; got != want {
+		t.Errorf("FileURL: got %q, want %q", got, want)
+	}
+}
+
 func TestFetchModule_Errors(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
 	defer cancel()
@@ -204,13 +1103,6 @@ func TestFetchModule_Errors(t *testing.T) {
 			wantGoModPath: "emp.ty/module",
 			wantHasGoMod:  false,
 		},
-		{
-			name:          "go.mod but no go files",
-			mod:           moduleNoGo,
-			wantErr:       derrors.BadModule,
-			wantGoModPath: "no.go/files",
-			wantHasGoMod:  true,
-		},
 	} {
 		for _, fetcher := range []struct {
 			name  string
@@ -288,3 +1180,25 @@ func TestExtractDeprecatedComment(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessGoModFileRequirements(t *testing.T) {
+	goMod := `
+		module example.com/m
+
+		require (
+			example.com/a v1.0.0
+			example.com/b v0.2.0 // indirect
+		)
+	`
+	mod := &internal.Module{}
+	if err := processGoModFile([]byte(goMod), mod); err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.Requirement{
+		{ModulePath: "example.com/a", Version: "v1.0.0"},
+		{ModulePath: "example.com/b", Version: "v0.2.0", Indirect: true},
+	}
+	if diff := cmp.Diff(want, mod.Requirements); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -36,8 +36,18 @@ var singleUnits = []*internal.Unit{
 	},
 	{
 		UnitMeta: internal.UnitMeta{
-			Name: "pkg",
-			Path: "example.com/single/pkg",
+			Name:         "pkg",
+			Path:         "example.com/single/pkg",
+			NumTestFiles: 1,
+			DocStats:     internal.DocStats{NumExported: 5, NumDocumented: 3},
+		},
+		Examples: &internal.Examples{
+			Package: []*internal.Example{
+				{Doc: "Example for the package.\n", Output: "hello\n"},
+			},
+			Symbol: []*internal.Example{
+				{Symbol: "F", Doc: "A function example.\n"},
+			},
 		},
 		Documentation: []*internal.Documentation{{
 			GOOS:     internal.All,
@@ -105,7 +115,10 @@ var moduleOnePackage = &testModule{
 				SourceInfo:        source.NewGitHubInfo("https://example.com/single", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
-			Units: singleUnits,
+			GoMod:       []byte("module example.com/single"),
+			Keywords:    []string{"module", "pkg", "readme", "sample", "test"},
+			HasPackages: true,
+			Units:       singleUnits,
 		},
 	},
 }
@@ -125,11 +138,24 @@ var moduleNoGoMod = &testModule{
 				SourceInfo:        source.NewGitHubInfo("https://example.com/nogo", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module example.com/nogo\n\ngo 1.12"),
+			Keywords:    []string{"basic", "module", "readme", "sample", "test"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "basic",
-						Path: "example.com/nogo",
+						Name:         "basic",
+						Path:         "example.com/nogo",
+						NumTestFiles: 1,
+						DocStats:     internal.DocStats{NumExported: 5, NumDocumented: 3},
+					},
+					Examples: &internal.Examples{
+						Package: []*internal.Example{
+							{Doc: "Example for the package.\n", Output: "hello\n"},
+						},
+						Symbol: []*internal.Example{
+							{Symbol: "F", Doc: "A function example.\n"},
+						},
 					},
 					Readme: &internal.Readme{
 						Filepath: "README.md",
@@ -165,6 +191,9 @@ var moduleMultiPackage = &testModule{
 				SourceInfo:        source.NewGitHubInfo("https://example.com/multi", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module example.com/multi\n\ngo 1.13"),
+			Keywords:    []string{"bar", "file", "foo", "readme", "testing"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -177,8 +206,10 @@ var moduleMultiPackage = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "bar",
-						Path: "example.com/multi/bar",
+						Name:          "bar",
+						Path:          "example.com/multi/bar",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1, NumDocumented: 1},
 					},
 					Readme: &internal.Readme{
 						Filepath: "bar/README",
@@ -205,8 +236,10 @@ var moduleMultiPackage = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "foo",
-						Path: "example.com/multi/foo",
+						Name:          "foo",
+						Path:          "example.com/multi/foo",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1, NumDocumented: 1},
 					},
 					Documentation: []*internal.Documentation{{
 						GOOS:     internal.All,
@@ -242,14 +275,6 @@ var moduleEmpty = &testModule{
 	fr: &FetchResult{Module: &internal.Module{}},
 }
 
-var moduleNoGo = &testModule{
-	mod: &proxytest.Module{
-		ModulePath: "no.go/files",
-		Files:      map[string]string{"go.mod": "module no.go/files"},
-	},
-	fr: &FetchResult{Module: &internal.Module{}},
-}
-
 var moduleBadPackages = &testModule{
 	mod: &proxytest.Module{
 		ModulePath: "bad.mod/module",
@@ -284,6 +309,9 @@ var moduleBadPackages = &testModule{
 				ModulePath:        "bad.mod/module",
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module bad.mod/module\n\ngo 1.12"),
+			Keywords:    []string{"bad", "good", "inside", "module"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -292,8 +320,10 @@ var moduleBadPackages = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "good",
-						Path: "bad.mod/module/good",
+						Name:          "good",
+						Path:          "bad.mod/module/good",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1, NumDocumented: 1},
 					},
 					Documentation: []*internal.Documentation{{
 						GOOS:     internal.All,
@@ -352,6 +382,9 @@ var moduleBuildConstraints = &testModule{
 				SourceInfo:        source.NewGitHubInfo("https://example.com/build-constraints", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module example.com/build-constraints"),
+			Keywords:    []string{"cpu", "detection", "feature", "implements", "library", "processor", "standard"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -360,8 +393,10 @@ var moduleBuildConstraints = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "cpu",
-						Path: "example.com/build-constraints/cpu",
+						Name:          "cpu",
+						Path:          "example.com/build-constraints/cpu",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -464,6 +499,8 @@ var moduleBadBuildContext = &testModule{
 				HasGoMod:          false,
 				IsRedistributable: false,
 			},
+			GoMod:       []byte("module github.com/bad-context\n\ngo 1.12"),
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -472,8 +509,9 @@ var moduleBadBuildContext = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "pkg",
-						Path: "github.com/bad-context/pkg",
+						Name:          "pkg",
+						Path:          "github.com/bad-context/pkg",
+						ExamplesValid: true,
 					},
 					Documentation: []*internal.Documentation{{
 						GOOS:   "linux",
@@ -497,6 +535,9 @@ var moduleNonRedist = &testModule{
 				SourceInfo:        source.NewGitHubInfo("https://example.com/nonredist", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module example.com/nonredist\n\ngo 1.13"),
+			Keywords:    []string{"bar", "baz", "file", "readme", "testing"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -509,8 +550,10 @@ var moduleNonRedist = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "bar",
-						Path: "example.com/nonredist/bar",
+						Name:          "bar",
+						Path:          "example.com/nonredist/bar",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1, NumDocumented: 1},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -533,8 +576,10 @@ var moduleNonRedist = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "baz",
-						Path: "example.com/nonredist/bar/baz",
+						Name:          "baz",
+						Path:          "example.com/nonredist/bar/baz",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1, NumDocumented: 1},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -557,8 +602,10 @@ var moduleNonRedist = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "unk",
-						Path: "example.com/nonredist/unk",
+						Name:          "unk",
+						Path:          "example.com/nonredist/unk",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1, NumDocumented: 1},
 					},
 					Readme: &internal.Readme{
 						Filepath: "unk/README.md",
@@ -585,6 +632,13 @@ var moduleNonRedist = &testModule{
 					Imports:       []string{"example.com/nonredist/bar", "fmt"},
 				},
 			},
+			LicenseConflict: true,
+		},
+		ValidationReport: &ValidationReport{
+			Entries: []*ValidationEntry{{
+				Kind:    LicenseConflict,
+				Message: "redistributable licenses (LICENSE, bar/LICENSE, bar/baz/COPYING) conflict with non-redistributable licenses (unk/LICENSE.md)",
+			}},
 		},
 	},
 	docStrings: map[string][]string{
@@ -608,6 +662,8 @@ var moduleBadImportPath = &testModule{
 			ModuleInfo: internal.ModuleInfo{
 				ModulePath: "bad.import.path.com",
 			},
+			GoMod:       []byte("module bad.import.path.com\n\ngo 1.12"),
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -626,8 +682,9 @@ var moduleBadImportPath = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "foo",
-						Path: "bad.import.path.com/good/import/path",
+						Name:          "foo",
+						Path:          "bad.import.path.com/good/import/path",
+						ExamplesValid: true,
 					},
 					Documentation: []*internal.Documentation{{GOOS: internal.All, GOARCH: internal.All}},
 					BuildContexts: []internal.BuildContext{internal.BuildContextAll},
@@ -679,6 +736,9 @@ var moduleDocTest = &testModule{
 				HasGoMod:          false,
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module doc.test\n\ngo 1.12"),
+			Keywords:    []string{"permalink", "documentation", "feature", "heading", "rendering", "testing"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -687,8 +747,9 @@ var moduleDocTest = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "permalink",
-						Path: "doc.test/permalink",
+						Name:          "permalink",
+						Path:          "doc.test/permalink",
+						ExamplesValid: true,
 					},
 					Documentation: []*internal.Documentation{{
 						GOOS:     internal.All,
@@ -727,11 +788,15 @@ var moduleDocTooLarge = &testModule{
 				HasGoMod:          false,
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module bigdoc.test\n\ngo 1.12"),
+			Keywords:    []string{"big", "documentation"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "bigdoc",
-						Path: "bigdoc.test",
+						Name:          "bigdoc",
+						Path:          "bigdoc.test",
+						ExamplesValid: true,
 					},
 					Documentation: []*internal.Documentation{{
 						GOOS:     internal.All,
@@ -777,6 +842,9 @@ var moduleWasm = &testModule{
 				SourceInfo:        source.NewGitHubInfo("https://github.com/my/module", "js", "js/v1.0.0"),
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module github.com/my/module/js\n\ngo 1.12"),
+			Keywords:    []string{"only", "readme", "wasm", "works"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -789,8 +857,10 @@ var moduleWasm = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "js",
-						Path: "github.com/my/module/js/js",
+						Name:          "js",
+						Path:          "github.com/my/module/js/js",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -855,10 +925,15 @@ var moduleStdMaster = &testModule{
 				SourceInfo:        source.NewStdlibInfo("master"),
 				IsRedistributable: true,
 			},
+			Keywords:    []string{"errors", "functions", "implements", "manipulate"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
 						Path:              "errors",
+						NumTestFiles:      1,
+						ExamplesValid:     true,
+						DocStats:          internal.DocStats{NumExported: 1, NumDocumented: 1},
 						Name:              "errors",
 						IsRedistributable: true,
 						ModuleInfo: internal.ModuleInfo{
@@ -867,6 +942,12 @@ var moduleStdMaster = &testModule{
 							IsRedistributable: true,
 						},
 					},
+					Examples: &internal.Examples{
+						Symbol: []*internal.Example{
+							{Symbol: "New", Output: "emit macho dwarf: elf header corrupted\n"},
+							{Symbol: "New", Suffix: "errorf", Doc: "The fmt package's Errorf function lets us use the package's formatting\nfeatures to create descriptive error messages.\n", Output: "user \"bimmler\" (id 17) not found\n"},
+						},
+					},
 					Documentation: []*internal.Documentation{
 						{
 							GOOS:     internal.All,
@@ -930,6 +1011,8 @@ var moduleStd = &testModule{
 				SourceInfo:        source.NewStdlibInfo("v1.12.5"),
 				IsRedistributable: true,
 			},
+			Keywords:    []string{"implements", "context", "errors", "flag", "json", "across", "api", "between", "boundaries", "builtin"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -939,8 +1022,10 @@ var moduleStd = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "builtin",
-						Path: "builtin",
+						Name:          "builtin",
+						Path:          "builtin",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 5, NumDocumented: 5},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -1321,8 +1406,9 @@ var moduleStd = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "main",
-						Path: "cmd/pprof",
+						Name:          "main",
+						Path:          "cmd/pprof",
+						ExamplesValid: true,
 					},
 					Readme: &internal.Readme{
 						Filepath: "cmd/pprof/README",
@@ -1336,21 +1422,25 @@ var moduleStd = &testModule{
 							GOOS:     "linux",
 							GOARCH:   "amd64",
 							Synopsis: "Pprof interprets and displays profiles of Go programs.",
+							Usage:    "go tool pprof binary profile",
 						},
 						{
 							GOOS:     "windows",
 							GOARCH:   "amd64",
 							Synopsis: "Pprof interprets and displays profiles of Go programs.",
+							Usage:    "go tool pprof binary profile",
 						},
 						{
 							GOOS:     "darwin",
 							GOARCH:   "amd64",
 							Synopsis: "Pprof interprets and displays profiles of Go programs.",
+							Usage:    "go tool pprof binary profile",
 						},
 						{
 							GOOS:     "js",
 							GOARCH:   "wasm",
 							Synopsis: "Pprof interprets and displays profiles of Go programs.",
+							Usage:    "go tool pprof binary profile",
 						},
 					},
 					BuildContexts: []internal.BuildContext{
@@ -1378,8 +1468,19 @@ var moduleStd = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "context",
-						Path: "context",
+						Name:          "context",
+						Path:          "context",
+						NumTestFiles:  5,
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 10, NumDocumented: 10},
+					},
+					Examples: &internal.Examples{
+						Symbol: []*internal.Example{
+							{Symbol: "WithCancel", Doc: "This example demonstrates the use of a cancelable context to prevent a\ngoroutine leak. By the end of the example function, the goroutine started\nby gen will return without leaking.\n", Output: "1\n2\n3\n4\n5\n"},
+							{Symbol: "WithDeadline", Doc: "This example passes a context with an arbitrary deadline to tell a blocking\nfunction that it should abandon its work as soon as it gets to it.\n", Output: "context deadline exceeded\n"},
+							{Symbol: "WithTimeout", Doc: "This example passes a context with a timeout to tell a blocking function that\nit should abandon its work after the timeout elapses.\n", Output: "context deadline exceeded\n"},
+							{Symbol: "WithValue", Doc: "This example demonstrates how a value can be passed to the context\nand also how to retrieve it if it exists.\n", Output: "found value: Go\nkey not found: color\n"},
+						},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -1507,8 +1608,29 @@ var moduleStd = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "json",
-						Path: "encoding/json",
+						Name:          "json",
+						Path:          "encoding/json",
+						NumTestFiles:  12,
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 48, NumDocumented: 38},
+					},
+					Examples: &internal.Examples{
+						Package: []*internal.Example{
+							{Suffix: "customMarshalJSON", Output: "Zoo Census:\n* Gophers: 3\n* Zebras:  2\n* Unknown: 3\n"},
+							{Suffix: "textMarshalJSON", Output: "Inventory Counts:\n* Small:        3\n* Large:        2\n* Unrecognized: 3\n"},
+						},
+						Symbol: []*internal.Example{
+							{Symbol: "Indent", Output: "[\n=\t{\n=\t\t\"Name\": \"Diamond Fork\",\n=\t\t\"Number\": 29\n=\t},\n=\t{\n=\t\t\"Name\": \"Sheep Creek\",\n=\t\t\"Number\": 51\n=\t}\n=]\n"},
+							{Symbol: "Marshal", Output: "{\"ID\":1,\"Name\":\"Reds\",\"Colors\":[\"Crimson\",\"Red\",\"Ruby\",\"Maroon\"]}\n"},
+							{Symbol: "MarshalIndent", Output: "{\n<prefix><indent>\"a\": 1,\n<prefix><indent>\"b\": 2\n<prefix>}\n"},
+							{Symbol: "Unmarshal", Output: "[{Name:Platypus Order:Monotremata} {Name:Quoll Order:Dasyuromorphia}]\n"},
+							{Symbol: "Valid", Output: "true false\n"},
+							{Symbol: "Decoder", Doc: "This example uses a Decoder to decode a stream of distinct JSON values.\n", Output: "Ed: Knock knock.\nSam: Who's there?\nEd: Go fmt.\nSam: Go fmt who?\nEd: Go fmt yourself!\n"},
+							{Symbol: "Decoder.Decode", Suffix: "stream", Doc: "This example uses a Decoder to decode a streaming array of JSON objects.\n", Output: "json.Delim: [\nEd: Knock knock.\nSam: Who's there?\nEd: Go fmt.\nSam: Go fmt who?\nEd: Go fmt yourself!\njson.Delim: ]\n"},
+							{Symbol: "Decoder.Token", Doc: "This example uses a Decoder to decode a stream of distinct JSON values.\n", Output: "json.Delim: { (more)\nstring: Message (more)\nstring: Hello (more)\nstring: Array (more)\njson.Delim: [ (more)\nfloat64: 1 (more)\nfloat64: 2 (more)\nfloat64: 3\njson.Delim: ] (more)\nstring: Null (more)\n<nil>: <nil> (more)\nstring: Number (more)\nfloat64: 1.234\njson.Delim: }\n"},
+							{Symbol: "RawMessage", Suffix: "marshal", Doc: "This example uses RawMessage to use a precomputed JSON during marshal.\n", Output: "{\n\t\"header\": {\n\t\t\"precomputed\": true\n\t},\n\t\"body\": \"Hello Gophers!\"\n}\n"},
+							{Symbol: "RawMessage", Suffix: "unmarshal", Doc: "This example uses RawMessage to delay parsing part of a JSON message.\n", Output: "YCbCr &{255 0 -10}\nRGB &{98 218 255}\n"},
+						},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -1688,10 +1810,12 @@ var moduleStd = &testModule{
 								},
 								{
 									SymbolMeta: internal.SymbolMeta{
-										Name:     "InvalidUTF8Error",
-										Synopsis: "type InvalidUTF8Error struct{ ... }",
-										Section:  "Types",
-										Kind:     "Type",
+										Name:               "InvalidUTF8Error",
+										Synopsis:           "type InvalidUTF8Error struct{ ... }",
+										Section:            "Types",
+										Kind:               "Type",
+										Deprecated:         true,
+										DeprecationComment: "No longer used; kept for compatibility.",
 									},
 									Children: []*internal.SymbolMeta{
 										{
@@ -1871,10 +1995,12 @@ var moduleStd = &testModule{
 								},
 								{
 									SymbolMeta: internal.SymbolMeta{
-										Name:     "UnmarshalFieldError",
-										Synopsis: "type UnmarshalFieldError struct{ ... }",
-										Section:  "Types",
-										Kind:     "Type",
+										Name:               "UnmarshalFieldError",
+										Synopsis:           "type UnmarshalFieldError struct{ ... }",
+										Section:            "Types",
+										Kind:               "Type",
+										Deprecated:         true,
+										DeprecationComment: "No longer used; kept for compatibility.",
 									},
 									Children: []*internal.SymbolMeta{
 										{
@@ -2055,8 +2181,20 @@ var moduleStd = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "errors",
-						Path: "errors",
+						Name:          "errors",
+						Path:          "errors",
+						NumTestFiles:  2,
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1, NumDocumented: 1},
+					},
+					Examples: &internal.Examples{
+						Package: []*internal.Example{
+							{Output: "1989-03-15 22:30:00 +0000 UTC: the file system has gone away\n"},
+						},
+						Symbol: []*internal.Example{
+							{Symbol: "New", Output: "emit macho dwarf: elf header corrupted\n"},
+							{Symbol: "New", Suffix: "errorf", Doc: "The fmt package's Errorf function lets us use the package's formatting\nfeatures to create descriptive error messages.\n", Output: "user \"bimmler\" (id 17) not found\n"},
+						},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -2079,8 +2217,19 @@ var moduleStd = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "flag",
-						Path: "flag",
+						Name:          "flag",
+						Path:          "flag",
+						NumTestFiles:  4,
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 74, NumDocumented: 74},
+					},
+					Examples: &internal.Examples{
+						Package: []*internal.Example{
+							{},
+						},
+						Symbol: []*internal.Example{
+							{Symbol: "Value", Output: "{scheme: \"https\", host: \"golang.org\", path: \"/pkg/flag/\"}\n"},
+						},
 					},
 					Imports: []string{"errors", "fmt", "io", "os", "reflect", "sort", "strconv", "strings", "time"},
 					Documentation: []*internal.Documentation{
@@ -2735,6 +2884,8 @@ var moduleMaster = &testModule{
 				Version:    "v0.0.0-20200706064627-355bc3f705ed",
 				SourceInfo: source.NewGitHubInfo("https://github.com/my/module", "", "355bc3f705ed"),
 			},
+			GoMod:       []byte("module github.com/my/module\n\ngo 1.12"),
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -2743,8 +2894,10 @@ var moduleMaster = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "foo",
-						Path: "github.com/my/module/foo",
+						Name:          "foo",
+						Path:          "github.com/my/module/foo",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -2786,6 +2939,8 @@ var moduleLatest = &testModule{
 				Version:    "v1.2.4",
 				SourceInfo: source.NewGitHubInfo("https://github.com/my/module", "", "v1.2.4"),
 			},
+			GoMod:       []byte("module github.com/my/module\n\ngo 1.12"),
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
@@ -2794,8 +2949,10 @@ var moduleLatest = &testModule{
 				},
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "foo",
-						Path: "github.com/my/module/foo",
+						Name:          "foo",
+						Path:          "github.com/my/module/foo",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 1},
 					},
 					Documentation: []*internal.Documentation{{
 						GOOS:     internal.All,
@@ -2830,11 +2987,16 @@ var moduleGenerics = &testModule{
 				SourceInfo:        source.NewGitHubInfo("https://example.com/generics", "", "v1.0.0"),
 				IsRedistributable: true,
 			},
+			GoMod:       []byte("module example.com/generics\n\ngo 1.18"),
+			Keywords:    []string{"generics"},
+			HasPackages: true,
 			Units: []*internal.Unit{
 				{
 					UnitMeta: internal.UnitMeta{
-						Name: "generics",
-						Path: "example.com/generics",
+						Name:          "generics",
+						Path:          "example.com/generics",
+						ExamplesValid: true,
+						DocStats:      internal.DocStats{NumExported: 2},
 					},
 					Documentation: []*internal.Documentation{
 						{
@@ -2892,7 +3054,7 @@ var moduleGenerics = &testModule{
 // The fetch result's documentation HTML is treated as a set
 // of substrings that should appear in the generated documentation.
 // The substrings are separated by a '~' character.
-func moduleWithExamples(path string, api []*internal.Symbol, source, test string, docSubstrings ...string) *testModule {
+func moduleWithExamples(path string, api []*internal.Symbol, docStats internal.DocStats, examples *internal.Examples, source, test string, docSubstrings ...string) *testModule {
 	return &testModule{
 		mod: &proxytest.Module{
 			ModulePath: path,
@@ -2916,6 +3078,9 @@ package example_test
 					HasGoMod:          false,
 					IsRedistributable: true,
 				},
+				GoMod:       []byte("module " + path + "\n\ngo 1.12"),
+				Keywords:    []string{"contains", "example", "examples"},
+				HasPackages: true,
 				Units: []*internal.Unit{
 					{
 						UnitMeta: internal.UnitMeta{
@@ -2924,9 +3089,13 @@ package example_test
 					},
 					{
 						UnitMeta: internal.UnitMeta{
-							Name: "example",
-							Path: path + "/example",
+							Name:          "example",
+							Path:          path + "/example",
+							DocStats:      docStats,
+							ExamplesValid: true,
+							NumTestFiles:  1,
 						},
+						Examples: examples,
 						Documentation: []*internal.Documentation{{
 							GOOS:     internal.All,
 							GOARCH:   internal.All,
@@ -2946,6 +3115,12 @@ package example_test
 
 var modulePackageExample = moduleWithExamples("package.example",
 	nil,
+	internal.DocStats{},
+	&internal.Examples{
+		Package: []*internal.Example{
+			{Doc: "Example for the package.\n", Output: "hello\n"},
+		},
+	},
 	``,
 	`import "fmt"
 
@@ -2967,6 +3142,12 @@ var moduleFuncExample = moduleWithExamples("func.example",
 			},
 		},
 	},
+	internal.DocStats{NumExported: 1},
+	&internal.Examples{
+		Symbol: []*internal.Example{
+			{Symbol: "F", Doc: "Example for the function.\n"},
+		},
+	},
 	`func F() {}
 `, `import "func.example/example"
 
@@ -2987,6 +3168,12 @@ var moduleTypeExample = moduleWithExamples("type.example",
 			},
 		},
 	},
+	internal.DocStats{NumExported: 1},
+	&internal.Examples{
+		Symbol: []*internal.Example{
+			{Symbol: "T", Doc: "Example for the type.\n"},
+		},
+	},
 
 	`type T struct{}
 `, `import "type.example/example"
@@ -3017,6 +3204,12 @@ var moduleMethodExample = moduleWithExamples("method.example",
 			},
 		},
 	},
+	internal.DocStats{NumExported: 2},
+	&internal.Examples{
+		Symbol: []*internal.Example{
+			{Symbol: "T.M", Doc: "Example for the method.\n"},
+		},
+	},
 	`type T struct {}
 
 func (*T) M() {}
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/mod/modfile"
@@ -81,6 +82,13 @@ type VolatileModuleGetter interface {
 	HasChanged(context.Context, internal.ModuleInfo) (bool, error)
 }
 
+// CommitModuleGetter is an additional interface that may be implemented by
+// ModuleGetters that know the commit a module version was built from.
+type CommitModuleGetter interface {
+	// Commit returns information about the commit of the module version.
+	Commit(ctx context.Context, path, version string) (*internal.Commit, error)
+}
+
 type proxyModuleGetter struct {
 	prox *proxy.Client
 	src  *source.Client
@@ -115,6 +123,20 @@ func (g *proxyModuleGetter) SourceInfo(ctx context.Context, path, version string
 	return source.ModuleInfo(ctx, g.src, path, version)
 }
 
+// Commit returns the hash of the commit of the module version, from the
+// origin reported by the proxy. It returns nil if the proxy doesn't report
+// an origin. The proxy doesn't report authors or committers.
+func (g *proxyModuleGetter) Commit(ctx context.Context, path, version string) (*internal.Commit, error) {
+	info, err := g.prox.Info(ctx, path, version)
+	if err != nil {
+		return nil, err
+	}
+	if info.Origin == nil || info.Origin.Hash == "" {
+		return nil, nil
+	}
+	return &internal.Commit{Hash: info.Origin.Hash}, nil
+}
+
 // SourceFS is unimplemented for modules served from the proxy, because we
 // link directly to the module's repo.
 func (g *proxyModuleGetter) SourceFS() (string, fs.FS) {
@@ -125,6 +147,93 @@ func (g *proxyModuleGetter) String() string {
 	return "Proxy"
 }
 
+// A directFallbackModuleGetter is a ModuleGetter that gets modules from a
+// proxy, and directly from their repositories when the proxy client falls
+// back to direct fetching.
+type directFallbackModuleGetter struct {
+	proxy  *proxyModuleGetter
+	direct ModuleGetter
+
+	mu         sync.Mutex
+	fromDirect map[internal.Modver]bool // resolved versions that direct serves
+}
+
+// NewProxyModuleGetterWithDirect returns a ModuleGetter like
+// NewProxyModuleGetter, except that a module version whose info p fails to
+// get with an error for which p.FallsBackToDirect is true is fetched with
+// direct instead, as the go command does for a GOPROXY list ending in
+// "direct". Direct is usually a getter returned by NewVCSModuleGetter.
+func NewProxyModuleGetterWithDirect(p *proxy.Client, s *source.Client, direct ModuleGetter) ModuleGetter {
+	return &directFallbackModuleGetter{
+		proxy:      &proxyModuleGetter{p, s},
+		direct:     direct,
+		fromDirect: map[internal.Modver]bool{},
+	}
+}
+
+// getter returns the getter that serves the given resolved version of the
+// module.
+func (g *directFallbackModuleGetter) getter(path, version string) ModuleGetter {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.fromDirect[internal.Modver{Path: path, Version: version}] {
+		return g.direct
+	}
+	return g.proxy
+}
+
+// Info returns basic information about the module from the proxy, or from
+// the direct getter if the proxy falls back to it.
+func (g *directFallbackModuleGetter) Info(ctx context.Context, path, version string) (*proxy.VersionInfo, error) {
+	info, err := g.proxy.Info(ctx, path, version)
+	if !g.proxy.prox.FallsBackToDirect(err) {
+		return info, err
+	}
+	log.Infof(ctx, "fetching %s@%s directly after proxy error: %v", path, version, err)
+	info, err = g.direct.Info(ctx, path, version)
+	if err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	g.fromDirect[internal.Modver{Path: path, Version: info.Version}] = true
+	g.mu.Unlock()
+	return info, nil
+}
+
+// Mod returns the contents of the module's go.mod file.
+func (g *directFallbackModuleGetter) Mod(ctx context.Context, path, version string) ([]byte, error) {
+	return g.getter(path, version).Mod(ctx, path, version)
+}
+
+// ContentDir returns an FS for the module's contents.
+func (g *directFallbackModuleGetter) ContentDir(ctx context.Context, path, version string) (fs.FS, error) {
+	return g.getter(path, version).ContentDir(ctx, path, version)
+}
+
+// SourceInfo returns information about where to find a module's repo and
+// source files.
+func (g *directFallbackModuleGetter) SourceInfo(ctx context.Context, path, version string) (*source.Info, error) {
+	return g.getter(path, version).SourceInfo(ctx, path, version)
+}
+
+// Commit returns information about the commit of the module version, if the
+// getter that serves it knows it.
+func (g *directFallbackModuleGetter) Commit(ctx context.Context, path, version string) (*internal.Commit, error) {
+	if cg, ok := g.getter(path, version).(CommitModuleGetter); ok {
+		return cg.Commit(ctx, path, version)
+	}
+	return nil, nil
+}
+
+// SourceFS is unimplemented, because we link directly to the module's repo.
+func (g *directFallbackModuleGetter) SourceFS() (string, fs.FS) {
+	return "", nil
+}
+
+func (g *directFallbackModuleGetter) String() string {
+	return "Proxy with direct fallback"
+}
+
 // Version and commit time are pre specified when fetching a local module, as these
 // fields are normally obtained from a proxy.
 var (
@@ -137,6 +246,8 @@ var (
 type directoryModuleGetter struct {
 	modulePath string
 	dir        string // absolute path to direction
+	version    string
+	commitTime time.Time
 }
 
 // NewDirectoryModuleGetter returns a ModuleGetter for reading a module from a directory.
@@ -158,9 +269,33 @@ func NewDirectoryModuleGetter(modulePath, dir string) (*directoryModuleGetter, e
 	return &directoryModuleGetter{
 		dir:        abs,
 		modulePath: modulePath,
+		version:    LocalVersion,
+		commitTime: LocalCommitTime,
 	}, nil
 }
 
+// NewDevDirectoryModuleGetter returns a ModuleGetter for reading the module
+// in dir, whose go.mod file provides the module path. Unlike the getter
+// returned by NewDirectoryModuleGetter, its version is a pseudo-version with
+// commit time t, so that modules fetched from the directory at successive
+// times can be stored alongside each other, each one the latest.
+func NewDevDirectoryModuleGetter(dir string, t time.Time) (*directoryModuleGetter, error) {
+	g, err := NewDirectoryModuleGetter("", dir)
+	if err != nil {
+		return nil, err
+	}
+	_, pathMajor, _ := module.SplitPathVersion(g.modulePath)
+	t = t.UTC().Truncate(time.Second)
+	g.version = module.PseudoVersion(module.PathMajorPrefix(pathMajor), "", t, "000000000000")
+	g.commitTime = t
+	return g, nil
+}
+
+// ModulePath returns the path of the module in the directory.
+func (g *directoryModuleGetter) ModulePath() string {
+	return g.modulePath
+}
+
 func (g *directoryModuleGetter) checkPath(path string) error {
 	if path != g.modulePath {
 		return fmt.Errorf("given module path %q does not match %q for directory %q: %w",
@@ -175,8 +310,8 @@ func (g *directoryModuleGetter) Info(ctx context.Context, path, version string)
 		return nil, err
 	}
 	return &proxy.VersionInfo{
-		Version: LocalVersion,
-		Time:    LocalCommitTime,
+		Version: g.version,
+		Time:    g.commitTime,
 	}, nil
 }
 
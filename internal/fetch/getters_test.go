@@ -7,16 +7,20 @@ package fetch
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/proxy"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
 	"golang.org/x/pkgsite/internal/testing/testhelper"
 	"golang.org/x/pkgsite/internal/version"
 )
@@ -36,6 +40,44 @@ func TestDirectoryModuleGetterEmpty(t *testing.T) {
 	}
 }
 
+func TestDevDirectoryModuleGetter(t *testing.T) {
+	ctx := context.Background()
+	tm := time.Date(2023, 6, 1, 12, 30, 45, 500, time.UTC)
+	for _, test := range []struct {
+		modulePath, want string
+	}{
+		{"example.com/m", "v0.0.0-20230601123045-000000000000"},
+		{"example.com/m/v2", "v2.0.0-20230601123045-000000000000"},
+		{"gopkg.in/m.v1", "v1.0.0-20230601123045-000000000000"},
+	} {
+		t.Run(test.modulePath, func(t *testing.T) {
+			dir, _ := testhelper.WriteTxtarToTempDir(t, fmt.Sprintf(`
+-- go.mod --
+module %s
+-- p.go --
+package p
+`, test.modulePath))
+			g, err := NewDevDirectoryModuleGetter(dir, tm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := g.ModulePath(); got != test.modulePath {
+				t.Errorf("ModulePath() = %q, want %q", got, test.modulePath)
+			}
+			info, err := g.Info(ctx, test.modulePath, version.Latest)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Version != test.want {
+				t.Errorf("got version %q, want %q", info.Version, test.want)
+			}
+			if want := tm.Truncate(time.Second); !info.Time.Equal(want) {
+				t.Errorf("got time %v, want %v", info.Time, want)
+			}
+		})
+	}
+}
+
 const multiModule = `
 -- go.work --
 go 1.21
@@ -325,3 +367,38 @@ func TestFSProxyGetter(t *testing.T) {
 		}
 	})
 }
+
+func TestProxyModuleGetterCommit(t *testing.T) {
+	ctx := context.Background()
+	const hash = "6f0c0d3e1b7c9b2a4e5f60718293a4b5c6d7e8f9"
+	proxyServer := proxytest.NewServer([]*proxytest.Module{{
+		ModulePath: "example.com/noorigin",
+		Version:    "v1.0.0",
+		Files:      map[string]string{"go.mod": "module example.com/noorigin"},
+	}})
+	proxyServer.AddRoute("/example.com/origin/@v/v1.0.0.info", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"Version": "v1.0.0", "Time": "2023-01-01T00:00:00Z", "Origin": {"VCS": "git", "URL": "https://example.com/origin", "Ref": "refs/tags/v1.0.0", "Hash": %q}}`, hash)
+	})
+	client, teardown, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+	g := NewProxyModuleGetter(client, nil).(CommitModuleGetter)
+
+	got, err := g.Commit(ctx, "example.com/origin", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&internal.Commit{Hash: hash}, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	got, err = g.Commit(ctx, "example.com/noorigin", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("without origin: got %+v, want nil", got)
+	}
+}
@@ -6,6 +6,7 @@ package fetch
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
 	"net/http"
 	"os"
@@ -63,6 +64,9 @@ func cleanFetchResult(t *testing.T, fr *FetchResult) *FetchResult {
 			Name:              u.Name,
 			IsRedistributable: u.IsRedistributable,
 			Licenses:          u.Licenses,
+			DocStats:          u.DocStats,
+			ExamplesValid:     u.ExamplesValid,
+			NumTestFiles:      u.NumTestFiles,
 		}
 		if u.IsPackage() && shouldSetPVS {
 			fr.PackageVersionStates = append(
@@ -117,6 +121,10 @@ func updateFetchResultVersions(t *testing.T, fr *FetchResult, local bool) *Fetch
 		for _, pvs := range fr.PackageVersionStates {
 			pvs.Version = LocalVersion
 		}
+		if !fr.HasGoMod {
+			// The directory getter synthesizes a go.mod without a go directive.
+			fr.Module.GoMod = []byte(fmt.Sprintf("module %s\n", fr.ModulePath))
+		}
 	} else {
 		for _, u := range fr.Module.Units {
 			// Copy all of ModuleInfo except HasGoMod.
@@ -0,0 +1,91 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/pkgsite/internal"
+)
+
+// maxKeywords is the maximum number of keywords extracted for a module.
+const maxKeywords = 10
+
+// minKeywordLen is the minimum length of a keyword, in runes.
+const minKeywordLen = 3
+
+// extractKeywords returns the keywords of the module with the given path and
+// units: the terms that occur most often in the README of the module root
+// and the synopses of its packages. Non-redistributable units are skipped, so
+// that their content cannot be recovered from the keywords. Terms are lower-cased words of letters
+// and digits; stop words, words that are too short and numbers are ignored.
+// At most maxKeywords terms are returned, in decreasing order of frequency
+// and then in alphabetical order, so the result is deterministic.
+func extractKeywords(modulePath string, units []*internal.Unit) []string {
+	counts := map[string]int{}
+	add := func(text string) {
+		for _, w := range keywordTerms(text) {
+			counts[w]++
+		}
+	}
+	for _, u := range units {
+		if !u.IsRedistributable {
+			continue
+		}
+		if u.Path == modulePath && u.Readme != nil {
+			add(u.Readme.Contents)
+		}
+		if u.IsPackage() && len(u.Documentation) > 0 {
+			add(u.Documentation[0].Synopsis)
+		}
+	}
+	var keywords []string
+	for w := range counts {
+		keywords = append(keywords, w)
+	}
+	sort.Slice(keywords, func(i, j int) bool {
+		ki, kj := keywords[i], keywords[j]
+		if counts[ki] != counts[kj] {
+			return counts[ki] > counts[kj]
+		}
+		return ki < kj
+	})
+	if len(keywords) > maxKeywords {
+		keywords = keywords[:maxKeywords]
+	}
+	return keywords
+}
+
+// keywordTerms returns the words of text that can be keywords, lower-cased.
+func keywordTerms(text string) []string {
+	var terms []string
+	for _, w := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		w = strings.ToLower(w)
+		if len([]rune(w)) < minKeywordLen || keywordStopWords[w] || strings.IndexFunc(w, unicode.IsLetter) < 0 {
+			continue
+		}
+		terms = append(terms, w)
+	}
+	return terms
+}
+
+// keywordStopWords are words that are too common in READMEs and synopses to
+// be useful as keywords.
+var keywordStopWords = map[string]bool{}
+
+func init() {
+	for _, w := range strings.Fields(`
+		about all also and any are but can com does for from github golang has
+		have how https into its not one org our package packages
+		see such than that the their then there these this use used uses
+		using was what when which will with you your www
+	`) {
+		keywordStopWords[w] = true
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestExtractKeywords(t *testing.T) {
+	const modulePath = "example.com/m"
+	unit := func(path, synopsis, readme string) *internal.Unit {
+		u := &internal.Unit{UnitMeta: internal.UnitMeta{Path: path, IsRedistributable: true}}
+		if synopsis != "" {
+			u.Name = "p"
+			u.Documentation = []*internal.Documentation{{Synopsis: synopsis}}
+		}
+		if readme != "" {
+			u.Readme = &internal.Readme{Filepath: "README.md", Contents: readme}
+		}
+		return u
+	}
+	for _, test := range []struct {
+		name  string
+		units []*internal.Unit
+		want  []string
+	}{
+		{
+			name:  "none",
+			units: []*internal.Unit{unit(modulePath, "", "")},
+			want:  nil,
+		},
+		{
+			name: "readme and synopses",
+			units: []*internal.Unit{
+				unit(modulePath, "", "An HTTP router. See https://example.com/router for the router docs."),
+				unit(modulePath+"/a", "Package a routes HTTP requests.", ""),
+				// Only the README of the module root is used.
+				unit(modulePath+"/b", "", "nested nested nested"),
+			},
+			want: []string{"router", "http", "docs", "example", "requests", "routes"},
+		},
+		{
+			name: "stop words short words and numbers",
+			units: []*internal.Unit{
+				unit(modulePath, "", "The go package is v2 and uses 1234 of them, with the 3d engine."),
+			},
+			want: []string{"engine", "them"},
+		},
+		{
+			name: "non-redistributable",
+			units: []*internal.Unit{
+				func() *internal.Unit {
+					u := unit(modulePath, "", "secret secret proprietary")
+					u.IsRedistributable = false
+					return u
+				}(),
+				unit(modulePath+"/a", "Package a routes requests.", ""),
+			},
+			want: []string{"requests", "routes"},
+		},
+		{
+			name: "limit",
+			units: []*internal.Unit{
+				unit(modulePath, "", "aaa bbb ccc ddd eee fff ggg hhh iii jjj kkk kkk"),
+			},
+			want: []string{"kkk", "aaa", "bbb", "ccc", "ddd", "eee", "fff", "ggg", "hhh", "iii"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := extractKeywords(modulePath, test.units)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@
 
 package fetch
 
+import "time"
+
 // Limits for discovery worker.
 const (
 	maxPackagesPerModule = 10000
@@ -16,3 +18,8 @@ const (
 )
 
 const megabyte = 1000 * 1000
+
+// DocRenderTimeout is the maximum time spent rendering the documentation of
+// a package for one build context. A package whose documentation takes
+// longer is stored without documentation.
+var DocRenderTimeout = time.Minute
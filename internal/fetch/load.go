@@ -19,13 +19,16 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"runtime/debug"
 	"sort"
 	"strings"
 
 	"go.opencensus.io/trace"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 )
@@ -49,6 +52,10 @@ func (bpe *BadPackageError) Error() string { return bpe.Err.Error() }
 //
 // If a package is fine except that its documentation is too large, loadPackage
 // returns a goPackage whose err field is a non-nil error with godoc.ErrTooLarge in its chain.
+// If rendering its documentation times out or panics, loadPackage returns a
+// goPackage without documentation whose err field has
+// derrors.PackageDocumentationRenderTimeout or
+// derrors.PackageDocumentationRenderPanic in its chain.
 func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, innerPath string,
 	sourceInfo *source.Info, modInfo *godoc.ModuleInfo) (_ *goPackage, err error) {
 	defer derrors.Wrap(&err, "loadPackage(ctx, zipGoFiles, %q, sourceInfo, modInfo)", innerPath)
@@ -103,9 +110,9 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 			pkg.docs = append(pkg.docs, &doc2)
 			continue
 		}
-		name, imports, synopsis, source, api, err := loadPackageForBuildContext(ctx,
+		name, source, info, err := loadPackageForBuildContext(ctx,
 			mfiles, innerPath, sourceInfo, modInfo)
-		for _, s := range api {
+		for _, s := range info.API {
 			s.GOOS = bc.GOOS
 			s.GOARCH = bc.GOARCH
 		}
@@ -122,30 +129,53 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 			// simple, return a single package with this error that will be used
 			// for all build contexts, and ignore the others.
 			return &goPackage{
-				err:     err,
-				path:    importPath,
-				v1path:  v1path,
-				name:    name,
-				imports: imports,
+				err:                err,
+				path:               importPath,
+				v1path:             v1path,
+				name:               name,
+				imports:            info.Imports,
+				docStats:           info.Stats,
+				examplesValid:      info.ExamplesValid,
+				examples:           info.Examples,
+				deprecated:         info.Deprecated,
+				deprecationComment: info.DeprecationComment,
 				docs: []*internal.Documentation{{
 					GOOS:     internal.All,
 					GOARCH:   internal.All,
-					Synopsis: synopsis,
+					Synopsis: info.Synopsis,
+					Usage:    info.Usage,
 					Source:   source,
-					API:      api,
+					API:      info.API,
 				}},
 			}, nil
+		case errors.Is(err, derrors.PackageDocumentationRenderTimeout),
+			errors.Is(err, derrors.PackageDocumentationRenderPanic):
+			// Rendering for other build contexts is likely to fail in the
+			// same way, so give up on the documentation entirely.
+			return &goPackage{
+				err:    err,
+				path:   importPath,
+				v1path: v1path,
+				name:   name,
+			}, nil
 		case err != nil:
 			// Serious error. Fail.
 			return nil, err
 		default:
 			// No error.
 			if pkg == nil {
+				// Use the imports, stats, example validity and deprecation
+				// from the first successful build context.
 				pkg = &goPackage{
-					path:    importPath,
-					v1path:  v1path,
-					name:    name,
-					imports: imports, // Use the imports from the first successful build context.
+					path:               importPath,
+					v1path:             v1path,
+					name:               name,
+					imports:            info.Imports,
+					docStats:           info.Stats,
+					examplesValid:      info.ExamplesValid,
+					examples:           info.Examples,
+					deprecated:         info.Deprecated,
+					deprecationComment: info.DeprecationComment,
 				}
 			}
 			// All the build contexts should use the same package name. Although
@@ -156,12 +186,19 @@ func loadPackage(ctx context.Context, contentDir fs.FS, goFilePaths []string, in
 					Err: fmt.Errorf("more than one package name (%q and %q)", pkg.name, name),
 				}
 			}
+			var html, text string
+			if experiment.IsActive(ctx, internal.ExperimentStoreDocFormats) {
+				html, text = renderDocFormats(ctx, source, innerPath, sourceInfo, modInfo)
+			}
 			doc := &internal.Documentation{
 				GOOS:     bc.GOOS,
 				GOARCH:   bc.GOARCH,
-				Synopsis: synopsis,
+				Synopsis: info.Synopsis,
+				Usage:    info.Usage,
 				Source:   source,
-				API:      api,
+				API:      info.API,
+				HTML:     html,
+				Text:     text,
 			}
 			docsByFiles[filesKey] = doc
 			pkg.docs = append(pkg.docs, doc)
@@ -205,8 +242,8 @@ var httpPost = http.Post
 // .go files that have been verified to be of reasonable size and that match
 // the build context.
 //
-// It returns the package name, list of imports, the package synopsis, and the
-// serialized source (AST) for the package.
+// It returns the package name, the serialized source (AST) for the package, and
+// the information extracted from its documentation.
 //
 // It returns an error with NotFound in its chain if the directory doesn't
 // contain a Go package or all .go files have been excluded by constraints. A
@@ -214,15 +251,18 @@ var httpPost = http.Post
 // not make up a valid package.
 //
 // If it returns an error with ErrTooLarge in its chain, the other return values
-// are still valid.
+// are still valid. If it returns an error with
+// derrors.PackageDocumentationRenderTimeout or
+// derrors.PackageDocumentationRenderPanic in its chain, only the package name
+// is valid.
 func loadPackageForBuildContext(ctx context.Context, files map[string][]byte, innerPath string, sourceInfo *source.Info, modInfo *godoc.ModuleInfo) (
-	name string, imports []string, synopsis string, source []byte, api []*internal.Symbol, err error) {
+	name string, source []byte, info godoc.DocInfo, err error) {
 	modulePath := modInfo.ModulePath
 	defer derrors.Wrap(&err, "loadPackageWithBuildContext(files, %q, %q, %+v)", innerPath, modulePath, sourceInfo)
 
 	packageName, goFiles, fset, err := loadFilesWithBuildContext(innerPath, files)
 	if err != nil {
-		return "", nil, "", nil, nil, err
+		return "", nil, godoc.DocInfo{}, err
 	}
 	docPkg := godoc.NewPackage(fset, modInfo.ModulePackages)
 	for _, pf := range goFiles {
@@ -238,14 +278,76 @@ func loadPackageForBuildContext(ctx context.Context, files map[string][]byte, in
 	// Encode first, because Render messes with the AST.
 	src, err := docPkg.Encode(ctx)
 	if err != nil {
-		return "", nil, "", nil, nil, err
+		return "", nil, godoc.DocInfo{}, err
+	}
+
+	di, err := docInfoWithTimeout(ctx, docPkg, innerPath, sourceInfo, modInfo)
+	if errors.Is(err, derrors.PackageDocumentationRenderTimeout) || errors.Is(err, derrors.PackageDocumentationRenderPanic) {
+		return packageName, nil, godoc.DocInfo{}, err
+	}
+	if err != nil {
+		return "", nil, godoc.DocInfo{}, err
+	}
+	return packageName, src, *di, nil
+}
+
+// docInfo allows package fetch tests to simulate slow rendering.
+var docInfo = (*godoc.Package).DocInfo
+
+// docInfoWithTimeout calls docInfo, giving up after DocRenderTimeout. The
+// context passed to docInfo is canceled on timeout, so that rendering stops
+// at its next check of the context; it does not wait for that, since go/doc
+// itself can't be interrupted. A panic during rendering is returned as an
+// error.
+func docInfoWithTimeout(ctx context.Context, docPkg *godoc.Package, innerPath string, sourceInfo *source.Info, modInfo *godoc.ModuleInfo) (*godoc.DocInfo, error) {
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, DocRenderTimeout)
+	defer cancel()
+
+	type result struct {
+		di  *godoc.DocInfo
+		err error
 	}
+	c := make(chan result, 1)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				log.Errorf(ctx, "panic rendering documentation for %q: %v\n%s", innerPath, e, debug.Stack())
+				c <- result{nil, fmt.Errorf("%v: %w", e, derrors.PackageDocumentationRenderPanic)}
+			}
+		}()
+		di, err := docInfo(docPkg, ctx, innerPath, sourceInfo, modInfo)
+		c <- result{di, err}
+	}()
+	select {
+	case r := <-c:
+		return r.di, r.err
+	case <-ctx.Done():
+		if err := parent.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("rendering took longer than %s: %w", DocRenderTimeout, derrors.PackageDocumentationRenderTimeout)
+	}
+}
 
-	synopsis, imports, api, err = docPkg.DocInfo(ctx, innerPath, sourceInfo, modInfo)
+// renderDocFormats returns the documentation for the package encoded in src
+// in the formats stored with it; see godoc.RenderFormats. The formats are
+// only a convenience for readers, so failing to render them is logged and
+// does not fail the fetch. Rendering them adds to the work of every fetch,
+// so they are only rendered with the store-doc-formats experiment.
+func renderDocFormats(ctx context.Context, src []byte, innerPath string, sourceInfo *source.Info, modInfo *godoc.ModuleInfo) (html, text string) {
+	defer func() {
+		if e := recover(); e != nil {
+			log.Errorf(ctx, "panic rendering documentation formats for %q: %v", innerPath, e)
+			html, text = "", ""
+		}
+	}()
+	html, text, err := godoc.RenderFormats(ctx, src, innerPath, sourceInfo, modInfo)
 	if err != nil {
-		return "", nil, "", nil, nil, err
+		log.Warningf(ctx, "%v", err)
+		return "", ""
 	}
-	return packageName, imports, synopsis, src, api, err
+	return html, text
 }
 
 // loadFilesWithBuildContext loads all the given Go files at innerPath. It
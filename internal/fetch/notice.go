@@ -0,0 +1,66 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"errors"
+	"io/fs"
+	"path"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// extractNotice returns the NOTICE file at the root of contentDir, or nil if
+// there is none. A file named NOTICE is preferred over one with an
+// extension, like NOTICE.txt or NOTICE.md.
+//
+// As for changelogs, a NOTICE file that is too large is ignored.
+func extractNotice(contentDir fs.FS) (_ *internal.Notice, err error) {
+	defer derrors.Wrap(&err, "extractNotice")
+
+	entries, err := fs.ReadDir(contentDir, ".")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) { // we can get NotExist on an empty FS
+			return nil, nil
+		}
+		return nil, err
+	}
+	var best fs.DirEntry
+	for _, e := range entries {
+		if e.IsDir() || !isNoticeFile(e.Name()) {
+			continue
+		}
+		if best == nil || (path.Ext(best.Name()) != "" && path.Ext(e.Name()) == "") {
+			best = e
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+	info, err := best.Info()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > MaxFileSize {
+		return nil, nil
+	}
+	c, err := readFSFile(contentDir, best.Name(), MaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	return &internal.Notice{Filepath: best.Name(), Contents: string(c)}, nil
+}
+
+// isNoticeFile reports whether file is a NOTICE file. Matching is case
+// insensitive, and .go files are never NOTICE files.
+func isNoticeFile(file string) bool {
+	ext := path.Ext(file)
+	if excludedReadmeExts[ext] {
+		return false
+	}
+	return strings.EqualFold(strings.TrimSuffix(file, ext), "NOTICE")
+}
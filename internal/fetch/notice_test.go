@@ -0,0 +1,94 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+func TestExtractNotice(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		files map[string]string
+		want  *internal.Notice
+	}{
+		{
+			name:  "none",
+			files: map[string]string{"README.md": "readme", "go.mod": "module m"},
+			want:  nil,
+		},
+		{
+			name:  "NOTICE",
+			files: map[string]string{"NOTICE": "Copyright Foo", "README.md": "readme"},
+			want:  &internal.Notice{Filepath: "NOTICE", Contents: "Copyright Foo"},
+		},
+		{
+			name:  "prefer no extension",
+			files: map[string]string{"NOTICE.md": "markdown", "Notice": "text"},
+			want:  &internal.Notice{Filepath: "Notice", Contents: "text"},
+		},
+		{
+			name:  "only at root",
+			files: map[string]string{"sub/NOTICE": "sub"},
+			want:  nil,
+		},
+		{
+			name:  "not Go files",
+			files: map[string]string{"notice.go": "package notice"},
+			want:  nil,
+		},
+		{
+			name:  "too large",
+			files: map[string]string{"NOTICE": strings.Repeat("x", int(MaxFileSize)+1)},
+			want:  nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, contents := range test.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(contents)}
+			}
+			got, err := extractNotice(fsys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFetchModule_Notice(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const notice = "This product includes software developed at Example Corp.\n"
+	mod := &proxytest.Module{
+		ModulePath: "example.com/notice",
+		Files: map[string]string{
+			"go.mod":  "module example.com/notice",
+			"LICENSE": testhelper.MITLicense,
+			"NOTICE":  notice,
+			"p.go":    "// Package p has a notice.\npackage p\n",
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := &internal.Notice{Filepath: "NOTICE", Contents: notice}
+	if diff := cmp.Diff(want, got.Module.Notice); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
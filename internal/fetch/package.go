@@ -34,9 +34,27 @@ type goPackage struct {
 	licenseMeta       []*licenses.Metadata // metadata of applicable licenses
 	// v1path is the package path of a package with major version 1 in a given
 	// series.
-	v1path string
-	docs   []*internal.Documentation // doc for different build contexts
-	err    error                     // non-fatal error when loading the package (e.g. documentation is too large)
+	v1path   string
+	docs     []*internal.Documentation // doc for different build contexts
+	docStats internal.DocStats         // from the first successful build context
+	err      error                     // non-fatal error when loading the package (e.g. documentation is too large)
+
+	// examplesValid reports whether the examples with output comments refer
+	// only to defined identifiers, in the first successful build context.
+	examplesValid bool
+
+	// examples holds the examples of the package, in the first successful
+	// build context.
+	examples *internal.Examples
+
+	// numTestFiles is the number of _test.go files in the package directory,
+	// regardless of build context.
+	numTestFiles int
+
+	// deprecated reports whether the package doc comment marks the package
+	// as deprecated. It is independent of the deprecation of the module.
+	deprecated         bool
+	deprecationComment string
 }
 
 // extractPackages returns a slice of packages from a filesystem arranged like a
@@ -49,6 +67,11 @@ type goPackage struct {
 // * a maximum file size (MaxFileSize)
 // * the particular set of build contexts we consider (goEnvs)
 // * whether the import path is valid.
+//
+// A module without any .go files has no packages, and extractPackages returns
+// no packages and no error for it. If the module has .go files but none of
+// them are in a package that can be processed, the error is
+// ErrModuleContainsNoPackages.
 func extractPackages(ctx context.Context, modulePath, resolvedVersion string, contentDir fs.FS, d *licenses.Detector, sourceInfo *source.Info) (_ []*goPackage, _ []*internal.PackageVersionState, err error) {
 	defer derrors.Wrap(&err, "extractPackages(ctx, %q, %q, r, d)", modulePath, resolvedVersion)
 	ctx, span := trace.StartSpan(ctx, "fetch.extractPackages")
@@ -106,6 +129,11 @@ func extractPackages(ctx context.Context, modulePath, resolvedVersion string, co
 			return err
 		}
 		if d.IsDir() {
+			if pathname != "." && isNestedModule(contentDir, pathname) {
+				// The directory and everything under it belong to a
+				// different module.
+				return fs.SkipDir
+			}
 			// Skip directories.
 			return nil
 		}
@@ -213,10 +241,22 @@ func extractPackages(ctx context.Context, modulePath, resolvedVersion string, co
 			if errors.Is(pkg.err, godoc.ErrTooLarge) {
 				status = derrors.PackageDocumentationHTMLTooLarge
 				errMsg = pkg.err.Error()
+			} else if errors.Is(pkg.err, derrors.PackageDocumentationRenderTimeout) {
+				status = derrors.PackageDocumentationRenderTimeout
+				errMsg = pkg.err.Error()
+			} else if errors.Is(pkg.err, derrors.PackageDocumentationRenderPanic) {
+				status = derrors.PackageDocumentationRenderPanic
+				errMsg = pkg.err.Error()
 			} else if pkg.err != nil {
-				// ErrTooLarge is the only valid value of pkg.err.
+				// ErrTooLarge and the render errors above are the only valid
+				// values of pkg.err.
 				return nil, nil, fmt.Errorf("bad package error for %s: %v", pkg.path, pkg.err)
 			}
+			for _, f := range goFiles {
+				if strings.HasSuffix(f, "_test.go") {
+					pkg.numTestFiles++
+				}
+			}
 			if d != nil { //  should only be nil for tests
 				isRedist, lics := d.PackageInfo(innerPath)
 				pkg.isRedistributable = isRedist
@@ -236,6 +276,10 @@ func extractPackages(ctx context.Context, modulePath, resolvedVersion string, co
 		})
 	}
 	if len(pkgs) == 0 {
+		if len(dirs) == 0 && len(packageVersionStates) == 0 {
+			// There are no .go files in the module.
+			return nil, nil, nil
+		}
 		return nil, packageVersionStates, ErrModuleContainsNoPackages
 	}
 	return pkgs, packageVersionStates, nil
@@ -272,3 +316,13 @@ func isVendored(importPath string) bool {
 	return strings.HasPrefix(importPath, "vendor/") ||
 		strings.Contains(importPath, "/vendor/")
 }
+
+// isNestedModule reports whether dir, a directory in contentDir, is the root
+// of a nested module, that is, whether it contains a go.mod file. The
+// packages in a nested module are not part of the enclosing module, as the go
+// command sees it. Module zips from the proxy never contain nested modules,
+// but module directories on disk can.
+func isNestedModule(contentDir fs.FS, dir string) bool {
+	info, err := fs.Stat(contentDir, path.Join(dir, "go.mod"))
+	return err == nil && !info.IsDir()
+}
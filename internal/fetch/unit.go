@@ -62,6 +62,12 @@ func moduleUnits(modulePath string, minfo internal.ModuleInfo,
 			dir.Name = pkg.name
 			dir.Imports = pkg.imports
 			dir.Documentation = pkg.docs
+			dir.DocStats = pkg.docStats
+			dir.ExamplesValid = pkg.examplesValid
+			dir.Examples = pkg.examples
+			dir.NumTestFiles = pkg.numTestFiles
+			dir.PackageDeprecated = pkg.deprecated
+			dir.PackageDeprecationComment = pkg.deprecationComment
 			var bcs []internal.BuildContext
 			for _, d := range dir.Documentation {
 				bcs = append(bcs, internal.BuildContext{GOOS: d.GOOS, GOARCH: d.GOARCH})
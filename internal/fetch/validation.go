@@ -0,0 +1,73 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/licenses"
+)
+
+// A ValidationReport lists problems found while processing a module that
+// don't prevent it from being stored, but that may make the result
+// inaccurate.
+type ValidationReport struct {
+	Entries []*ValidationEntry
+}
+
+// A ValidationEntry is a single problem in a ValidationReport.
+type ValidationEntry struct {
+	Kind    ValidationKind
+	Message string
+}
+
+// A ValidationKind is the kind of problem described by a ValidationEntry.
+type ValidationKind string
+
+// LicenseConflict is the kind of entry reported when the license files of a
+// module disagree about whether it is redistributable.
+const LicenseConflict ValidationKind = "license-conflict"
+
+func (r *ValidationReport) add(kind ValidationKind, format string, args ...any) {
+	r.Entries = append(r.Entries, &ValidationEntry{Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+// Has reports whether r has an entry of the given kind.
+func (r *ValidationReport) Has(kind ValidationKind) bool {
+	if r == nil {
+		return false
+	}
+	for _, e := range r.Entries {
+		if e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// validateModule returns a report of the problems with mod, or nil if there
+// are none.
+func validateModule(mod *internal.Module) *ValidationReport {
+	r := &ValidationReport{}
+	validateLicenses(mod, r)
+	if len(r.Entries) == 0 {
+		return nil
+	}
+	return r
+}
+
+// validateLicenses reports conflicts between the licenses of mod, and sets
+// mod.LicenseConflict if there are any.
+func validateLicenses(mod *internal.Module, r *ValidationReport) {
+	c := licenses.FindConflict(mod.Licenses)
+	if c == nil {
+		return
+	}
+	mod.LicenseConflict = true
+	r.add(LicenseConflict, "redistributable licenses (%s) conflict with non-redistributable licenses (%s)",
+		strings.Join(c.Redistributable, ", "), strings.Join(c.NonRedistributable, ", "))
+}
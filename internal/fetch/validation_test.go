@@ -0,0 +1,62 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+func TestFetchModule_LicenseConflict(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for _, test := range []struct {
+		name         string
+		files        map[string]string
+		wantConflict bool
+	}{
+		{
+			name: "no conflict",
+			files: map[string]string{
+				"LICENSE":     testhelper.MITLicense,
+				"sub/LICENSE": testhelper.MITLicense,
+				"sub/p.go":    "package p",
+				"m.go":        "package m",
+			},
+			wantConflict: false,
+		},
+		{
+			name: "conflict",
+			files: map[string]string{
+				"LICENSE":             testhelper.MITLicense,
+				"proprietary/LICENSE": "All rights reserved. Redistribution is not permitted.",
+				"proprietary/p.go":    "package p",
+				"m.go":                "package m",
+			},
+			wantConflict: true,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			files := map[string]string{"go.mod": "module example.com/conflict"}
+			for name, contents := range test.files {
+				files[name] = contents
+			}
+			got, _ := proxyFetcher(t, false, ctx, &proxytest.Module{ModulePath: "example.com/conflict", Files: files}, "")
+			if got.Error != nil {
+				t.Fatal(got.Error)
+			}
+			if got.Module.LicenseConflict != test.wantConflict {
+				t.Errorf("LicenseConflict = %t, want %t", got.Module.LicenseConflict, test.wantConflict)
+			}
+			if g := got.ValidationReport.Has(LicenseConflict); g != test.wantConflict {
+				t.Errorf("ValidationReport has license conflict: %t, want %t; report: %+v", g, test.wantConflict, got.ValidationReport)
+			}
+		})
+	}
+}
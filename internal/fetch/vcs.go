@@ -0,0 +1,448 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	modzip "golang.org/x/mod/zip"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/proxy"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// A vcsModuleGetter is a ModuleGetter that downloads modules directly from
+// their git repositories, as the go command does for GOPROXY=direct. It can
+// serve modules, such as private ones, that no proxy has.
+type vcsModuleGetter struct {
+	dir string // where repositories are cloned
+	src *source.Client
+
+	// repo returns the URL of the git repository containing the given
+	// version of the module, and the directory of the module relative to the
+	// repository root. The directory may or may not include the module's
+	// major version suffix; resolve works that out from the repository.
+	repo func(ctx context.Context, modulePath, version string) (repoURL, moduleDir string, err error)
+
+	mu      sync.Mutex             // protects repoMus
+	repoMus map[string]*sync.Mutex // serialize cloning and updating each repository
+}
+
+// NewVCSModuleGetter returns a ModuleGetter that clones git repositories into
+// dir and builds module zips from them. The repository of a module is found
+// with the source package, using src.
+//
+// It requires the git command.
+func NewVCSModuleGetter(dir string, src *source.Client) (_ *vcsModuleGetter, err error) {
+	defer derrors.Wrap(&err, "NewVCSModuleGetter(%q)", dir)
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return nil, err
+	}
+	g := &vcsModuleGetter{dir: abs, src: src, repoMus: map[string]*sync.Mutex{}}
+	g.repo = g.sourceRepo
+	return g, nil
+}
+
+// NewVCSModuleGetterForTesting returns a ModuleGetter like NewVCSModuleGetter,
+// except that every module is found at the root of the git repository at
+// repoURL.
+func NewVCSModuleGetterForTesting(dir, repoURL string) (ModuleGetter, error) {
+	g, err := NewVCSModuleGetter(dir, source.NewClientForTesting())
+	if err != nil {
+		return nil, err
+	}
+	g.repo = func(context.Context, string, string) (string, string, error) { return repoURL, "", nil }
+	return g, nil
+}
+
+// sourceRepo finds the repository of modulePath with source.ModuleInfo.
+func (g *vcsModuleGetter) sourceRepo(ctx context.Context, modulePath, vers string) (string, string, error) {
+	info, err := source.ModuleInfo(ctx, g.src, modulePath, vers)
+	if err != nil {
+		return "", "", err
+	}
+	if info == nil {
+		return "", "", fmt.Errorf("no repository found for %q: %w", modulePath, derrors.NotFound)
+	}
+	return info.RepoURL(), info.ModuleDir(), nil
+}
+
+// A vcsModule is a module version resolved to a commit in a local clone.
+type vcsModule struct {
+	dir       string // local clone
+	moduleDir string // module directory in the repository
+	version   string // resolved version
+	rev       string // commit hash
+}
+
+// resolve finds the commit for the given version of modulePath, cloning or
+// updating its repository as needed.
+func (g *vcsModuleGetter) resolve(ctx context.Context, modulePath, vers string) (_ *vcsModule, err error) {
+	defer derrors.Wrap(&err, "resolve(%q, %q)", modulePath, vers)
+
+	repoURL, moduleDir, err := g.repo(ctx, modulePath, vers)
+	if err != nil {
+		return nil, err
+	}
+	// As for the go command, tags are prefixed with the module directory
+	// without its major version suffix, and the module is either in that
+	// directory or in its major version subdirectory.
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+	codeDir := moduleDir
+	if strings.HasPrefix(pathMajor, "/") && path.Base(moduleDir) == pathMajor[1:] {
+		codeDir = strings.TrimSuffix(path.Dir(moduleDir), ".")
+	}
+	// The latest version can change at any time, so always update for it.
+	// Other versions are usually already in an existing clone.
+	dir, err := g.clone(ctx, repoURL, vers == version.Latest)
+	if err != nil {
+		return nil, err
+	}
+	m := &vcsModule{dir: dir, moduleDir: codeDir}
+	m.version, m.rev, err = resolveVCSVersion(ctx, dir, modulePath, codeDir, vers)
+	if errors.Is(err, derrors.NotFound) && vers != version.Latest {
+		if _, err := g.clone(ctx, repoURL, true); err != nil {
+			return nil, err
+		}
+		m.version, m.rev, err = resolveVCSVersion(ctx, dir, modulePath, codeDir, vers)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(pathMajor, "/") {
+		majorDir := path.Join(codeDir, pathMajor[1:])
+		ok, err := hasFile(ctx, dir, m.rev, path.Join(majorDir, "go.mod"))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			m.moduleDir = majorDir
+		}
+	}
+	return m, nil
+}
+
+// clone returns the directory of a clone of the repository at repoURL,
+// cloning it if necessary. If update is true, an existing clone is updated
+// from the repository.
+func (g *vcsModuleGetter) clone(ctx context.Context, repoURL string, update bool) (string, error) {
+	sum := sha256.Sum256([]byte(repoURL))
+	dir := filepath.Join(g.dir, hex.EncodeToString(sum[:8]))
+
+	unlock := g.lockRepo(dir)
+	defer unlock()
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		if update {
+			if _, err := runGit(ctx, dir, "fetch", "--quiet", "--tags", "--force", "origin"); err != nil {
+				return "", err
+			}
+		}
+		return dir, nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if _, err := runGit(ctx, g.dir, "clone", "--quiet", "--no-checkout", "--", repoURL, dir); err != nil {
+		os.RemoveAll(dir)
+		// We can't tell a missing repository from one we aren't allowed to
+		// read, so treat both as not found.
+		return "", fmt.Errorf("%v: %w", err, derrors.NotFound)
+	}
+	return dir, nil
+}
+
+// lockRepo locks the clone at dir, so that it is cloned or updated by one
+// caller at a time, and returns a function that unlocks it. Other clones can
+// be cloned or updated meanwhile.
+func (g *vcsModuleGetter) lockRepo(dir string) (unlock func()) {
+	g.mu.Lock()
+	mu := g.repoMus[dir]
+	if mu == nil {
+		mu = &sync.Mutex{}
+		g.repoMus[dir] = mu
+	}
+	g.mu.Unlock()
+	mu.Lock()
+	return mu.Unlock
+}
+
+// resolveVCSVersion returns the resolved version and commit hash for the
+// requested version of the module whose tags are prefixed with codeDir in the
+// clone at dir.
+//
+// Release versions are found from tags, which are prefixed with codeDir as for
+// the go command. If there are no tags, the latest version is a pseudo-version
+// for the head of the default branch.
+func resolveVCSVersion(ctx context.Context, dir, modulePath, codeDir, vers string) (resolved, rev string, err error) {
+	tagPrefix := ""
+	if codeDir != "" {
+		tagPrefix = codeDir + "/"
+	}
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+	switch {
+	case vers == version.Latest:
+		out, err := runGit(ctx, dir, "tag", "--list", tagPrefix+"v*")
+		if err != nil {
+			return "", "", err
+		}
+		var versions []string
+		for _, tag := range strings.Fields(string(out)) {
+			v := strings.TrimPrefix(tag, tagPrefix)
+			if semver.IsValid(v) && semver.Canonical(v) == v && module.CheckPathMajor(v, pathMajor) == nil {
+				versions = append(versions, v)
+			}
+		}
+		if len(versions) > 0 {
+			v := version.LatestOf(versions)
+			rev, err := revParse(ctx, dir, "refs/tags/"+tagPrefix+v)
+			return v, rev, err
+		}
+		rev, err := revParse(ctx, dir, "origin/HEAD")
+		if err != nil {
+			return "", "", err
+		}
+		t, err := commitTime(ctx, dir, rev)
+		if err != nil {
+			return "", "", err
+		}
+		major := module.PathMajorPrefix(pathMajor)
+		if major == "" {
+			major = "v0"
+		}
+		return module.PseudoVersion(major, "", t, rev[:12]), rev, nil
+	case module.IsPseudoVersion(vers):
+		rev, err := checkPseudoVersion(ctx, dir, tagPrefix, vers)
+		return vers, rev, err
+	default:
+		tag := tagPrefix + strings.TrimSuffix(vers, "+incompatible")
+		rev, err := revParse(ctx, dir, "refs/tags/"+tag)
+		return vers, rev, err
+	}
+}
+
+// checkPseudoVersion returns the commit hash of the pseudo-version vers in the
+// clone at dir, after checking it as the go command does: the commit must be
+// the one named by the pseudo-version's revision, have its timestamp, and
+// descend from the tag of its base version, if any. If the commit does not
+// exist it returns an error wrapping derrors.NotFound; if any of the other
+// checks fails, an error wrapping derrors.InvalidArgument.
+func checkPseudoVersion(ctx context.Context, dir, tagPrefix, vers string) (_ string, err error) {
+	short, err := module.PseudoVersionRev(vers)
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", err, derrors.InvalidArgument)
+	}
+	rev, err := revParse(ctx, dir, short)
+	if err != nil {
+		return "", err
+	}
+	if len(short) != 12 || !strings.HasPrefix(rev, short) {
+		return "", fmt.Errorf("%s: revision %q is not the 12-character prefix of commit %s: %w", vers, short, rev, derrors.InvalidArgument)
+	}
+	t, err := module.PseudoVersionTime(vers)
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", err, derrors.InvalidArgument)
+	}
+	ct, err := commitTime(ctx, dir, rev)
+	if err != nil {
+		return "", err
+	}
+	if !t.Equal(ct) {
+		return "", fmt.Errorf("%s: timestamp does not match commit time %s: %w", vers, ct.Format(module.PseudoVersionTimestampFormat), derrors.InvalidArgument)
+	}
+	base, err := module.PseudoVersionBase(vers)
+	if err != nil {
+		return "", fmt.Errorf("%v: %w", err, derrors.InvalidArgument)
+	}
+	if base == "" {
+		return rev, nil
+	}
+	tag := tagPrefix + strings.TrimSuffix(base, "+incompatible")
+	baseRev, err := revParse(ctx, dir, "refs/tags/"+tag)
+	if err != nil {
+		return "", fmt.Errorf("%s: base version tag %q not found: %w", vers, tag, derrors.InvalidArgument)
+	}
+	if _, err := runGit(ctx, dir, "merge-base", "--is-ancestor", baseRev, rev); err != nil {
+		var ee *exec.ExitError
+		if errors.As(err, &ee) && ee.ExitCode() == 1 {
+			return "", fmt.Errorf("%s: commit does not descend from tag %q: %w", vers, tag, derrors.InvalidArgument)
+		}
+		return "", err
+	}
+	return rev, nil
+}
+
+// Info returns basic information about the module.
+func (g *vcsModuleGetter) Info(ctx context.Context, path, vers string) (_ *proxy.VersionInfo, err error) {
+	defer derrors.Wrap(&err, "vcsModuleGetter.Info(%q, %q)", path, vers)
+
+	m, err := g.resolve(ctx, path, vers)
+	if err != nil {
+		return nil, err
+	}
+	t, err := commitTime(ctx, m.dir, m.rev)
+	if err != nil {
+		return nil, err
+	}
+	return &proxy.VersionInfo{Version: m.version, Time: t}, nil
+}
+
+// Commit returns the hash, author and committer of the commit that the
+// module version was built from.
+func (g *vcsModuleGetter) Commit(ctx context.Context, path, vers string) (_ *internal.Commit, err error) {
+	defer derrors.Wrap(&err, "vcsModuleGetter.Commit(%q, %q)", path, vers)
+
+	m, err := g.resolve(ctx, path, vers)
+	if err != nil {
+		return nil, err
+	}
+	out, err := runGit(ctx, m.dir, "log", "-1", "--format=%H%n%an%n%ae%n%cn%n%ce", m.rev)
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if len(lines) != 5 {
+		return nil, fmt.Errorf("unexpected git log output %q", out)
+	}
+	return &internal.Commit{
+		Hash:           lines[0],
+		AuthorName:     lines[1],
+		AuthorEmail:    lines[2],
+		CommitterName:  lines[3],
+		CommitterEmail: lines[4],
+	}, nil
+}
+
+// Mod returns the contents of the module's go.mod file. If the module has no
+// go.mod file, it returns a minimal one, as the go command does.
+func (g *vcsModuleGetter) Mod(ctx context.Context, modulePath, vers string) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "vcsModuleGetter.Mod(%q, %q)", modulePath, vers)
+
+	m, err := g.resolve(ctx, modulePath, vers)
+	if err != nil {
+		return nil, err
+	}
+	goMod := path.Join(m.moduleDir, "go.mod")
+	ok, err := hasFile(ctx, m.dir, m.rev, goMod)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []byte(fmt.Sprintf("module %s\n", modfile.AutoQuote(modulePath))), nil
+	}
+	return runGit(ctx, m.dir, "show", m.rev+":"+goMod)
+}
+
+// ContentDir returns an FS for the module's contents, built from the module's
+// directory in the repository according to the rules for module zip files.
+func (g *vcsModuleGetter) ContentDir(ctx context.Context, path, vers string) (_ fs.FS, err error) {
+	defer derrors.Wrap(&err, "vcsModuleGetter.ContentDir(%q, %q)", path, vers)
+
+	m, err := g.resolve(ctx, path, vers)
+	if err != nil {
+		return nil, err
+	}
+	subdir := ""
+	if m.moduleDir != "" {
+		subdir = m.moduleDir + "/"
+	}
+	var buf bytes.Buffer
+	if err := modzip.CreateFromVCS(&buf, module.Version{Path: path, Version: m.version}, m.dir, m.rev, subdir); err != nil {
+		return nil, fmt.Errorf("%v: %w", err, derrors.BadModule)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, err
+	}
+	return fs.Sub(zr, path+"@"+m.version)
+}
+
+// SourceInfo gets information about a module's repo and source files by
+// calling source.ModuleInfo.
+func (g *vcsModuleGetter) SourceInfo(ctx context.Context, path, version string) (*source.Info, error) {
+	return source.ModuleInfo(ctx, g.src, path, version)
+}
+
+// SourceFS is unimplemented for modules fetched directly, because we link
+// directly to the module's repo.
+func (g *vcsModuleGetter) SourceFS() (string, fs.FS) {
+	return "", nil
+}
+
+// For testing.
+func (g *vcsModuleGetter) String() string {
+	return fmt.Sprintf("VCS(%s)", g.dir)
+}
+
+// revParse returns the commit hash for rev in the clone at dir. It returns
+// an error wrapping derrors.NotFound if there is no such commit.
+func revParse(ctx context.Context, dir, rev string) (string, error) {
+	out, err := runGit(ctx, dir, "rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("%q: %w", rev, derrors.NotFound)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// hasFile reports whether file exists at rev in the clone at dir.
+func hasFile(ctx context.Context, dir, rev, file string) (bool, error) {
+	out, err := runGit(ctx, dir, "ls-tree", "--name-only", rev, "--", file)
+	if err != nil {
+		return false, err
+	}
+	return len(bytes.TrimSpace(out)) > 0, nil
+}
+
+// commitTime returns the commit time of rev in the clone at dir.
+func commitTime(ctx context.Context, dir, rev string) (time.Time, error) {
+	out, err := runGit(ctx, dir, "log", "-1", "--format=%ct", rev)
+	if err != nil {
+		return time.Time{}, err
+	}
+	secs, err := strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(secs, 0).UTC(), nil
+}
+
+// runGit runs git with args in dir and returns its standard output.
+func runGit(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	// Fail instead of prompting for credentials.
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return out, nil
+}
@@ -0,0 +1,250 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal/proxy"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+const vcsTestModule = `
+-- go.mod --
+module example.com/direct
+
+go 1.19
+-- README.md --
+This is a README.
+-- LICENSE --
+` + testhelper.MITLicense + `
+-- pkg/pkg.go --
+// Package pkg is fetched directly.
+package pkg
+
+// Direct reports whether the module was fetched directly.
+func Direct() bool { return true }
+`
+
+func TestVCSModuleGetter(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	repoURL := testhelper.ServeGitRepo(t, vcsTestModule, "v1.2.3")
+	g, err := NewVCSModuleGetter(t.TempDir(), source.NewClientForTesting())
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.repo = func(context.Context, string, string) (string, string, error) { return repoURL, "", nil }
+
+	const modulePath = "example.com/direct"
+	fr := FetchModule(ctx, modulePath, version.Latest, g)
+	if fr.Error != nil {
+		t.Fatal(fr.Error)
+	}
+	if got, want := fr.ResolvedVersion, "v1.2.3"; got != want {
+		t.Errorf("resolved version: got %q, want %q", got, want)
+	}
+	if want := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC); !fr.Module.CommitTime.Equal(want) {
+		t.Errorf("commit time: got %s, want %s", fr.Module.CommitTime, want)
+	}
+	if c := fr.Module.Commit; c == nil || len(c.Hash) != 40 || c.AuthorName != "gopher" || c.CommitterEmail != "gopher@example.com" {
+		t.Errorf("commit: got %+v, want gopher's", c)
+	}
+	if !fr.HasGoMod {
+		t.Error("HasGoMod = false, want true")
+	}
+	var pkgs []string
+	for _, u := range fr.Module.Units {
+		if u.IsPackage() {
+			pkgs = append(pkgs, u.Path)
+		}
+	}
+	if len(pkgs) != 1 || pkgs[0] != modulePath+"/pkg" {
+		t.Errorf("got packages %v, want [%s/pkg]", pkgs, modulePath)
+	}
+
+	// The clone is reused for a specific version.
+	fr = FetchModule(ctx, modulePath, "v1.2.3", g)
+	if fr.Error != nil {
+		t.Fatal(fr.Error)
+	}
+	if fr := FetchModule(ctx, modulePath, "v9.9.9", g); fr.Status != http.StatusNotFound {
+		t.Errorf("fetching missing version: got status %d, want %d", fr.Status, http.StatusNotFound)
+	}
+}
+
+func TestVCSModuleGetterMajorVersions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	const modulePath = "example.com/direct/v2"
+	for _, test := range []struct {
+		name     string
+		contents string
+		wantPkg  string // the package of the v2 module
+	}{
+		{
+			// The v2 module is in the v2 subdirectory of the v1 module.
+			name: "major subdirectory",
+			contents: vcsTestModule + `
+-- v2/go.mod --
+module example.com/direct/v2
+
+go 1.19
+-- v2/LICENSE --
+` + testhelper.MITLicense + `
+-- v2/sub/sub.go --
+// Package sub is in the v2 subdirectory.
+package sub
+`,
+			wantPkg: modulePath + "/sub",
+		},
+		{
+			// The v2 module replaces the v1 module at the repository root; a
+			// v2 branch would be tagged the same way.
+			name:     "major branch",
+			contents: strings.Replace(vcsTestModule, "module example.com/direct\n", "module example.com/direct/v2\n", 1),
+			wantPkg:  modulePath + "/pkg",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			repoURL := testhelper.ServeGitRepo(t, test.contents, "v2.0.0")
+			g, err := NewVCSModuleGetter(t.TempDir(), source.NewClientForTesting())
+			if err != nil {
+				t.Fatal(err)
+			}
+			// source.ModuleInfo reports the module directory with or without
+			// the major version suffix, depending on what it can find out.
+			for _, moduleDir := range []string{"", "v2"} {
+				g.repo = func(context.Context, string, string) (string, string, error) { return repoURL, moduleDir, nil }
+				fr := FetchModule(ctx, modulePath, version.Latest, g)
+				if fr.Error != nil {
+					t.Fatalf("module directory %q: %v", moduleDir, fr.Error)
+				}
+				if got, want := fr.ResolvedVersion, "v2.0.0"; got != want {
+					t.Errorf("module directory %q: resolved version: got %q, want %q", moduleDir, got, want)
+				}
+				goMod, err := g.Mod(ctx, modulePath, "v2.0.0")
+				if err != nil {
+					t.Fatal(err)
+				}
+				if want := "module " + modulePath + "\n"; !strings.HasPrefix(string(goMod), want) {
+					t.Errorf("module directory %q: got go.mod %q, want it to start with %q", moduleDir, goMod, want)
+				}
+				var pkgs []string
+				for _, u := range fr.Module.Units {
+					if u.IsPackage() {
+						pkgs = append(pkgs, u.Path)
+					}
+				}
+				if len(pkgs) != 1 || pkgs[0] != test.wantPkg {
+					t.Errorf("module directory %q: got packages %v, want [%s]", moduleDir, pkgs, test.wantPkg)
+				}
+			}
+		})
+	}
+}
+
+func TestVCSModuleGetterPseudoVersions(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	const modulePath = "example.com/direct"
+	direct, err := NewVCSModuleGetterForTesting(t.TempDir(), testhelper.ServeGitRepo(t, vcsTestModule, "v1.2.3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := direct.(*vcsModuleGetter).Commit(ctx, modulePath, "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rev := c.Hash[:12]
+	// The commit time of the test repository.
+	const ts = "20230102030405"
+
+	for _, test := range []struct {
+		vers       string
+		wantStatus int
+	}{
+		{"v0.0.0-" + ts + "-" + rev, http.StatusOK},
+		{"v1.2.4-0." + ts + "-" + rev, http.StatusOK},
+		// The timestamp is not the commit time.
+		{"v0.0.0-20230102030406-" + rev, http.StatusBadRequest},
+		// The revision is not a prefix of the commit's hash.
+		{"v0.0.0-" + ts + "-HEAD", http.StatusBadRequest},
+		// The revision is too short.
+		{"v0.0.0-" + ts + "-" + rev[:7], http.StatusBadRequest},
+		// There is no tag for the base version v1.3.0.
+		{"v1.3.1-0." + ts + "-" + rev, http.StatusBadRequest},
+		// There is no such commit.
+		{"v0.0.0-" + ts + "-abcdefabcdef", http.StatusNotFound},
+	} {
+		if fr := FetchModule(ctx, modulePath, test.vers, direct); fr.Status != test.wantStatus {
+			t.Errorf("%s: got status %d (error %v), want %d", test.vers, fr.Status, fr.Error, test.wantStatus)
+		}
+	}
+}
+
+func TestProxyModuleGetterWithDirect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	const modulePath = "example.com/direct"
+	direct, err := NewVCSModuleGetterForTesting(t.TempDir(), testhelper.ServeGitRepo(t, vcsTestModule, "v1.2.3"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The proxy serves an older version of the module only.
+	proxyServer := proxytest.NewServer([]*proxytest.Module{{
+		ModulePath: modulePath,
+		Version:    "v1.0.0",
+		Files:      map[string]string{"go.mod": "module " + modulePath, "LICENSE": testhelper.MITLicense, "a.go": "package a"},
+	}})
+	testClient, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+	newGetter := func(goproxy string) ModuleGetter {
+		t.Helper()
+		prox, err := proxy.NewFromGOPROXY(goproxy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prox.HTTPClient = testClient.HTTPClient
+		return NewProxyModuleGetterWithDirect(prox, source.NewClientForTesting(), direct)
+	}
+
+	g := newGetter("https://proxy.test,direct")
+	// The proxy serves the version it has.
+	if fr := FetchModule(ctx, modulePath, "v1.0.0", g); fr.Error != nil || fr.Module.Commit != nil {
+		t.Errorf("v1.0.0: got error %v, commit %+v; want the proxy's module", fr.Error, fr.Module.Commit)
+	}
+	// The other one is fetched directly.
+	fr := FetchModule(ctx, modulePath, "v1.2.3", g)
+	if fr.Error != nil {
+		t.Fatal(fr.Error)
+	}
+	if c := fr.Module.Commit; c == nil || len(c.Hash) != 40 {
+		t.Errorf("v1.2.3: got commit %+v, want one from the repository", c)
+	}
+	if got := proxyServer.ZipRequests(); got != 1 {
+		t.Errorf("got %d zip downloads from the proxy, want 1", got)
+	}
+
+	// Without direct in the list, the proxy's error is returned.
+	g = newGetter("https://proxy.test")
+	if fr := FetchModule(ctx, modulePath, "v1.2.3", g); fr.Status != http.StatusNotFound {
+		t.Errorf("without direct: got status %d, want %d", fr.Status, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// extractWorkspace returns a description of the go.work file at the root of
+// contentDir, or nil if there is none.
+//
+// The go command ignores go.work files in dependencies, so a go.work file
+// that is too large or can't be parsed is ignored rather than causing the
+// fetch to fail.
+func extractWorkspace(ctx context.Context, contentDir fs.FS) (_ *internal.Workspace, err error) {
+	defer derrors.Wrap(&err, "extractWorkspace")
+
+	info, err := fs.Stat(contentDir, "go.work")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if info.IsDir() || info.Size() > MaxFileSize {
+		return nil, nil
+	}
+	data, err := readFSFile(contentDir, "go.work", MaxFileSize)
+	if err != nil {
+		return nil, err
+	}
+	wf, err := modfile.ParseWork("go.work", data, nil)
+	if err != nil {
+		log.Infof(ctx, "ignoring invalid go.work file: %v", err)
+		return nil, nil
+	}
+	w := &internal.Workspace{}
+	if wf.Go != nil {
+		w.GoVersion = wf.Go.Version
+	}
+	for _, u := range wf.Use {
+		w.Dirs = append(w.Dirs, path.Clean(u.Path))
+	}
+	return w, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetch
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+func TestExtractWorkspace(t *testing.T) {
+	for _, test := range []struct {
+		name  string
+		files map[string]string
+		want  *internal.Workspace
+	}{
+		{
+			name:  "none",
+			files: map[string]string{"go.mod": "module m"},
+			want:  nil,
+		},
+		{
+			name: "use directives",
+			files: map[string]string{
+				"go.work": "go 1.19\n\nuse (\n\t.\n\t./tools/\n)\n\nuse ./cmd\n",
+			},
+			want: &internal.Workspace{GoVersion: "1.19", Dirs: []string{".", "tools", "cmd"}},
+		},
+		{
+			name:  "only at root",
+			files: map[string]string{"sub/go.work": "go 1.19\n\nuse .\n"},
+			want:  nil,
+		},
+		{
+			name:  "invalid",
+			files: map[string]string{"go.work": "not a go.work file"},
+			want:  nil,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fsys := fstest.MapFS{}
+			for name, contents := range test.files {
+				fsys[name] = &fstest.MapFile{Data: []byte(contents)}
+			}
+			got, err := extractWorkspace(context.Background(), fsys)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFetchModule_Workspace(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := &proxytest.Module{
+		ModulePath: "example.com/workspace",
+		Files: map[string]string{
+			"go.mod":  "module example.com/workspace",
+			"go.work": "go 1.19\n\nuse (\n\t.\n\t./api\n)\n",
+			"LICENSE": testhelper.MITLicense,
+			"p.go":    "// Package p is in a workspace.\npackage p\n",
+		},
+	}
+	got, _ := proxyFetcher(t, false, ctx, mod, "")
+	if got.Error != nil {
+		t.Fatal(got.Error)
+	}
+	want := &internal.Workspace{GoVersion: "1.19", Dirs: []string{".", "api"}}
+	if diff := cmp.Diff(want, got.Module.Workspace); diff != "" {
+		t.Errorf("mismatch (-want +got):\n%s", diff)
+	}
+}
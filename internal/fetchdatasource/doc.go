@@ -0,0 +1,46 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchdatasource
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc"
+)
+
+// WriteDoc fetches the package at path and writes its documentation to w as
+// plain text, in the form rendered by godoc.Package.RenderText. The module
+// path and version may be internal.UnknownModulePath and version.Latest.
+//
+// WriteDoc uses only the data source's getters, so it is suitable for
+// command-line tools that work without a database.
+func (ds *FetchDataSource) WriteDoc(ctx context.Context, w io.Writer, path, modulePath, version string) (err error) {
+	defer derrors.Wrap(&err, "FetchDataSource.WriteDoc(%q, %q, %q)", path, modulePath, version)
+
+	um, err := ds.GetUnitMeta(ctx, path, modulePath, version)
+	if err != nil {
+		return err
+	}
+	if !um.IsPackage() {
+		return fmt.Errorf("%s is not a package: %w", path, derrors.NotFound)
+	}
+	u, err := ds.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		return err
+	}
+	if len(u.Documentation) == 0 || u.Documentation[0].Source == nil {
+		return fmt.Errorf("no documentation for %s: %w", path, derrors.NotFound)
+	}
+	text, err := godoc.RenderTextFromUnit(ctx, u)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, text)
+	return err
+}
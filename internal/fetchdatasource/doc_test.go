@@ -0,0 +1,58 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchdatasource
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+func TestWriteDoc(t *testing.T) {
+	testModules := []*proxytest.Module{
+		{
+			ModulePath: "example.com/doc",
+			Files: map[string]string{
+				"LICENSE": testhelper.MITLicense,
+				"greet/greet.go": `
+					// Package greet prints greetings.
+					package greet
+
+					// Hello returns a greeting for name.
+					func Hello(name string) string { return "hello, " + name }
+				`,
+			},
+		},
+	}
+	ctx, ds, teardown := setup(t, testModules, false)
+	defer teardown()
+
+	var sb strings.Builder
+	if err := ds.WriteDoc(ctx, &sb, "example.com/doc/greet", internal.UnknownModulePath, version.Latest); err != nil {
+		t.Fatal(err)
+	}
+	got := sb.String()
+	for _, want := range []string{
+		"Package greet prints greetings.",
+		"func Hello(name string) string",
+		"Hello returns a greeting for name.",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q:\n%s", want, got)
+		}
+	}
+
+	// The module root is not a package.
+	err := ds.WriteDoc(ctx, &sb, "example.com/doc", "example.com/doc", version.Latest)
+	if !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
@@ -30,8 +30,9 @@ import (
 // FetchDataSource implements the internal.DataSource interface, by trying a list of
 // fetch.ModuleGetters to fetch modules and caching the results.
 type FetchDataSource struct {
-	opts  Options
-	cache *lru.Cache
+	opts        Options
+	cache       *lru.Cache
+	sourceFiles *lru.Cache // from sourceFileKey to []byte; see GetSourceFile
 }
 
 // Options are parameters for creating a new FetchDataSource.
@@ -51,13 +52,18 @@ func (o Options) New() *FetchDataSource {
 		// Can only happen if size is bad, and we control it.
 		panic(err)
 	}
+	sourceFiles, err := lru.New(maxCachedSourceFiles)
+	if err != nil {
+		panic(err)
+	}
 	opts := o
 	// Copy getters slice so caller doesn't modify us.
 	opts.Getters = make([]fetch.ModuleGetter, len(opts.Getters))
 	copy(opts.Getters, o.Getters)
 	return &FetchDataSource{
-		opts:  opts,
-		cache: cache,
+		opts:        opts,
+		cache:       cache,
+		sourceFiles: sourceFiles,
 	}
 }
 
@@ -267,6 +273,9 @@ func (ds *FetchDataSource) GetUnit(ctx context.Context, um *internal.UnitMeta, f
 	} else {
 		u2.Documentation = nil
 	}
+	if fields&internal.WithLicenses != 0 {
+		u2.Notice = m.Notice
+	}
 	return &u2, nil
 }
 
@@ -19,6 +19,7 @@ import (
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/licenses"
 	"golang.org/x/pkgsite/internal/proxy"
@@ -523,6 +524,55 @@ func TestGetUnit(t *testing.T) {
 	}
 }
 
+func TestGetUnitDocTree(t *testing.T) {
+	testModules := []*proxytest.Module{
+		{
+			ModulePath: "example.com/tree",
+			Files: map[string]string{
+				"LICENSE": testhelper.MITLicense,
+				"p/p.go": `
+					// Package p has a type.
+					package p
+
+					// T is a type.
+					type T int
+
+					// M is a method of T.
+					func (T) M() {}
+				`,
+			},
+		},
+	}
+	ctx, ds, teardown := setup(t, testModules, false)
+	defer teardown()
+
+	um, err := ds.GetUnitMeta(ctx, "example.com/tree/p", internal.UnknownModulePath, version.Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := ds.GetUnit(ctx, um, internal.WithDocSource, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := godoc.DocTreeFromUnit(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(tree.Symbols), 1; got != want {
+		t.Fatalf("got %d top-level symbols, want %d", got, want)
+	}
+	typ := tree.Symbols[0]
+	if typ.Name != "T" || typ.Kind != internal.SymbolKindType {
+		t.Fatalf("got symbol %s of kind %s, want type T", typ.Name, typ.Kind)
+	}
+	if len(typ.Children) != 1 || typ.Children[0].Name != "T.M" || typ.Children[0].Kind != internal.SymbolKindMethod {
+		t.Fatalf("got children %+v, want method T.M", typ.Children)
+	}
+	if m := typ.Children[0]; m.Doc != "M is a method of T.\n" || m.Filename != "p.go" || m.Line == 0 {
+		t.Errorf("got method doc %q at %s:%d", m.Doc, m.Filename, m.Line)
+	}
+}
+
 func TestBuildConstraints(t *testing.T) {
 	// The Unit returned by GetUnit should have a single Documentation that
 	// matches the BuildContext argument.
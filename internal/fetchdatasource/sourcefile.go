@@ -0,0 +1,112 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchdatasource
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+const (
+	maxCachedSourceFiles = 500
+	// Larger files are read from the module each time they are requested,
+	// to bound the memory used by the cache.
+	maxCachedSourceFileSize = 256 * 1024
+)
+
+// sourceFileKey is the key of the source file cache.
+type sourceFileKey struct {
+	internal.Modver
+	file string
+}
+
+// GetSourceFile returns the contents of a Go source file in the given module
+// version. The file is a '/'-separated path relative to the module root.
+//
+// The module is fetched, or read from the cache, as for GetUnit. Since module
+// contents are not kept after the module is processed, the file is read
+// again from the ModuleGetter that fetched the module, and cached
+// separately.
+//
+// GetSourceFile returns an error wrapping derrors.NotFound if the file does
+// not exist or is not redistributable.
+func (ds *FetchDataSource) GetSourceFile(ctx context.Context, modulePath, version, file string) (_ []byte, err error) {
+	defer derrors.Wrap(&err, "FetchDataSource.GetSourceFile(%q, %q, %q)", modulePath, version, file)
+
+	if !fs.ValidPath(file) || path.Ext(file) != ".go" {
+		return nil, fmt.Errorf("%q is not a Go file path: %w", file, derrors.InvalidArgument)
+	}
+	m, err := ds.getModule(ctx, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	if !fileIsRedistributable(m, file) {
+		return nil, fmt.Errorf("%s is not redistributable: %w", file, derrors.NotFound)
+	}
+	key := sourceFileKey{internal.Modver{Path: m.ModulePath, Version: m.Version}, file}
+	if c, ok := ds.sourceFiles.Get(key); ok {
+		return c.([]byte), nil
+	}
+
+	var contentDir fs.FS
+	if m.ModulePath == stdlib.ModulePath {
+		contentDir, _, _, err = stdlib.ContentDir(m.Version)
+	} else {
+		g, _, _ := ds.cacheGet(m.ModulePath, m.Version)
+		if g == nil {
+			return nil, fmt.Errorf("no getter for %s@%s", m.ModulePath, m.Version)
+		}
+		contentDir, err = g.ContentDir(ctx, m.ModulePath, m.Version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	info, err := fs.Stat(contentDir, file)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, derrors.NotFound)
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%s is a directory: %w", file, derrors.NotFound)
+	}
+	if info.Size() > fetch.MaxFileSize {
+		return nil, fmt.Errorf("%s is too large (%d bytes): %w", file, info.Size(), derrors.InvalidArgument)
+	}
+	contents, err := fs.ReadFile(contentDir, file)
+	if err != nil {
+		return nil, err
+	}
+	if len(contents) <= maxCachedSourceFileSize {
+		ds.sourceFiles.Add(key, contents)
+	}
+	return contents, nil
+}
+
+// fileIsRedistributable reports whether the file, relative to the root of m,
+// may be displayed. A file is redistributable if the nearest unit containing
+// it is, so that files in directories that are not units, like testdata,
+// inherit the license of the enclosing package. Files outside any unit are
+// redistributable if the module is.
+func fileIsRedistributable(m *internal.Module, file string) bool {
+	for dir := path.Dir(file); dir != "."; dir = path.Dir(dir) {
+		unitPath := m.ModulePath + "/" + dir
+		if m.ModulePath == stdlib.ModulePath {
+			unitPath = dir
+		}
+		if u := findUnit(m, unitPath); u != nil {
+			return u.IsRedistributable
+		}
+	}
+	if u := findUnit(m, m.ModulePath); u != nil {
+		return u.IsRedistributable
+	}
+	return m.IsRedistributable
+}
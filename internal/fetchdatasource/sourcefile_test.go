@@ -0,0 +1,78 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fetchdatasource
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+func TestGetSourceFile(t *testing.T) {
+	const contents = "// Package p is for testing.\npackage p\n\n// P is a function.\nfunc P() {}\n"
+	mod := &proxytest.Module{
+		ModulePath: "example.com/src",
+		Files: map[string]string{
+			"go.mod":  "module example.com/src",
+			"LICENSE": testhelper.MITLicense,
+			"p/p.go":  contents,
+		},
+	}
+	// The testdata directory is not a unit, so it takes the license of the
+	// enclosing package.
+	nonRedist := &proxytest.Module{
+		ModulePath: "example.com/unkdata",
+		Files: map[string]string{
+			"go.mod":            "module example.com/unkdata",
+			"LICENSE":           testhelper.MITLicense,
+			"unk/LICENSE.md":    "An unknown license.",
+			"unk/unk.go":        "package unk\n",
+			"unk/testdata/x.go": "package x\n",
+		},
+	}
+	ctx, ds, teardown := setup(t, append([]*proxytest.Module{mod, nonRedist}, defaultTestModules...), false)
+	defer teardown()
+
+	got, err := ds.GetSourceFile(ctx, "example.com/src", "v1.0.0", "p/p.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != contents {
+		t.Errorf("got %q, want %q", got, contents)
+	}
+	// The second read comes from the cache.
+	key := sourceFileKey{internal.Modver{Path: "example.com/src", Version: "v1.0.0"}, "p/p.go"}
+	if _, ok := ds.sourceFiles.Get(key); !ok {
+		t.Error("file was not cached")
+	}
+	got, err = ds.GetSourceFile(ctx, "example.com/src", "v1.0.0", "p/p.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != contents {
+		t.Errorf("from cache: got %q, want %q", got, contents)
+	}
+
+	for _, test := range []struct {
+		modulePath, file string
+		want             error
+	}{
+		{"example.com/src", "p/missing.go", derrors.NotFound},
+		{"example.com/src", "go.mod", derrors.InvalidArgument},
+		{"example.com/src", "../p/p.go", derrors.InvalidArgument},
+		{"example.com/nonredist", "unk/unk.go", derrors.NotFound},
+		{"example.com/unkdata", "unk/unk.go", derrors.NotFound},
+		{"example.com/unkdata", "unk/testdata/x.go", derrors.NotFound},
+	} {
+		_, err := ds.GetSourceFile(ctx, test.modulePath, "v1.0.0", test.file)
+		if !errors.Is(err, test.want) {
+			t.Errorf("%s %s: got error %v, want %v", test.modulePath, test.file, err, test.want)
+		}
+	}
+}
@@ -126,9 +126,28 @@ func (s *Server) servePathNotFoundPage(w http.ResponseWriter, r *http.Request,
 			// not successful. Do not redirect this request.
 			return errUnitNotFoundWithoutFetch
 		}
-		u := constructUnitURL(fr.goModPath, fr.goModPath, version.Latest)
+		// Keep the requested version if the canonical module has it.
+		targetVersion := version.Latest
+		if requestedVersion != version.Latest {
+			if _, err := db.GetUnitMeta(ctx, fr.goModPath, fr.goModPath, requestedVersion); err == nil {
+				targetVersion = requestedVersion
+			} else if !errors.Is(err, derrors.NotFound) {
+				log.Error(ctx, err)
+			}
+		}
+		u := constructUnitURL(fr.goModPath, fr.goModPath, targetVersion)
 		cookie.Set(w, cookie.AlternativeModuleFlash, fullPath, u)
-		http.Redirect(w, r, u, http.StatusFound)
+		// A version of a module whose go.mod file declares a different path
+		// has been superseded by that version of the canonical path, so the
+		// redirect is permanent. It is cached only briefly, in case the
+		// canonical path changes. Redirects to the latest version, and other
+		// redirects, such as to a higher major version, may change.
+		code := http.StatusFound
+		if fr.status == derrors.ToStatus(derrors.AlternativeModule) && targetVersion != version.Latest {
+			code = http.StatusPermanentRedirect
+			w.Header().Set("Cache-Control", "max-age=3600")
+		}
+		http.Redirect(w, r, u, code)
 		return nil
 	case http.StatusInternalServerError:
 		return pathNotFoundError(ctx, fullPath, requestedVersion)
@@ -138,9 +157,9 @@ func (s *Server) servePathNotFoundPage(w http.ResponseWriter, r *http.Request,
 			return
 		}
 
-		// If a module has a status of 404, but s.taskIDChangeInterval has
-		// passed, allow the module to be refetched.
-		if fr.status == http.StatusNotFound && time.Since(fr.updatedAt) > s.taskIDChangeInterval {
+		// If a module has a status of 404 or 410, but s.taskIDChangeInterval
+		// has passed, allow the module to be refetched.
+		if (fr.status == http.StatusNotFound || fr.status == http.StatusGone) && time.Since(fr.updatedAt) > s.taskIDChangeInterval {
 			return pathNotFoundError(ctx, fullPath, requestedVersion)
 		}
 
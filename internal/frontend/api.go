@@ -0,0 +1,375 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// apiUnitPathPrefix is the URL path prefix for the unit JSON API.
+const apiUnitPathPrefix = "/api/v1/unit/"
+
+// apiLicensesPathPrefix is the URL path prefix for the module licenses JSON
+// API.
+const apiLicensesPathPrefix = "/api/v1/licenses/"
+
+// apiSchemaVersion is the version of the JSON API response schema. It is
+// part of every response, and must be incremented when a field is removed or
+// its meaning changes. Adding fields does not require a new version.
+const apiSchemaVersion = 1
+
+// APIUnit is the response of the unit JSON API, served at
+// /api/v1/unit/<path>[@<version>].
+//
+// The fields query parameter selects which sections of the unit are
+// included, as a comma-separated list of "meta", "doc", "readme", "licenses"
+// and "imports". The fields of a section that isn't selected are absent from
+// the response. Without the parameter, the meta, doc and licenses sections
+// are included.
+//
+// No field contains HTML rendered by the site. Readme is the unrendered
+// source of the README, usually markdown, which may contain HTML of its own;
+// clients that render it must sanitize it. All other strings are plain text.
+type APIUnit struct {
+	SchemaVersion int `json:"schemaVersion"`
+
+	Path       string `json:"path"`
+	ModulePath string `json:"modulePath"`
+	Version    string `json:"version"`
+
+	*APIUnitMeta
+	*APIUnitDoc
+	*APIUnitReadme
+	*APIUnitLicenses
+	*APIUnitImports
+}
+
+// APIUnitMeta is the meta section of an APIUnit.
+type APIUnitMeta struct {
+	Name              string `json:"name,omitempty"`
+	IsPackage         bool   `json:"isPackage"`
+	IsModule          bool   `json:"isModule"`
+	IsCommand         bool   `json:"isCommand"`
+	IsRedistributable bool   `json:"isRedistributable"`
+
+	CommitTime time.Time `json:"commitTime"`
+
+	Deprecated          bool   `json:"deprecated,omitempty"`
+	DeprecationComment  string `json:"deprecationComment,omitempty"`
+	Retracted           bool   `json:"retracted,omitempty"`
+	RetractionRationale string `json:"retractionRationale,omitempty"`
+}
+
+// APIUnitDoc is the doc section of an APIUnit.
+type APIUnitDoc struct {
+	// Synopsis is the synopsis of the package documentation, if the unit is
+	// a package.
+	Synopsis string `json:"synopsis,omitempty"`
+
+	// PackageDeprecated reports whether the package's doc comment marks it as
+	// deprecated, independently of the module.
+	PackageDeprecated         bool   `json:"packageDeprecated,omitempty"`
+	PackageDeprecationComment string `json:"packageDeprecationComment,omitempty"`
+
+	// DocText is the documentation of the package as plain text, as stored
+	// when the package was fetched. It is empty if the documentation was
+	// not stored in that form.
+	DocText string `json:"docText,omitempty"`
+}
+
+// APIUnitReadme is the readme section of an APIUnit. Its fields are empty if
+// the unit has no README.
+type APIUnitReadme struct {
+	ReadmeFilePath string `json:"readmeFilePath,omitempty"`
+	// Readme is the unrendered contents of the README, usually markdown.
+	Readme string `json:"readme,omitempty"`
+}
+
+// APIUnitLicenses is the licenses section of an APIUnit.
+type APIUnitLicenses struct {
+	Licenses []*APILicense `json:"licenses"`
+}
+
+// APIUnitImports is the imports section of an APIUnit.
+type APIUnitImports struct {
+	Imports []string `json:"imports"`
+}
+
+// apiUnitSections maps the sections that can be selected with the fields
+// query parameter to the fields GetUnit must read for them. The meta and
+// licenses sections only need the UnitMeta.
+var apiUnitSections = map[string]internal.FieldSet{
+	"meta":     internal.MinimalFields,
+	"doc":      internal.WithMain | internal.WithDocText,
+	"readme":   internal.WithMain,
+	"licenses": internal.MinimalFields,
+	"imports":  internal.WithImports,
+}
+
+// defaultAPIUnitSections are the sections served when the fields query
+// parameter is absent.
+var defaultAPIUnitSections = []string{"meta", "doc", "licenses"}
+
+// parseAPIUnitFields parses the value of the fields query parameter. It
+// returns the set of selected sections and the fields GetUnit must read.
+func parseAPIUnitFields(value string) (sections map[string]bool, fs internal.FieldSet, err error) {
+	names := defaultAPIUnitSections
+	if value != "" {
+		names = strings.Split(value, ",")
+	}
+	sections = map[string]bool{}
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		f, ok := apiUnitSections[name]
+		if !ok {
+			return nil, 0, fmt.Errorf("unknown field %q: %w", name, derrors.InvalidArgument)
+		}
+		sections[name] = true
+		fs |= f
+	}
+	return sections, fs, nil
+}
+
+// APILicense describes a license file that applies to a unit.
+type APILicense struct {
+	Types    []string `json:"types"`
+	FilePath string   `json:"filePath"`
+}
+
+// APIModuleLicenses is the response of the module licenses JSON API, served
+// at /api/v1/licenses/<module>[@<version>]. It lists every license file in
+// the module version, including those in subdirectories.
+type APIModuleLicenses struct {
+	SchemaVersion int `json:"schemaVersion"`
+
+	ModulePath string            `json:"modulePath"`
+	Version    string            `json:"version"`
+	Licenses   []*APILicenseText `json:"licenses"`
+}
+
+// APILicenseText is a license file with its full text. Contents is empty if
+// the license is not redistributable; then only its detected types are
+// served.
+type APILicenseText struct {
+	APILicense
+	IsRedistributable bool   `json:"isRedistributable"`
+	Contents          string `json:"contents,omitempty"`
+}
+
+// apiError is the response of the JSON API when a request fails.
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// apiErrorHandler is like errorHandler, but reports errors as JSON instead of
+// serving an error page.
+func (s *Server) apiErrorHandler(f func(w http.ResponseWriter, r *http.Request, ds internal.DataSource) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ds := s.getDataSource(r.Context())
+		if err := f(w, r, ds); err != nil {
+			s.serveAPIError(w, r, err)
+		}
+	}
+}
+
+func (s *Server) serveAPIError(w http.ResponseWriter, r *http.Request, err error) {
+	ctx := r.Context()
+	status := derrors.ToStatus(err)
+	var serr *serverError
+	if errors.As(err, &serr) {
+		status = serr.status
+	}
+	msg := http.StatusText(status)
+	if uerr := new(userError); errors.As(err, &uerr) {
+		msg = uerr.userMessage
+	}
+	if status == http.StatusInternalServerError {
+		log.Error(ctx, err)
+		s.reportError(ctx, err, w, r)
+	} else {
+		log.Infof(ctx, "returning %d (%s) for error %v", status, http.StatusText(status), err)
+	}
+	writeJSON(w, r, status, &apiError{Code: status, Message: msg})
+}
+
+// serveAPIUnit handles requests for the unit JSON API. It expects paths of
+// the form "/api/v1/unit/<path>[@<version>]", using the same path and version
+// syntax as unit pages.
+func (s *Server) serveAPIUnit(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveAPIUnit(w, r, ds)")
+	defer middleware.ElapsedStat(r.Context(), "serveAPIUnit")()
+
+	ctx := r.Context()
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return &serverError{status: http.StatusMethodNotAllowed}
+	}
+	urlInfo, err := extractURLPathInfo("/" + strings.TrimPrefix(r.URL.Path, apiUnitPathPrefix))
+	if err != nil {
+		return &serverError{status: http.StatusBadRequest, err: err}
+	}
+	if !isSupportedVersion(urlInfo.fullPath, urlInfo.requestedVersion) {
+		return &serverError{
+			status: http.StatusBadRequest,
+			err:    fmt.Errorf("%q is not a valid version: %w", urlInfo.requestedVersion, derrors.InvalidArgument),
+		}
+	}
+	if err := checkExcluded(ctx, ds, urlInfo.fullPath); err != nil {
+		return err
+	}
+	sections, fields, err := parseAPIUnitFields(r.FormValue("fields"))
+	if err != nil {
+		return &serverError{status: http.StatusBadRequest, err: err}
+	}
+	um, err := ds.GetUnitMeta(ctx, urlInfo.fullPath, urlInfo.modulePath, urlInfo.requestedVersion)
+	if err != nil {
+		return err
+	}
+	bc := internal.BuildContext{GOOS: r.FormValue("GOOS"), GOARCH: r.FormValue("GOARCH")}
+	u, err := ds.GetUnit(ctx, um, fields, bc)
+	if err != nil {
+		return err
+	}
+	writeJSON(w, r, http.StatusOK, newAPIUnit(u, sections))
+	return nil
+}
+
+// serveAPILicenses handles requests for the module licenses JSON API. It
+// expects paths of the form "/api/v1/licenses/<module>[@<version>]", where
+// the version may be any version accepted on unit pages and defaults to the
+// latest.
+func (s *Server) serveAPILicenses(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveAPILicenses(w, r, ds)")
+	defer middleware.ElapsedStat(r.Context(), "serveAPILicenses")()
+
+	ctx := r.Context()
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return &serverError{status: http.StatusMethodNotAllowed}
+	}
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		// The proxydatasource does not store all the licenses of a module.
+		return datasourceNotSupportedErr()
+	}
+	modulePath, requestedVersion, found := strings.Cut(strings.TrimPrefix(r.URL.Path, apiLicensesPathPrefix), "@")
+	if !found {
+		requestedVersion = version.Latest
+	}
+	if modulePath == "" || !isSupportedVersion(modulePath, requestedVersion) {
+		return &serverError{
+			status: http.StatusBadRequest,
+			err:    fmt.Errorf("invalid module path or version in %q: %w", r.URL.Path, derrors.InvalidArgument),
+		}
+	}
+	if err := checkExcluded(ctx, ds, modulePath); err != nil {
+		return err
+	}
+	um, err := ds.GetUnitMeta(ctx, modulePath, modulePath, requestedVersion)
+	if err != nil {
+		return err
+	}
+	lics, err := db.GetAllModuleLicenses(ctx, um.ModulePath, um.Version)
+	if err != nil {
+		return err
+	}
+	resp := &APIModuleLicenses{
+		SchemaVersion: apiSchemaVersion,
+		ModulePath:    um.ModulePath,
+		Version:       um.Version,
+		Licenses:      []*APILicenseText{},
+	}
+	for _, l := range lics {
+		resp.Licenses = append(resp.Licenses, &APILicenseText{
+			APILicense:        APILicense{Types: l.Types, FilePath: l.FilePath},
+			IsRedistributable: licenses.Redistributable(l.Types),
+			Contents:          string(l.Contents),
+		})
+	}
+	writeJSON(w, r, http.StatusOK, resp)
+	return nil
+}
+
+// newAPIUnit converts a unit into its JSON API representation, with the
+// given sections.
+func newAPIUnit(u *internal.Unit, sections map[string]bool) *APIUnit {
+	au := &APIUnit{
+		SchemaVersion: apiSchemaVersion,
+		Path:          u.Path,
+		ModulePath:    u.ModulePath,
+		Version:       u.Version,
+	}
+	if sections["meta"] {
+		au.APIUnitMeta = &APIUnitMeta{
+			Name:                u.Name,
+			IsPackage:           u.IsPackage(),
+			IsModule:            u.IsModule(),
+			IsCommand:           u.IsCommand(),
+			IsRedistributable:   u.IsRedistributable,
+			CommitTime:          u.CommitTime,
+			Deprecated:          u.Deprecated,
+			DeprecationComment:  u.DeprecationComment,
+			Retracted:           u.Retracted,
+			RetractionRationale: u.RetractionRationale,
+		}
+	}
+	if sections["doc"] {
+		au.APIUnitDoc = &APIUnitDoc{
+			PackageDeprecated:         u.PackageDeprecated,
+			PackageDeprecationComment: u.PackageDeprecationComment,
+		}
+		if len(u.Documentation) > 0 {
+			au.Synopsis = u.Documentation[0].Synopsis
+			au.DocText = u.Documentation[0].Text
+		}
+	}
+	if sections["readme"] {
+		au.APIUnitReadme = &APIUnitReadme{}
+		if u.Readme != nil {
+			au.ReadmeFilePath = u.Readme.Filepath
+			au.Readme = u.Readme.Contents
+		}
+	}
+	if sections["licenses"] {
+		au.APIUnitLicenses = &APIUnitLicenses{Licenses: []*APILicense{}}
+		for _, l := range u.Licenses {
+			au.Licenses = append(au.Licenses, &APILicense{Types: l.Types, FilePath: l.FilePath})
+		}
+	}
+	if sections["imports"] {
+		au.APIUnitImports = &APIUnitImports{Imports: u.Imports}
+		if au.Imports == nil {
+			au.Imports = []string{}
+		}
+	}
+	return au
+}
+
+// writeJSON writes v to w as JSON with the given status.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		log.Errorf(r.Context(), "json.Marshal: %v", err)
+		httpErrorStatus(w, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if _, err := w.Write(data); err != nil {
+		log.Errorf(r.Context(), "w.Write: %v", err)
+	}
+}
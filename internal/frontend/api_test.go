@@ -0,0 +1,248 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+func TestServeAPIUnit(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	const docText = "package foo\n\nPackage foo is a package.\n"
+	m := sample.Module(sample.ModulePath, "v1.2.0", sample.Suffix)
+	m.Packages()[0].Documentation[0].Text = docText
+	postgres.MustInsertModule(ctx, t, testDB, m)
+	_, handler, _ := newTestServer(t, nil, nil)
+
+	wantMeta := &APIUnitMeta{
+		Name:              sample.PackageName,
+		IsPackage:         true,
+		IsRedistributable: true,
+		CommitTime:        sample.CommitTime,
+	}
+	wantLicenses := &APIUnitLicenses{
+		Licenses: []*APILicense{
+			{Types: []string{sample.LicenseType}, FilePath: sample.LicenseFilePath},
+		},
+	}
+	for _, test := range []struct {
+		name, urlPath string
+		wantStatus    int
+		want          *APIUnit
+	}{
+		{
+			name:       "package at latest",
+			urlPath:    apiUnitPathPrefix + sample.PackagePath,
+			wantStatus: http.StatusOK,
+			want: &APIUnit{
+				SchemaVersion:   apiSchemaVersion,
+				Path:            sample.PackagePath,
+				ModulePath:      sample.ModulePath,
+				Version:         "v1.2.0",
+				APIUnitMeta:     wantMeta,
+				APIUnitDoc:      &APIUnitDoc{Synopsis: sample.Doc.Synopsis, DocText: docText},
+				APIUnitLicenses: wantLicenses,
+			},
+		},
+		{
+			name:       "selected fields",
+			urlPath:    apiUnitPathPrefix + sample.PackagePath + "?fields=meta,licenses",
+			wantStatus: http.StatusOK,
+			want: &APIUnit{
+				SchemaVersion:   apiSchemaVersion,
+				Path:            sample.PackagePath,
+				ModulePath:      sample.ModulePath,
+				Version:         "v1.2.0",
+				APIUnitMeta:     wantMeta,
+				APIUnitLicenses: wantLicenses,
+			},
+		},
+		{
+			name:       "readme and imports",
+			urlPath:    apiUnitPathPrefix + sample.ModulePath + "?fields=readme,imports",
+			wantStatus: http.StatusOK,
+			want: &APIUnit{
+				SchemaVersion: apiSchemaVersion,
+				Path:          sample.ModulePath,
+				ModulePath:    sample.ModulePath,
+				Version:       "v1.2.0",
+				APIUnitReadme: &APIUnitReadme{
+					ReadmeFilePath: sample.ReadmeFilePath,
+					Readme:         sample.ReadmeContents,
+				},
+				APIUnitImports: &APIUnitImports{Imports: []string{}},
+			},
+		},
+		{
+			name:       "unknown field",
+			urlPath:    apiUnitPathPrefix + sample.PackagePath + "?fields=meta,bogus",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not found",
+			urlPath:    apiUnitPathPrefix + sample.ModulePath + "/nope",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "bad version",
+			urlPath:    apiUnitPathPrefix + sample.PackagePath + "@v1.x",
+			wantStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", test.urlPath, nil))
+			if w.Code != test.wantStatus {
+				t.Fatalf("got status %d, want %d", w.Code, test.wantStatus)
+			}
+			if got, want := w.Header().Get("Content-Type"), "application/json; charset=utf-8"; got != want {
+				t.Errorf("Content-Type: got %q, want %q", got, want)
+			}
+			if test.want == nil {
+				return
+			}
+			var got APIUnit
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, &got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServeAPILicenses(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	m := sample.Module("example.com/mit", "v1.0.0", "p")
+	sample.ReplaceLicense(m, &licenses.License{Metadata: sample.LicenseMetadata()[0], Contents: []byte(testhelper.MITLicense)})
+	postgres.MustInsertModule(ctx, t, testDB, m)
+	nonRedist := sample.Module("example.com/nonredist", "v1.0.0", "p")
+	sample.AddLicense(nonRedist, sample.NonRedistributableLicense)
+	postgres.MustInsertModule(ctx, t, testDB, nonRedist)
+	_, handler, _ := newTestServer(t, nil, nil)
+
+	for _, test := range []struct {
+		name, urlPath string
+		wantStatus    int
+		want          *APIModuleLicenses
+	}{
+		{
+			name:       "MIT",
+			urlPath:    apiLicensesPathPrefix + "example.com/mit@v1.0.0",
+			wantStatus: http.StatusOK,
+			want: &APIModuleLicenses{
+				SchemaVersion: apiSchemaVersion,
+				ModulePath:    "example.com/mit",
+				Version:       "v1.0.0",
+				Licenses: []*APILicenseText{{
+					APILicense:        APILicense{Types: []string{"MIT"}, FilePath: sample.LicenseFilePath},
+					IsRedistributable: true,
+					Contents:          testhelper.MITLicense,
+				}},
+			},
+		},
+		{
+			name:       "with non-redistributable license, at latest",
+			urlPath:    apiLicensesPathPrefix + "example.com/nonredist",
+			wantStatus: http.StatusOK,
+			want: &APIModuleLicenses{
+				SchemaVersion: apiSchemaVersion,
+				ModulePath:    "example.com/nonredist",
+				Version:       "v1.0.0",
+				Licenses: []*APILicenseText{
+					{
+						APILicense:        APILicense{Types: []string{"MIT"}, FilePath: sample.LicenseFilePath},
+						IsRedistributable: true,
+						Contents:          string(sample.Licenses()[0].Contents),
+					},
+					{
+						// Only the type of a non-redistributable license is served.
+						APILicense: APILicense{
+							Types:    sample.NonRedistributableLicense.Types,
+							FilePath: sample.NonRedistributableLicense.FilePath,
+						},
+					},
+				},
+			},
+		},
+		{
+			name:       "not found",
+			urlPath:    apiLicensesPathPrefix + "example.com/mit@v1.1.0",
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "bad version",
+			urlPath:    apiLicensesPathPrefix + "example.com/mit@v1.x",
+			wantStatus: http.StatusBadRequest,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", test.urlPath, nil))
+			if w.Code != test.wantStatus {
+				t.Fatalf("got status %d, want %d", w.Code, test.wantStatus)
+			}
+			if test.want == nil {
+				return
+			}
+			var got APIModuleLicenses
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(test.want, &got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseAPIUnitFields(t *testing.T) {
+	for _, test := range []struct {
+		value      string
+		want       []string
+		wantFields internal.FieldSet
+	}{
+		{"", []string{"doc", "licenses", "meta"}, internal.WithMain | internal.WithDocText},
+		{"meta,licenses", []string{"licenses", "meta"}, internal.MinimalFields},
+		{"readme, imports", []string{"imports", "readme"}, internal.WithMain | internal.WithImports},
+	} {
+		sections, fields, err := parseAPIUnitFields(test.value)
+		if err != nil {
+			t.Fatalf("%q: %v", test.value, err)
+		}
+		var got []string
+		for s := range sections {
+			got = append(got, s)
+		}
+		sort.Strings(got)
+		if !cmp.Equal(got, test.want) || fields != test.wantFields {
+			t.Errorf("%q: got %v, %d; want %v, %d", test.value, got, fields, test.want, test.wantFields)
+		}
+	}
+	if _, _, err := parseAPIUnitFields("meta,bogus"); !errors.Is(err, derrors.InvalidArgument) {
+		t.Errorf("unknown field: got %v, want InvalidArgument", err)
+	}
+}
@@ -5,10 +5,25 @@
 package frontend
 
 import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/version"
 )
 
+// versionBadgePathPrefix is the URL path prefix for version badges.
+// No module path starts with "version/", because the first element of a
+// module path must contain a dot, and there is no standard library package
+// by that name.
+const versionBadgePathPrefix = "/badge/version/"
+
 type badgePage struct {
 	basePage
 	// LinkPath is the URL path of the badge will link to.
@@ -20,6 +35,10 @@ type badgePage struct {
 // badgeHandler serves a Go SVG badge image for requests to /badge/<path>
 // and a badge generation tool page for requests to /badge/[?path=<path>].
 func (s *Server) badgeHandler(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(r.URL.Path, versionBadgePathPrefix) {
+		s.errorHandler(s.serveVersionBadge)(w, r)
+		return
+	}
 	path := strings.TrimPrefix(r.URL.Path, "/badge/")
 	if path != "" {
 		serveFileFS(w, r, s.staticFS, "frontend/badge/badge.svg")
@@ -41,3 +60,72 @@ func (s *Server) badgeHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	s.servePage(r.Context(), w, "badge", page)
 }
+
+// Colors of the value half of a version badge.
+const (
+	badgeColorDefault    = "#007D9C"
+	badgeColorDeprecated = "#DFB317"
+	badgeColorRetracted  = "#E05D44"
+	badgeColorUnknown    = "#9F9F9F"
+)
+
+// serveVersionBadge serves an SVG badge showing the latest version of the
+// module containing path, for requests to /badge/version/<path>.svg.
+// The badge is colored differently if the module is deprecated, or if its
+// latest version is retracted.
+func (s *Server) serveVersionBadge(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveVersionBadge(w, r, ds)")
+
+	ctx := r.Context()
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, versionBadgePathPrefix), ".svg")
+	status := http.StatusOK
+	value, color := "unknown", badgeColorUnknown
+	um, err := ds.GetUnitMeta(ctx, path, internal.UnknownModulePath, version.Latest)
+	switch {
+	case errors.Is(err, derrors.NotFound):
+		status = http.StatusNotFound
+	case err != nil:
+		return err
+	case um.Retracted:
+		value, color = um.Version+" (retracted)", badgeColorRetracted
+	case um.Deprecated:
+		value, color = um.Version+" (deprecated)", badgeColorDeprecated
+	default:
+		value, color = um.Version, badgeColorDefault
+	}
+	w.Header().Set("Content-Type", "image/svg+xml")
+	// READMEs embedding the badge are often served through image proxies,
+	// which should not keep it for long.
+	w.Header().Set("Cache-Control", "max-age=3600")
+	w.WriteHeader(status)
+	if _, err := w.Write(versionBadgeSVG("go", value, color)); err != nil {
+		log.Errorf(ctx, "w.Write: %v", err)
+	}
+	return nil
+}
+
+// versionBadgeSVG returns a flat badge with the given label and value, with
+// the value on a background of the given color.
+func versionBadgeSVG(label, value, color string) []byte {
+	// Approximate text widths for 11px Verdana, as used by most badges.
+	const charWidth, padding = 7, 10
+	lw := len(label)*charWidth + padding
+	vw := len(value)*charWidth + padding
+	esc := func(s string) string {
+		var b bytes.Buffer
+		xml.EscapeText(&b, []byte(s))
+		return b.String()
+	}
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`,
+		lw+vw, esc(label), esc(value))
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, esc(label), esc(value))
+	fmt.Fprintf(&b, `<rect width="%d" height="20" rx="3" fill="#5C5C5C"/>`, lw+vw)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" rx="3" fill="%s"/>`, lw, vw, color)
+	fmt.Fprintf(&b, `<rect x="%d" width="4" height="20" fill="%s"/>`, lw, color)
+	b.WriteString(`<g fill="#FFFFFF" text-anchor="middle" font-family="Verdana,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, lw/2, esc(label))
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, lw+vw/2, esc(value))
+	b.WriteString(`</g></svg>`)
+	return b.Bytes()
+}
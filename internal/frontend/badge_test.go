@@ -5,9 +5,14 @@
 package frontend
 
 import (
+	"context"
+	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
 )
 
 func TestBadgeHandler_ServeSVG(t *testing.T) {
@@ -63,3 +68,39 @@ func TestBadgeHandler_ServeBadgeTool(t *testing.T) {
 		})
 	}
 }
+
+func TestBadgeHandler_ServeVersionBadge(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	_, handler, teardown := newTestServer(t, nil, nil)
+	defer teardown()
+	for _, v := range []string{"v1.0.0", "v1.1.0"} {
+		postgres.MustInsertModule(ctx, t, testDB, sample.Module(sample.ModulePath, v, "foo"))
+	}
+
+	for _, test := range []struct {
+		path       string
+		wantStatus int
+		want       string
+	}{
+		{"/badge/version/" + sample.ModulePath + ".svg", http.StatusOK, ">v1.1.0</text>"},
+		{"/badge/version/" + sample.ModulePath + "/foo.svg", http.StatusOK, ">v1.1.0</text>"},
+		{"/badge/version/example.com/unknown.svg", http.StatusNotFound, ">unknown</text>"},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, test.wantStatus)
+			}
+			if got, want := w.Result().Header.Get("Content-Type"), "image/svg+xml"; got != want {
+				t.Errorf("Content-Type = %q, want %q", got, want)
+			}
+			if got := w.Body.String(); !strings.Contains(got, test.want) {
+				t.Errorf("badge does not contain %q:\n%s", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/vuln"
+)
+
+// unitPageETag returns a weak entity tag for the unit page of um served for r.
+//
+// The tag changes whenever the resolved module version or its commit time
+// changes, when the module version is reprocessed, when the latest-version
+// information or the vulnerabilities shown on the page change, and when a new
+// version of the frontend is deployed.
+func unitPageETag(r *http.Request, um *internal.UnitMeta, latest internal.LatestInfo, vulns []vuln.Vuln, appVersion string) string {
+	exps := experiment.FromContext(r.Context()).Active()
+	sort.Strings(exps)
+	var vulnIDs []string
+	for _, v := range vulns {
+		vulnIDs = append(vulnIDs, v.ID)
+	}
+	sort.Strings(vulnIDs)
+	h := sha256.New()
+	for _, s := range []string{
+		appVersion,
+		r.URL.String(),
+		strings.Join(exps, ","),
+		um.Path,
+		um.ModulePath,
+		um.Version,
+		um.CommitTime.UTC().Format(time.RFC3339Nano),
+		um.ProcessedAt.UTC().Format(time.RFC3339Nano),
+		latest.MinorVersion,
+		latest.MinorModulePath,
+		fmt.Sprint(latest.UnitExistsAtMinor),
+		latest.MajorModulePath,
+		latest.MajorUnitPath,
+		strings.Join(vulnIDs, ","),
+	} {
+		fmt.Fprintf(h, "%s\x00", s)
+	}
+	return fmt.Sprintf(`W/"%x"`, h.Sum(nil)[:16])
+}
+
+// setValidators sets the ETag and Last-Modified headers of h. They should
+// only be set on 200 and 304 responses, since they describe the page.
+//
+// A zero lastModified omits the Last-Modified header.
+func setValidators(h http.Header, etag string, lastModified time.Time) {
+	h.Set("ETag", etag)
+	if !lastModified.IsZero() {
+		h.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+}
+
+// checkNotModified evaluates the conditional headers of r against etag and
+// lastModified as described in RFC 9110, section 13. If the client's copy is
+// current, it writes a 304 Not Modified response with the ETag and
+// Last-Modified headers and returns true. Otherwise it leaves w alone, and
+// the caller should set the headers with setValidators once it knows the page
+// can be served.
+//
+// A zero lastModified is never matched by If-Modified-Since.
+func checkNotModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	// If-None-Match takes precedence over If-Modified-Since.
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatches(inm, etag) {
+			return false
+		}
+	} else {
+		ims := r.Header.Get("If-Modified-Since")
+		if ims == "" || lastModified.IsZero() {
+			return false
+		}
+		t, err := http.ParseTime(ims)
+		if err != nil {
+			return false
+		}
+		// HTTP dates have a resolution of one second.
+		if lastModified.Truncate(time.Second).After(t) {
+			return false
+		}
+	}
+	// A 304 response must not carry a body or content headers.
+	h := w.Header()
+	setValidators(h, etag, lastModified)
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// etagMatches reports whether the value of an If-None-Match header matches
+// etag, using the weak comparison function.
+func etagMatches(header, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
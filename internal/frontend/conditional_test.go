@@ -0,0 +1,137 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestCheckNotModified(t *testing.T) {
+	const etag = `W/"abc"`
+	modTime := time.Date(2019, 1, 30, 0, 0, 0, 0, time.UTC)
+	for _, test := range []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{"no conditions", nil, false},
+		{"etag match", map[string]string{"If-None-Match": `W/"abc"`}, true},
+		{"strong etag match", map[string]string{"If-None-Match": `"abc"`}, true},
+		{"etag in list", map[string]string{"If-None-Match": `"x", W/"abc"`}, true},
+		{"star", map[string]string{"If-None-Match": "*"}, true},
+		{"etag mismatch", map[string]string{"If-None-Match": `W/"def"`}, false},
+		{"same time", map[string]string{"If-Modified-Since": modTime.Format(http.TimeFormat)}, true},
+		{"later time", map[string]string{"If-Modified-Since": modTime.Add(time.Hour).Format(http.TimeFormat)}, true},
+		{"earlier time", map[string]string{"If-Modified-Since": modTime.Add(-time.Hour).Format(http.TimeFormat)}, false},
+		{"bad time", map[string]string{"If-Modified-Since": "yesterday"}, false},
+		{
+			"etag takes precedence",
+			map[string]string{
+				"If-None-Match":     `W/"def"`,
+				"If-Modified-Since": modTime.Format(http.TimeFormat),
+			},
+			false,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/p", nil)
+			for k, v := range test.headers {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			got := checkNotModified(w, r, etag, modTime)
+			if got != test.want {
+				t.Fatalf("got %t, want %t", got, test.want)
+			}
+			if got && w.Code != http.StatusNotModified {
+				t.Errorf("got status %d, want %d", w.Code, http.StatusNotModified)
+			}
+			// The validators are only set on 304 responses; the caller sets
+			// them on others once it knows the page can be served.
+			wantETag, wantLastModified := "", ""
+			if got {
+				wantETag, wantLastModified = etag, modTime.Format(http.TimeFormat)
+			}
+			if g := w.Header().Get("ETag"); g != wantETag {
+				t.Errorf("ETag: got %q, want %q", g, wantETag)
+			}
+			if g := w.Header().Get("Last-Modified"); g != wantLastModified {
+				t.Errorf("Last-Modified: got %q, want %q", g, wantLastModified)
+			}
+		})
+	}
+}
+
+func TestUnitPageNotModified(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	_, handler, teardown := newTestServer(t, nil, nil)
+	defer teardown()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, sample.Suffix)
+	postgres.MustInsertModule(ctx, t, testDB, m)
+
+	get := func(headers map[string]string) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest("GET", "/"+sample.ModulePath+"@"+sample.VersionString, nil)
+		for k, v := range headers {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	w := get(nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+	}
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("missing ETag header")
+	}
+	// The page was last modified when the module was processed.
+	lastModified := w.Header().Get("Last-Modified")
+	um, err := testDB.GetUnitMeta(ctx, sample.ModulePath, sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := um.ProcessedAt.UTC().Format(http.TimeFormat); lastModified != want {
+		t.Errorf("Last-Modified: got %q, want %q", lastModified, want)
+	}
+
+	for _, headers := range []map[string]string{
+		{"If-None-Match": etag},
+		{"If-Modified-Since": lastModified},
+	} {
+		w := get(headers)
+		if w.Code != http.StatusNotModified {
+			t.Errorf("%v: got status %d, want %d", headers, w.Code, http.StatusNotModified)
+		}
+		if w.Body.Len() != 0 {
+			t.Errorf("%v: got non-empty body", headers)
+		}
+	}
+
+	// A stale entity tag gets the full page.
+	if w := get(map[string]string{"If-None-Match": `W/"stale"`}); w.Code != http.StatusOK {
+		t.Errorf("stale ETag: got status %d, want %d", w.Code, http.StatusOK)
+	}
+
+	// Reprocessing the module changes the page, and so its entity tag.
+	postgres.MustInsertModule(ctx, t, testDB, m)
+	if w := get(map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("after reprocessing: got status %d, want %d", w.Code, http.StatusOK)
+	}
+}
@@ -25,12 +25,14 @@ import (
 	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/middleware"
 	"golang.org/x/pkgsite/internal/postgres"
 	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/queue"
 	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 	"golang.org/x/pkgsite/internal/version"
+	"golang.org/x/pkgsite/internal/xcontext"
 )
 
 var (
@@ -114,6 +116,82 @@ func (s *Server) serveFetch(w http.ResponseWriter, r *http.Request, ds internal.
 	return nil
 }
 
+// fetchOnDemand fetches the unit described by info, which is not in the
+// database, if on-demand fetching is enabled and the unit's path has never
+// been fetched. Since robots.txt disallows crawlers only from /fetch, and any
+// path can be requested, fetches are not made for crawlers, and are limited
+// per client by s.onDemandFetchLimiter. It waits up to s.onDemandFetchTimeout
+// for the fetch, and returns the fetched unit's metadata so that the page can
+// be rendered immediately.
+//
+// fetchOnDemand returns nil, nil if the unit was not fetched, either because
+// on-demand fetching is disabled or not allowed for r, or because the fetch
+// failed; the caller should then serve the not-found page, which explains any
+// failure. If the fetch takes too long, fetchOnDemand returns an error that
+// serves a page asking the user to check back later, while the worker
+// finishes the fetch.
+//
+// If several users request the same page at once, only one fetch is run, and
+// the others wait for its result.
+func (s *Server) fetchOnDemand(ctx context.Context, r *http.Request, ds internal.DataSource, info *urlPathInfo) (_ *internal.UnitMeta, err error) {
+	defer derrors.Wrap(&err, "fetchOnDemand(%q, %q, %q)", info.fullPath, info.modulePath, info.requestedVersion)
+
+	if s.onDemandFetchTimeout <= 0 || s.queue == nil || stdlib.Contains(info.fullPath) || isCrawler(r.UserAgent()) {
+		return nil, nil
+	}
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		return nil, nil
+	}
+	// Only fetch paths that have never been fetched. Earlier failures are
+	// explained by the not-found page.
+	if _, err := previousFetchStatusAndResponse(ctx, db, info.fullPath, info.modulePath, info.requestedVersion); !errors.Is(err, derrors.NotFound) {
+		return nil, nil
+	}
+	if s.onDemandFetchLimiter != nil {
+		// Fail open if the client can't be identified, like RateLimit.
+		if key := middleware.ClientKey(r); key != "" {
+			if allowed, _ := s.onDemandFetchLimiter.Allow(ctx, key); !allowed {
+				log.Infof(ctx, "fetchOnDemand: rate limit reached for %s", info.fullPath)
+				return nil, nil
+			}
+		}
+	}
+
+	// The shared fetch must not be canceled with the request that started
+	// it, since others may be waiting for it. It runs with the values of that
+	// request's context but only its own timeout.
+	type result struct {
+		status   int
+		timedOut bool
+	}
+	key := info.fullPath + "|" + info.modulePath + "|" + info.requestedVersion
+	v, _, _ := s.onDemandFetches.Do(key, func() (any, error) {
+		fctx, cancel := context.WithTimeout(xcontext.Detach(ctx), s.onDemandFetchTimeout)
+		defer cancel()
+		status, _ := s.fetchAndPoll(fctx, ds, info.modulePath, info.fullPath, info.requestedVersion)
+		// Any failure after the timeout has passed may be caused by it.
+		return result{status, status == http.StatusRequestTimeout || fctx.Err() != nil}, nil
+	})
+	switch res := v.(result); {
+	case res.status == http.StatusOK:
+		return ds.GetUnitMeta(ctx, info.fullPath, info.modulePath, info.requestedVersion)
+	case res.timedOut:
+		return nil, &serverError{
+			status: http.StatusAccepted,
+			epage:  &errorPage{MessageData: stillProcessingMessage(info.fullPath, info.requestedVersion)},
+		}
+	default:
+		return nil, nil
+	}
+}
+
+// stillProcessingMessage returns the message telling the user that the
+// fetch of fullPath at requestedVersion has not finished.
+func stillProcessingMessage(fullPath, requestedVersion string) string {
+	return fmt.Sprintf("We're still working on “%s”. Check back in a few minutes!", displayPath(fullPath, requestedVersion))
+}
+
 type fetchResult struct {
 	modulePath   string
 	goModPath    string
@@ -250,7 +328,7 @@ func resultFromFetchRequest(results []*fetchResult, fullPath, requestedVersion s
 			// If the context timed out or was canceled before all of the requests
 			// finished, return an error letting the user to check back later. The
 			// worker will still be processing the modules in the background.
-			fr.responseText = fmt.Sprintf("We're still working on “%s”. Check back in a few minutes!", displayPath(fullPath, requestedVersion))
+			fr.responseText = stillProcessingMessage(fullPath, requestedVersion)
 			return fr, nil
 		case http.StatusInternalServerError:
 			fr.responseText = "Oops! Something went wrong."
@@ -414,6 +492,7 @@ func checkForPath(ctx context.Context, db *postgres.DB,
 
 	switch fr.status {
 	case http.StatusNotFound,
+		http.StatusGone,
 		derrors.ToStatus(derrors.DBModuleInsertInvalid),
 		http.StatusInternalServerError:
 		if time.Since(vm.UpdatedAt) > taskIDChangeInterval {
@@ -7,7 +7,11 @@ package frontend
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,6 +20,7 @@ import (
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/postgres"
 	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/queue"
 	"golang.org/x/pkgsite/internal/testing/sample"
 	"golang.org/x/pkgsite/internal/testing/testhelper"
 	"golang.org/x/pkgsite/internal/version"
@@ -223,3 +228,147 @@ func TestCandidateModulePaths(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchOnDemand(t *testing.T) {
+	path := "/" + testModulePath + "/bar/foo"
+	get := func(handler http.Handler, userAgent string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", path, nil)
+		r.Header.Set("User-Agent", userAgent)
+		handler.ServeHTTP(w, r)
+		return w
+	}
+	enable := func(scfg *ServerConfig) { scfg.OnDemandFetchTimeout = testFetchTimeout }
+
+	t.Run("disabled", func(t *testing.T) {
+		_, handler, teardown := newTestServer(t, testModulesForProxy, nil)
+		defer teardown()
+		if w := get(handler, ""); w.Code != http.StatusNotFound {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("slow fetch", func(t *testing.T) {
+		// If the fetch times out, the page says that the path is being
+		// processed.
+		var q *queue.InMemory
+		_, handler, teardown := newTestServerWithConfig(t, testModulesForProxy, nil, func(scfg *ServerConfig) {
+			scfg.OnDemandFetchTimeout = time.Nanosecond
+			q = scfg.Queue.(*queue.InMemory)
+		})
+		defer teardown()
+		w := get(handler, "")
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusAccepted)
+		}
+		if want := "still working on"; !strings.Contains(w.Body.String(), want) {
+			t.Errorf("body does not contain %q", want)
+		}
+		// Let the fetch finish before the database is reset.
+		q.WaitForTesting(context.Background())
+	})
+
+	t.Run("crawler", func(t *testing.T) {
+		_, handler, teardown := newTestServerWithConfig(t, testModulesForProxy, nil, enable)
+		defer teardown()
+		if w := get(handler, "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"); w.Code != http.StatusNotFound {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		_, handler, teardown := newTestServerWithConfig(t, testModulesForProxy, nil, func(scfg *ServerConfig) {
+			enable(scfg)
+			scfg.OnDemandFetchRateLimiter = denyingRateLimiter{}
+		})
+		defer teardown()
+		if w := get(handler, ""); w.Code != http.StatusNotFound {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+
+	t.Run("fetched", func(t *testing.T) {
+		// Otherwise the path is fetched and rendered on its first view.
+		_, handler, teardown := newTestServerWithConfig(t, testModulesForProxy, nil, enable)
+		defer teardown()
+		w := get(handler, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+		}
+		if want := `id="Foo"`; !strings.Contains(w.Body.String(), want) {
+			t.Errorf("body does not contain %q", want)
+		}
+	})
+}
+
+// denyingRateLimiter is a middleware.RateLimiter that allows no requests.
+type denyingRateLimiter struct{}
+
+func (denyingRateLimiter) Allow(context.Context, string) (bool, time.Duration) {
+	return false, time.Minute
+}
+
+// countingQueue is a queue.Queue that counts the fetches it schedules.
+type countingQueue struct {
+	queue.Queue
+	n atomic.Int32
+
+	mu     sync.Mutex
+	counts map[internal.Modver]int // number of fetches of each module version
+}
+
+func (q *countingQueue) ScheduleFetch(ctx context.Context, modulePath, version string, opts *queue.Options) (bool, error) {
+	q.n.Add(1)
+	q.mu.Lock()
+	if q.counts == nil {
+		q.counts = map[internal.Modver]int{}
+	}
+	q.counts[internal.Modver{Path: modulePath, Version: version}]++
+	q.mu.Unlock()
+	return q.Queue.ScheduleFetch(ctx, modulePath, version, opts)
+}
+
+func TestFetchOnDemandConcurrent(t *testing.T) {
+	var q *countingQueue
+	_, handler, teardown := newTestServerWithConfig(t, testModulesForProxy, nil, func(scfg *ServerConfig) {
+		q = &countingQueue{Queue: scfg.Queue}
+		scfg.Queue = q
+		scfg.OnDemandFetchTimeout = testFetchTimeout
+	})
+	defer teardown()
+
+	const numRequests = 5
+	var wg sync.WaitGroup
+	codes := make([]int, numRequests)
+	for i := 0; i < numRequests; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", "/"+testModulePath+"/bar/foo", nil))
+			codes[i] = w.Code
+		}()
+	}
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("request %d: got status %d, want %d", i, code, http.StatusOK)
+		}
+	}
+	// Each candidate module path is fetched once, however many requests
+	// there are.
+	candidates, err := candidateModulePaths(testModulePath + "/bar/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := int(q.n.Load()); got != len(candidates) {
+		t.Errorf("got %d fetches scheduled, want %d", got, len(candidates))
+	}
+	for mv, n := range q.counts {
+		if n != 1 {
+			t.Errorf("%s: got %d fetches scheduled, want 1", mv, n)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+// goModPathPrefix is the URL path prefix for go.mod files, which are served
+// at /gomod/<module>@<version>.
+const goModPathPrefix = "/gomod/"
+
+// serveGoMod serves the go.mod file of a module version as plain text.
+func (s *Server) serveGoMod(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveGoMod(w, r, ds)")
+
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		// The proxydatasource does not store go.mod files.
+		return datasourceNotSupportedErr()
+	}
+	modulePath, version, found := strings.Cut(strings.TrimPrefix(r.URL.Path, goModPathPrefix), "@")
+	if !found || modulePath == "" || version == "" {
+		return &serverError{
+			status: http.StatusBadRequest,
+			err:    errors.New("path must be of the form /gomod/<module>@<version>"),
+		}
+	}
+	contents, err := db.GetGoMod(r.Context(), modulePath, version)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if _, err := w.Write(contents); err != nil {
+		log.Errorf(r.Context(), "w.Write: %v", err)
+	}
+	return nil
+}
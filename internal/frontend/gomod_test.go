@@ -0,0 +1,55 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeGoMod(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	_, handler, teardown := newTestServer(t, nil, nil)
+	defer teardown()
+
+	const goMod = "module example.com/gomod\n\ngo 1.19\n\nrequire example.com/dep v1.2.3\n"
+	m := sample.Module("example.com/gomod", "v1.0.0", "p")
+	m.GoMod = []byte(goMod)
+	postgres.MustInsertModule(ctx, t, testDB, m)
+
+	for _, test := range []struct {
+		path       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"/gomod/example.com/gomod@v1.0.0", http.StatusOK, goMod},
+		{"/gomod/example.com/gomod@v1.1.0", http.StatusNotFound, ""},
+		{"/gomod/example.com/gomod", http.StatusBadRequest, ""},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+		if w.Code != test.wantStatus {
+			t.Errorf("%s: got status %d, want %d", test.path, w.Code, test.wantStatus)
+			continue
+		}
+		if test.wantStatus != http.StatusOK {
+			continue
+		}
+		if got := w.Body.String(); got != test.wantBody {
+			t.Errorf("%s: got body %q, want %q", test.path, got, test.wantBody)
+		}
+		if got, want := w.Header().Get("Content-Type"), "text/plain; charset=utf-8"; got != want {
+			t.Errorf("%s: got Content-Type %q, want %q", test.path, got, want)
+		}
+	}
+}
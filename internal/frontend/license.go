@@ -22,9 +22,17 @@ type License struct {
 	Source string
 }
 
+// Notice contains information used for the NOTICE file section.
+type Notice struct {
+	*internal.Notice
+	Source string
+}
+
 // LicensesDetails contains license information for a package or module.
 type LicensesDetails struct {
 	Licenses []License
+	// Notice is the module's NOTICE file, if any.
+	Notice *Notice
 }
 
 // LicenseMetadata contains license metadata that is used in the package
@@ -41,7 +49,14 @@ func fetchLicensesDetails(ctx context.Context, ds internal.DataSource, um *inter
 	if err != nil {
 		return nil, err
 	}
-	return &LicensesDetails{Licenses: transformLicenses(um.ModulePath, um.Version, u.LicenseContents)}, nil
+	ld := &LicensesDetails{Licenses: transformLicenses(um.ModulePath, um.Version, u.LicenseContents)}
+	if u.Notice != nil {
+		ld.Notice = &Notice{
+			Notice: u.Notice,
+			Source: fileSource(um.ModulePath, um.Version, u.Notice.Filepath),
+		}
+	}
+	return ld, nil
 }
 
 // transformLicenses transforms licenses.License into a License
@@ -40,6 +40,10 @@ type MainDetails struct {
 	// Readme is the rendered readme HTML.
 	Readme safehtml.HTML
 
+	// Changelog is the rendered HTML of the module's changelog, shown on
+	// the module root only.
+	Changelog safehtml.HTML
+
 	// ReadmeOutline is a collection of headings from the readme file
 	// used to render the readme outline in the sidebar.
 	ReadmeOutline []*Heading
@@ -67,10 +71,18 @@ type MainDetails struct {
 	MobileOutline safehtml.HTML
 	IsPackage     bool
 
+	// DocumentationPurged reports whether the documentation of the package
+	// was purged and must be fetched again to be displayed.
+	DocumentationPurged bool
+
 	// DocSynopsis is used as the content for the <meta name="Description">
 	// tag on the main unit page.
 	DocSynopsis string
 
+	// Usage is the usage synopsis of a command, shown above its
+	// documentation.
+	Usage string
+
 	// GOOS and GOARCH are the build context for the doc.
 	GOOS, GOARCH string
 
@@ -113,6 +125,15 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 	if err != nil {
 		return nil, err
 	}
+	if len(unit.Documentation) == 0 && bc != (internal.BuildContext{}) && um.IsPackage() {
+		// There is no documentation for the requested build context, so
+		// fall back to the preferred one, which is linux/amd64 if the
+		// package has it.
+		unit, err = ds.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+		if err != nil {
+			return nil, err
+		}
+	}
 	subdirectories := getSubdirectories(um, unit.Subdirectories, requestedVersion)
 	if err != nil {
 		return nil, err
@@ -125,11 +146,16 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 	if err != nil {
 		return nil, err
 	}
+	changelog, err := changelogContent(ctx, unit)
+	if err != nil {
+		return nil, err
+	}
 	var (
 		docParts           = &dochtml.Parts{}
 		docLinks, modLinks []link
 		files              []*File
 		synopsis           string
+		usage              string
 		goos, goarch       string
 		buildContexts      []internal.BuildContext
 	)
@@ -143,6 +169,7 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 
 	if doc != nil {
 		synopsis = doc.Synopsis
+		usage = doc.Usage
 		goos = doc.GOOS
 		goarch = doc.GOARCH
 		buildContexts = unit.BuildContexts
@@ -202,6 +229,7 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 		Licenses:          transformLicenseMetadata(um.Licenses),
 		CommitTime:        absoluteTime(um.CommitTime),
 		Readme:            readme.HTML,
+		Changelog:         changelog.HTML,
 		ReadmeOutline:     readme.Outline,
 		ReadmeLinks:       readme.Links,
 		DocLinks:          docLinks,
@@ -209,6 +237,7 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 		DocOutline:        docParts.Outline,
 		DocBody:           docParts.Body,
 		DocSynopsis:       synopsis,
+		Usage:             usage,
 		GOOS:              goos,
 		GOARCH:            goarch,
 		BuildContexts:     buildContexts,
@@ -222,6 +251,8 @@ func fetchMainDetails(ctx context.Context, ds internal.DataSource, um *internal.
 		ModFileURL:        um.SourceInfo.ModuleURL() + "/go.mod",
 		IsTaggedVersion:   isTaggedVersion,
 		IsStableVersion:   isStableVersion,
+
+		DocumentationPurged: unit.DocumentationPurged,
 	}, nil
 }
 
@@ -260,6 +291,19 @@ func readmeContent(ctx context.Context, u *internal.Unit) (_ *Readme, err error)
 	return ProcessReadme(ctx, u)
 }
 
+// changelogContent renders the changelog of u, if it has one, in the same way
+// as a readme.
+func changelogContent(ctx context.Context, u *internal.Unit) (_ *Readme, err error) {
+	defer derrors.Wrap(&err, "changelogContent(%q, %q, %q)", u.Path, u.ModulePath, u.Version)
+	if !u.IsRedistributable || u.Changelog == nil {
+		return &Readme{}, nil
+	}
+	return processReadme(ctx, &internal.Readme{
+		Filepath: u.Changelog.Filepath,
+		Contents: u.Changelog.Contents,
+	}, u.SourceInfo)
+}
+
 const missingDocReplacement = `<p>Documentation is missing.</p>`
 
 func getHTML(ctx context.Context, u *internal.Unit, docPkg *godoc.Package,
@@ -0,0 +1,73 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"encoding/xml"
+	"net/http"
+
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// openSearchXMLNS is the XML namespace of OpenSearch description documents.
+const openSearchXMLNS = "http://a9.com/-/spec/opensearch/1.1/"
+
+// openSearchDescription is an OpenSearch description document, as described
+// at https://github.com/dewitt/opensearch.
+type openSearchDescription struct {
+	XMLName       xml.Name        `xml:"OpenSearchDescription"`
+	XMLNS         string          `xml:"xmlns,attr"`
+	ShortName     string          `xml:"ShortName"`
+	Description   string          `xml:"Description"`
+	InputEncoding string          `xml:"InputEncoding"`
+	Image         openSearchImage `xml:"Image"`
+	URL           openSearchURL   `xml:"Url"`
+}
+
+type openSearchImage struct {
+	Width  int    `xml:"width,attr"`
+	Height int    `xml:"height,attr"`
+	Type   string `xml:"type,attr"`
+	URL    string `xml:",chardata"`
+}
+
+type openSearchURL struct {
+	Type     string `xml:"type,attr"`
+	Method   string `xml:"method,attr"`
+	Template string `xml:"template,attr"`
+}
+
+// openSearchHandler serves the OpenSearch description document, which lets
+// browsers add the site's search as a search engine.
+func (s *Server) openSearchHandler(w http.ResponseWriter, r *http.Request) {
+	base := s.siteURL(r)
+	d := &openSearchDescription{
+		XMLNS:         openSearchXMLNS,
+		ShortName:     "Go Packages",
+		Description:   "Search for Go packages",
+		InputEncoding: "UTF-8",
+		Image: openSearchImage{
+			Width:  16,
+			Height: 16,
+			Type:   "image/x-icon",
+			URL:    base + "/favicon.ico",
+		},
+		URL: openSearchURL{
+			Type:     "text/html",
+			Method:   "get",
+			Template: base + "/search?q={searchTerms}",
+		},
+	}
+	data, err := xml.Marshal(d)
+	if err != nil {
+		log.Errorf(r.Context(), "xml.Marshal: %v", err)
+		httpErrorStatus(w, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/opensearchdescription+xml; charset=utf-8")
+	if _, err := w.Write(append([]byte(xml.Header), data...)); err != nil {
+		log.Errorf(r.Context(), "w.Write: %v", err)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOpenSearchHandler(t *testing.T) {
+	_, handler, teardown := newTestServer(t, nil, nil)
+	defer teardown()
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "https://pkg.go.dev/opensearch.xml", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if got := w.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/opensearchdescription+xml") {
+		t.Errorf("Content-Type = %q, want application/opensearchdescription+xml", got)
+	}
+	var d openSearchDescription
+	if err := xml.Unmarshal(w.Body.Bytes(), &d); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, w.Body)
+	}
+	if d.XMLName.Space != openSearchXMLNS {
+		t.Errorf("namespace = %q, want %q", d.XMLName.Space, openSearchXMLNS)
+	}
+	if got, want := d.URL.Template, "https://pkg.go.dev/search?q={searchTerms}"; got != want {
+		t.Errorf("template = %q, want %q", got, want)
+	}
+}
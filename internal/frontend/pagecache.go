@@ -0,0 +1,106 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// A PageCache is an in-process cache of rendered unit pages.
+//
+// Entries are grouped by the series path of the module they belong to, so
+// that all pages of a module can be invalidated when any of its versions is
+// inserted into the database. See InvalidateModule. Only insertions made
+// through the same postgres.DB, by hooks added with AddInsertModuleHook, can
+// invalidate entries; pages of modules inserted by the worker or another
+// process stay in the cache until they expire.
+type PageCache struct {
+	lru *lru.Cache // from pageCacheKey to *pageCacheEntry
+
+	// ttl computes the time an entry should remain in the cache.
+	ttl func(*http.Request) time.Duration
+}
+
+type pageCacheKey struct {
+	seriesPath  string
+	url         string
+	experiments string
+}
+
+type pageCacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// NewPageCache returns a PageCache that holds at most size pages.
+// Pages expire after the same time they would in the Redis cache.
+func NewPageCache(size int) (*PageCache, error) {
+	c, err := lru.New(size)
+	if err != nil {
+		return nil, err
+	}
+	return &PageCache{lru: c, ttl: detailsTTL}, nil
+}
+
+// InvalidateModule removes all cached pages for modules in the series of
+// modulePath. It is called when modulePath@version is inserted into the
+// database. Invalidating the entire series, rather than just the given
+// version, keeps the latest-version information on other pages current.
+//
+// InvalidateModule can be used as a postgres.InsertModuleHook.
+func (c *PageCache) InvalidateModule(ctx context.Context, modulePath, version string) {
+	seriesPath := internal.SeriesPathForModule(modulePath)
+	n := 0
+	for _, k := range c.lru.Keys() {
+		if k.(pageCacheKey).seriesPath == seriesPath {
+			c.lru.Remove(k)
+			n++
+		}
+	}
+	if n > 0 {
+		log.Debugf(ctx, "page cache: removed %d pages for %s@%s", n, modulePath, version)
+	}
+}
+
+// cacheKey returns the key for the page of um served for r, and reports
+// whether the page may be cached at all.
+func (c *PageCache) cacheKey(r *http.Request, um *internal.UnitMeta, requestedVersion string) (pageCacheKey, bool) {
+	if !isStableUnitPage(r, requestedVersion) {
+		return pageCacheKey{}, false
+	}
+	exps := experiment.FromContext(r.Context()).Active()
+	sort.Strings(exps)
+	return pageCacheKey{
+		seriesPath:  internal.SeriesPathForModule(um.ModulePath),
+		url:         r.URL.String(),
+		experiments: strings.Join(exps, ","),
+	}, true
+}
+
+func (c *PageCache) get(key pageCacheKey) ([]byte, bool) {
+	v, ok := c.lru.Get(key)
+	if !ok {
+		return nil, false
+	}
+	e := v.(*pageCacheEntry)
+	if time.Now().After(e.expires) {
+		c.lru.Remove(key)
+		return nil, false
+	}
+	return e.body, true
+}
+
+func (c *PageCache) put(r *http.Request, key pageCacheKey, body []byte) {
+	c.lru.Add(key, &pageCacheEntry{body: body, expires: time.Now().Add(c.ttl(r))})
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestPageCache(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	defer postgres.ResetTestDB(testDB, t)
+
+	pc, err := NewPageCache(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.AddInsertModuleHook(pc.InvalidateModule)()
+	_, handler, teardown := newTestServerWithConfig(t, nil, nil, func(cfg *ServerConfig) {
+		cfg.PageCache = pc
+	})
+	defer teardown()
+
+	insert := func(readme string) {
+		m := sample.Module(sample.ModulePath, sample.VersionString, sample.Suffix)
+		m.Units[0].Readme = &internal.Readme{Filepath: sample.ReadmeFilePath, Contents: readme}
+		postgres.MustInsertModule(ctx, t, testDB, m)
+	}
+	get := func() string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/"+sample.ModulePath+"@"+sample.VersionString, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", w.Code, http.StatusOK)
+		}
+		return w.Body.String()
+	}
+
+	insert("original readme")
+	if body := get(); !strings.Contains(body, "original readme") {
+		t.Fatal("first request: missing original readme")
+	}
+
+	// Change the readme behind InsertModule's back. The second request should
+	// be served from the cache.
+	if _, err := testDB.Underlying().Exec(ctx, `UPDATE readmes SET contents = 'sneaky readme'`); err != nil {
+		t.Fatal(err)
+	}
+	if body := get(); !strings.Contains(body, "original readme") {
+		t.Error("second request: page not served from cache")
+	}
+
+	// Re-inserting the module should invalidate the cached page.
+	insert("updated readme")
+	if body := get(); !strings.Contains(body, "updated readme") {
+		t.Error("after re-insert: got stale page")
+	}
+
+	// Pages whose documentation was purged should not be cached, so that
+	// each request can schedule the fetch that restores it.
+	insert("purged readme")
+	if err := testDB.PurgeDocumentation(ctx, sample.ModulePath, sample.VersionString); err != nil {
+		t.Fatal(err)
+	}
+	if body := get(); !strings.Contains(body, "purged readme") {
+		t.Fatal("after purge: missing purged readme")
+	}
+	if _, err := testDB.Underlying().Exec(ctx, `UPDATE readmes SET contents = 'sneaky readme'`); err != nil {
+		t.Fatal(err)
+	}
+	if body := get(); !strings.Contains(body, "sneaky readme") {
+		t.Error("after purge: page served from cache")
+	}
+}
@@ -25,10 +25,12 @@ import (
 	"github.com/go-redis/redis/v8"
 	"github.com/google/safehtml"
 	"github.com/google/safehtml/template"
+	lru "github.com/hashicorp/golang-lru"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/licenses"
 	"golang.org/x/pkgsite/internal/log"
@@ -38,6 +40,7 @@ import (
 	"golang.org/x/pkgsite/internal/static"
 	"golang.org/x/pkgsite/internal/version"
 	"golang.org/x/pkgsite/internal/vuln"
+	"golang.org/x/sync/singleflight"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -62,13 +65,24 @@ type Server struct {
 	reportingClient      *errorreporting.Client
 	fileMux              *http.ServeMux
 	vulnClient           *vuln.Client
+	pageCache            *PageCache
+	rateLimiter          middleware.RateLimiter
+	onDemandFetchTimeout time.Duration
+	onDemandFetchLimiter middleware.RateLimiter
+	onDemandFetches      singleflight.Group // keyed by path, module path and version
+	baseURL              string             // scheme and host, without a trailing slash
 	versionID            string
 	instanceID           string
 
-	mu        sync.Mutex // Protects all fields below
-	templates map[string]*template.Template
+	mu             sync.Mutex // Protects all fields below
+	templates      map[string]*template.Template
+	restoreFetches *lru.Cache // from internal.Modver to the time a restore fetch was scheduled
 }
 
+// maxRestoreFetches is the number of module versions for which the server
+// remembers that it scheduled a fetch to restore purged documentation.
+const maxRestoreFetches = 1000
+
 // ServerConfig contains everything needed by a Server.
 type ServerConfig struct {
 	Config *config.Config
@@ -86,6 +100,29 @@ type ServerConfig struct {
 	StaticPath           string // used only for dynamic loading in dev mode
 	ReportingClient      *errorreporting.Client
 	VulndbClient         *vuln.Client
+	// PageCache, if non-nil, caches rendered unit pages in memory.
+	PageCache *PageCache
+	// RateLimiter, if non-nil, limits the rate of requests from each client
+	// to the search and documentation handlers.
+	RateLimiter middleware.RateLimiter
+	// DocTemplateFS, if non-nil, holds templates in its doc directory that
+	// override those of the documentation body. See
+	// dochtml.ParseBodyTemplate.
+	DocTemplateFS *template.TrustedFS
+	// OnDemandFetchTimeout, if positive, makes a request for a unit page
+	// whose path has never been fetched fetch it, waiting up to this long
+	// for the fetch before serving the page. If the fetch takes longer, a
+	// page saying that the path is still being processed is served.
+	OnDemandFetchTimeout time.Duration
+	// OnDemandFetchRateLimiter, if non-nil, limits the rate of fetches on
+	// page views from each client. Requests over the limit are served the
+	// not-found page, from which the user can still request a fetch.
+	OnDemandFetchRateLimiter middleware.RateLimiter
+	// BaseURL is the scheme and host of the site, like
+	// "https://pkg.go.dev", for the absolute URLs in the sitemap and the
+	// OpenSearch description. If empty, the scheme and host of each
+	// request are used, which is suitable only for local use.
+	BaseURL string
 }
 
 // NewServer creates a new Server for the given database and template directory.
@@ -96,6 +133,13 @@ func NewServer(scfg ServerConfig) (_ *Server, err error) {
 		return nil, fmt.Errorf("error parsing templates: %v", err)
 	}
 	dochtml.LoadTemplates(scfg.TemplateFS)
+	if scfg.DocTemplateFS != nil {
+		t, err := dochtml.ParseBodyTemplate(*scfg.DocTemplateFS, "doc/*.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("error parsing documentation templates: %v", err)
+		}
+		godoc.DocBodyTemplate = t
+	}
 	s := &Server{
 		getDataSource:        scfg.DataSourceGetter,
 		queue:                scfg.Queue,
@@ -111,6 +155,15 @@ func NewServer(scfg ServerConfig) (_ *Server, err error) {
 		reportingClient:      scfg.ReportingClient,
 		fileMux:              http.NewServeMux(),
 		vulnClient:           scfg.VulndbClient,
+		pageCache:            scfg.PageCache,
+		rateLimiter:          scfg.RateLimiter,
+		onDemandFetchTimeout: scfg.OnDemandFetchTimeout,
+		onDemandFetchLimiter: scfg.OnDemandFetchRateLimiter,
+		baseURL:              strings.TrimSuffix(scfg.BaseURL, "/"),
+	}
+	s.restoreFetches, err = lru.New(maxRestoreFetches)
+	if err != nil {
+		return nil, err
 	}
 	if scfg.Config != nil {
 		s.appVersionLabel = scfg.Config.AppVersionLabel()
@@ -146,6 +199,13 @@ func (s *Server) Install(handle func(string, http.Handler), redisClient *redis.C
 		searchHandler = middleware.Cache("search", redisClient, searchTTL, authValues)(searchHandler)
 		vulnHandler = middleware.Cache("vuln", redisClient, vulnTTL, authValues)(vulnHandler)
 	}
+	if s.rateLimiter != nil {
+		// Limit requests to the handlers that are expensive to serve. Cached
+		// responses count against the limit too, to discourage scraping.
+		rl := middleware.RateLimit(s.rateLimiter, authValues)
+		detailHandler = rl(detailHandler)
+		searchHandler = rl(searchHandler)
+	}
 	// Each AppEngine instance is created in response to a start request, which
 	// is an empty HTTP GET request to /_ah/start when scaling is set to manual
 	// or basic, and /_ah/warmup when scaling is automatic and min_instances is
@@ -163,7 +223,7 @@ func (s *Server) Install(handle func(string, http.Handler), redisClient *redis.C
 		serveFileFS(w, r, s.staticFS, "shared/icon/favicon.ico")
 	}))
 
-	handle("/sitemap/", http.StripPrefix("/sitemap/", http.FileServer(http.Dir("private/sitemap"))))
+	handle(sitemapPathPrefix, s.errorHandler(s.serveSitemap))
 	handle("/mod/", http.HandlerFunc(s.handleModuleDetailsRedirect))
 	handle("/pkg/", http.HandlerFunc(s.handlePackageDetailsRedirect))
 	handle("/fetch/", fetchHandler)
@@ -172,6 +232,8 @@ func (s *Server) Install(handle func(string, http.Handler), redisClient *redis.C
 	handle("/play/share", http.HandlerFunc(s.proxyPlayground))
 	handle("/search", searchHandler)
 	handle("/search-help", s.staticPageHandler("search-help", "Search Help"))
+	handle("/opensearch.xml", http.HandlerFunc(s.openSearchHandler))
+	handle(goModPathPrefix, s.errorHandler(s.serveGoMod))
 	handle("/license-policy", s.licensePolicyHandler())
 	handle("/about", s.staticPageHandler("about", "About"))
 	handle("/badge/", http.HandlerFunc(s.badgeHandler))
@@ -184,6 +246,8 @@ func (s *Server) Install(handle func(string, http.Handler), redisClient *redis.C
 	handle("/golang.org/x", s.staticPageHandler("subrepo", "Sub-repositories"))
 	handle("/files/", http.StripPrefix("/files", s.fileMux))
 	handle("/vuln/", vulnHandler)
+	handle(apiUnitPathPrefix, s.apiErrorHandler(s.serveAPIUnit))
+	handle(apiLicensesPathPrefix, s.apiErrorHandler(s.serveAPILicenses))
 	handle("/", detailHandler)
 	if s.serveStats {
 		handle("/detail-stats/",
@@ -303,6 +367,27 @@ var crawlers = []string{
 	"+http://ahrefs.com/robot",
 }
 
+// crawlerMarkers are substrings of the User-Agent headers of crawlers that
+// the crawlers list does not match.
+var crawlerMarkers = []string{"bot", "crawl", "spider", "slurp"}
+
+// isCrawler reports whether userAgent is that of a known crawler or looks
+// like one.
+func isCrawler(userAgent string) bool {
+	for _, c := range crawlers {
+		if strings.Contains(userAgent, c) {
+			return true
+		}
+	}
+	userAgent = strings.ToLower(userAgent)
+	for _, m := range crawlerMarkers {
+		if strings.Contains(userAgent, m) {
+			return true
+		}
+	}
+	return false
+}
+
 // detailsTTL assigns the cache TTL for package detail requests.
 func detailsTTL(r *http.Request) time.Duration {
 	userAgent := r.Header.Get("User-Agent")
@@ -557,7 +642,7 @@ func (s *Server) serveError(w http.ResponseWriter, r *http.Request, err error) {
 	ctx := r.Context()
 	var serr *serverError
 	if !errors.As(err, &serr) {
-		serr = &serverError{status: http.StatusInternalServerError, err: err}
+		serr = &serverError{status: errorStatus(err), err: err}
 	}
 	if serr.status == http.StatusInternalServerError {
 		log.Error(ctx, err)
@@ -575,6 +660,19 @@ func (s *Server) serveError(w http.ResponseWriter, r *http.Request, err error) {
 	s.serveErrorPage(w, r, serr.status, serr.epage)
 }
 
+// errorStatus returns the HTTP status to serve for an error that is not a
+// *serverError, based on its derrors classification.
+func errorStatus(err error) int {
+	switch status := derrors.ToStatus(err); status {
+	case http.StatusNotFound, http.StatusGone:
+		return status
+	case derrors.ToStatus(derrors.NotFetched):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 // reportError sends the error to the GCP Error Reporting service.
 func (s *Server) reportError(ctx context.Context, err error, w http.ResponseWriter, r *http.Request) {
 	if s.reportingClient == nil {
@@ -624,12 +722,25 @@ func (s *Server) serveErrorPage(w http.ResponseWriter, r *http.Request, status i
 	}
 }
 
+// statusErrorMessages holds explanations for error pages that don't provide
+// their own message, keyed by HTTP status.
+var statusErrorMessages = map[int]string{
+	http.StatusNotFound:            "The page you are looking for could not be found.",
+	http.StatusGone:                "This module version is no longer available. It may have been removed or disabled.",
+	http.StatusInternalServerError: "Something went wrong on our end. Please try again later.",
+}
+
 // renderErrorPage executes error.tmpl with the given errorPage
 func (s *Server) renderErrorPage(ctx context.Context, status int, templateName string, page *errorPage) ([]byte, error) {
 	statusInfo := fmt.Sprintf("%d %s", status, http.StatusText(status))
 	if page == nil {
 		page = &errorPage{}
 	}
+	if msg, ok := statusErrorMessages[status]; ok && page.messageTemplate.String() == "" && page.MessageData == nil {
+		page.messageTemplate = template.MakeTrustedTemplate(
+			`<h3 class="Error-message">{{.Status}}</h3><p class="Error-message">{{.Message}}</p>`)
+		page.MessageData = struct{ Status, Message string }{statusInfo, msg}
+	}
 	if page.messageTemplate.String() == "" {
 		page.messageTemplate = template.MakeTrustedTemplate(`<h3 class="Error-message">{{.}}</h3>`)
 	}
@@ -6,6 +6,7 @@ package frontend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -47,6 +48,52 @@ func TestMain(m *testing.M) {
 	postgres.RunDBTests("discovery_frontend_test", m, &testDB)
 }
 
+func TestServeErrorStatus(t *testing.T) {
+	s, _, teardown := newTestServer(t, nil, nil)
+	defer teardown()
+
+	for _, test := range []struct {
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			fmt.Errorf("GetUnitMeta: %w", derrors.NotFound),
+			http.StatusNotFound,
+			statusErrorMessages[http.StatusNotFound],
+		},
+		{
+			fmt.Errorf("proxy: %w", derrors.ProxyGone),
+			http.StatusGone,
+			statusErrorMessages[http.StatusGone],
+		},
+		{
+			errors.New("bad"),
+			http.StatusInternalServerError,
+			statusErrorMessages[http.StatusInternalServerError],
+		},
+		{
+			&serverError{status: http.StatusBadRequest, err: derrors.NotFound},
+			http.StatusBadRequest,
+			"400 Bad Request",
+		},
+	} {
+		t.Run(test.err.Error(), func(t *testing.T) {
+			h := s.errorHandler(func(http.ResponseWriter, *http.Request, internal.DataSource) error {
+				return test.err
+			})
+			w := httptest.NewRecorder()
+			h(w, httptest.NewRequest("GET", "/p", nil))
+			if w.Code != test.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, test.wantStatus)
+			}
+			if got := w.Body.String(); !strings.Contains(got, test.wantMessage) {
+				t.Errorf("page does not contain %q", test.wantMessage)
+			}
+		})
+	}
+}
+
 func TestHTMLInjection(t *testing.T) {
 	_, handler, _ := newTestServer(t, nil, nil)
 	w := httptest.NewRecorder()
@@ -1057,12 +1104,20 @@ func serverTestCases() []serverTestCase {
 				in(".UnitBuildContext-titleContext", hasText("windows/amd64"))),
 		},
 		{
-			name:           "two docs no match",
+			name:           "two docs windows/amd64",
+			urlPath:        "/a.com/two/pkg?GOOS=windows&GOARCH=amd64",
+			wantStatusCode: http.StatusOK,
+			want: in("",
+				in(".Documentation-variables", hasText("var W")),
+				in(".UnitBuildContext-titleContext", hasText("windows/amd64"))),
+		},
+		{
+			name:           "two docs no match falls back to linux",
 			urlPath:        "/a.com/two/pkg?GOOS=dragonfly",
 			wantStatusCode: http.StatusOK,
 			want: in("",
-				notIn(".Documentation-variables"),
-				notIn(".UnitBuildContext-titleContext")),
+				in(".Documentation-variables", hasText("var L")),
+				in(".UnitBuildContext-titleContext", hasText("linux/amd64"))),
 		},
 	}
 }
@@ -1382,18 +1437,18 @@ func TestServer404Redirect(t *testing.T) {
 
 	for _, test := range []struct {
 		name, path, flash string
+		status            int
 	}{
-		{"github url", "/" + sample.ModulePath + "/blob/master", ""},
-		{"alternative module", "/" + alternativeModule.ModulePath, "module.path/alternative"},
-		{"module not in v1", "/" + v1modpath, "notinv1.mod"},
-		{"import path not in v1", "/" + v1path, "notinv1.mod/foo"},
+		{"github url", "/" + sample.ModulePath + "/blob/master", "", http.StatusFound},
+		{"alternative module", "/" + alternativeModule.ModulePath, "module.path/alternative", http.StatusFound},
+		{"module not in v1", "/" + v1modpath, "notinv1.mod", http.StatusFound},
+		{"import path not in v1", "/" + v1path, "notinv1.mod/foo", http.StatusFound},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			w := httptest.NewRecorder()
 			handler.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
-			// Check for http.StatusFound, which indicates a redirect.
-			if w.Code != http.StatusFound {
-				t.Errorf("%q: got status code = %d, want %d", test.path, w.Code, http.StatusFound)
+			if w.Code != test.status {
+				t.Errorf("%q: got status code = %d, want %d", test.path, w.Code, test.status)
 			}
 			res := w.Result()
 			c := findCookie(cookie.AlternativeModuleFlash, res.Cookies())
@@ -1432,6 +1487,52 @@ func TestServer404Redirect(t *testing.T) {
 	}
 }
 
+// Verify that a version of a module path superseded by the path in its go.mod
+// file redirects permanently to the same version of the canonical path, and
+// that other versions redirect temporarily to the latest version.
+func TestServer404Redirect_CanonicalModulePath(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	defer postgres.ResetTestDB(testDB, t)
+	postgres.MustInsertModule(ctx, t, testDB, sample.DefaultModule())
+	for _, v := range []string{version.Latest, sample.VersionString, "v9.9.9"} {
+		if err := testDB.UpsertVersionMap(ctx, &internal.VersionMap{
+			ModulePath:       "old.path/module",
+			GoModPath:        sample.ModulePath,
+			RequestedVersion: v,
+			ResolvedVersion:  sample.VersionString,
+			Status:           derrors.ToStatus(derrors.AlternativeModule),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, handler, _ := newTestServer(t, nil, nil)
+	for _, test := range []struct {
+		path, wantLocation, wantCacheControl string
+		wantStatus                           int
+	}{
+		{"/old.path/module", "/" + sample.ModulePath, "", http.StatusFound},
+		{"/old.path/module@" + sample.VersionString, "/" + sample.ModulePath + "@" + sample.VersionString, "max-age=3600", http.StatusPermanentRedirect},
+		{"/old.path/module@v9.9.9", "/" + sample.ModulePath, "", http.StatusFound},
+	} {
+		t.Run(test.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+			if w.Code != test.wantStatus {
+				t.Errorf("got status code = %d, want %d", w.Code, test.wantStatus)
+			}
+			if got := w.Header().Get("Location"); got != test.wantLocation {
+				t.Errorf("got Location %q, want %q", got, test.wantLocation)
+			}
+			if got := w.Header().Get("Cache-Control"); got != test.wantCacheControl {
+				t.Errorf("got Cache-Control %q, want %q", got, test.wantCacheControl)
+			}
+		})
+	}
+}
+
 func findCookie(name string, cookies []*http.Cookie) *http.Cookie {
 	for _, c := range cookies {
 		if c.Name == name {
@@ -1459,6 +1560,23 @@ func mustRequest(urlPath string, t *testing.T) *http.Request {
 	return r
 }
 
+func TestIsCrawler(t *testing.T) {
+	for _, test := range []struct {
+		userAgent string
+		want      bool
+	}{
+		{"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", true},
+		{"Mozilla/5.0 (compatible; YandexBot/3.0; +http://yandex.com/bots)", true},
+		{"Mozilla/5.0 (compatible; Yahoo! Slurp; http://help.yahoo.com/help/us/ysearch/slurp)", true},
+		{"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0 Safari/537.36", false},
+		{"", false},
+	} {
+		if got := isCrawler(test.userAgent); got != test.want {
+			t.Errorf("isCrawler(%q) = %t, want %t", test.userAgent, got, test.want)
+		}
+	}
+}
+
 func TestDetailsTTL(t *testing.T) {
 	tests := []struct {
 		r    *http.Request
@@ -1516,6 +1634,13 @@ func TestTagRoute(t *testing.T) {
 }
 
 func newTestServer(t *testing.T, proxyModules []*proxytest.Module, redisClient *redis.Client, experimentNames ...string) (*Server, http.Handler, func()) {
+	t.Helper()
+	return newTestServerWithConfig(t, proxyModules, redisClient, nil, experimentNames...)
+}
+
+// newTestServerWithConfig is like newTestServer, but calls configure, if it
+// is non-nil, on the configuration of the server before creating it.
+func newTestServerWithConfig(t *testing.T, proxyModules []*proxytest.Module, redisClient *redis.Client, configure func(*ServerConfig), experimentNames ...string) (*Server, http.Handler, func()) {
 	t.Helper()
 	proxyClient, teardown := proxytest.SetupTestClient(t, proxyModules)
 	sourceClient := source.NewClient(sourceTimeout)
@@ -1526,7 +1651,7 @@ func newTestServer(t *testing.T, proxyModules []*proxytest.Module, redisClient *
 			return FetchAndUpdateState(ctx, mpath, version, proxyClient, sourceClient, testDB)
 		})
 
-	s, err := NewServer(ServerConfig{
+	scfg := ServerConfig{
 		DataSourceGetter:     func(context.Context) internal.DataSource { return testDB },
 		Queue:                q,
 		TaskIDChangeInterval: 10 * time.Minute,
@@ -1536,7 +1661,11 @@ func newTestServer(t *testing.T, proxyModules []*proxytest.Module, redisClient *
 		StaticFS:     static.FS,
 		ThirdPartyFS: thirdparty.FS,
 		StaticPath:   "../../static",
-	})
+	}
+	if configure != nil {
+		configure(&scfg)
+	}
+	s, err := NewServer(scfg)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -0,0 +1,143 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+const (
+	// sitemapPathPrefix is the URL path prefix for sitemap files.
+	sitemapPathPrefix = "/sitemap/"
+
+	// sitemapIndexName is the name of the sitemap index file, which lists
+	// the sitemap files.
+	sitemapIndexName = "index.xml"
+
+	// sitemapPackagesName is the name of the sitemap files. The "start"
+	// query parameter of each file is the first package path it lists.
+	sitemapPackagesName = "packages.xml"
+
+	// sitemapXMLNS is the XML namespace of the sitemap protocol.
+	sitemapXMLNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+)
+
+// maxSitemapURLs is the maximum number of URLs in a single sitemap file,
+// as defined by https://www.sitemaps.org/protocol.html.
+// Variable for testing.
+var maxSitemapURLs = 50000
+
+type sitemapIndex struct {
+	XMLName  xml.Name         `xml:"sitemapindex"`
+	XMLNS    string           `xml:"xmlns,attr"`
+	Sitemaps []sitemapPointer `xml:"sitemap"`
+}
+
+type sitemapPointer struct {
+	Loc string `xml:"loc"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+// serveSitemap serves the sitemap index at /sitemap/index.xml, and the
+// sitemap files it points to at /sitemap/packages.xml?start=<path>, where
+// path is the first package path of the file. Each sitemap file lists at
+// most maxSitemapURLs package pages.
+func (s *Server) serveSitemap(w http.ResponseWriter, r *http.Request, ds internal.DataSource) (err error) {
+	defer derrors.Wrap(&err, "serveSitemap(w, r, ds)")
+	defer middleware.ElapsedStat(r.Context(), "serveSitemap")()
+
+	db, ok := ds.(*postgres.DB)
+	if !ok {
+		// The proxydatasource does not support the sitemap.
+		return datasourceNotSupportedErr()
+	}
+	ctx := r.Context()
+	base := s.siteURL(r)
+	switch strings.TrimPrefix(r.URL.Path, sitemapPathPrefix) {
+	case sitemapIndexName:
+		starts, err := db.GetSitemapPageStarts(ctx, maxSitemapURLs)
+		if err != nil {
+			return err
+		}
+		index := sitemapIndex{XMLNS: sitemapXMLNS}
+		for _, start := range starts {
+			index.Sitemaps = append(index.Sitemaps, sitemapPointer{
+				Loc: fmt.Sprintf("%s%s%s?start=%s", base, sitemapPathPrefix, sitemapPackagesName, url.QueryEscape(start)),
+			})
+		}
+		return writeXML(w, r, &index)
+	case sitemapPackagesName:
+		entries, err := db.GetSitemapEntries(ctx, r.FormValue("start"), maxSitemapURLs)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return &serverError{status: http.StatusNotFound}
+		}
+		set := sitemapURLSet{XMLNS: sitemapXMLNS}
+		for _, e := range entries {
+			u := sitemapURL{Loc: base + "/" + e.PackagePath}
+			if !e.LastModified.IsZero() {
+				u.LastMod = e.LastModified.UTC().Format(time.RFC3339)
+			}
+			set.URLs = append(set.URLs, u)
+		}
+		return writeXML(w, r, &set)
+	default:
+		return &serverError{status: http.StatusNotFound}
+	}
+}
+
+// siteURL returns the scheme and host of the site, without a trailing slash.
+// It is the configured base URL of s, or, if there is none, the scheme and
+// host of r.
+func (s *Server) siteURL(r *http.Request) string {
+	if s.baseURL != "" {
+		return s.baseURL
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+// writeXML writes v to w as an XML document.
+func writeXML(w http.ResponseWriter, r *http.Request, v any) error {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	if _, err := w.Write([]byte(xml.Header)); err != nil {
+		log.Errorf(r.Context(), "w.Write: %v", err)
+		return nil
+	}
+	if _, err := w.Write(data); err != nil {
+		log.Errorf(r.Context(), "w.Write: %v", err)
+	}
+	return nil
+}
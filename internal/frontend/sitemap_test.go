@@ -0,0 +1,91 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package frontend
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestServeSitemap(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	defer func(n int) { maxSitemapURLs = n }(maxSitemapURLs)
+	maxSitemapURLs = 1
+
+	// The base URL is configured, so the host of the requests is ignored.
+	_, handler, teardown := newTestServerWithConfig(t, nil, nil, func(cfg *ServerConfig) {
+		cfg.BaseURL = "https://pkg.go.dev/"
+	})
+	defer teardown()
+
+	for _, m := range []string{"example.com/a", "example.com/b"} {
+		postgres.MustInsertModule(ctx, t, testDB, sample.Module(m, "v1.0.0", ""))
+	}
+
+	get := func(path string, v any) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:8080"+path, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: got status %d, want %d", path, w.Code, http.StatusOK)
+		}
+		if err := xml.Unmarshal(w.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s: invalid XML: %v\n%s", path, err, w.Body)
+		}
+	}
+
+	var index sitemapIndex
+	get("/sitemap/index.xml", &index)
+	var gotLocs []string
+	for _, s := range index.Sitemaps {
+		gotLocs = append(gotLocs, s.Loc)
+	}
+	wantLocs := []string{
+		"https://pkg.go.dev/sitemap/packages.xml?start=example.com%2Fa",
+		"https://pkg.go.dev/sitemap/packages.xml?start=example.com%2Fb",
+	}
+	if diff := cmp.Diff(wantLocs, gotLocs); diff != "" {
+		t.Errorf("index mismatch (-want, +got):\n%s", diff)
+	}
+
+	gotLocs = nil
+	for _, loc := range wantLocs {
+		path := strings.TrimPrefix(loc, "https://pkg.go.dev")
+		var set sitemapURLSet
+		get(path, &set)
+		if set.XMLName.Space != sitemapXMLNS {
+			t.Errorf("%s: got namespace %q, want %q", path, set.XMLName.Space, sitemapXMLNS)
+		}
+		for _, u := range set.URLs {
+			if u.LastMod == "" {
+				t.Errorf("%s: %s: missing lastmod", path, u.Loc)
+			}
+			gotLocs = append(gotLocs, u.Loc)
+		}
+	}
+	wantLocs = []string{"https://pkg.go.dev/example.com/a", "https://pkg.go.dev/example.com/b"}
+	if diff := cmp.Diff(wantLocs, gotLocs); diff != "" {
+		t.Errorf("URLs mismatch (-want, +got):\n%s", diff)
+	}
+
+	for _, path := range []string{"/sitemap/packages.xml?start=example.com%2Fc", "/sitemap/0.xml"} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s: got status %d, want %d", path, w.Code, http.StatusNotFound)
+		}
+	}
+}
@@ -17,8 +17,10 @@ import (
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/cookie"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/queue"
 	"golang.org/x/pkgsite/internal/stdlib"
 	"golang.org/x/pkgsite/internal/version"
 	"golang.org/x/pkgsite/internal/vuln"
@@ -102,6 +104,26 @@ type UnitPage struct {
 	IsGoProject bool
 }
 
+// isStableUnitPage reports whether the unit page served for r is the same
+// for every request for it, until a version of its module is inserted or its
+// latest-version information changes. Only such pages may be cached or
+// answered with 304 Not Modified.
+func isStableUnitPage(r *http.Request, requestedVersion string) bool {
+	// Default branches are moving targets.
+	if internal.DefaultBranches[requestedVersion] {
+		return false
+	}
+	// Pages with the flash cookie show a one-time banner.
+	_, err := r.Cookie(cookie.AlternativeModuleFlash)
+	return err != nil
+}
+
+// canServeNotModified reports whether a unit page request may be answered
+// with a 304 Not Modified response.
+func (s *Server) canServeNotModified(r *http.Request, requestedVersion string) bool {
+	return isStableUnitPage(r, requestedVersion) && !s.shouldServeJSON(r)
+}
+
 // serveUnitPage serves a unit page for a path.
 func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *http.Request,
 	ds internal.DataSource, info *urlPathInfo) (err error) {
@@ -124,7 +146,70 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 		if !errors.Is(err, derrors.NotFound) {
 			return err
 		}
-		return s.servePathNotFoundPage(w, r, ds, info.fullPath, info.modulePath, info.requestedVersion)
+		um, err = s.fetchOnDemand(ctx, r, ds, info)
+		if err != nil {
+			return err
+		}
+		if um == nil {
+			return s.servePathNotFoundPage(w, r, ds, info.fullPath, info.modulePath, info.requestedVersion)
+		}
+	}
+
+	if info.requestedVersion == version.Latest && !um.Retracted &&
+		experiment.IsActive(ctx, internal.ExperimentRedirectToLatestVersion) {
+		// GetUnitMeta resolved the latest good version. Redirect to it, so that
+		// the URL identifies the version being shown. If every version is
+		// retracted, um is the latest retracted one; render it, and let the
+		// header warn about the retraction.
+		u := canonicalURLPath(um.Path, um.ModulePath, info.requestedVersion, um.Version)
+		if r.URL.RawQuery != "" {
+			u += "?" + r.URL.RawQuery
+		}
+		http.Redirect(w, r, u, http.StatusFound)
+		return nil
+	}
+
+	// If we've already called GetUnitMeta for an unknown module path and the latest version, pass
+	// it to GetLatestInfo to avoid a redundant call.
+	var latestUnitMeta *internal.UnitMeta
+	if info.modulePath == internal.UnknownModulePath && info.requestedVersion == version.Latest {
+		latestUnitMeta = um
+	}
+	latestInfo := s.GetLatestInfo(ctx, um.Path, um.ModulePath, latestUnitMeta)
+
+	// Get vulnerability information.
+	vulns := vuln.VulnsForPackage(ctx, um.ModulePath, um.Version, um.Path, s.vulnClient)
+
+	// etag is empty if the page can't be validated with conditional requests.
+	var etag string
+	if s.canServeNotModified(r, info.requestedVersion) {
+		etag = unitPageETag(r, um, latestInfo, vulns, s.appVersionLabel)
+		// The page changes when the module version is reprocessed, so it was
+		// last modified then, not at its commit time.
+		if checkNotModified(w, r, etag, um.ProcessedAt) {
+			return nil
+		}
+	}
+	writeBody := func(body []byte) {
+		if etag != "" {
+			setValidators(w.Header(), etag, um.ProcessedAt)
+		}
+		if _, err := w.Write(body); err != nil {
+			log.Errorf(ctx, "w.Write: %v", err)
+		}
+	}
+
+	var (
+		cacheKey pageCacheKey
+		cacheOK  bool
+	)
+	if s.pageCache != nil && !s.shouldServeJSON(r) {
+		cacheKey, cacheOK = s.pageCache.cacheKey(r, um, info.requestedVersion)
+		if body, ok := s.pageCache.get(cacheKey); cacheOK && ok {
+			log.Debugf(ctx, "serving %q from page cache", r.URL)
+			writeBody(body)
+			return nil
+		}
 	}
 
 	makeDepsDevURL := depsDevURLGenerator(ctx, um)
@@ -133,12 +218,18 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 	// affects the documentation and synopsis. Omitting both results in an empty
 	// build context, which will match the first (and preferred) build context.
 	// It's also okay to provide just one (e.g. GOOS=windows), which will select
-	// the first doc with that value, ignoring the other one.
+	// the first doc with that value, ignoring the other one. If no doc matches,
+	// the one for the preferred build context is served.
 	bc := internal.BuildContext{GOOS: r.FormValue("GOOS"), GOARCH: r.FormValue("GOARCH")}
 	d, err := fetchDetailsForUnit(ctx, r, tab, ds, um, info.requestedVersion, bc, s.vulnClient)
 	if err != nil {
 		return err
 	}
+	md, ok := d.(*MainDetails)
+	purged := ok && md.DocumentationPurged
+	if purged {
+		s.scheduleRestoreFetch(r.URL.Path, um.ModulePath, um.Version)
+	}
 	if s.shouldServeJSON(r) {
 		return s.serveJSONPage(w, r, d)
 	}
@@ -180,13 +271,6 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 		return nil
 	}
 
-	// If we've already called GetUnitMeta for an unknown module path and the latest version, pass
-	// it to GetLatestInfo to avoid a redundant call.
-	var latestUnitMeta *internal.UnitMeta
-	if info.modulePath == internal.UnknownModulePath && info.requestedVersion == version.Latest {
-		latestUnitMeta = um
-	}
-	latestInfo := s.GetLatestInfo(ctx, um.Path, um.ModulePath, latestUnitMeta)
 	var redirectPath string
 	redirectPath, err = cookie.Extract(w, r, cookie.AlternativeModuleFlash)
 	if err != nil {
@@ -235,10 +319,18 @@ func (s *Server) serveUnitPage(ctx context.Context, w http.ResponseWriter, r *ht
 		page.MetaDescription = metaDescription(main.DocSynopsis)
 	}
 
-	// Get vulnerability information.
-	page.Vulns = vuln.VulnsForPackage(ctx, um.ModulePath, um.Version, um.Path, s.vulnClient)
+	page.Vulns = vulns
 
-	s.servePage(ctx, w, tabSettings.TemplateName, page)
+	body, err := s.renderPage(ctx, tabSettings.TemplateName, page)
+	if err != nil {
+		return err
+	}
+	// Don't cache a page whose documentation was purged: serving it from the
+	// cache would skip scheduling the fetch that restores the documentation.
+	if cacheOK && !purged {
+		s.pageCache.put(r, cacheKey, body)
+	}
+	writeBody(body)
 	return nil
 }
 
@@ -307,3 +399,38 @@ func canonicalURLPath(fullPath, modulePath, requestedVersion, resolvedVersion st
 func isGoProject(modulePath string) bool {
 	return modulePath == stdlib.ModulePath || strings.HasPrefix(modulePath, "golang.org")
 }
+
+// restoreFetchInterval is the minimum time between two fetches scheduled by a
+// frontend to restore the purged documentation of a module version.
+const restoreFetchInterval = 10 * time.Minute
+
+// scheduleRestoreFetch schedules a fetch of modulePath@version to restore its
+// purged documentation, unless one was scheduled in the last
+// restoreFetchInterval, so that the views of a purged page before the fetch
+// completes don't each enqueue a task.
+func (s *Server) scheduleRestoreFetch(urlPath, modulePath, version string) {
+	if s.queue == nil {
+		return
+	}
+	key := internal.Modver{Path: modulePath, Version: version}
+	now := time.Now()
+	s.mu.Lock()
+	if t, ok := s.restoreFetches.Get(key); ok && now.Sub(t.(time.Time)) < restoreFetchInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.restoreFetches.Add(key, now)
+	s.mu.Unlock()
+
+	// Use a separate context so that the task is enqueued even if the
+	// request's context is canceled.
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		defer cancel()
+		log.Infof(ctx, "serveUnitPage: Scheduling %q@%q to be fetched to restore purged documentation", modulePath, version)
+		opts := &queue.Options{Source: queue.SourceFrontendValue}
+		if _, err := s.queue.ScheduleFetch(ctx, modulePath, version, opts); err != nil {
+			log.Errorf(ctx, "serveUnitPage(%q): scheduling fetch for %q@%q: %v", urlPath, modulePath, version, err)
+		}
+	}()
+}
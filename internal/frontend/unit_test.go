@@ -5,9 +5,14 @@
 package frontend
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/postgres"
 	"golang.org/x/pkgsite/internal/testing/sample"
 )
 
@@ -147,3 +152,64 @@ func TestMetaDescription(t *testing.T) {
 		}
 	}
 }
+
+func TestRedirectToLatestVersion(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	_, handler, teardown := newTestServer(t, nil, nil, internal.ExperimentRedirectToLatestVersion)
+	defer teardown()
+
+	for _, v := range []string{"v1.0.0", "v1.1.0", "v1.2.0-pre"} {
+		postgres.MustInsertModule(ctx, t, testDB, sample.Module(sample.ModulePath, v, "foo"))
+	}
+
+	for _, test := range []struct {
+		path, want string
+	}{
+		{"/" + sample.ModulePath + "/foo", "/" + sample.ModulePath + "@v1.1.0/foo"},
+		{"/" + sample.ModulePath + "@latest/foo?tab=licenses", "/" + sample.ModulePath + "@v1.1.0/foo?tab=licenses"},
+		{"/" + sample.ModulePath, "/" + sample.ModulePath + "@v1.1.0"},
+	} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", test.path, nil))
+		if w.Code != http.StatusFound {
+			t.Errorf("%s: got status %d, want %d", test.path, w.Code, http.StatusFound)
+			continue
+		}
+		if got := w.Header().Get("Location"); got != test.want {
+			t.Errorf("%s: got Location %q, want %q", test.path, got, test.want)
+		}
+	}
+
+	// Versioned paths are served directly.
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/"+sample.ModulePath+"@v1.0.0/foo", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("versioned path: got status %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestScheduleRestoreFetch(t *testing.T) {
+	var q *countingQueue
+	s, _, teardown := newTestServerWithConfig(t, nil, nil, func(scfg *ServerConfig) {
+		q = &countingQueue{Queue: scfg.Queue}
+		scfg.Queue = q
+	})
+	defer teardown()
+
+	// Repeated views of a purged page schedule a single fetch.
+	for i := 0; i < 3; i++ {
+		s.scheduleRestoreFetch("/m.com", "m.com", "v1.0.0")
+	}
+	s.scheduleRestoreFetch("/m.com", "m.com", "v1.1.0")
+	deadline := time.Now().Add(5 * time.Second)
+	for q.n.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := q.n.Load(); got != 2 {
+		t.Errorf("got %d fetches scheduled, want 2", got)
+	}
+}
@@ -7,11 +7,31 @@ package dochtml
 import (
 	"go/doc"
 	"regexp"
+	"strings"
 )
 
 // "Deprecated:" at the start of a paragraph.
 var deprecatedRx = regexp.MustCompile(`(^|\n\s*\n)\s*Deprecated:`)
 
+// A blank line, which ends a paragraph.
+var paragraphEndRx = regexp.MustCompile(`\n\s*\n`)
+
+// Deprecation reports whether the doc comment text s marks its subject as
+// deprecated, following the convention described at
+// https://go.dev/wiki/Deprecated. It also returns the rest of the
+// "Deprecated:" paragraph, on a single line.
+func Deprecation(s string) (deprecated bool, comment string) {
+	loc := deprecatedRx.FindStringIndex(s)
+	if loc == nil {
+		return false, ""
+	}
+	rest := s[loc[1]:]
+	if end := paragraphEndRx.FindStringIndex(rest); end != nil {
+		rest = rest[:end[0]]
+	}
+	return true, strings.Join(strings.Fields(rest), " ")
+}
+
 // isDeprecated reports whether the string has a "Deprecated" line.
 func isDeprecated(s string) bool {
 	return deprecatedRx.MatchString(s)
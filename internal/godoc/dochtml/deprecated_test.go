@@ -25,3 +25,22 @@ func TestIsDeprecated(t *testing.T) {
 		}
 	}
 }
+
+func TestDeprecation(t *testing.T) {
+	for _, test := range []struct {
+		text        string
+		wantDep     bool
+		wantComment string
+	}{
+		{"Package p does things.\n", false, ""},
+		{"Deprecated: use q instead.\n", true, "use q instead."},
+		{"Package p does things.\n\nDeprecated: this package\nis frozen.\n\nMore.\n", true, "this package is frozen."},
+		{"Package p mentions Deprecated: in the middle.\n", false, ""},
+		{"F does things.\n  \n  Deprecated: use G.\n", true, "use G."},
+	} {
+		gotDep, gotComment := Deprecation(test.text)
+		if gotDep != test.wantDep || gotComment != test.wantComment {
+			t.Errorf("Deprecation(%q) = %t, %q; want %t, %q", test.text, gotDep, gotComment, test.wantDep, test.wantComment)
+		}
+	}
+}
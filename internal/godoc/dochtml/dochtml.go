@@ -64,6 +64,16 @@ type RenderOptions struct {
 	ModInfo      *ModuleInfo
 	Limit        int64 // If zero, a default limit of 10 megabytes is used.
 	BuildContext internal.BuildContext
+	// PackageURLTemplate optionally specifies the URL of the documentation
+	// of other packages, used for links to them and their identifiers.
+	// "{importPath}" in the template is replaced by the package path,
+	// versioned if the package is in the same module. If empty, the
+	// template "/{importPath}" is used.
+	PackageURLTemplate string
+	// BodyTemplate optionally specifies the template used to render the body
+	// of the documentation, in place of the one loaded by LoadTemplates. It
+	// should be created with ParseBodyTemplate.
+	BodyTemplate *template.Template
 }
 
 // templateData holds the data passed to the HTML templates in this package.
@@ -81,6 +91,7 @@ type Parts struct {
 	Outline       safehtml.HTML // outline for large screens
 	MobileOutline safehtml.HTML // outline for mobile
 	Links         []render.Link // "Links" section of package doc
+	TOC           []*TOCEntry   // table of contents, with the links of the outline
 }
 
 // Render renders package documentation HTML for the
@@ -112,13 +123,18 @@ func Render(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Render
 		return html
 	}
 
+	body := bodyTemplate
+	if opt.BodyTemplate != nil {
+		body = opt.BodyTemplate
+	}
 	parts := &Parts{
-		Body:          exec(bodyTemplate),
+		Body:          exec(body),
 		Outline:       exec(outlineTemplate),
 		MobileOutline: exec(sidenavTemplate),
 		// links must be called after body, because the call to
 		// render_doc_extract_links in body.tmpl creates the links.
 		Links: links(),
+		TOC:   buildTOC(data, funcs["render_short_synopsis"].(func(ast.Node) (string, error))),
 	}
 	if err != nil {
 		return nil, err
@@ -251,21 +267,7 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 		delete(p.Notes, k)
 	}
 
-	r := render.New(ctx, fset, p, &render.Options{
-		PackageURL: func(path string) string {
-			// Use the same module version for imported packages that belong to
-			// the same module.
-			versionedPath := path
-			if opt.ModInfo != nil {
-				versionedPath = versionedPkgPath(path, opt.ModInfo)
-			}
-			var search string
-			if opt.BuildContext.GOOS != "" && opt.BuildContext.GOOS != "all" {
-				search = "?GOOS=" + opt.BuildContext.GOOS
-			}
-			return "/" + versionedPath + search
-		},
-	})
+	r := newRenderer(ctx, fset, p, opt)
 
 	fileLink := func(name string) safehtml.HTML {
 		return linkHTML(name, opt.FileLinkFunc(name), "Documentation-file")
@@ -298,6 +300,29 @@ func renderInfo(ctx context.Context, fset *token.FileSet, p *doc.Package, opt Re
 	return funcs, data, r.Links
 }
 
+// newRenderer returns a renderer for the documentation of p.
+func newRenderer(ctx context.Context, fset *token.FileSet, p *doc.Package, opt RenderOptions) *render.Renderer {
+	return render.New(ctx, fset, p, &render.Options{
+		PackageURL: func(path string) string {
+			// Use the same module version for imported packages that belong to
+			// the same module.
+			versionedPath := path
+			if opt.ModInfo != nil {
+				versionedPath = versionedPkgPath(path, opt.ModInfo)
+			}
+			var search string
+			if opt.BuildContext.GOOS != "" && opt.BuildContext.GOOS != "all" {
+				search = "?GOOS=" + opt.BuildContext.GOOS
+			}
+			tmpl := opt.PackageURLTemplate
+			if tmpl == "" {
+				tmpl = "/{importPath}"
+			}
+			return strings.ReplaceAll(tmpl, "{importPath}", versionedPath) + search
+		},
+	})
+}
+
 // executeToHTMLWithLimit executes tmpl on data and returns the result as a safehtml.HTML.
 // It returns an error if the size of the result exceeds limit.
 func executeToHTMLWithLimit(tmpl *template.Template, data any, limit int64) (safehtml.HTML, error) {
@@ -100,6 +100,90 @@ func TestRenderDeprecated(t *testing.T) {
 	compareWithGolden(t, parts, "deprecated-on", *update)
 }
 
+func TestRenderPackageURLTemplate(t *testing.T) {
+	LoadTemplates(templateFS)
+	const src = `
+// Package p reads from an io.Reader.
+package p
+
+import "io"
+
+var _ io.Reader
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/module/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := testRenderOptions
+	opts.PackageURLTemplate = "https://docs.example.com/{importPath}/"
+	parts, err := Render(context.Background(), fset, d, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `<a href="https://docs.example.com/io/#Reader">io.Reader</a>`
+	if got := parts.Body.String(); !strings.Contains(got, want) {
+		t.Errorf("body does not contain %s:\n%s", want, got)
+	}
+}
+
+func TestRenderBodyTemplate(t *testing.T) {
+	LoadTemplates(templateFS)
+	const src = `
+// Package p greets.
+package p
+
+// Hello returns a greeting.
+func Hello() string { return "hello" }
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/module/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fsys := template.TrustedFSFromTrustedSource(template.TrustedSourceFromConstant("testdata/templates"))
+	opts := testRenderOptions
+	opts.BodyTemplate, err = ParseBodyTemplate(fsys, "doc/body.tmpl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts, err := Render(context.Background(), fset, d, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := parts.Body.String()
+	for _, want := range []string{
+		`<div class="Custom-docs">`,
+		`<div class="Custom-func">`,
+		`<h4 tabindex="-1" id="Hello" data-kind="function"`,
+		"Hello returns a greeting.",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body does not contain %s:\n%s", want, body)
+		}
+	}
+	if strings.Contains(body, "Documentation-content") {
+		t.Errorf("body contains the default wrapper:\n%s", body)
+	}
+
+	// The parts not overridden are unchanged.
+	def, err := Render(context.Background(), fset, d, testRenderOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts.Outline != def.Outline {
+		t.Error("outline differs from the default")
+	}
+}
+
 func compareWithGolden(t *testing.T, parts *Parts, name string, update bool) {
 	got := fmt.Sprintf("%s\n----\n%s\n----\n%s\n", parts.Body, parts.Outline, parts.MobileOutline)
 	// Remove blank lines and whitespace around lines.
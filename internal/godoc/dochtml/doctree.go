@@ -0,0 +1,100 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/token"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// DocTree returns the documentation of p as a tree of symbols. The symbols
+// and their SymbolMeta are those returned by GetSymbols; each also has its
+// doc comment and position.
+func DocTree(p *doc.Package, fset *token.FileSet) (_ *internal.DocTree, err error) {
+	defer derrors.Wrap(&err, "DocTree for %q", p.ImportPath)
+	syms, err := GetSymbols(p, fset)
+	if err != nil {
+		return nil, err
+	}
+	decls := symbolDecls(p)
+	node := func(sm *internal.SymbolMeta) *internal.DocNode {
+		n := &internal.DocNode{SymbolMeta: *sm}
+		if d, ok := decls[sm.Name]; ok {
+			n.Doc = d.doc
+			pos := fset.Position(d.pos)
+			n.Filename = pos.Filename
+			n.Line = pos.Line
+		}
+		return n
+	}
+	tree := &internal.DocTree{Doc: p.Doc}
+	for _, s := range syms {
+		n := node(&s.SymbolMeta)
+		for _, c := range s.Children {
+			n.Children = append(n.Children, node(c))
+		}
+		tree.Symbols = append(tree.Symbols, n)
+	}
+	return tree, nil
+}
+
+// A symbolDecl is the doc comment and position of a symbol.
+type symbolDecl struct {
+	doc string
+	pos token.Pos
+}
+
+// symbolDecls returns the declarations of the symbols in p, keyed by the
+// symbol names used by GetSymbols.
+func symbolDecls(p *doc.Package) map[string]symbolDecl {
+	decls := map[string]symbolDecl{}
+	addValues := func(vals []*doc.Value) {
+		for _, v := range vals {
+			for _, spec := range v.Decl.Specs {
+				for _, id := range spec.(*ast.ValueSpec).Names {
+					decls[id.Name] = symbolDecl{v.Doc, id.Pos()}
+				}
+			}
+		}
+	}
+	addFuncs := func(prefix string, fs []*doc.Func) {
+		for _, f := range fs {
+			decls[prefix+f.Name] = symbolDecl{f.Doc, f.Decl.Name.Pos()}
+		}
+	}
+	addFields := func(prefix string, fields *ast.FieldList) {
+		for _, f := range fields.List {
+			for _, id := range f.Names {
+				decls[prefix+id.Name] = symbolDecl{f.Doc.Text(), id.Pos()}
+			}
+		}
+	}
+
+	addValues(p.Consts)
+	addValues(p.Vars)
+	addFuncs("", p.Funcs)
+	for _, t := range p.Types {
+		spec, ok := t.Decl.Specs[0].(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+		decls[t.Name] = symbolDecl{t.Doc, spec.Name.Pos()}
+		addValues(t.Consts)
+		addValues(t.Vars)
+		addFuncs("", t.Funcs)
+		addFuncs(t.Name+".", t.Methods)
+		switch st := spec.Type.(type) {
+		case *ast.StructType:
+			addFields(t.Name+".", st.Fields)
+		case *ast.InterfaceType:
+			addFields(t.Name+".", st.Methods)
+		}
+	}
+	return decls
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+)
+
+func TestDocTree(t *testing.T) {
+	const src = `// Package p is for testing.
+package p
+
+// Max is the maximum.
+const Max = 10
+
+// T is a type.
+type T struct {
+	// F is a field.
+	F int
+}
+
+// NewT returns a T.
+func NewT() *T { return nil }
+
+// M is a method.
+func (*T) M() {}
+
+// I is an interface.
+type I interface {
+	// N is a method.
+	N()
+}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/module/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := DocTree(d, fset)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node := func(name, parent, synopsis string, kind internal.SymbolKind, section internal.SymbolSection,
+		doc string, line int, children ...*internal.DocNode) *internal.DocNode {
+		return &internal.DocNode{
+			SymbolMeta: internal.SymbolMeta{
+				Name:       name,
+				ParentName: parent,
+				Synopsis:   synopsis,
+				Kind:       kind,
+				Section:    section,
+			},
+			Doc:      doc,
+			Filename: "p.go",
+			Line:     line,
+			Children: children,
+		}
+	}
+	want := &internal.DocTree{
+		Doc: "Package p is for testing.\n",
+		Symbols: []*internal.DocNode{
+			node("Max", "", "const Max", internal.SymbolKindConstant, internal.SymbolSectionConstants,
+				"Max is the maximum.\n", 5),
+			node("I", "", "type I interface{ ... }", internal.SymbolKindType, internal.SymbolSectionTypes,
+				"I is an interface.\n", 20,
+				node("I.N", "I", "N func()", internal.SymbolKindMethod, internal.SymbolSectionTypes,
+					"N is a method.\n", 22)),
+			node("T", "", "type T struct{ ... }", internal.SymbolKindType, internal.SymbolSectionTypes,
+				"T is a type.\n", 8,
+				node("NewT", "T", "func NewT() *T", internal.SymbolKindFunction, internal.SymbolSectionTypes,
+					"NewT returns a T.\n", 14),
+				node("T.F", "T", "F int", internal.SymbolKindField, internal.SymbolSectionTypes,
+					"F is a field.\n", 10),
+				node("T.M", "T", "func (*T) M()", internal.SymbolKindMethod, internal.SymbolSectionTypes,
+					"M is a method.\n", 17)),
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"go/ast"
 	"go/doc"
 	"go/token"
+	"regexp"
+	"strings"
 
 	"github.com/google/safehtml/template"
 )
@@ -117,6 +119,49 @@ func newPackageIDs(pkg *doc.Package, related ...*doc.Package) *packageIDs {
 	return pids
 }
 
+// importPathsByName maps the names of the packages in imports to their
+// import paths, for linking references like "io.Reader" in doc comments.
+// A package's name is assumed to be the last element of its path, ignoring
+// a major version suffix. Names that are ambiguous, or that are the same as
+// pkgName, the name of the importing package, are omitted.
+func importPathsByName(pkgName string, imports []string) map[string]string {
+	m := map[string]string{}
+	seen := map[string]bool{pkgName: true}
+	for _, path := range imports {
+		name := importName(path)
+		if name == "" {
+			continue
+		}
+		if seen[name] {
+			delete(m, name)
+			continue
+		}
+		seen[name] = true
+		m[name] = path
+	}
+	return m
+}
+
+// majorVersionRx matches a major version path element, like "v2".
+var majorVersionRx = regexp.MustCompile(`^v[0-9]+$`)
+
+// importName returns the likely package name of the package with the given
+// import path, or the empty string if it cannot be guessed.
+func importName(path string) string {
+	elems := strings.Split(path, "/")
+	name := elems[len(elems)-1]
+	if majorVersionRx.MatchString(name) && len(elems) > 1 {
+		name = elems[len(elems)-2]
+	}
+	if i := strings.Index(name, ".v"); i > 0 && strings.HasPrefix(path, "gopkg.in/") {
+		name = name[:i] // gopkg.in/yaml.v3
+	}
+	if !token.IsIdentifier(name) {
+		return ""
+	}
+	return name
+}
+
 // declIDs is a collection of identifiers that are related to the ast.Decl
 // currently being processed. Using Decl-level variables allows us to provide
 // greater accuracy in linking when comments refer to the variable names.
@@ -223,7 +223,7 @@ func (r *Renderer) blocksToHTML(bs []comment.Block, useParagraph, extractLinks b
 func (r *Renderer) blockToHTML(b comment.Block, useParagraph, extractLinks bool) safe.HTML {
 	switch b := b.(type) {
 	case *comment.Paragraph:
-		th := r.textsToHTML(b.Text)
+		th := concatHTML(b.Text, r.paragraphTextToHTML)
 		if useParagraph {
 			return ExecuteToHTML(paraTemplate, th)
 		}
@@ -279,6 +279,45 @@ func (r *Renderer) textToHTML(t comment.Text) safe.HTML {
 	}
 }
 
+// paragraphTextToHTML is like textToHTML, but also links references to the
+// identifiers of imported packages in plain text. It is not used for
+// headings and link text, which must not contain links themselves.
+func (r *Renderer) paragraphTextToHTML(t comment.Text) safe.HTML {
+	if p, ok := t.(comment.Plain); ok {
+		return r.linkImportedIdentifiers(string(p))
+	}
+	return r.textToHTML(t)
+}
+
+// importedIdentRx matches a reference to an exported identifier of another
+// package, like "io.Reader" or "http.Client.Do".
+var importedIdentRx = regexp.MustCompile(`\b([A-Za-z_][A-Za-z0-9_]*)\.([A-Z][A-Za-z0-9_]*(?:\.[A-Z][A-Za-z0-9_]*)?)\b`)
+
+// linkImportedIdentifiers returns s as HTML, with each reference to an
+// exported identifier of a package imported by the package being rendered
+// linked to that identifier. References to other packages are left as plain
+// text. The rest of s is processed by linkRFCs.
+func (r *Renderer) linkImportedIdentifiers(s string) safe.HTML {
+	var hs []safe.HTML
+	last := 0
+	for _, m := range importedIdentRx.FindAllStringSubmatchIndex(s, -1) {
+		if m[0] > 0 && (s[m[0]-1] == '.' || s[m[0]-1] == '/') {
+			continue // part of a longer path, like a.b.C or a/b.C
+		}
+		pkgPath, ok := r.importPaths[s[m[2]:m[3]]]
+		if !ok {
+			continue
+		}
+		idr := identifierResolver{packageURL: r.packageURL}
+		url := idr.toURL(pkgPath, s[m[4]:m[5]])
+		hs = append(hs, linkRFCs(s[last:m[0]]),
+			ExecuteToHTML(linkTemplate, link{"", url, s[m[0]:m[1]]}))
+		last = m[1]
+	}
+	hs = append(hs, linkRFCs(s[last:]))
+	return safe.HTMLConcat(hs...)
+}
+
 func (r *Renderer) docLinkURL(dl *comment.DocLink) string {
 	var url string
 	if dl.ImportPath != "" {
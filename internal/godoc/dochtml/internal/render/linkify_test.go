@@ -241,6 +241,74 @@ TLSUnique contains the tls-unique channel binding value (see RFC
 	}
 }
 
+func TestFormatDocHTMLImportedIdentifiers(t *testing.T) {
+	const src = `
+// Package p reads things.
+package p
+
+import (
+	"io"
+	"net/http"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	_ io.Reader
+	_ http.Client
+	_ yaml.Node
+)
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := New(context.Background(), fset, pkg, &Options{
+		PackageURL: func(path string) string { return "/pkg/" + path },
+	})
+	for _, test := range []struct {
+		doc, want string
+	}{
+		{
+			"Read from an io.Reader.",
+			`<p>Read from an <a href="/pkg/io#Reader">io.Reader</a>.
+</p>`,
+		},
+		{
+			"Use http.Client.Do, or a yaml.Node.",
+			`<p>Use <a href="/pkg/net/http#Client.Do">http.Client.Do</a>, or a <a href="/pkg/gopkg.in/yaml.v3#Node">yaml.Node</a>.
+</p>`,
+		},
+		{
+			// Packages that aren't imported aren't linked.
+			"A bytes.Buffer or an os.File.",
+			"<p>A bytes.Buffer or an os.File.\n</p>",
+		},
+		{
+			// Neither are unexported identifiers or longer paths.
+			"See io.pipe and example.io.Reader.",
+			"<p>See io.pipe and example.io.Reader.\n</p>",
+		},
+		{
+			"Errors:\n  - io.EOF",
+			`<p>Errors:
+</p><ul class="Documentation-bulletList">
+  <li><a href="/pkg/io#EOF">io.EOF</a></li>
+</ul>`,
+		},
+	} {
+		got := r.formatDocHTML(test.doc, false)
+		want := testconversions.MakeHTMLForTest(test.want)
+		if diff := cmp.Diff(want, got, cmp.AllowUnexported(safehtml.HTML{})); diff != "" {
+			t.Errorf("%q: mismatch (-want +got)\n%s", test.doc, diff)
+		}
+	}
+}
+
 func TestDeclHTML(t *testing.T) {
 	for _, test := range []struct {
 		name   string
@@ -27,6 +27,7 @@ var (
 type Renderer struct {
 	fset          *token.FileSet
 	pids          *packageIDs
+	importPaths   map[string]string // package name to path, for imports of the package
 	packageURL    func(string) string
 	ctx           context.Context
 	docTmpl       *template.Template
@@ -98,6 +99,7 @@ func New(ctx context.Context, fset *token.FileSet, pkg *doc.Package, opts *Optio
 	return &Renderer{
 		fset:          fset,
 		pids:          pids,
+		importPaths:   importPathsByName(pkg.Name, pkg.Imports),
 		packageURL:    packageURL,
 		docTmpl:       docDataTmpl,
 		exampleTmpl:   exampleTmpl,
@@ -0,0 +1,156 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/google/safehtml"
+	"github.com/google/safehtml/template"
+	"github.com/google/safehtml/uncheckedconversions"
+	"golang.org/x/pkgsite/internal/godoc/dochtml/internal/render"
+)
+
+// sourceTmpl renders a Go source file. It expects a []sourceLine.
+//
+// Each line has an id of the form "L<n>", matching the fragments of the line
+// URLs of source.Info.
+var sourceTmpl = template.Must(template.New("").Parse(`<pre class="SourceFile">
+{{- range . -}}
+<span class="SourceFile-line" id="{{.ID}}"><a class="SourceFile-lineNumber" href="#{{.ID}}">{{.Number}}</a>
+{{- range .Spans -}}
+{{- if eq .Kind "comment" -}}<span class="SourceFile-comment">{{.Text}}</span>
+{{- else if eq .Kind "string" -}}<span class="SourceFile-string">{{.Text}}</span>
+{{- else if eq .Kind "keyword" -}}<span class="SourceFile-keyword">{{.Text}}</span>
+{{- else -}}{{.Text}}
+{{- end -}}
+{{- end}}
+</span>
+{{- end -}}
+</pre>`))
+
+type sourceLine struct {
+	Number int
+	ID     safehtml.Identifier
+	Spans  []sourceSpan
+}
+
+// A sourceSpan is a piece of a line of source. Its Kind is "comment",
+// "string", "keyword" or empty for other text.
+type sourceSpan struct {
+	Kind string
+	Text string
+}
+
+// RenderSource renders the contents of a Go source file as HTML, with line
+// numbers and syntax highlighting. Each line can be linked to with a fragment
+// of the form "#L<n>", where n is its 1-based line number.
+//
+// The source need not be valid Go: text that can't be scanned is rendered
+// without highlighting.
+func RenderSource(src []byte) safehtml.HTML {
+	var (
+		lines []*sourceLine
+		cur   *sourceLine
+	)
+	newLine := func() {
+		n := len(lines) + 1
+		// IdentifierFromConstantPrefix would add a hyphen after the "L".
+		id := uncheckedconversions.IdentifierFromStringKnownToSatisfyTypeContract("L" + strconv.Itoa(n))
+		cur = &sourceLine{Number: n, ID: id}
+		lines = append(lines, cur)
+	}
+	newLine()
+	// add appends text to the current line, starting new lines at newlines.
+	add := func(kind, text string) {
+		for {
+			i := strings.IndexByte(text, '\n')
+			if i < 0 {
+				break
+			}
+			if i > 0 {
+				cur.Spans = append(cur.Spans, sourceSpan{kind, text[:i]})
+			}
+			newLine()
+			text = text[i+1:]
+		}
+		if text != "" {
+			cur.Spans = append(cur.Spans, sourceSpan{kind, text})
+		}
+	}
+
+	s := string(src)
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var sc scanner.Scanner
+	// Ignore errors, so that the rest of the file is still highlighted.
+	sc.Init(file, src, func(token.Position, string) {}, scanner.ScanComments)
+	last := 0 // offset in s of the first byte not yet added
+	for {
+		pos, tok, lit := sc.Scan()
+		if tok == token.EOF {
+			break
+		}
+		var kind string
+		switch {
+		case tok == token.COMMENT:
+			kind = "comment"
+		case tok == token.STRING || tok == token.CHAR:
+			kind = "string"
+		case tok.IsKeyword():
+			kind = "keyword"
+		default:
+			continue
+		}
+		start := file.Offset(pos)
+		end := tokenEnd(s, start, lit)
+		if start < last || end > len(s) {
+			continue
+		}
+		add("", s[last:start])
+		add(kind, s[start:end])
+		last = end
+	}
+	add("", s[last:])
+	// Don't render an empty line after a final newline.
+	if len(lines) > 1 && len(cur.Spans) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return render.ExecuteToHTML(sourceTmpl, lines)
+}
+
+// tokenEnd returns the offset in s of the end of the token starting at
+// start, whose literal value is lit.
+//
+// The scanner removes carriage returns from comments and raw strings, so the
+// end of those is found in s itself.
+func tokenEnd(s string, start int, lit string) int {
+	rest := s[start:]
+	var n int
+	switch {
+	case strings.HasPrefix(rest, "//"):
+		n = strings.IndexByte(rest, '\n')
+	case strings.HasPrefix(rest, "/*"):
+		n = strings.Index(rest, "*/")
+		if n >= 0 {
+			n += len("*/")
+		}
+	case strings.HasPrefix(rest, "`"):
+		n = strings.IndexByte(rest[1:], '`')
+		if n >= 0 {
+			n += 2
+		}
+	default:
+		n = len(lit)
+	}
+	if n < 0 {
+		// Unterminated or at the end of the file.
+		n = len(rest)
+	}
+	return start + n
+}
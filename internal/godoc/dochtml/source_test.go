@@ -0,0 +1,34 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSource(t *testing.T) {
+	src := "// Package p is <for> testing.\npackage p\n\n/* a\nb */\nconst s = `x\ny` + \"<&>\"\n"
+	got := RenderSource([]byte(src)).String()
+	for _, want := range []string{
+		`<pre class="SourceFile">`,
+		`<span class="SourceFile-line" id="L1"><a class="SourceFile-lineNumber" href="#L1">1</a><span class="SourceFile-comment">// Package p is &lt;for&gt; testing.</span>`,
+		`id="L2"><a class="SourceFile-lineNumber" href="#L2">2</a><span class="SourceFile-keyword">package</span> p`,
+		// Multi-line tokens are split across lines.
+		`<span class="SourceFile-comment">/* a</span>`,
+		`href="#L5">5</a><span class="SourceFile-comment">b */</span>`,
+		`<span class="SourceFile-string">y` + "`" + `</span> + <span class="SourceFile-string">&#34;&lt;&amp;&gt;&#34;</span>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, `id="L8"`) {
+		t.Errorf("got a line after the final newline:\n%s", got)
+	}
+	if strings.Contains(got, "<for>") {
+		t.Errorf("code was not escaped:\n%s", got)
+	}
+}
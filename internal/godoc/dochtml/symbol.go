@@ -44,14 +44,14 @@ func constants(consts []*doc.Value) []*internal.Symbol {
 			if n == "_" {
 				continue
 			}
-			syms = append(syms, &internal.Symbol{
-				SymbolMeta: internal.SymbolMeta{
-					Name:     n,
-					Synopsis: "const " + n,
-					Section:  internal.SymbolSectionConstants,
-					Kind:     internal.SymbolKindConstant,
-				},
-			})
+			sm := internal.SymbolMeta{
+				Name:     n,
+				Synopsis: "const " + n,
+				Section:  internal.SymbolSectionConstants,
+				Kind:     internal.SymbolKindConstant,
+			}
+			sm.Deprecated, sm.DeprecationComment = Deprecation(c.Doc)
+			syms = append(syms, &internal.Symbol{SymbolMeta: sm})
 		}
 	}
 	return syms
@@ -73,15 +73,14 @@ func variables(vars []*doc.Value, fset *token.FileSet) (_ []*internal.Symbol, er
 					vs.Names = []*ast.Ident{ident}
 				}
 				syn := render.ConstOrVarSynopsis(&vs, fset, token.VAR, "", 0, 0)
-				syms = append(syms,
-					&internal.Symbol{
-						SymbolMeta: internal.SymbolMeta{
-							Name:     ident.Name,
-							Synopsis: syn,
-							Section:  internal.SymbolSectionVariables,
-							Kind:     internal.SymbolKindVariable,
-						},
-					})
+				sm := internal.SymbolMeta{
+					Name:     ident.Name,
+					Synopsis: syn,
+					Section:  internal.SymbolSectionVariables,
+					Kind:     internal.SymbolKindVariable,
+				}
+				sm.Deprecated, sm.DeprecationComment = Deprecation(v.Doc)
+				syms = append(syms, &internal.Symbol{SymbolMeta: sm})
 			}
 
 		}
@@ -92,14 +91,14 @@ func variables(vars []*doc.Value, fset *token.FileSet) (_ []*internal.Symbol, er
 func functions(p *doc.Package, fset *token.FileSet) []*internal.Symbol {
 	var syms []*internal.Symbol
 	for _, f := range p.Funcs {
-		syms = append(syms, &internal.Symbol{
-			SymbolMeta: internal.SymbolMeta{
-				Name:     f.Name,
-				Synopsis: render.OneLineNodeDepth(fset, f.Decl, 0),
-				Section:  internal.SymbolSectionFunctions,
-				Kind:     internal.SymbolKindFunction,
-			},
-		})
+		sm := internal.SymbolMeta{
+			Name:     f.Name,
+			Synopsis: render.OneLineNodeDepth(fset, f.Decl, 0),
+			Section:  internal.SymbolSectionFunctions,
+			Kind:     internal.SymbolKindFunction,
+		}
+		sm.Deprecated, sm.DeprecationComment = Deprecation(f.Doc)
+		syms = append(syms, &internal.Symbol{SymbolMeta: sm})
 	}
 	return syms
 }
@@ -127,6 +126,7 @@ func types(p *doc.Package, fset *token.FileSet) ([]*internal.Symbol, error) {
 				Kind:     internal.SymbolKindType,
 			},
 		}
+		t.Deprecated, t.DeprecationComment = Deprecation(typ.Doc)
 		fields := fieldsForType(typ.Name, spec, fset)
 		if err != nil {
 			return nil, err
@@ -177,13 +177,15 @@ func variablesForType(t *doc.Type, fset *token.FileSet) (_ []*internal.SymbolMet
 func functionsForType(t *doc.Type, fset *token.FileSet) []*internal.SymbolMeta {
 	var syms []*internal.SymbolMeta
 	for _, f := range t.Funcs {
-		syms = append(syms, &internal.SymbolMeta{
+		sm := &internal.SymbolMeta{
 			Name:       f.Name,
 			ParentName: t.Name,
 			Kind:       internal.SymbolKindFunction,
 			Synopsis:   render.OneLineNodeDepth(fset, f.Decl, 0),
 			Section:    internal.SymbolSectionTypes,
-		})
+		}
+		sm.Deprecated, sm.DeprecationComment = Deprecation(f.Doc)
+		syms = append(syms, sm)
 	}
 	return syms
 }
@@ -201,13 +203,15 @@ func fieldsForType(typName string, spec *ast.TypeSpec, fset *token.FileSet) []*i
 		for _, n := range f.Names {
 			synopsis := fmt.Sprintf("%s %s", n, render.OneLineNodeDepth(fset, f.Type, 0))
 			name := typName + "." + n.Name
-			syms = append(syms, &internal.SymbolMeta{
+			sm := &internal.SymbolMeta{
 				Name:       name,
 				ParentName: typName,
 				Kind:       internal.SymbolKindField,
 				Synopsis:   synopsis,
 				Section:    internal.SymbolSectionTypes,
-			})
+			}
+			sm.Deprecated, sm.DeprecationComment = Deprecation(f.Doc.Text())
+			syms = append(syms, sm)
 		}
 	}
 	return syms
@@ -216,13 +220,15 @@ func fieldsForType(typName string, spec *ast.TypeSpec, fset *token.FileSet) []*i
 func methodsForType(t *doc.Type, spec *ast.TypeSpec, fset *token.FileSet) ([]*internal.SymbolMeta, error) {
 	var syms []*internal.SymbolMeta
 	for _, m := range t.Methods {
-		syms = append(syms, &internal.SymbolMeta{
+		sm := &internal.SymbolMeta{
 			Name:       t.Name + "." + m.Name,
 			ParentName: t.Name,
 			Kind:       internal.SymbolKindMethod,
 			Synopsis:   render.OneLineNodeDepth(fset, m.Decl, 0),
 			Section:    internal.SymbolSectionTypes,
-		})
+		}
+		sm.Deprecated, sm.DeprecationComment = Deprecation(m.Doc)
+		syms = append(syms, sm)
 	}
 	if st, ok := spec.Type.(*ast.InterfaceType); ok {
 		for _, m := range st.Methods.List {
@@ -235,13 +241,15 @@ func methodsForType(t *doc.Type, spec *ast.TypeSpec, fset *token.FileSet) ([]*in
 			for _, n := range m.Names {
 				name := t.Name + "." + n.Name
 				synopsis := render.OneLineField(fset, m, 0)
-				syms = append(syms, &internal.SymbolMeta{
+				sm := &internal.SymbolMeta{
 					Name:       name,
 					ParentName: t.Name,
 					Kind:       internal.SymbolKindMethod,
 					Synopsis:   synopsis,
 					Section:    internal.SymbolSectionTypes,
-				})
+				}
+				sm.Deprecated, sm.DeprecationComment = Deprecation(m.Doc.Text())
+				syms = append(syms, sm)
 			}
 		}
 	}
@@ -0,0 +1,105 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/token"
+	"strings"
+
+	"github.com/google/safehtml"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// A SymbolDoc is the rendered documentation of a single symbol.
+type SymbolDoc struct {
+	// Name is the name of the symbol, such as "F" or "T.M".
+	Name string
+	// Synopsis is a one-line summary of the symbol's declaration.
+	Synopsis string
+	// Decl is the HTML of the declaration, which for a constant or variable
+	// is the whole declaration group it belongs to.
+	Decl safehtml.HTML
+	// Doc is the HTML of the doc comment.
+	Doc safehtml.HTML
+	// SourceURL is the URL of the declaration in the source, if known.
+	SourceURL string
+}
+
+// RenderSymbol renders the documentation of the exported symbol with the
+// given name in p, without rendering the rest of the package. The name is
+// that of a top-level declaration, or of a type followed by a dot and one
+// of its methods. RenderSymbol returns an error wrapping derrors.NotFound if
+// there is no such symbol.
+//
+// Only the SourceLinkFunc, ModInfo, BuildContext and PackageURLTemplate
+// fields of opt are used.
+func RenderSymbol(ctx context.Context, fset *token.FileSet, p *doc.Package, name string, opt RenderOptions) (_ *SymbolDoc, err error) {
+	defer derrors.Wrap(&err, "dochtml.RenderSymbol(%q)", name)
+
+	last := name[strings.LastIndexByte(name, '.')+1:]
+	if !token.IsExported(last) {
+		return nil, fmt.Errorf("%q is not exported: %w", name, derrors.NotFound)
+	}
+	var items []*item
+	consts, vars, funcs, types := packageToItems(p, collectExamples(p).Map)
+	for _, is := range [][]*item{consts, vars, funcs, types} {
+		items = append(items, is...)
+	}
+	it := findItem(items, name)
+	if it == nil {
+		return nil, fmt.Errorf("no symbol %q: %w", name, derrors.NotFound)
+	}
+	r := newRenderer(ctx, fset, p, opt)
+	decl := r.DeclHTML(it.Doc, it.Decl)
+	sd := &SymbolDoc{
+		Name:     name,
+		Synopsis: r.Synopsis(it.Decl),
+		Decl:     decl.Decl,
+		Doc:      decl.Doc,
+	}
+	if opt.SourceLinkFunc != nil {
+		sd.SourceURL = opt.SourceLinkFunc(it.Decl)
+	}
+	return sd, nil
+}
+
+// findItem returns the item in items, or in the items of their types, that
+// documents name, or nil if there is none.
+func findItem(items []*item, name string) *item {
+	for _, it := range items {
+		if it.FullName == name || (it.Name == "" && declares(it.Decl, name)) {
+			return it
+		}
+		for _, sub := range [][]*item{it.Consts, it.Vars, it.Funcs, it.Methods} {
+			if it := findItem(sub, name); it != nil {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+// declares reports whether decl, a constant or variable declaration,
+// declares name.
+func declares(decl ast.Decl, name string) bool {
+	gd, ok := decl.(*ast.GenDecl)
+	if !ok {
+		return false
+	}
+	for _, spec := range gd.Specs {
+		if vs, ok := spec.(*ast.ValueSpec); ok {
+			for _, n := range vs.Names {
+				if n.Name == name {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/doc"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+func TestRenderSymbol(t *testing.T) {
+	const src = `
+// Package p is for testing.
+package p
+
+// Max is the maximum.
+const (
+	Max = 10
+	min = 0
+)
+
+// F returns a greeting.
+func F(name string) string { return "" }
+
+// T is a type.
+type T int
+
+// NewT returns a T.
+func NewT() T { return 0 }
+
+// M is a method.
+func (T) M() {}
+
+func (T) m() {}
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := doc.NewFromFiles(fset, []*ast.File{f}, "example.com/module/p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, test := range []struct {
+		name, wantSynopsis, wantDoc string
+	}{
+		{"F", "func F(name string) string", "F returns a greeting."},
+		{"Max", "const Max = 10", "Max is the maximum."},
+		{"T", "type T int", "T is a type."},
+		{"NewT", "func NewT() T", "NewT returns a T."},
+		{"T.M", "func (T) M()", "M is a method."},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := RenderSymbol(ctx, fset, d, test.name, testRenderOptions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != test.name {
+				t.Errorf("got name %q, want %q", got.Name, test.name)
+			}
+			if got.Synopsis != test.wantSynopsis {
+				t.Errorf("got synopsis %q, want %q", got.Synopsis, test.wantSynopsis)
+			}
+			if !strings.Contains(got.Doc.String(), test.wantDoc) {
+				t.Errorf("doc %q does not contain %q", got.Doc, test.wantDoc)
+			}
+			if got.Decl.String() == "" {
+				t.Error("empty declaration")
+			}
+			if got.SourceURL != "src" {
+				t.Errorf("got source URL %q, want %q", got.SourceURL, "src")
+			}
+		})
+	}
+	for _, name := range []string{"min", "T.m", "G", "T.N"} {
+		if _, err := RenderSymbol(ctx, fset, d, name, testRenderOptions); !errors.Is(err, derrors.NotFound) {
+			t.Errorf("%s: got %v, want NotFound", name, err)
+		}
+	}
+}
@@ -5,18 +5,22 @@
 package dochtml
 
 import (
+	"errors"
 	"go/doc"
 	"path"
 	"reflect"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/safehtml"
 	"github.com/google/safehtml/template"
+	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/godoc/dochtml/internal/render"
 )
 
 var (
 	loadOnce sync.Once
+	loaded   atomic.Bool // set after the templates are loaded
 
 	// TODO(golang.org/issue/5060): finalize URL scheme and design for notes,
 	// then it becomes more viable to factor out inline CSS style.
@@ -39,9 +43,38 @@ func LoadTemplates(fsys template.TrustedFS) {
 		sidenavTemplate = template.Must(template.New("sidenav-mobile.tmpl").
 			Funcs(tmpl).
 			ParseFS(fsys, path.Join(dir, "sidenav-mobile.tmpl")))
+		loaded.Store(true)
 	})
 }
 
+// TemplatesLoaded reports whether LoadTemplates has been called, so that
+// documentation can be rendered.
+func TemplatesLoaded() bool {
+	return loaded.Load()
+}
+
+// ParseBodyTemplate returns a template for the body of the documentation,
+// for use in RenderOptions.BodyTemplate. It starts from the templates loaded
+// by LoadTemplates, which must have been called, and adds the templates in
+// the files of fsys matching patterns. A file replaces the template with its
+// base name, and the templates it defines replace those of the same name.
+//
+// For example, a file named body.tmpl replaces the structure of the whole
+// body, while one that defines "declaration" changes only how declarations
+// are wrapped. The templates have the same data and functions as the
+// defaults.
+func ParseBodyTemplate(fsys template.TrustedFS, patterns ...string) (_ *template.Template, err error) {
+	defer derrors.Wrap(&err, "ParseBodyTemplate(%v)", patterns)
+	if bodyTemplate == nil {
+		return nil, errors.New("LoadTemplates has not been called")
+	}
+	t, err := bodyTemplate.Clone()
+	if err != nil {
+		return nil, err
+	}
+	return t.ParseFS(fsys, patterns...)
+}
+
 var tmpl = map[string]any{
 	"ternary": func(q, a, b any) any {
 		v := reflect.ValueOf(q)
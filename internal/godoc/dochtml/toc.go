@@ -0,0 +1,114 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"go/ast"
+	"sort"
+)
+
+// A TOCEntry is an entry in the table of contents of the documentation of a
+// package. It holds the same links as the outline.
+type TOCEntry struct {
+	// Title is the text of the entry, such as "Constants" or "F(x)".
+	Title string
+	// Anchor is the id of the element of the body that the entry links to.
+	Anchor string
+	// Children are the entries nested under this one, such as the symbols
+	// of a section or the methods of a type.
+	Children []*TOCEntry
+}
+
+// buildTOC returns the table of contents for the documentation described by
+// data. It has an entry for each top-level section, and the entries for
+// those that list symbols have a child for each symbol. shortSynopsis is used
+// for the titles of functions, like render_short_synopsis in the outline
+// template.
+func buildTOC(data templateData, shortSynopsis func(ast.Node) (string, error)) []*TOCEntry {
+	p := data.Package
+	synopsis := func(it *item) string {
+		s, err := shortSynopsis(it.Decl)
+		if err != nil {
+			return it.FullName
+		}
+		return s
+	}
+	funcEntries := func(items []*item) []*TOCEntry {
+		var es []*TOCEntry
+		for _, it := range items {
+			es = append(es, &TOCEntry{Title: synopsis(it), Anchor: it.FullName})
+		}
+		return es
+	}
+	valueEntries := func(items []*item) []*TOCEntry {
+		var es []*TOCEntry
+		for _, it := range items {
+			for _, name := range declNames(it.Decl) {
+				es = append(es, &TOCEntry{Title: name, Anchor: name})
+			}
+		}
+		return es
+	}
+
+	var toc []*TOCEntry
+	if p.Doc != "" || len(data.Examples.Map[""]) > 0 {
+		toc = append(toc, &TOCEntry{Title: "Overview", Anchor: "pkg-overview"})
+	}
+	if len(data.Consts) > 0 || len(data.Vars) > 0 || len(data.Funcs) > 0 || len(data.Types) > 0 {
+		index := &TOCEntry{Title: "Index", Anchor: "pkg-index"}
+		if len(data.Examples.List) > 0 {
+			index.Children = []*TOCEntry{{Title: "Examples", Anchor: "pkg-examples"}}
+		}
+		types := &TOCEntry{Title: "Types", Anchor: "pkg-types"}
+		for _, t := range data.Types {
+			te := &TOCEntry{Title: "type " + t.Name, Anchor: t.Name}
+			te.Children = append(te.Children, valueEntries(t.Consts)...)
+			te.Children = append(te.Children, valueEntries(t.Vars)...)
+			te.Children = append(te.Children, funcEntries(t.Funcs)...)
+			te.Children = append(te.Children, funcEntries(t.Methods)...)
+			types.Children = append(types.Children, te)
+		}
+		toc = append(toc,
+			index,
+			&TOCEntry{Title: "Constants", Anchor: "pkg-constants", Children: valueEntries(data.Consts)},
+			&TOCEntry{Title: "Variables", Anchor: "pkg-variables", Children: valueEntries(data.Vars)},
+			&TOCEntry{Title: "Functions", Anchor: "pkg-functions", Children: funcEntries(data.Funcs)},
+			types)
+	}
+	if len(p.Notes) > 0 {
+		notes := &TOCEntry{Title: "Notes", Anchor: "pkg-notes"}
+		var markers []string
+		for m := range p.Notes {
+			markers = append(markers, m)
+		}
+		sort.Strings(markers)
+		for _, m := range markers {
+			h := data.NoteHeaders[m]
+			notes.Children = append(notes.Children, &TOCEntry{Title: h.Label + "s", Anchor: h.SafeIdentifier.String()})
+		}
+		toc = append(toc, notes)
+	}
+	return toc
+}
+
+// declNames returns the names declared by decl, a constant or variable
+// declaration, in order.
+func declNames(decl ast.Decl) []string {
+	gd, ok := decl.(*ast.GenDecl)
+	if !ok {
+		return nil
+	}
+	var names []string
+	for _, spec := range gd.Specs {
+		if vs, ok := spec.(*ast.ValueSpec); ok {
+			for _, n := range vs.Names {
+				if n.Name != "_" {
+					names = append(names, n.Name)
+				}
+			}
+		}
+	}
+	return names
+}
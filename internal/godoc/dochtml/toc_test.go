@@ -0,0 +1,68 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package dochtml
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderTOC(t *testing.T) {
+	LoadTemplates(templateFS)
+	fset, d := mustLoadPackage("everydecl")
+	parts, err := Render(context.Background(), fset, d, testRenderOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sections []string
+	for _, e := range parts.TOC {
+		sections = append(sections, e.Anchor)
+	}
+	wantSections := []string{"pkg-overview", "pkg-index", "pkg-constants", "pkg-variables", "pkg-functions", "pkg-types", "pkg-notes"}
+	if diff := cmp.Diff(wantSections, sections); diff != "" {
+		t.Errorf("sections mismatch (-want, +got):\n%s", diff)
+	}
+
+	titles := map[string]string{}
+	var add func([]*TOCEntry)
+	add = func(es []*TOCEntry) {
+		for _, e := range es {
+			if !strings.HasPrefix(e.Anchor, "pkg-") {
+				titles[e.Anchor] = e.Title
+			}
+			add(e.Children)
+		}
+	}
+	add(parts.TOC)
+	want := map[string]string{
+		"C":   "C",
+		"V":   "V",
+		"F":   "F()",
+		"A":   "type A",
+		"B":   "type B",
+		"I1":  "type I1",
+		"I2":  "type I2",
+		"S1":  "type S1",
+		"S2":  "type S2",
+		"T":   "type T",
+		"CT":  "CT",
+		"VT":  "VT",
+		"TF":  "TF()",
+		"T.M": "M()",
+	}
+	if diff := cmp.Diff(want, titles); diff != "" {
+		t.Errorf("symbols mismatch (-want, +got):\n%s", diff)
+	}
+	// Every anchor must be the id of an element of the body.
+	for anchor := range titles {
+		if !strings.Contains(parts.Body.String(), `id="`+anchor+`"`) {
+			t.Errorf("no element with id %q in body", anchor)
+		}
+	}
+}
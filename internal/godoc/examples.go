@@ -0,0 +1,205 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/ast"
+	"go/doc"
+	"go/token"
+	"go/types"
+	"path"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+)
+
+// examplesValid reports whether the examples of d that have output comments
+// refer only to identifiers that are defined. It does not type-check the
+// examples, since the package's dependencies are not available, but it does
+// catch examples that use identifiers that have been renamed or removed.
+//
+// Identifiers qualified with the name of an import other than the package
+// itself are assumed to be defined.
+func (p *Package) examplesValid(d *doc.Package) bool {
+	files := map[string]*ast.File{}
+	for _, f := range p.Files {
+		files[f.Name] = f.AST
+	}
+	for _, e := range allExamples(d) {
+		if e.Output == "" && !e.EmptyOutput {
+			continue
+		}
+		if e.Code == nil {
+			continue
+		}
+		f := files[p.Fset.Position(e.Code.Pos()).Filename]
+		if f == nil {
+			continue
+		}
+		if !p.exampleValid(d, f, e.Code) {
+			return false
+		}
+	}
+	return true
+}
+
+// exampleValid reports whether the identifiers in code, which is part of
+// the file f, are defined.
+func (p *Package) exampleValid(d *doc.Package, f *ast.File, code ast.Node) bool {
+	// Names that can be used unqualified in the file.
+	scope := p.topLevelNames(f.Name.Name)
+	// Imported package names, and whether each is the documented package.
+	imports := map[string]bool{}
+	guessed := false // whether the name of some import was guessed
+	for _, spec := range f.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			return false
+		}
+		var name string
+		switch {
+		case spec.Name != nil:
+			name = spec.Name.Name
+		case importPath == d.ImportPath:
+			name = d.Name
+		default:
+			name = guessPackageName(importPath)
+			guessed = true
+		}
+		switch name {
+		case ".":
+			// Anything could be defined.
+			return true
+		case "_":
+			continue
+		}
+		imports[name] = importPath == d.ImportPath
+	}
+	pkgNames := p.topLevelNames(d.Name)
+
+	// Identifiers used as the X of a selector.
+	qualifiers := map[*ast.Ident]*ast.SelectorExpr{}
+	ast.Inspect(code, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				qualifiers[id] = sel
+			}
+		}
+		return true
+	})
+	for _, id := range f.Unresolved {
+		if id.Pos() < code.Pos() || id.Pos() >= code.End() {
+			continue
+		}
+		if isSelf, ok := imports[id.Name]; ok {
+			sel := qualifiers[id]
+			if isSelf && sel != nil && (!token.IsExported(sel.Sel.Name) || !pkgNames[sel.Sel.Name]) {
+				return false
+			}
+			continue
+		}
+		if scope[id.Name] || types.Universe.Lookup(id.Name) != nil {
+			continue
+		}
+		if qualifiers[id] != nil && guessed {
+			// The package name of an import may not have been guessed
+			// correctly.
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+// topLevelNames returns the names declared at the top level of the files of
+// p whose package clause is pkgName. Methods are not included.
+func (p *Package) topLevelNames(pkgName string) map[string]bool {
+	if names := p.declared[pkgName]; names != nil {
+		return names
+	}
+	names := map[string]bool{}
+	for _, f := range p.Files {
+		if f.AST.Name.Name == pkgName {
+			addTopLevelNames(names, f.AST)
+		}
+	}
+	return names
+}
+
+// addTopLevelNames adds the names declared at the top level of f to names.
+func addTopLevelNames(names map[string]bool, f *ast.File) {
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil {
+				names[decl.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					names[spec.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, n := range spec.Names {
+						names[n.Name] = true
+					}
+				}
+			}
+		}
+	}
+}
+
+// guessPackageName returns the likely package name of the package with the
+// given import path, which is usually its last element without a major
+// version suffix.
+func guessPackageName(importPath string) string {
+	if prefix, _, ok := module.SplitPathVersion(importPath); ok && prefix != "" {
+		importPath = prefix
+	}
+	name := path.Base(importPath)
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexAny(name, ".-"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// examples returns the examples of d, classified as package or symbol
+// examples, or nil if d has none.
+func examples(d *doc.Package) *internal.Examples {
+	var exs internal.Examples
+	dochtml.WalkExamples(d, func(id string, ex *doc.Example) {
+		e := &internal.Example{Symbol: id, Suffix: ex.Suffix, Doc: ex.Doc, Output: ex.Output}
+		if id == "" {
+			exs.Package = append(exs.Package, e)
+		} else {
+			exs.Symbol = append(exs.Symbol, e)
+		}
+	})
+	if exs.Package == nil && exs.Symbol == nil {
+		return nil
+	}
+	return &exs
+}
+
+// allExamples returns all the examples of d.
+func allExamples(d *doc.Package) []*doc.Example {
+	exs := append([]*doc.Example(nil), d.Examples...)
+	addFuncs := func(fs []*doc.Func) {
+		for _, f := range fs {
+			exs = append(exs, f.Examples...)
+		}
+	}
+	addFuncs(d.Funcs)
+	for _, t := range d.Types {
+		exs = append(exs, t.Examples...)
+		addFuncs(t.Funcs)
+		addFuncs(t.Methods)
+	}
+	return exs
+}
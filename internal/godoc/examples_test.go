@@ -0,0 +1,151 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+)
+
+func TestExamplesValid(t *testing.T) {
+	const pkgFile = `
+package p
+
+type T int
+
+func (T) M() int { return 0 }
+
+func F() int { return 1 }
+
+func helper() int { return 2 }
+`
+	for _, test := range []struct {
+		name     string
+		testFile string
+		want     bool
+	}{
+		{
+			name: "external",
+			testFile: `
+package p_test
+
+import (
+	"fmt"
+	"strings"
+
+	"example.com/p"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func ExampleF() {
+	var t p.T
+	x := p.F() + t.M()
+	fmt.Println(strings.Repeat("a", x), yaml.Marshal, len(local()))
+	// Output: a
+}
+
+func local() string { return "" }
+`,
+			want: true,
+		},
+		{
+			name: "internal",
+			testFile: `
+package p
+
+import "fmt"
+
+func ExampleF() {
+	fmt.Println(F() + helper())
+	// Output: 3
+}
+`,
+			want: true,
+		},
+		{
+			name: "no output",
+			testFile: `
+package p_test
+
+import "example.com/p"
+
+func ExampleF() {
+	p.Missing()
+}
+`,
+			want: true,
+		},
+		{
+			name: "missing exported symbol",
+			testFile: `
+package p_test
+
+import (
+	"fmt"
+
+	"example.com/p"
+)
+
+func ExampleF() {
+	fmt.Println(p.Missing())
+	// Output: 1
+}
+`,
+			want: false,
+		},
+		{
+			name: "unexported symbol",
+			testFile: `
+package p_test
+
+import (
+	"fmt"
+
+	"example.com/p"
+)
+
+func ExampleF() {
+	fmt.Println(p.helper())
+	// Output: 2
+}
+`,
+			want: false,
+		},
+		{
+			name: "undefined identifier",
+			testFile: `
+package p
+
+import "fmt"
+
+func ExampleF() {
+	fmt.Println(undefined())
+	// Output: 1
+}
+`,
+			want: false,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			fset := token.NewFileSet()
+			p := NewPackage(fset, nil)
+			for name, src := range map[string]string{"p.go": pkgFile, "example_test.go": test.testFile} {
+				f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+				if err != nil {
+					t.Fatal(err)
+				}
+				p.AddFile(f, true)
+			}
+			d, err := p.docPackage("", &ModuleInfo{ModulePath: "example.com/p", ResolvedVersion: "v1.0.0"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.examplesValid(d); got != test.want {
+				t.Errorf("got %t, want %t", got, test.want)
+			}
+		})
+	}
+}
@@ -22,6 +22,10 @@ type Package struct {
 	Fset *token.FileSet
 	encPackage
 	renderCalled bool
+	// declared holds the names declared at the top level of the added
+	// files, by package clause. It is recorded before AddFile removes any
+	// nodes, and is not encoded.
+	declared map[string]map[string]bool
 }
 
 // encPackage holds the fields of Package that can be directly encoded.
@@ -61,6 +65,13 @@ func NewPackage(fset *token.FileSet, modPaths map[string]bool) *Package {
 // are unsuitable for anything other than the methods of this package.
 func (p *Package) AddFile(f *ast.File, removeNodes bool) {
 	filename := p.Fset.Position(f.Package).Filename
+	if p.declared == nil {
+		p.declared = map[string]map[string]bool{}
+	}
+	if p.declared[f.Name.Name] == nil {
+		p.declared[f.Name.Name] = map[string]bool{}
+	}
+	addTopLevelNames(p.declared[f.Name.Name], f)
 	// Don't trim anything from a test file or one in a XXX_test package; it
 	// may be part of a playable example.
 	if removeNodes && !strings.HasSuffix(filename, "_test.go") && !strings.HasSuffix(f.Name.Name, "_test") {
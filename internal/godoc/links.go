@@ -0,0 +1,65 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// StripExternalLinks returns the documentation HTML h with links that leave
+// the page removed: links to identifiers in other packages, to source files,
+// and to other sites. The text of each removed link is kept. Links to
+// fragments of the page itself, like those from the index to the
+// declarations, are kept together with all other markup. It can be applied
+// to the HTML read with internal.WithDocHTML, for embedding the documentation
+// where outbound links are undesirable.
+func StripExternalLinks(h string) (_ string, err error) {
+	defer derrors.Wrap(&err, "StripExternalLinks")
+
+	var (
+		b     strings.Builder
+		depth int // number of open links that were removed
+	)
+	z := html.NewTokenizer(strings.NewReader(h))
+	for {
+		tt := z.Next()
+		// Token and TagName may modify the slice returned by Raw.
+		raw := string(z.Raw())
+		switch tt {
+		case html.ErrorToken:
+			if err := z.Err(); !errors.Is(err, io.EOF) {
+				return "", err
+			}
+			return b.String(), nil
+		case html.StartTagToken:
+			if t := z.Token(); t.DataAtom == atom.A && isExternalLink(t) {
+				depth++
+				continue
+			}
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "a" && depth > 0 {
+				depth--
+				continue
+			}
+		}
+		b.WriteString(raw)
+	}
+}
+
+// isExternalLink reports whether the a element t links outside of the page.
+func isExternalLink(t html.Token) bool {
+	for _, a := range t.Attr {
+		if a.Key == "href" {
+			return !strings.HasPrefix(a.Val, "#")
+		}
+	}
+	return false
+}
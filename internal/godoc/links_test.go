@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import "testing"
+
+func TestStripExternalLinks(t *testing.T) {
+	for _, test := range []struct {
+		name, in, want string
+	}{
+		{
+			name: "no links",
+			in:   `<p>Package p is <em>documented</em>.</p>`,
+			want: `<p>Package p is <em>documented</em>.</p>`,
+		},
+		{
+			name: "identifier in another package",
+			in:   `<pre>func F() *<a href="/strings#Builder">strings.Builder</a></pre>`,
+			want: `<pre>func F() *strings.Builder</pre>`,
+		},
+		{
+			name: "source and other sites",
+			in:   `<a class="Documentation-source" href="https://example.com/p.go#L3">func F</a> see <a href="https://go.dev">go.dev</a>`,
+			want: `func F see go.dev`,
+		},
+		{
+			name: "fragment links are kept",
+			in:   `<a href="#F">F</a> returns <a href="/fmt#Stringer">fmt.Stringer</a>`,
+			want: `<a href="#F">F</a> returns fmt.Stringer`,
+		},
+		{
+			name: "markup inside a link is kept",
+			in:   `<a href="/io#Reader"><code>io.Reader</code></a>`,
+			want: `<code>io.Reader</code>`,
+		},
+		{
+			name: "anchor without href",
+			in:   `<a id="F"></a>`,
+			want: `<a id="F"></a>`,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := StripExternalLinks(test.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != test.want {
+				t.Errorf("got  %s\nwant %s", got, test.want)
+			}
+		})
+	}
+}
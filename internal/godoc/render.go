@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"go/ast"
 	"go/doc"
+	"go/token"
 	"path"
 	"sort"
 	"strings"
@@ -39,24 +40,115 @@ const (
 // It is a variable for testing.
 var MaxDocumentationHTML = 20 * megabyte
 
+// DocBodyTemplate, if non-nil, replaces the default template for the body of
+// rendered documentation. See dochtml.ParseBodyTemplate.
+var DocBodyTemplate *template.Template
+
+// DocInfo is information extracted from a package's documentation.
+type DocInfo struct {
+	Synopsis string
+	// Usage is the usage synopsis of a command, if any.
+	Usage   string
+	Imports []string
+	API     []*internal.Symbol
+	Stats   internal.DocStats
+	// ExamplesValid reports whether the examples with output comments refer
+	// only to defined identifiers.
+	ExamplesValid bool
+	// Examples holds the package's examples, or nil if there are none.
+	Examples *internal.Examples
+	// Deprecated reports whether the package doc comment has a
+	// "Deprecated:" paragraph, and DeprecationComment holds its text.
+	Deprecated         bool
+	DeprecationComment string
+}
+
 // DocInfo returns information extracted from the package's documentation.
 // This destroys p's AST; do not call any methods of p after it returns.
-func (p *Package) DocInfo(ctx context.Context, innerPath string, sourceInfo *source.Info, modInfo *ModuleInfo) (
-	synopsis string, imports []string, api []*internal.Symbol, err error) {
+func (p *Package) DocInfo(ctx context.Context, innerPath string, sourceInfo *source.Info, modInfo *ModuleInfo) (_ *DocInfo, err error) {
 	// This is mostly copied from internal/fetch/fetch.go.
 	defer derrors.Wrap(&err, "godoc.Package.DocInfo(%q, %q, %q)", modInfo.ModulePath, modInfo.ResolvedVersion, innerPath)
 
 	p.renderCalled = true
 	d, err := p.docPackage(innerPath, modInfo)
 	if err != nil {
-		return "", nil, nil, err
+		return nil, err
 	}
-
-	api, err = dochtml.GetSymbols(d, p.Fset)
+	// Computing the documentation can take long; stop if the caller has
+	// given up on it.
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	api, err := dochtml.GetSymbols(d, p.Fset)
 	if err != nil {
-		return "", nil, nil, err
+		return nil, err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	info := &DocInfo{
+		Synopsis:      synopsis(d.Doc),
+		Imports:       cleanImports(d.Imports, d.ImportPath),
+		API:           api,
+		Stats:         docStats(d),
+		ExamplesValid: p.examplesValid(d),
+		Examples:      examples(d),
+	}
+	info.Deprecated, info.DeprecationComment = dochtml.Deprecation(d.Doc)
+	if d.Name == "main" {
+		info.Usage = commandUsage(d.Doc)
+	}
+	return info, nil
+}
+
+// docStats counts the exported symbols of d and how many of them have a doc
+// comment. A constant or variable is documented if either its own spec or
+// the declaration it belongs to has a comment.
+func docStats(d *doc.Package) internal.DocStats {
+	var s internal.DocStats
+	add := func(name, comment string) {
+		if !token.IsExported(name) {
+			return
+		}
+		s.NumExported++
+		if strings.TrimSpace(comment) != "" {
+			s.NumDocumented++
+		}
+	}
+	addValues := func(vals []*doc.Value) {
+		for _, v := range vals {
+			for _, spec := range v.Decl.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				comment := v.Doc
+				if comment == "" {
+					comment = vs.Doc.Text() + vs.Comment.Text()
+				}
+				for _, n := range vs.Names {
+					add(n.Name, comment)
+				}
+			}
+		}
+	}
+	addFuncs := func(fs []*doc.Func) {
+		for _, f := range fs {
+			add(f.Name, f.Doc)
+		}
+	}
+
+	addValues(d.Consts)
+	addValues(d.Vars)
+	addFuncs(d.Funcs)
+	for _, t := range d.Types {
+		add(t.Name, t.Doc)
+		addValues(t.Consts)
+		addValues(t.Vars)
+		addFuncs(t.Funcs)
+		addFuncs(t.Methods)
 	}
-	return doc.Synopsis(d.Doc), cleanImports(d.Imports, d.ImportPath), api, nil
+	return s
 }
 
 // cleanImports cleans import paths, in the sense of path.Clean.
@@ -167,6 +259,7 @@ func (p *Package) renderOptions(innerPath string, sourceInfo *source.Info, modIn
 		SinceVersionFunc: sinceVersionFunc(modInfo.ModulePath, nameToVersion),
 		Limit:            int64(MaxDocumentationHTML),
 		BuildContext:     bc,
+		BodyTemplate:     DocBodyTemplate,
 	}
 }
 
@@ -235,6 +328,100 @@ func (p *Package) Render(ctx context.Context, innerPath string,
 	return parts, nil
 }
 
+// RenderSymbol renders the documentation for the single exported symbol
+// name in the package, as described by dochtml.RenderSymbol.
+// Rendering destroys p's AST; do not call any methods of p after it returns.
+func (p *Package) RenderSymbol(ctx context.Context, innerPath string,
+	sourceInfo *source.Info, modInfo *ModuleInfo, name string,
+	bc internal.BuildContext) (_ *dochtml.SymbolDoc, err error) {
+	p.renderCalled = true
+
+	d, err := p.docPackage(innerPath, modInfo)
+	if err != nil {
+		return nil, err
+	}
+	opts := p.renderOptions(innerPath, sourceInfo, modInfo, nil, bc)
+	return dochtml.RenderSymbol(ctx, p.Fset, d, name, opts)
+}
+
+// DocTree returns the documentation for the package as a tree of symbols,
+// as described by dochtml.DocTree.
+// It destroys p's AST; do not call any methods of p after it returns.
+func (p *Package) DocTree(innerPath string, modInfo *ModuleInfo) (_ *internal.DocTree, err error) {
+	p.renderCalled = true
+
+	d, err := p.docPackage(innerPath, modInfo)
+	if err != nil {
+		return nil, err
+	}
+	return dochtml.DocTree(d, p.Fset)
+}
+
+// DocTreeFromUnit is a convenience function that first decodes the source
+// in the unit, which must exist, and then calls DocTree.
+func DocTreeFromUnit(u *internal.Unit) (_ *internal.DocTree, err error) {
+	docPkg, err := DecodePackage(u.Documentation[0].Source)
+	if err != nil {
+		return nil, err
+	}
+	modInfo := &ModuleInfo{
+		ModulePath:      u.ModulePath,
+		ResolvedVersion: u.Version,
+		ModulePackages:  nil, // will be provided by docPkg
+	}
+	return docPkg.DocTree(innerPathForUnit(u), modInfo)
+}
+
+// SymbolDocFromUnit is a convenience function that first decodes the source
+// in the unit, which must exist, and then calls RenderSymbol with the build
+// context of the documentation.
+func SymbolDocFromUnit(ctx context.Context, u *internal.Unit, name string) (_ *dochtml.SymbolDoc, err error) {
+	doc := u.Documentation[0]
+	docPkg, err := DecodePackage(doc.Source)
+	if err != nil {
+		return nil, err
+	}
+	modInfo := &ModuleInfo{
+		ModulePath:      u.ModulePath,
+		ResolvedVersion: u.Version,
+		ModulePackages:  nil, // will be provided by docPkg
+	}
+	return docPkg.RenderSymbol(ctx, innerPathForUnit(u), u.SourceInfo, modInfo, name, doc.BuildContext())
+}
+
+// RenderFormats renders the documentation for the package encoded in src
+// in the formats that are stored with it: the HTML of the documentation
+// body, and plain text as rendered by RenderText. The HTML is rendered
+// without the versions in which symbols were added, since those depend on
+// later versions of the module, and for no build context in particular.
+// The HTML is empty if the documentation templates have not been loaded.
+func RenderFormats(ctx context.Context, src []byte, innerPath string,
+	sourceInfo *source.Info, modInfo *ModuleInfo) (html, text string, err error) {
+	defer derrors.Wrap(&err, "godoc.RenderFormats(%q, %q, %q)", modInfo.ModulePath, modInfo.ResolvedVersion, innerPath)
+
+	// Each rendering destroys the AST, so decode it for each.
+	if dochtml.TemplatesLoaded() {
+		p, err := DecodePackage(src)
+		if err != nil {
+			return "", "", err
+		}
+		parts, err := p.Render(ctx, innerPath, sourceInfo, modInfo, nil, internal.BuildContext{})
+		if err != nil {
+			return "", "", err
+		}
+		html = parts.Body.String()
+	}
+	p, err := DecodePackage(src)
+	if err != nil {
+		return "", "", err
+	}
+	text, err = p.RenderText(ctx, innerPath, modInfo)
+	if err != nil {
+		return "", "", err
+	}
+	return html, text, nil
+}
+
 // RenderFromUnit is a convenience function that first decodes the source
 // in the unit, which must exist, and then calls Render.
 func RenderFromUnit(ctx context.Context, u *internal.Unit,
@@ -248,11 +435,5 @@ func RenderFromUnit(ctx context.Context, u *internal.Unit,
 		ResolvedVersion: u.Version,
 		ModulePackages:  nil, // will be provided by docPkg
 	}
-	var innerPath string
-	if u.ModulePath == stdlib.ModulePath {
-		innerPath = u.Path
-	} else if u.Path != u.ModulePath {
-		innerPath = u.Path[len(u.ModulePath)+1:]
-	}
-	return docPkg.Render(ctx, innerPath, u.SourceInfo, modInfo, nil, bc)
+	return docPkg.Render(ctx, innerPathForUnit(u), u.SourceInfo, modInfo, nil, bc)
 }
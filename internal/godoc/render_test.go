@@ -44,22 +44,22 @@ func TestDocInfo(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			wantSyn, wantImports, _, err := p.DocInfo(ctx, name, si, mi)
+			want, err := p.DocInfo(ctx, name, si, mi)
 			if err != nil {
 				t.Fatal(err)
 			}
 
 			check := func(p *Package) {
 				t.Helper()
-				gotSyn, gotImports, _, err := p.DocInfo(ctx, name, si, mi)
+				got, err := p.DocInfo(ctx, name, si, mi)
 				if err != nil {
 					t.Fatal(err)
 				}
-				if gotSyn != wantSyn {
-					t.Errorf("synopsis: got %q, want %q", gotSyn, wantSyn)
+				if got.Synopsis != want.Synopsis {
+					t.Errorf("synopsis: got %q, want %q", got.Synopsis, want.Synopsis)
 				}
-				if !cmp.Equal(gotImports, wantImports) {
-					t.Errorf("imports: got %v, want %v", gotImports, wantImports)
+				if !cmp.Equal(got.Imports, want.Imports) {
+					t.Errorf("imports: got %v, want %v", got.Imports, want.Imports)
 				}
 			}
 
@@ -0,0 +1,51 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"go/doc"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxSynopsisLength is the maximum length of a synopsis, in runes,
+// including the ellipsis added when it is truncated. If it is zero or
+// negative, synopses are not truncated.
+//
+// It is a variable so it can be configured.
+var MaxSynopsisLength = 300
+
+// synopsis returns the synopsis of a package whose doc comment is
+// docText: its first sentence, as returned by doc.Synopsis.
+//
+// A synopsis longer than MaxSynopsisLength is truncated at the last word
+// boundary that leaves room for an ellipsis. Punctuation and unbalanced
+// markup, such as an opening parenthesis or a backquote, are removed from
+// the end of the truncated text before the ellipsis is added.
+func synopsis(docText string) string {
+	s := doc.Synopsis(docText)
+	if MaxSynopsisLength <= 0 || utf8.RuneCountInString(s) <= MaxSynopsisLength {
+		return s
+	}
+	const ellipsis = "…"
+	runes := []rune(s)
+	n := MaxSynopsisLength - utf8.RuneCountInString(ellipsis)
+	cut := string(runes[:n])
+	if !unicode.IsSpace(runes[n]) {
+		// The cut is in the middle of a word; drop the partial word.
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	cut = strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(trailingMarkup, r)
+	})
+	return cut + ellipsis
+}
+
+// trailingMarkup holds the characters removed from the end of a truncated
+// synopsis.
+const trailingMarkup = ".,;:!?-([{<*_`'\""
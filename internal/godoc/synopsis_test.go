@@ -0,0 +1,60 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSynopsis(t *testing.T) {
+	defer func(n int) { MaxSynopsisLength = n }(MaxSynopsisLength)
+	MaxSynopsisLength = 40
+
+	for _, test := range []struct {
+		name, doc, want string
+	}{
+		{
+			name: "short",
+			doc:  "Package p does things. More text.",
+			want: "Package p does things.",
+		},
+		{
+			name: "cut in a word",
+			doc:  "Package p implements a very long description of what it is for.",
+			want: "Package p implements a very long…",
+		},
+		{
+			name: "cut at a space",
+			doc:  "Package p implements the frobnicator now and forever.",
+			want: "Package p implements the frobnicator…",
+		},
+		{
+			name: "trailing punctuation",
+			doc:  "Package p reads files and directories, links and more.",
+			want: "Package p reads files and directories…",
+		},
+		{
+			name: "trailing markup",
+			doc:  "Package p works with its helper `frobnicate` and others.",
+			want: "Package p works with its helper…",
+		},
+		{
+			name: "link",
+			doc:  "Package p wraps [io.Reader] values to count the bytes they read.",
+			want: "Package p wraps io.Reader values to…",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := synopsis(test.doc)
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+			if n := utf8.RuneCountInString(got); n > MaxSynopsisLength {
+				t.Errorf("got length %d, want at most %d", n, MaxSynopsisLength)
+			}
+		})
+	}
+}
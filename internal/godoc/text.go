@@ -0,0 +1,157 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/doc"
+	"go/printer"
+	"go/token"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/stdlib"
+)
+
+// textIndent is the indentation of doc comments below their declarations in
+// plain-text documentation, matching the output of "go doc".
+const textIndent = "    "
+
+// RenderText renders the documentation for the package as plain text, in a
+// form similar to the output of "go doc -all". The result contains no HTML.
+// Rendering destroys p's AST; do not call any methods of p after it returns.
+func (p *Package) RenderText(ctx context.Context, innerPath string, modInfo *ModuleInfo) (_ string, err error) {
+	defer derrors.Wrap(&err, "godoc.Package.RenderText(%q, %q, %q)", modInfo.ModulePath, modInfo.ResolvedVersion, innerPath)
+
+	p.renderCalled = true
+	d, err := p.docPackage(innerPath, modInfo)
+	if err != nil {
+		return "", err
+	}
+	r := &textRenderer{fset: p.Fset, pkg: d}
+	return r.render(), nil
+}
+
+// RenderTextFromUnit is a convenience function that first decodes the source
+// in the unit, which must exist, and then calls RenderText.
+func RenderTextFromUnit(ctx context.Context, u *internal.Unit) (_ string, err error) {
+	docPkg, err := DecodePackage(u.Documentation[0].Source)
+	if err != nil {
+		return "", err
+	}
+	modInfo := &ModuleInfo{
+		ModulePath:      u.ModulePath,
+		ResolvedVersion: u.Version,
+		ModulePackages:  nil, // will be provided by docPkg
+	}
+	return docPkg.RenderText(ctx, innerPathForUnit(u), modInfo)
+}
+
+// innerPathForUnit returns the path of u relative to its module.
+func innerPathForUnit(u *internal.Unit) string {
+	if u.ModulePath == stdlib.ModulePath {
+		return u.Path
+	}
+	if u.Path != u.ModulePath {
+		return u.Path[len(u.ModulePath)+1:]
+	}
+	return ""
+}
+
+// A textRenderer writes the plain-text form of a doc.Package.
+type textRenderer struct {
+	fset *token.FileSet
+	pkg  *doc.Package
+	buf  bytes.Buffer
+}
+
+func (r *textRenderer) render() string {
+	d := r.pkg
+	fmt.Fprintf(&r.buf, "package %s // import %q\n\n", d.Name, d.ImportPath)
+	if d.Doc != "" {
+		r.buf.Write(d.Text(d.Doc))
+		r.buf.WriteByte('\n')
+	}
+	r.values("CONSTANTS", d.Consts)
+	r.values("VARIABLES", d.Vars)
+	if len(d.Funcs) > 0 {
+		r.section("FUNCTIONS")
+		for _, f := range d.Funcs {
+			r.fn(f)
+		}
+	}
+	if len(d.Types) > 0 {
+		r.section("TYPES")
+		for _, t := range d.Types {
+			r.decl(t.Decl)
+			r.comment(t.Doc)
+			for _, v := range t.Consts {
+				r.decl(v.Decl)
+				r.comment(v.Doc)
+			}
+			for _, v := range t.Vars {
+				r.decl(v.Decl)
+				r.comment(v.Doc)
+			}
+			for _, f := range t.Funcs {
+				r.fn(f)
+			}
+			for _, f := range t.Methods {
+				r.fn(f)
+			}
+		}
+	}
+	return strings.TrimRight(r.buf.String(), "\n") + "\n"
+}
+
+func (r *textRenderer) section(title string) {
+	fmt.Fprintf(&r.buf, "%s\n\n", title)
+}
+
+func (r *textRenderer) values(title string, vals []*doc.Value) {
+	if len(vals) == 0 {
+		return
+	}
+	r.section(title)
+	for _, v := range vals {
+		r.decl(v.Decl)
+		r.comment(v.Doc)
+	}
+}
+
+func (r *textRenderer) fn(f *doc.Func) {
+	// Print only the signature.
+	decl := *f.Decl
+	decl.Body = nil
+	decl.Doc = nil
+	r.decl(&decl)
+	r.comment(f.Doc)
+}
+
+func (r *textRenderer) decl(n ast.Node) {
+	if g, ok := n.(*ast.GenDecl); ok {
+		c := *g
+		c.Doc = nil
+		n = &c
+	}
+	if err := printer.Fprint(&r.buf, r.fset, n); err != nil {
+		fmt.Fprintf(&r.buf, "<printing error: %v>", err)
+	}
+	r.buf.WriteByte('\n')
+}
+
+// comment writes the indented doc comment, followed by a blank line.
+func (r *textRenderer) comment(text string) {
+	if text != "" {
+		pr := r.pkg.Printer()
+		pr.TextPrefix = textIndent
+		r.buf.Write(pr.Text(r.pkg.Parser().Parse(text)))
+	}
+	r.buf.WriteByte('\n')
+}
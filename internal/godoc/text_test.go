@@ -0,0 +1,53 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderText(t *testing.T) {
+	ctx := context.Background()
+	mi := &ModuleInfo{
+		ModulePath:      "a.com/M",
+		ResolvedVersion: "v1.2.3",
+	}
+	p, err := packageForDir(filepath.Join("testdata", "p"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Round-trip through the encoded form, as stored in the DB.
+	data, err := p.Encode(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err = DecodePackage(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := p.RenderText(ctx, "p", mi)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`package p // import "a.com/M/p"`,
+		"Package p is for testing godoc.Render.",
+		"func F(t time.Time)\n    exported func",
+		"func TF() T\n    typeFunc",
+		"func (T) M()",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+	for _, bad := range []string{"<", "func unexp", "fmt.Println"} {
+		if strings.Contains(got, bad) {
+			t.Errorf("found %q in:\n%s", bad, got)
+		}
+	}
+}
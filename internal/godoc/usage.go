@@ -0,0 +1,97 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import "strings"
+
+// commandUsage returns the usage synopsis from the doc comment of a command,
+// or the empty string if there is none.
+//
+// It follows the convention used by the go command and the tools in the
+// standard library: a line consisting of "Usage:", followed by one or more
+// indented lines, as in
+//
+//	Usage:
+//
+//		gofmt [flags] [path ...]
+//
+// A line of the form "Usage: cmd [flags]" is also recognized.
+// The result has its common indentation removed.
+func commandUsage(docText string) string {
+	lines := strings.Split(docText, "\n")
+	for i, line := range lines {
+		if isIndented(line) {
+			continue
+		}
+		rest, ok := cutUsagePrefix(strings.TrimSpace(line))
+		if !ok {
+			continue
+		}
+		if rest != "" {
+			return rest
+		}
+		// Collect the indented block following the Usage line, allowing
+		// blank lines before and within it.
+		var block []string
+		for _, l := range lines[i+1:] {
+			if strings.TrimSpace(l) == "" {
+				block = append(block, "")
+				continue
+			}
+			if !isIndented(l) {
+				break
+			}
+			block = append(block, l)
+		}
+		return unindent(block)
+	}
+	return ""
+}
+
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+}
+
+// cutUsagePrefix reports whether s starts a usage section, and returns the
+// text following the "Usage:" prefix.
+func cutUsagePrefix(s string) (rest string, ok bool) {
+	const prefix = "usage:"
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(s[len(prefix):]), true
+}
+
+// unindent removes leading and trailing blank lines from lines, and the
+// longest whitespace prefix common to the others, then joins them.
+func unindent(lines []string) string {
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	prefix := ""
+	for i, l := range lines {
+		if l == "" {
+			continue
+		}
+		indent := l[:len(l)-len(strings.TrimLeft(l, " \t"))]
+		if i == 0 {
+			prefix = indent
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	for i, l := range lines {
+		lines[i] = strings.TrimPrefix(l, prefix)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package godoc
+
+import "testing"
+
+func TestCommandUsage(t *testing.T) {
+	for _, test := range []struct {
+		name, doc, want string
+	}{
+		{
+			name: "block",
+			doc: "Gofmt formats Go programs.\n\nUsage:\n\n\tgofmt [flags] [path ...]\n\n" +
+				"The flags are:\n\n\t-d\n\t\tDo not print reformatted sources.\n",
+			want: "gofmt [flags] [path ...]",
+		},
+		{
+			name: "multiline block",
+			doc:  "Usage:\n\tcmd build [-o output] [packages]\n\n\tcmd test [packages]\nMore text.\n",
+			want: "cmd build [-o output] [packages]\n\ncmd test [packages]",
+		},
+		{
+			name: "inline",
+			doc:  "Hello greets.\n\nusage: hello [-name name]\n",
+			want: "hello [-name name]",
+		},
+		{
+			name: "indented usage line is code",
+			doc:  "Example:\n\n\tUsage: not a heading\n",
+			want: "",
+		},
+		{
+			name: "none",
+			doc:  "Hello greets.\n",
+			want: "",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if got := commandUsage(test.doc); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
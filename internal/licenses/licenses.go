@@ -31,6 +31,7 @@ import (
 	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/google/licensecheck"
 	"golang.org/x/mod/module"
@@ -223,6 +224,38 @@ func init() {
 	}
 }
 
+// allowlist, if set, replaces redistributableLicenseTypes as the set of
+// license types that allow redistribution.
+var allowlist atomic.Pointer[map[string]bool]
+
+// DefaultRedistributableLicenseTypes returns the license types that allow
+// redistribution unless changed by SetRedistributableLicenseTypes, in sorted
+// order.
+func DefaultRedistributableLicenseTypes() []string {
+	return setToSortedSlice(redistributableLicenseTypes)
+}
+
+// SetRedistributableLicenseTypes sets the license types that allow
+// redistribution. Types are as reported by licensecheck, which uses SPDX
+// identifiers for standard licenses. If types is nil, the default set is
+// restored.
+//
+// It lets a deployment with its own license policy include or exclude
+// specific licenses. It should be called at startup, before any licenses are
+// detected, since it changes the result of Redistributable and so of every
+// Detector.
+func SetRedistributableLicenseTypes(types []string) {
+	if types == nil {
+		allowlist.Store(nil)
+		return
+	}
+	m := map[string]bool{}
+	for _, t := range types {
+		m[t] = true
+	}
+	allowlist.Store(&m)
+}
+
 // nonOSILicenses lists licenses that are not approved by OSI.
 var nonOSILicenses = map[string]bool{
 	"AGPL-3.0-only":                 true,
@@ -583,12 +616,16 @@ func DetectFile(contents []byte, filename string, logf func(string, ...any)) ([]
 // All the licenses we see that are relevant must be redistributable, and
 // we must see at least one such license.
 func Redistributable(licenseTypes []string) bool {
+	allowed := redistributableLicenseTypes
+	if a := allowlist.Load(); a != nil {
+		allowed = *a
+	}
 	sawRedist := false
 	for _, t := range licenseTypes {
 		if ignorableLicenseTypes[t] {
 			continue
 		}
-		if !redistributableLicenseTypes[t] {
+		if !allowed[t] {
 			return false
 		}
 		sawRedist = true
@@ -596,6 +633,47 @@ func Redistributable(licenseTypes []string) bool {
 	return sawRedist
 }
 
+// A Conflict describes license files in a module that disagree about
+// whether it is redistributable.
+type Conflict struct {
+	// Redistributable holds the paths of the license files that permit
+	// redistribution.
+	Redistributable []string
+	// NonRedistributable holds the paths of the license files that don't.
+	NonRedistributable []string
+}
+
+// FindConflict returns the conflict between lics, or nil if there is
+// none. Licenses conflict if at least one permits redistribution and at least
+// one does not. Licenses whose types are all ignorable, like font licenses,
+// never conflict.
+func FindConflict(lics []*License) *Conflict {
+	var c Conflict
+	for _, l := range lics {
+		if allIgnorable(l.Types) {
+			continue
+		}
+		if Redistributable(l.Types) {
+			c.Redistributable = append(c.Redistributable, l.FilePath)
+		} else {
+			c.NonRedistributable = append(c.NonRedistributable, l.FilePath)
+		}
+	}
+	if len(c.Redistributable) == 0 || len(c.NonRedistributable) == 0 {
+		return nil
+	}
+	return &c
+}
+
+func allIgnorable(licenseTypes []string) bool {
+	for _, t := range licenseTypes {
+		if !ignorableLicenseTypes[t] {
+			return false
+		}
+	}
+	return true
+}
+
 func types(lics []*License) []string {
 	var types []string
 	for _, l := range lics {
@@ -347,6 +347,34 @@ func TestRedistributable(t *testing.T) {
 	}
 }
 
+func TestFindConflict(t *testing.T) {
+	lic := func(path string, types ...string) *License {
+		return &License{Metadata: &Metadata{Types: types, FilePath: path}}
+	}
+	for _, test := range []struct {
+		name string
+		lics []*License
+		want *Conflict
+	}{
+		{"none", nil, nil},
+		{"all redistributable", []*License{lic("LICENSE", "MIT"), lic("a/LICENSE", "GPL-2.0")}, nil},
+		{"none redistributable", []*License{lic("LICENSE", unknownLicenseType), lic("a/LICENSE", "CommonsClause")}, nil},
+		{"ignorable", []*License{lic("LICENSE", "MIT"), lic("fonts/LICENSE", "OFL-1.1")}, nil},
+		{
+			"conflict",
+			[]*License{lic("LICENSE", "MIT"), lic("a/LICENSE", "GPL-3.0"), lic("b/LICENSE", unknownLicenseType)},
+			&Conflict{Redistributable: []string{"LICENSE", "a/LICENSE"}, NonRedistributable: []string{"b/LICENSE"}},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := FindConflict(test.lics)
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestPaths(t *testing.T) {
 	zr := newZipReader(t, "m@v1", map[string]string{
 		"LICENSE":            "",
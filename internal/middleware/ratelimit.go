@@ -0,0 +1,145 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru"
+	"golang.org/x/pkgsite/internal/config"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// A RateLimiter decides whether a client may make another request.
+type RateLimiter interface {
+	// Allow reports whether the client identified by key may make a request
+	// now. If not, it also returns how long the client should wait before
+	// trying again.
+	Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration)
+}
+
+// RateLimit returns a middleware that limits the rate of requests from each
+// client IP block using limiter. Clients are identified as in Quota, falling
+// back to the remote address of the request.
+//
+// If a request is disallowed, a 429 (TooManyRequests) is served, with a
+// Retry-After header. Requests carrying one of authValues in the
+// BypassQuotaAuthHeader are never limited.
+func RateLimit(limiter RateLimiter, authValues []string) Middleware {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			authVal := r.Header.Get(config.BypassQuotaAuthHeader)
+			for _, wantVal := range authValues {
+				if authVal == wantVal {
+					h.ServeHTTP(w, r)
+					return
+				}
+			}
+			key := ClientKey(r)
+			if key == "" {
+				// Fail open if the client can't be identified.
+				h.ServeHTTP(w, r)
+				return
+			}
+			allowed, retryAfter := limiter.Allow(ctx, key)
+			if !allowed {
+				log.Infof(ctx, "RateLimit: blocking %s for %s", r.URL.Path, retryAfter)
+				secs := int(math.Ceil(retryAfter.Seconds()))
+				if secs < 1 {
+					secs = 1
+				}
+				w.Header().Set("Retry-After", strconv.Itoa(secs))
+				const tmr = http.StatusTooManyRequests
+				http.Error(w, http.StatusText(tmr), tmr)
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ClientKey returns the IP block of the client that made r, or the empty
+// string if it cannot be determined.
+func ClientKey(r *http.Request) string {
+	header := r.Header.Get("X-Godoc-Forwarded-For")
+	if header == "" {
+		header = r.Header.Get("X-Forwarded-For")
+	}
+	if header == "" {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			return ""
+		}
+		header = host
+	}
+	return ipKey(header)
+}
+
+// A LocalRateLimiter is a RateLimiter that keeps a token bucket for each
+// client in memory. It limits each instance of a server separately.
+type LocalRateLimiter struct {
+	qps   float64
+	burst float64
+	now   func() time.Time // for testing
+
+	mu      sync.Mutex
+	buckets *lru.Cache // from key to *tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewLocalRateLimiter returns a LocalRateLimiter that allows each client qps
+// requests per second on average, with bursts of up to burst requests. It
+// tracks at most maxEntries clients; the least recently seen are forgotten
+// first.
+func NewLocalRateLimiter(qps float64, burst, maxEntries int) (*LocalRateLimiter, error) {
+	if qps <= 0 || burst <= 0 {
+		return nil, fmt.Errorf("NewLocalRateLimiter: qps and burst must be positive, got %g and %d", qps, burst)
+	}
+	c, err := lru.New(maxEntries)
+	if err != nil {
+		return nil, err
+	}
+	return &LocalRateLimiter{
+		qps:     qps,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: c,
+	}, nil
+}
+
+// Allow implements RateLimiter.
+func (l *LocalRateLimiter) Allow(ctx context.Context, key string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var b *tokenBucket
+	if v, ok := l.buckets.Get(key); ok {
+		b = v.(*tokenBucket)
+		b.tokens = math.Min(l.burst, b.tokens+now.Sub(b.last).Seconds()*l.qps)
+		b.last = now
+	} else {
+		b = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets.Add(key, b)
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / l.qps * float64(time.Second))
+	return false, wait
+}
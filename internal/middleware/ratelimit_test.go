@@ -0,0 +1,88 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal/config"
+)
+
+func TestLocalRateLimiter(t *testing.T) {
+	ctx := context.Background()
+	l, err := NewLocalRateLimiter(2, 3, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	check := func(key string, want bool) time.Duration {
+		t.Helper()
+		got, retryAfter := l.Allow(ctx, key)
+		if got != want {
+			t.Fatalf("Allow(%q) at %s: got %t, want %t", key, now, got, want)
+		}
+		return retryAfter
+	}
+
+	// The burst is allowed, then requests are blocked.
+	for i := 0; i < 3; i++ {
+		check("a", true)
+	}
+	if got, want := check("a", false), 500*time.Millisecond; got != want {
+		t.Errorf("retryAfter: got %s, want %s", got, want)
+	}
+	// Other clients are unaffected.
+	check("b", true)
+
+	// Tokens are replenished at the given rate.
+	now = now.Add(500 * time.Millisecond)
+	check("a", true)
+	check("a", false)
+}
+
+func TestRateLimit(t *testing.T) {
+	l, err := NewLocalRateLimiter(1, 2, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.now = func() time.Time { return time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC) }
+	h := RateLimit(l, []string{"bypass"})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	get := func(ip, auth string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/search?q=foo", nil)
+		r.Header.Set("X-Forwarded-For", ip)
+		if auth != "" {
+			r.Header.Set(config.BypassQuotaAuthHeader, auth)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := get("1.2.3.4", ""); w.Code != http.StatusOK {
+			t.Fatalf("request %d: got status %d, want %d", i, w.Code, http.StatusOK)
+		}
+	}
+	w := get("1.2.3.5", "") // same IP block
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("got status %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got, want := w.Header().Get("Retry-After"), "1"; got != want {
+		t.Errorf("Retry-After: got %q, want %q", got, want)
+	}
+	if w := get("1.2.3.4", "bypass"); w.Code != http.StatusOK {
+		t.Errorf("with bypass header: got status %d, want %d", w.Code, http.StatusOK)
+	}
+	if w := get("5.6.7.8", ""); w.Code != http.StatusOK {
+		t.Errorf("other client: got status %d, want %d", w.Code, http.StatusOK)
+	}
+}
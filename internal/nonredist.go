@@ -11,6 +11,11 @@ func (m *Module) RemoveNonRedistributableData() {
 	for _, l := range m.Licenses {
 		l.RemoveNonRedistributableData()
 	}
+	if !m.IsRedistributable {
+		m.Notice = nil
+		m.Changelog = nil
+		m.Keywords = nil
+	}
 	for _, d := range m.Units {
 		d.RemoveNonRedistributableData()
 	}
@@ -20,6 +25,8 @@ func (u *Unit) RemoveNonRedistributableData() {
 	if !u.IsRedistributable {
 		u.Readme = nil
 		u.Documentation = nil
+		u.Examples = nil
+		u.Notice = nil
 	}
 }
 
@@ -0,0 +1,40 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestModuleRemoveNonRedistributableData(t *testing.T) {
+	newModule := func(redist bool) *Module {
+		return &Module{
+			ModuleInfo: ModuleInfo{ModulePath: "m.com", IsRedistributable: redist},
+			Notice:     &Notice{Filepath: "NOTICE", Contents: "Copyright Example Corp."},
+			Changelog:  &Changelog{Filepath: "CHANGELOG.md", Contents: "# v1.0.0"},
+			Keywords:   []string{"router"},
+		}
+	}
+
+	m := newModule(true)
+	m.RemoveNonRedistributableData()
+	if diff := cmp.Diff(newModule(true), m); diff != "" {
+		t.Errorf("redistributable module changed (-want, +got):\n%s", diff)
+	}
+
+	m = newModule(false)
+	m.RemoveNonRedistributableData()
+	if m.Notice != nil {
+		t.Errorf("got notice %+v, want nil", m.Notice)
+	}
+	if m.Changelog != nil {
+		t.Errorf("got changelog %+v, want nil", m.Changelog)
+	}
+	if m.Keywords != nil {
+		t.Errorf("got keywords %v, want nil", m.Keywords)
+	}
+}
@@ -5,9 +5,12 @@
 package internal
 
 import (
+	"fmt"
 	"path"
+	"strings"
 
 	"golang.org/x/mod/module"
+	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/stdlib"
 )
 
@@ -59,3 +62,32 @@ func CandidateModulePaths(fullPath string) []string {
 	}
 	return r[:len(r)-2]
 }
+
+// CleanImportPath checks that p, a path requested by a user, is a valid
+// module path, and returns its normalized form. The standard library, whose
+// module path is "std", is always valid. The error returned for an invalid
+// path wraps derrors.InvalidArgument and says which rule p breaks.
+func CleanImportPath(p string) (_ string, err error) {
+	defer derrors.Wrap(&err, "CleanImportPath(%q)", p)
+
+	p = strings.TrimSpace(p)
+	if p == stdlib.ModulePath {
+		return p, nil
+	}
+	if p == "" {
+		return "", fmt.Errorf("empty path: %w", derrors.InvalidArgument)
+	}
+	if strings.HasSuffix(p, "/") {
+		return "", fmt.Errorf("trailing slash: %w", derrors.InvalidArgument)
+	}
+	if err := module.CheckImportPath(p); err != nil {
+		return "", fmt.Errorf("%v: %w", err, derrors.InvalidArgument)
+	}
+	if _, _, ok := module.SplitPathVersion(p); !ok {
+		return "", fmt.Errorf("invalid major version suffix: %w", derrors.InvalidArgument)
+	}
+	if err := module.CheckPath(p); err != nil {
+		return "", fmt.Errorf("%v: %w", err, derrors.InvalidArgument)
+	}
+	return p, nil
+}
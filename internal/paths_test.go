@@ -5,9 +5,11 @@
 package internal
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/derrors"
 )
 
 func TestCandidateModulePaths(t *testing.T) {
@@ -51,3 +53,37 @@ func TestCandidateModulePaths(t *testing.T) {
 		}
 	}
 }
+
+func TestCleanImportPath(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"github.com/google/go-cmp", "github.com/google/go-cmp"},
+		{" example.com/mod/v2 ", "example.com/mod/v2"},
+		{"std", "std"},
+	} {
+		got, err := CleanImportPath(test.in)
+		if err != nil {
+			t.Fatalf("%q: %v", test.in, err)
+		}
+		if got != test.want {
+			t.Errorf("%q: got %q, want %q", test.in, got, test.want)
+		}
+	}
+
+	for _, in := range []string{
+		"",
+		"example.com/mod/",
+		"example.com//mod",
+		"example.com/a..b/../c",
+		"example.com/no$dollars",
+		"example.com/mod/v1",
+		"example.com/mod/v2.1",
+		"gopkg.in/yaml",
+		"nodot/mod",
+	} {
+		if _, err := CleanImportPath(in); !errors.Is(err, derrors.InvalidArgument) {
+			t.Errorf("%q: got error %v, want InvalidArgument", in, err)
+		}
+	}
+}
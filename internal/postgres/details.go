@@ -12,10 +12,12 @@ import (
 	"fmt"
 	"reflect"
 
+	"github.com/lib/pq"
 	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/database"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/stdlib"
 )
 
 // GetNestedModules returns the latest major version of all nested modules
@@ -99,6 +101,71 @@ func (db *DB) GetImportedBy(ctx context.Context, pkgPath, modulePath string, lim
 	return database.Collect1[string](ctx, db.db, query, pkgPath, modulePath, limit)
 }
 
+// maxSimilarCandidatesPerImport bounds the number of packages that
+// GetSimilarPackages considers for each import of the package, so that
+// widely imported packages don't make it scan most of imports_unique.
+const maxSimilarCandidatesPerImport = 1000
+
+// GetSimilarPackages returns up to limit packages that are similar to the
+// package with the given path, for recommendations. Packages are similar if
+// they import some of the same packages. Each shared import counts in
+// inverse proportion to the number of candidate packages that import it, so
+// sharing a rarely used import counts for more than sharing a popular one.
+// Imports of the standard library are ignored, since almost every package
+// shares some of them, and so are packages in the same module as path.
+func (db *DB) GetSimilarPackages(ctx context.Context, path string, limit int) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "GetSimilarPackages(ctx, %q, %d)", path, limit)
+	defer middleware.ElapsedStat(ctx, "GetSimilarPackages")()
+
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty: %w", derrors.InvalidArgument)
+	}
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive: %w", derrors.InvalidArgument)
+	}
+	var (
+		modulePath string
+		imports    []string
+	)
+	collect := func(rows *sql.Rows) error {
+		var mp, imp string
+		if err := rows.Scan(&mp, &imp); err != nil {
+			return err
+		}
+		modulePath = mp
+		if !stdlib.Contains(imp) {
+			imports = append(imports, imp)
+		}
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, `
+		SELECT DISTINCT from_module_path, to_path
+		FROM imports_unique
+		WHERE from_path = $1`, collect, path); err != nil {
+		return nil, err
+	}
+	if len(imports) == 0 {
+		return nil, nil
+	}
+	query := `
+		SELECT from_path
+		FROM (
+			SELECT c.from_path, COUNT(*) OVER (PARTITION BY i.to_path) AS importers
+			FROM unnest($1::text[]) AS i(to_path)
+			CROSS JOIN LATERAL (
+				SELECT DISTINCT from_path
+				FROM imports_unique iu
+				WHERE iu.to_path = i.to_path AND iu.from_module_path <> $2
+				ORDER BY from_path
+				LIMIT $3
+			) c
+		) s
+		GROUP BY from_path
+		ORDER BY SUM(1.0 / importers) DESC, from_path
+		LIMIT $4`
+	return database.Collect1[string](ctx, db.db, query, pq.Array(imports), modulePath, maxSimilarCandidatesPerImport, limit)
+}
+
 // GetImportedByCount returns the number of packages that import pkgPath.
 func (db *DB) GetImportedByCount(ctx context.Context, pkgPath, modulePath string) (_ int, err error) {
 	defer derrors.WrapStack(&err, "GetImportedByCount(ctx, %q, %q)", pkgPath, modulePath)
@@ -160,6 +227,27 @@ func (db *DB) GetModuleInfo(ctx context.Context, modulePath, resolvedVersion str
 	return mi, nil
 }
 
+// HasPackages reports whether the given module version contains any
+// packages. A module without packages is stored with its licenses and
+// README, but it has no package pages to show.
+// It returns a NotFound error if the module version is not in the database.
+func (db *DB) HasPackages(ctx context.Context, modulePath, resolvedVersion string) (has bool, err error) {
+	defer derrors.WrapStack(&err, "HasPackages(ctx, %q, %q)", modulePath, resolvedVersion)
+
+	err = db.db.QueryRow(ctx, `
+		SELECT COALESCE(has_packages, true)
+		FROM modules
+		WHERE module_path = $1 AND version = $2
+	`, modulePath, resolvedVersion).Scan(&has)
+	if err == sql.ErrNoRows {
+		return false, derrors.NotFound
+	}
+	if err != nil {
+		return false, err
+	}
+	return has, nil
+}
+
 // jsonbScanner scans a jsonb value into a Go value.
 type jsonbScanner struct {
 	ptr any // a pointer to a Go struct or other JSON-serializable value
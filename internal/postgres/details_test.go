@@ -284,6 +284,91 @@ func TestGetImportedBy(t *testing.T) {
 	}
 }
 
+func TestGetSimilarPackages(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	var (
+		m1 = sample.Module("similar.com/one", "v1.0.0", "pkg", "sibling")
+		m2 = sample.Module("similar.com/two", "v1.0.0", "pkg")
+		m3 = sample.Module("similar.com/three", "v1.0.0", "pkg")
+		m4 = sample.Module("similar.com/four", "v1.0.0", "pkg")
+
+		pkg1 = m1.Packages()[0]
+		pkg2 = m2.Packages()[0]
+		pkg3 = m3.Packages()[0]
+		pkg4 = m4.Packages()[0]
+	)
+	pkg1.Imports = []string{"fmt", "example.com/a", "example.com/b"}
+	// Packages in the same module are not recommended.
+	m1.Packages()[1].Imports = []string{"example.com/a", "example.com/b"}
+	pkg2.Imports = []string{"example.com/a", "example.com/b"}
+	pkg3.Imports = []string{"fmt", "example.com/a"}
+	pkg4.Imports = []string{"fmt"} // shares only a standard library import
+	for _, m := range []*internal.Module{m1, m2, m3, m4} {
+		MustInsertModule(ctx, t, testDB, m)
+	}
+
+	for _, test := range []struct {
+		path  string
+		limit int
+		want  []string
+	}{
+		{pkg1.Path, 10, []string{pkg2.Path, pkg3.Path}},
+		{pkg1.Path, 1, []string{pkg2.Path}},
+		{pkg3.Path, 10, []string{pkg1.Path, pkg2.Path}},
+		{pkg4.Path, 10, nil},
+		{"not.found/pkg", 10, nil},
+	} {
+		got, err := testDB.GetSimilarPackages(ctx, test.path, test.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("GetSimilarPackages(%q, %d) mismatch (-want +got):\n%s", test.path, test.limit, diff)
+		}
+	}
+	if _, err := testDB.GetSimilarPackages(ctx, "", 10); !errors.Is(err, derrors.InvalidArgument) {
+		t.Errorf("GetSimilarPackages with empty path: got %v, want InvalidArgument", err)
+	}
+	if _, err := testDB.GetSimilarPackages(ctx, pkg1.Path, 0); !errors.Is(err, derrors.InvalidArgument) {
+		t.Errorf("GetSimilarPackages with zero limit: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestHasPackages(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	withPkgs := sample.Module("has.com/pkgs", "v1.0.0", "p")
+	noPkgs := sample.Module("has.com/nopkgs", "v1.0.0")
+	for _, m := range []*internal.Module{withPkgs, noPkgs} {
+		MustInsertModule(ctx, t, testDB, m)
+	}
+	for _, test := range []struct {
+		modulePath string
+		want       bool
+	}{
+		{withPkgs.ModulePath, true},
+		{noPkgs.ModulePath, false},
+	} {
+		got, err := testDB.HasPackages(ctx, test.modulePath, "v1.0.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != test.want {
+			t.Errorf("HasPackages(%q) = %t, want %t", test.modulePath, got, test.want)
+		}
+	}
+	if _, err := testDB.HasPackages(ctx, "not.found", "v1.0.0"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
 func TestJSONBScanner(t *testing.T) {
 	t.Parallel()
 	type S struct{ A int }
@@ -0,0 +1,29 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// GetDirectoryTree returns the tree of the directories of all the packages
+// in the given module version, as described by internal.NewDirectoryTree,
+// with a single query. It lets the directories section of a module root page
+// be rendered in full.
+//
+// The packages are those that GetUnit returns as the Subdirectories of the
+// module root, with non-redistributable data removed in the same way.
+func (db *DB) GetDirectoryTree(ctx context.Context, modulePath, resolvedVersion string) (_ *internal.DirectoryNode, err error) {
+	defer derrors.WrapStack(&err, "GetDirectoryTree(ctx, %q, %q)", modulePath, resolvedVersion)
+
+	pkgs, err := getPackagesInUnit(ctx, db.db, modulePath, modulePath, resolvedVersion, -1, db.bypassLicenseCheck)
+	if err != nil {
+		return nil, err
+	}
+	return internal.NewDirectoryTree(modulePath, pkgs), nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetDirectoryTree(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const modulePath = "example.com/tree"
+	MustInsertModule(ctx, t, testDB, sample.Module(modulePath, sample.VersionString, "a", "a/b/c", "a/b/d", "z"))
+
+	got, err := testDB.GetDirectoryTree(ctx, modulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// paths flattens the tree to its paths, marking directories that are not
+	// packages with a trailing slash.
+	var paths func(n *internal.DirectoryNode) []any
+	paths = func(n *internal.DirectoryNode) []any {
+		p := n.Path
+		if n.Package == nil {
+			p += "/"
+		}
+		r := []any{p}
+		for _, c := range n.Children {
+			r = append(r, paths(c))
+		}
+		return r
+	}
+	want := []any{modulePath + "/",
+		[]any{modulePath + "/a",
+			[]any{modulePath + "/a/b/",
+				[]any{modulePath + "/a/b/c"},
+				[]any{modulePath + "/a/b/d"}}},
+		[]any{modulePath + "/z"},
+	}
+	if diff := cmp.Diff(want, paths(got)); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware"
+)
+
+// getUnitDocFormats reads the documentation formats of u, which must be a
+// package, that were stored when it was fetched, for the formats selected
+// by fields. If the documentation of u has already been read, the formats
+// are read for its build context; otherwise they are read for the first
+// build context that matches bc, and u.Documentation is set to hold them.
+func (db *DB) getUnitDocFormats(ctx context.Context, u *internal.Unit, fields internal.FieldSet, bc internal.BuildContext) (err error) {
+	defer derrors.WrapStack(&err, "getUnitDocFormats(ctx, %q, %q, %q, %v)", u.Path, u.ModulePath, u.Version, bc)
+	defer middleware.ElapsedStat(ctx, "getUnitDocFormats")()
+
+	var doc *internal.Documentation
+	if len(u.Documentation) > 0 {
+		doc = u.Documentation[0]
+		bc = doc.BuildContext()
+	}
+	var (
+		found      bool
+		bcMin      internal.BuildContext
+		html, text string
+	)
+	collect := func(rows *sql.Rows) error {
+		var (
+			dbc  internal.BuildContext
+			h, t string
+		)
+		if err := rows.Scan(&dbc.GOOS, &dbc.GOARCH, database.NullIsEmpty(&h), database.NullIsEmpty(&t)); err != nil {
+			return err
+		}
+		if bc.Match(dbc) && (!found || internal.CompareBuildContexts(dbc, bcMin) < 0) {
+			found, bcMin, html, text = true, dbc, h, t
+		}
+		return nil
+	}
+	err = db.db.RunQuery(ctx, `
+		SELECT d.goos, d.goarch, d.html, d.text
+		FROM documentation d
+		INNER JOIN units u ON u.id = d.unit_id
+		INNER JOIN paths p ON p.id = u.path_id
+		INNER JOIN modules m ON m.id = u.module_id
+		WHERE p.path = $1 AND m.module_path = $2 AND m.version = $3`,
+		collect, u.Path, u.ModulePath, u.Version)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+	if doc == nil {
+		doc = &internal.Documentation{GOOS: bcMin.GOOS, GOARCH: bcMin.GOARCH}
+		u.Documentation = []*internal.Documentation{doc}
+	}
+	if fields&internal.WithDocHTML != 0 {
+		doc.HTML = html
+	}
+	if fields&internal.WithDocText != 0 {
+		doc.Text = text
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware"
+)
+
+// getUnitDocSource sets u.Documentation to the encoded documentation source
+// of u, which must be a package, for the first build context that matches
+// bc. It does nothing if the documentation source has already been read
+// into u, or if the package has no documentation source.
+func (db *DB) getUnitDocSource(ctx context.Context, u *internal.Unit, bc internal.BuildContext) (err error) {
+	defer derrors.WrapStack(&err, "getUnitDocSource(ctx, %q, %q, %q, %v)", u.Path, u.ModulePath, u.Version, bc)
+	defer middleware.ElapsedStat(ctx, "getUnitDocSource")()
+
+	if len(u.Documentation) > 0 && u.Documentation[0].Source != nil {
+		return nil
+	}
+	var (
+		src   []byte
+		bcMin internal.BuildContext
+	)
+	collect := func(rows *sql.Rows) error {
+		var (
+			dbc internal.BuildContext
+			s   []byte
+		)
+		if err := rows.Scan(&dbc.GOOS, &dbc.GOARCH, &s); err != nil {
+			return err
+		}
+		if bc.Match(dbc) && (src == nil || internal.CompareBuildContexts(dbc, bcMin) < 0) {
+			src, bcMin = s, dbc
+		}
+		return nil
+	}
+	err = db.db.RunQuery(ctx, `
+		SELECT d.goos, d.goarch, d.source
+		FROM documentation d
+		INNER JOIN units u ON u.id = d.unit_id
+		INNER JOIN paths p ON p.id = u.path_id
+		INNER JOIN modules m ON m.id = u.module_id
+		WHERE p.path = $1 AND m.module_path = $2 AND m.version = $3
+			AND d.source IS NOT NULL`,
+		collect, u.Path, u.ModulePath, u.Version)
+	if err != nil {
+		return err
+	}
+	if src == nil {
+		return nil
+	}
+	u.Documentation = []*internal.Documentation{{GOOS: bcMin.GOOS, GOARCH: bcMin.GOARCH, Source: src}}
+	return nil
+}
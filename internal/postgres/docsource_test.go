@@ -0,0 +1,87 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetUnitDocSource(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module("example.com/m", "v1.2.3", "pkg")
+	m.Packages()[0].Documentation = []*internal.Documentation{sample.Documentation(sample.GOOS, sample.GOARCH, `
+		// Package pkg is for testing.
+		package pkg
+
+		// T is a type.
+		type T int
+
+		// M is a method.
+		func (T) M() {}
+
+		// Hello returns a greeting for name.
+		func Hello(name string) string { return "" }
+	`)}
+	MustInsertModule(ctx, t, testDB, m)
+
+	um, err := testDB.GetUnitMeta(ctx, "example.com/m/pkg", "example.com/m", "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The source is read with or without the rest of the unit, and can be
+	// rendered as a tree of symbols or as the documentation of one symbol.
+	for _, fields := range []internal.FieldSet{internal.WithDocSource, internal.WithMain | internal.WithDocSource} {
+		u, err := testDB.GetUnit(ctx, um, fields, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(u.Documentation) != 1 || u.Documentation[0].Source == nil {
+			t.Fatalf("fields %d: got Documentation %+v, want one with source", fields, u.Documentation)
+		}
+		tree, err := godoc.DocTreeFromUnit(u)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var typ *internal.DocNode
+		for _, n := range tree.Symbols {
+			if n.Name == "T" {
+				typ = n
+			}
+		}
+		if typ == nil || len(typ.Children) != 1 || typ.Children[0].Name != "T.M" {
+			t.Errorf("fields %d: got %+v, want type T with method T.M", fields, typ)
+		}
+
+		sd, err := godoc.SymbolDocFromUnit(ctx, u, "Hello")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "func Hello(name string) string"; sd.Synopsis != want {
+			t.Errorf("fields %d: got synopsis %q, want %q", fields, sd.Synopsis, want)
+		}
+		if want := "Hello returns a greeting for name."; !strings.Contains(sd.Doc.String(), want) {
+			t.Errorf("fields %d: doc %q does not contain %q", fields, sd.Doc, want)
+		}
+		if !strings.HasPrefix(sd.SourceURL, "https://example.com/m/") {
+			t.Errorf("fields %d: got source URL %q", fields, sd.SourceURL)
+		}
+		if _, err := godoc.SymbolDocFromUnit(ctx, u, "Goodbye"); !errors.Is(err, derrors.NotFound) {
+			t.Errorf("fields %d: unknown symbol: got %v, want NotFound", fields, err)
+		}
+	}
+}
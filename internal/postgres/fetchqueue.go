@@ -0,0 +1,253 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// Priorities of module versions in the fetch queue. Versions with a higher
+// priority are dequeued first.
+const (
+	// FetchPriorityBackfill is the priority of bulk fetches, such as those of
+	// versions read from the module index.
+	FetchPriorityBackfill = 0
+	// FetchPriorityUser is the priority of fetches that a user is waiting
+	// for, such as those requested from the frontend.
+	FetchPriorityUser = 10
+)
+
+// EnqueueFetch adds the module version to the fetch queue with the given
+// priority. It reports whether the version was added, which it is not if it
+// is already in the queue; in that case its priority is raised to the given
+// one if that is higher.
+//
+// The fetch queue is kept in the database so that it survives worker
+// restarts, and so that several workers can share it without an external
+// queue.
+func (db *DB) EnqueueFetch(ctx context.Context, modulePath, version string, priority int) (enqueued bool, err error) {
+	defer derrors.WrapStack(&err, "EnqueueFetch(ctx, %q, %q, %d)", modulePath, version, priority)
+
+	n, err := db.db.Exec(ctx, `
+		INSERT INTO fetch_queue (module_path, version, priority)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (module_path, version) DO NOTHING`,
+		modulePath, version, priority)
+	if err != nil {
+		return false, err
+	}
+	if n == 1 {
+		return true, nil
+	}
+	_, err = db.db.Exec(ctx, `
+		UPDATE fetch_queue
+		SET priority = $3
+		WHERE module_path = $1 AND version = $2 AND priority < $3`,
+		modulePath, version, priority)
+	return false, err
+}
+
+// A FetchLease is a module version leased from the fetch queue by
+// DequeueFetch.
+type FetchLease struct {
+	internal.Modver
+	// Token identifies the lease. When a lease expires and the version is
+	// dequeued again, the new lease has a different token.
+	Token string
+}
+
+// DequeueFetch leases the module version with the highest priority in the
+// fetch queue, among those that are not leased, and returns the lease. Of
+// versions with the same priority, the one that has been in the queue
+// longest is chosen. The lease lasts for the given duration. A version stays
+// in the queue until MarkFetchDone is called with its lease; if that does not
+// happen before the lease expires, because the worker failed or was
+// restarted, the version is handed out again, with a new lease.
+//
+// Versions that have been dead-lettered by MarkFetchFailed are not returned.
+// A version whose lease expired after it had been dequeued maxAttempts times
+// is dead-lettered too, rather than handed out again, so that a version that
+// makes workers crash or hang is not retried forever.
+// DequeueFetch returns an error wrapping derrors.NotFound if there is no
+// version to fetch.
+func (db *DB) DequeueFetch(ctx context.Context, lease time.Duration, maxAttempts int) (_ *FetchLease, err error) {
+	defer derrors.WrapStack(&err, "DequeueFetch(ctx, %s, %d)", lease, maxAttempts)
+
+	if _, err := db.db.Exec(ctx, `
+		UPDATE fetch_queue
+		SET
+			dead_lettered = TRUE,
+			leased_until = NULL,
+			lease_token = NULL,
+			last_error = 'lease expired'
+		WHERE NOT dead_lettered
+			AND leased_until <= CURRENT_TIMESTAMP
+			AND attempts >= $1`,
+		maxAttempts); err != nil {
+		return nil, err
+	}
+
+	token, err := newLeaseToken()
+	if err != nil {
+		return nil, err
+	}
+	// SKIP LOCKED lets concurrent callers lease different rows instead of
+	// waiting for each other.
+	query := `
+		UPDATE fetch_queue
+		SET
+			leased_until = CURRENT_TIMESTAMP + $1 * INTERVAL '1 millisecond',
+			lease_token = $2,
+			attempts = attempts + 1
+		WHERE (module_path, version) = (
+			SELECT module_path, version
+			FROM fetch_queue
+			WHERE NOT dead_lettered
+				AND (leased_until IS NULL OR leased_until <= CURRENT_TIMESTAMP)
+			ORDER BY priority DESC, enqueued_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING module_path, version`
+	l := &FetchLease{Token: token}
+	err = db.db.QueryRow(ctx, query, lease.Milliseconds(), token).Scan(&l.Path, &l.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("fetch queue is empty: %w", derrors.NotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// newLeaseToken returns a random token for a lease of the fetch queue.
+func newLeaseToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// MarkFetchDone removes the leased module version from the fetch queue. It
+// should be called once the version has been fetched, or has failed with an
+// error that retrying will not fix. It returns an error wrapping
+// derrors.NotFound if the lease is no longer held, because it expired and
+// the version was dequeued again; the new holder of the lease is then
+// responsible for the version.
+func (db *DB) MarkFetchDone(ctx context.Context, lease *FetchLease) (err error) {
+	defer derrors.WrapStack(&err, "MarkFetchDone(ctx, %s)", lease.Modver)
+
+	n, err := db.db.Exec(ctx, `
+		DELETE FROM fetch_queue
+		WHERE module_path = $1 AND version = $2 AND lease_token = $3`,
+		lease.Path, lease.Version, lease.Token)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("lease of %s is not held: %w", lease.Modver, derrors.NotFound)
+	}
+	return nil
+}
+
+// MarkFetchFailed records that the fetch of the leased module version failed
+// with the error message errMsg, and releases the lease so the version can
+// be retried. If the version has been dequeued maxAttempts times or more, it
+// is dead-lettered instead: it is no longer dequeued until
+// RequeueDeadLetteredFetch is called for it. MarkFetchFailed reports whether
+// the version was dead-lettered, and returns an error wrapping
+// derrors.NotFound if the lease is no longer held.
+func (db *DB) MarkFetchFailed(ctx context.Context, lease *FetchLease, errMsg string, maxAttempts int) (deadLettered bool, err error) {
+	defer derrors.WrapStack(&err, "MarkFetchFailed(ctx, %s, %d)", lease.Modver, maxAttempts)
+
+	query := `
+		UPDATE fetch_queue
+		SET
+			leased_until = NULL,
+			lease_token = NULL,
+			last_error = $4,
+			dead_lettered = attempts >= $5
+		WHERE module_path = $1 AND version = $2 AND lease_token = $3
+		RETURNING dead_lettered`
+	err = db.db.QueryRow(ctx, query, lease.Path, lease.Version, lease.Token, errMsg, maxAttempts).Scan(&deadLettered)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, fmt.Errorf("lease of %s is not held: %w", lease.Modver, derrors.NotFound)
+	}
+	if err != nil {
+		return false, err
+	}
+	return deadLettered, nil
+}
+
+// A DeadLetteredFetch is a module version in the fetch queue whose fetch
+// failed too many times.
+type DeadLetteredFetch struct {
+	ModulePath string
+	Version    string
+	EnqueuedAt time.Time
+	Attempts   int
+	LastError  string
+}
+
+// GetDeadLetteredFetches returns the dead-lettered module versions in the
+// fetch queue, oldest first.
+func (db *DB) GetDeadLetteredFetches(ctx context.Context) (_ []*DeadLetteredFetch, err error) {
+	defer derrors.WrapStack(&err, "GetDeadLetteredFetches(ctx)")
+
+	query := `
+		SELECT module_path, version, enqueued_at, attempts, last_error
+		FROM fetch_queue
+		WHERE dead_lettered
+		ORDER BY enqueued_at`
+	var fetches []*DeadLetteredFetch
+	collect := func(rows *sql.Rows) error {
+		var f DeadLetteredFetch
+		if err := rows.Scan(&f.ModulePath, &f.Version, &f.EnqueuedAt, &f.Attempts, database.NullIsEmpty(&f.LastError)); err != nil {
+			return err
+		}
+		fetches = append(fetches, &f)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect); err != nil {
+		return nil, err
+	}
+	return fetches, nil
+}
+
+// RequeueDeadLetteredFetch returns the dead-lettered module version to the
+// fetch queue, with its attempts and error cleared. It returns an error
+// wrapping derrors.NotFound if the version is not dead-lettered.
+func (db *DB) RequeueDeadLetteredFetch(ctx context.Context, modulePath, version string) (err error) {
+	defer derrors.WrapStack(&err, "RequeueDeadLetteredFetch(ctx, %q, %q)", modulePath, version)
+
+	n, err := db.db.Exec(ctx, `
+		UPDATE fetch_queue
+		SET
+			dead_lettered = FALSE,
+			attempts = 0,
+			last_error = NULL,
+			leased_until = NULL,
+			lease_token = NULL
+		WHERE module_path = $1 AND version = $2 AND dead_lettered`,
+		modulePath, version)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("%s@%s is not dead-lettered: %w", modulePath, version, derrors.NotFound)
+	}
+	return nil
+}
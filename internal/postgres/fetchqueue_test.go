@@ -0,0 +1,241 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+func TestFetchQueue(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for _, mv := range []internal.Modver{
+		{Path: "example.com/a", Version: "v1.0.0"},
+		{Path: "example.com/b", Version: "v1.0.0"},
+		{Path: "example.com/a", Version: "v1.0.0"},
+	} {
+		if _, err := testDB.EnqueueFetch(ctx, mv.Path, mv.Version, FetchPriorityBackfill); err != nil {
+			t.Fatal(err)
+		}
+		// Keep the enqueue times distinct, so the order is deterministic.
+		time.Sleep(10 * time.Millisecond)
+	}
+	if enqueued, err := testDB.EnqueueFetch(ctx, "example.com/a", "v1.0.0", FetchPriorityBackfill); err != nil || enqueued {
+		t.Errorf("EnqueueFetch of a queued version = %t, %v; want false, nil", enqueued, err)
+	}
+
+	const maxAttempts = 10
+	dequeue := func(lease time.Duration, want string) *FetchLease {
+		t.Helper()
+		got, err := testDB.DequeueFetch(ctx, lease, maxAttempts)
+		if want == "" {
+			if !errors.Is(err, derrors.NotFound) {
+				t.Fatalf("DequeueFetch: got %v, %v; want NotFound", got, err)
+			}
+			return nil
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.String() != want {
+			t.Fatalf("DequeueFetch = %s, want %s", got, want)
+		}
+		return got
+	}
+
+	// The first worker leases a briefly; the second worker gets b, and then
+	// nothing, because a is still leased.
+	const lease = 200 * time.Millisecond
+	start := time.Now()
+	staleA := dequeue(lease, "example.com/a@v1.0.0")
+	b := dequeue(time.Hour, "example.com/b@v1.0.0")
+	dequeue(time.Hour, "")
+
+	var leasedUntil time.Time
+	if err := testDB.db.QueryRow(ctx, `
+		SELECT leased_until FROM fetch_queue WHERE module_path = $1 AND version = $2`,
+		staleA.Path, staleA.Version).Scan(&leasedUntil); err != nil {
+		t.Fatal(err)
+	}
+	// Allow for some clock skew between the test and the database.
+	if earliest, latest := start.Add(lease-time.Second), time.Now().Add(lease+time.Second); leasedUntil.Before(earliest) || leasedUntil.After(latest) {
+		t.Errorf("leased_until = %s, want between %s and %s", leasedUntil, earliest, latest)
+	}
+
+	// Once the lease expires, a is handed out again, with a new lease.
+	time.Sleep(lease + 100*time.Millisecond)
+	a := dequeue(time.Hour, "example.com/a@v1.0.0")
+	if a.Token == staleA.Token {
+		t.Errorf("lease token of a was not renewed: %q", a.Token)
+	}
+	// The expired lease no longer allows marking a as done.
+	if err := testDB.MarkFetchDone(ctx, staleA); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("MarkFetchDone with expired lease: got %v, want NotFound", err)
+	}
+
+	// Versions that are done are no longer in the queue, even when their
+	// leases expire.
+	for _, l := range []*FetchLease{a, b} {
+		if err := testDB.MarkFetchDone(ctx, l); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := testDB.db.Exec(ctx, `UPDATE fetch_queue SET leased_until = NULL`); err != nil {
+		t.Fatal(err)
+	}
+	dequeue(time.Hour, "")
+}
+
+func TestFetchQueueDeadLetter(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const (
+		modulePath  = "example.com/bad"
+		version     = "v1.0.0"
+		maxAttempts = 3
+	)
+	if _, err := testDB.EnqueueFetch(ctx, modulePath, version, FetchPriorityBackfill); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= maxAttempts; i++ {
+		l, err := testDB.DequeueFetch(ctx, time.Hour, maxAttempts)
+		if err != nil {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+		deadLettered, err := testDB.MarkFetchFailed(ctx, l, fmt.Sprintf("failure %d", i), maxAttempts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The lease was released, so it can't be used again.
+		if _, err := testDB.MarkFetchFailed(ctx, l, "again", maxAttempts); !errors.Is(err, derrors.NotFound) {
+			t.Errorf("attempt %d: MarkFetchFailed with released lease: got %v, want NotFound", i, err)
+		}
+		if want := i == maxAttempts; deadLettered != want {
+			t.Errorf("attempt %d: dead-lettered = %t, want %t", i, deadLettered, want)
+		}
+	}
+	if _, err := testDB.DequeueFetch(ctx, time.Hour, maxAttempts); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("DequeueFetch of dead-lettered version: got %v, want NotFound", err)
+	}
+
+	got, err := testDB.GetDeadLetteredFetches(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*DeadLetteredFetch{{
+		ModulePath: modulePath,
+		Version:    version,
+		Attempts:   maxAttempts,
+		LastError:  fmt.Sprintf("failure %d", maxAttempts),
+	}}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(DeadLetteredFetch{}, "EnqueuedAt")); diff != "" {
+		t.Errorf("GetDeadLetteredFetches mismatch (-want, +got):\n%s", diff)
+	}
+
+	if err := testDB.RequeueDeadLetteredFetch(ctx, modulePath, version); err != nil {
+		t.Fatal(err)
+	}
+	if err := testDB.RequeueDeadLetteredFetch(ctx, modulePath, version); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("RequeueDeadLetteredFetch of queued version: got %v, want NotFound", err)
+	}
+	mv, err := testDB.DequeueFetch(ctx, time.Hour, maxAttempts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := mv.String(), modulePath+"@"+version; got != want {
+		t.Errorf("DequeueFetch after requeue = %s, want %s", got, want)
+	}
+}
+
+func TestFetchQueueDeadLetterExpiredLease(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const (
+		modulePath  = "example.com/hang"
+		version     = "v1.0.0"
+		maxAttempts = 2
+		lease       = 100 * time.Millisecond
+	)
+	if _, err := testDB.EnqueueFetch(ctx, modulePath, version, FetchPriorityBackfill); err != nil {
+		t.Fatal(err)
+	}
+	// The worker that leased the version never reports back, every time.
+	for i := 1; i <= maxAttempts; i++ {
+		if _, err := testDB.DequeueFetch(ctx, lease, maxAttempts); err != nil {
+			t.Fatalf("attempt %d: %v", i, err)
+		}
+		time.Sleep(lease + 100*time.Millisecond)
+	}
+	if _, err := testDB.DequeueFetch(ctx, lease, maxAttempts); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("DequeueFetch after %d expired leases: got %v, want NotFound", maxAttempts, err)
+	}
+	got, err := testDB.GetDeadLetteredFetches(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*DeadLetteredFetch{{
+		ModulePath: modulePath,
+		Version:    version,
+		Attempts:   maxAttempts,
+		LastError:  "lease expired",
+	}}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(DeadLetteredFetch{}, "EnqueuedAt")); diff != "" {
+		t.Errorf("GetDeadLetteredFetches mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestFetchQueuePriority(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	enqueue := func(modulePath string, priority int) {
+		t.Helper()
+		if _, err := testDB.EnqueueFetch(ctx, modulePath, "v1.0.0", priority); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	enqueue("example.com/low", FetchPriorityBackfill)
+	enqueue("example.com/raised", FetchPriorityBackfill)
+	enqueue("example.com/high", FetchPriorityUser)
+	// Enqueuing a queued version with a higher priority raises its priority.
+	enqueue("example.com/raised", FetchPriorityUser)
+	// Enqueuing it with a lower priority does not lower it.
+	enqueue("example.com/high", FetchPriorityBackfill)
+
+	for _, want := range []string{"example.com/raised", "example.com/high", "example.com/low"} {
+		got, err := testDB.DequeueFetch(ctx, time.Hour, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Path != want {
+			t.Errorf("DequeueFetch = %s, want %s", got.Path, want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// GetGoMod returns the contents of the go.mod file stored for the given
+// module version. It returns an error with NotFound in its chain if the
+// module version or its go.mod file was not stored.
+func (db *DB) GetGoMod(ctx context.Context, modulePath, version string) (_ []byte, err error) {
+	defer derrors.WrapStack(&err, "GetGoMod(ctx, %q, %q)", modulePath, version)
+
+	var contents []byte
+	err = db.db.QueryRow(ctx, `
+		SELECT g.contents
+		FROM module_go_mods g
+		INNER JOIN modules m ON m.id = g.module_id
+		WHERE m.module_path = $1 AND m.version = $2`,
+		modulePath, version).Scan(&contents)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	case nil:
+		return contents, nil
+	default:
+		return nil, err
+	}
+}
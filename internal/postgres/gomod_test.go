@@ -0,0 +1,41 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetGoMod(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const goMod = "module example.com/m\n\ngo 1.19\n"
+	m := sample.Module("example.com/m", "v1.0.0", "p")
+	m.GoMod = []byte(goMod)
+	MustInsertModule(ctx, t, testDB, m)
+	MustInsertModule(ctx, t, testDB, sample.Module("example.com/m", "v1.1.0", "p"))
+
+	got, err := testDB.GetGoMod(ctx, "example.com/m", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != goMod {
+		t.Errorf("got %q, want %q", got, goMod)
+	}
+	for _, v := range []string{"v1.1.0", "v9.9.9"} {
+		if _, err := testDB.GetGoMod(ctx, "example.com/m", v); !errors.Is(err, derrors.NotFound) {
+			t.Errorf("%s: got error %v, want NotFound", v, err)
+		}
+	}
+}
@@ -41,21 +41,43 @@ func (db *DB) InsertModule(ctx context.Context, m *internal.Module, lmv *interna
 		derrors.WrapStack(&err, "DB.InsertModule(ctx, Module(%q, %q))", m.ModulePath, m.Version)
 	}()
 
-	if err := validateModule(m); err != nil {
+	if err := db.prepareModule(ctx, m); err != nil {
+		return false, err
+	}
+	isLatest, err = db.saveModule(ctx, m, lmv)
+	if err != nil {
 		return false, err
 	}
+	db.runInsertModuleHooks(ctx, m)
+	return isLatest, nil
+}
+
+// prepareModule validates m before it is saved, and removes its
+// non-redistributable data unless the license check is bypassed.
+func (db *DB) prepareModule(ctx context.Context, m *internal.Module) error {
+	if err := validateModule(m); err != nil {
+		return err
+	}
 	// Compare existing data from the database, and the module to be
 	// inserted. Rows that currently exist should not be missing from the
 	// new module. We want to be sure that we will overwrite every row that
 	// pertains to the module.
 	if err := db.comparePaths(ctx, m); err != nil {
-		return false, err
+		return err
 	}
 	if !db.bypassLicenseCheck {
 		// If we are not bypassing license checking, remove data for non-redistributable modules.
 		m.RemoveNonRedistributableData()
 	}
-	return db.saveModule(ctx, m, lmv)
+	return nil
+}
+
+func (db *DB) runInsertModuleHooks(ctx context.Context, m *internal.Module) {
+	for _, h := range db.insertModuleHooks {
+		if h != nil {
+			h(ctx, m.ModulePath, m.Version)
+		}
+	}
 }
 
 // saveModule inserts a Module into the database along with its packages,
@@ -88,109 +110,135 @@ func (db *DB) saveModule(ctx context.Context, m *internal.Module, lmv *internal.
 	}
 
 	err = db.db.Transact(ctx, sql.LevelRepeatableRead, func(tx *database.DB) error {
-		moduleID, err := insertModule(ctx, tx, m)
-		if err != nil {
-			return err
-		}
-		// Compare existing data from the database, and the module to be
-		// inserted. Rows that currently exist should not be missing from the
-		// new module. We want to be sure that we will overwrite every row that
-		// pertains to the module.
-		if err := db.compareLicenses(ctx, moduleID, m.Licenses); err != nil {
-			return err
-		}
-		if err := insertLicenses(ctx, tx, m, moduleID); err != nil {
-			return err
-		}
-		pathToUnitID, pathToDocs, err := db.insertUnits(ctx, tx, m, moduleID, pathToID)
-		if err != nil {
-			return err
-		}
+		var err error
+		isLatest, err = db.saveModuleTx(ctx, tx, m, lmv, pathToID)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return isLatest, nil
+}
 
-		// Obtain a transaction-scoped exclusive advisory lock on the module
-		// path. The transaction that holds the lock is the only one that can
-		// execute the subsequent code on any module with the given path. That
-		// means that conflicts from two transactions both believing they are
-		// working on the latest version of a given module cannot happen.
-		// The lock is released automatically at the end of the transaction.
-		if err := lock(ctx, tx, m.ModulePath); err != nil {
-			return err
-		}
+// saveModuleTx does the work of saveModule inside the transaction tx, after
+// the module's paths have been inserted.
+func (db *DB) saveModuleTx(ctx context.Context, tx *database.DB, m *internal.Module, lmv *internal.LatestModuleVersions, pathToID map[string]int) (isLatest bool, err error) {
+	moduleID, err := insertModule(ctx, tx, m)
+	if err != nil {
+		return false, err
+	}
+	// Compare existing data from the database, and the module to be
+	// inserted. Rows that currently exist should not be missing from the
+	// new module. We want to be sure that we will overwrite every row that
+	// pertains to the module.
+	if err := db.compareLicenses(ctx, moduleID, m.Licenses); err != nil {
+		return false, err
+	}
+	if err := insertLicenses(ctx, tx, m, moduleID); err != nil {
+		return false, err
+	}
+	if err := insertRequirements(ctx, tx, m, moduleID); err != nil {
+		return false, err
+	}
+	if err := insertChangelog(ctx, tx, m, moduleID); err != nil {
+		return false, err
+	}
+	if err := insertNotice(ctx, tx, m, moduleID); err != nil {
+		return false, err
+	}
+	if err := insertGoMod(ctx, tx, m, moduleID); err != nil {
+		return false, err
+	}
+	if err := insertWorkspace(ctx, tx, m, moduleID); err != nil {
+		return false, err
+	}
+	pathToUnitID, pathToDocs, err := db.insertUnits(ctx, tx, m, moduleID, pathToID)
+	if err != nil {
+		return false, err
+	}
 
-		// We only insert into imports_unique and search_documents if this is
-		// the latest version of the module.
-		// By the time this function is called, we've already inserted into the modules table.
-		// So the query in getLatestGoodVersion will include this version.
-		latest, err := getLatestGoodVersion(ctx, tx, m.ModulePath, lmv)
-		if err != nil {
-			return err
-		}
-		// Update the DB with the latest version, even if we are not the latest.
-		// (Perhaps we just learned of a retraction that affects the good latest
-		// version.)
-		if err := updateLatestGoodVersion(ctx, tx, m.ModulePath, latest); err != nil {
-			return err
-		}
-		isLatest = m.Version == latest
-		if err := insertSymbols(ctx, tx, m.ModulePath, m.Version, isLatest, pathToID, pathToUnitID, pathToDocs); err != nil {
-			return err
-		}
-		if !isLatest {
-			return nil
-		}
+	// Obtain a transaction-scoped exclusive advisory lock on the module
+	// path. The transaction that holds the lock is the only one that can
+	// execute the subsequent code on any module with the given path. That
+	// means that conflicts from two transactions both believing they are
+	// working on the latest version of a given module cannot happen.
+	// The lock is released automatically at the end of the transaction.
+	if err := lock(ctx, tx, m.ModulePath); err != nil {
+		return false, err
+	}
 
-		// Here, this module is the latest good version.
-		if err := insertImportsUnique(ctx, tx, m); err != nil {
-			return err
-		}
+	// We only insert into imports_unique and search_documents if this is
+	// the latest version of the module.
+	// By the time this function is called, we've already inserted into the modules table.
+	// So the query in getLatestGoodVersion will include this version.
+	latest, err := getLatestGoodVersion(ctx, tx, m.ModulePath, lmv)
+	if err != nil {
+		return false, err
+	}
+	// Update the DB with the latest version, even if we are not the latest.
+	// (Perhaps we just learned of a retraction that affects the good latest
+	// version.)
+	if err := updateLatestGoodVersion(ctx, tx, m.ModulePath, latest); err != nil {
+		return false, err
+	}
+	isLatest = m.Version == latest
+	if err := insertSymbols(ctx, tx, m.ModulePath, m.Version, isLatest, pathToID, pathToUnitID, pathToDocs); err != nil {
+		return false, err
+	}
+	if !isLatest {
+		return false, nil
+	}
 
-		var pkgPaths []string
-		for _, u := range m.Packages() {
-			pkgPaths = append(pkgPaths, u.Path)
-		}
-		if err := deleteOtherModulePackagesFromSearchDocuments(ctx, tx, m.ModulePath, pkgPaths); err != nil {
-			return err
-		}
+	// Here, this module is the latest good version.
+	if err := insertImportsUnique(ctx, tx, m); err != nil {
+		return false, err
+	}
 
-		// If the most recent version of this module has an alternative module
-		// path, then do not insert its packages into search_documents (and
-		// delete whatever is there). This happens when a module that initially
-		// does not have a go.mod file is forked or fetched via some
-		// non-canonical path (such as an alternative capitalization), and then
-		// in a later version acquires a go.mod file.
-		//
-		// To take an actual example: github.com/sirupsen/logrus@v1.1.0 has a go.mod
-		// file that establishes that path as canonical. But v1.0.6 does not have a
-		// go.mod file. So the miscapitalized path github.com/Sirupsen/logrus at
-		// v1.1.0 is marked as an alternative path (code 491) by
-		// internal/fetch.FetchModule and is not inserted into the DB, but at
-		// v1.0.6 it is considered valid, and we end up here. We still insert
-		// github.com/Sirupsen/logrus@v1.0.6 in the modules table and friends so
-		// that users who import it can find information about it, but we don't want
-		// it showing up in search results.
-		//
-		// Note that we end up here only if we first saw the alternative version
-		// (github.com/Sirupsen/logrus@v1.1.0 in the example) and then see the valid
-		// one. The "if code == 491" section of internal/worker.fetchAndUpdateState
-		// handles the case where we fetch the versions in the other order.
-		alt, err := isAlternativeModulePath(ctx, tx, m.ModulePath)
-		if err != nil {
-			return err
-		}
-		if alt {
-			log.Infof(ctx, "%s@%s: not inserting into search documents", m.ModulePath, m.Version)
-			return nil
-		}
-		// Insert the module's packages into search_documents.
-		if err := upsertSearchDocuments(ctx, tx, m); err != nil {
-			return err
-		}
-		return upsertSymbolSearchDocuments(ctx, tx, m.ModulePath, m.Version)
-	})
+	var pkgPaths []string
+	for _, u := range m.Packages() {
+		pkgPaths = append(pkgPaths, u.Path)
+	}
+	if err := deleteOtherModulePackagesFromSearchDocuments(ctx, tx, m.ModulePath, pkgPaths); err != nil {
+		return false, err
+	}
+
+	// If the most recent version of this module has an alternative module
+	// path, then do not insert its packages into search_documents (and
+	// delete whatever is there). This happens when a module that initially
+	// does not have a go.mod file is forked or fetched via some
+	// non-canonical path (such as an alternative capitalization), and then
+	// in a later version acquires a go.mod file.
+	//
+	// To take an actual example: github.com/sirupsen/logrus@v1.1.0 has a go.mod
+	// file that establishes that path as canonical. But v1.0.6 does not have a
+	// go.mod file. So the miscapitalized path github.com/Sirupsen/logrus at
+	// v1.1.0 is marked as an alternative path (code 491) by
+	// internal/fetch.FetchModule and is not inserted into the DB, but at
+	// v1.0.6 it is considered valid, and we end up here. We still insert
+	// github.com/Sirupsen/logrus@v1.0.6 in the modules table and friends so
+	// that users who import it can find information about it, but we don't want
+	// it showing up in search results.
+	//
+	// Note that we end up here only if we first saw the alternative version
+	// (github.com/Sirupsen/logrus@v1.1.0 in the example) and then see the valid
+	// one. The "if code == 491" section of internal/worker.fetchAndUpdateState
+	// handles the case where we fetch the versions in the other order.
+	alt, err := isAlternativeModulePath(ctx, tx, m.ModulePath)
 	if err != nil {
 		return false, err
 	}
-	return isLatest, nil
+	if alt {
+		log.Infof(ctx, "%s@%s: not inserting into search documents", m.ModulePath, m.Version)
+		return true, nil
+	}
+	// Insert the module's packages into search_documents.
+	if err := upsertSearchDocuments(ctx, tx, m); err != nil {
+		return false, err
+	}
+	if err := upsertSymbolSearchDocuments(ctx, tx, m.ModulePath, m.Version); err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 // isAlternativeModulePath reports whether the module path is "alternative,"
@@ -230,6 +278,15 @@ func insertModule(ctx context.Context, db *database.DB, m *internal.Module) (_ i
 	if err != nil {
 		return 0, err
 	}
+	// A module fetched again without commit information, for example
+	// because its getter doesn't know it, keeps the information it had.
+	var commitJSON []byte
+	if m.Commit != nil {
+		commitJSON, err = json.Marshal(m.Commit)
+		if err != nil {
+			return 0, err
+		}
+	}
 	versionType, err := version.ParseType(m.Version)
 	if err != nil {
 		return 0, err
@@ -246,13 +303,19 @@ func insertModule(ctx context.Context, db *database.DB, m *internal.Module) (_ i
 			source_info,
 			redistributable,
 			has_go_mod,
-			incompatible)
-		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
+			incompatible,
+			commit_info,
+			keywords,
+			has_packages)
+		VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
 		ON CONFLICT
 			(module_path, version)
 		DO UPDATE SET
 			source_info=excluded.source_info,
-			redistributable=excluded.redistributable
+			commit_info=COALESCE(excluded.commit_info, modules.commit_info),
+			redistributable=excluded.redistributable,
+			keywords=excluded.keywords,
+			has_packages=excluded.has_packages
 		RETURNING id`,
 		m.ModulePath,
 		m.Version,
@@ -264,6 +327,9 @@ func insertModule(ctx context.Context, db *database.DB, m *internal.Module) (_ i
 		m.IsRedistributable,
 		m.HasGoMod,
 		version.IsIncompatible(m.Version),
+		commitJSON,
+		pq.Array(m.Keywords),
+		m.HasPackages,
 	).Scan(&moduleID)
 	if err != nil {
 		return 0, err
@@ -299,6 +365,128 @@ func insertLicenses(ctx context.Context, db *database.DB, m *internal.Module, mo
 	return nil
 }
 
+// insertRequirements replaces the require directives stored for the module
+// with m.Requirements.
+func insertRequirements(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	ctx, span := trace.StartSpan(ctx, "insertRequirements")
+	defer span.End()
+	defer derrors.WrapStack(&err, "insertRequirements(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM module_requires WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	// A go.mod file may require the same module more than once; the go
+	// command uses the highest version, and so do we.
+	pathToReq := map[string]*internal.Requirement{}
+	for _, r := range m.Requirements {
+		if cur, ok := pathToReq[r.ModulePath]; !ok || semver.Compare(r.Version, cur.Version) > 0 {
+			pathToReq[r.ModulePath] = r
+		}
+	}
+	if len(pathToReq) == 0 {
+		return nil
+	}
+	var paths []string
+	for p := range pathToReq {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var values []any
+	for _, p := range paths {
+		r := pathToReq[p]
+		values = append(values, moduleID, p, r.Version, r.Indirect)
+	}
+	cols := []string{"module_id", "required_path", "required_version", "indirect"}
+	return db.BulkInsert(ctx, "module_requires", cols, values, "")
+}
+
+// insertChangelog replaces the changelog stored for the module with
+// m.Changelog.
+func insertChangelog(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	ctx, span := trace.StartSpan(ctx, "insertChangelog")
+	defer span.End()
+	defer derrors.WrapStack(&err, "insertChangelog(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM module_changelogs WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	if m.Changelog == nil {
+		return nil
+	}
+	// Do not add a changelog with empty or zero contents.
+	contents := makeValidUnicode(m.Changelog.Contents)
+	if len(contents) == 0 {
+		return nil
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO module_changelogs (module_id, file_path, contents)
+		VALUES ($1, $2, $3)`, moduleID, m.Changelog.Filepath, contents)
+	return err
+}
+
+// insertNotice replaces the NOTICE file stored for the module with m.Notice.
+func insertNotice(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	ctx, span := trace.StartSpan(ctx, "insertNotice")
+	defer span.End()
+	defer derrors.WrapStack(&err, "insertNotice(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM module_notices WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	if m.Notice == nil {
+		return nil
+	}
+	contents := makeValidUnicode(m.Notice.Contents)
+	if len(contents) == 0 {
+		return nil
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO module_notices (module_id, file_path, contents)
+		VALUES ($1, $2, $3)`, moduleID, m.Notice.Filepath, contents)
+	return err
+}
+
+// insertGoMod replaces the go.mod file stored for the module with m.GoMod.
+func insertGoMod(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	ctx, span := trace.StartSpan(ctx, "insertGoMod")
+	defer span.End()
+	defer derrors.WrapStack(&err, "insertGoMod(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM module_go_mods WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	if m.GoMod == nil {
+		return nil
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO module_go_mods (module_id, contents)
+		VALUES ($1, $2)`, moduleID, m.GoMod)
+	return err
+}
+
+// insertWorkspace replaces the go.work information stored for the module with
+// m.Workspace.
+func insertWorkspace(ctx context.Context, db *database.DB, m *internal.Module, moduleID int) (err error) {
+	ctx, span := trace.StartSpan(ctx, "insertWorkspace")
+	defer span.End()
+	defer derrors.WrapStack(&err, "insertWorkspace(ctx, %q, %q)", m.ModulePath, m.Version)
+
+	if _, err := db.Exec(ctx, `DELETE FROM module_workspaces WHERE module_id = $1`, moduleID); err != nil {
+		return err
+	}
+	if m.Workspace == nil {
+		return nil
+	}
+	dirs := m.Workspace.Dirs
+	if dirs == nil {
+		dirs = []string{}
+	}
+	_, err = db.Exec(ctx, `
+		INSERT INTO module_workspaces (module_id, go_version, dirs)
+		VALUES ($1, $2, $3)`, moduleID, m.Workspace.GoVersion, pq.Array(dirs))
+	return err
+}
+
 // insertImportsUnique inserts and removes rows from the imports_unique table. It should only
 // be called if the given module's version is the latest.
 func insertImportsUnique(ctx context.Context, tx *database.DB, m *internal.Module) (err error) {
@@ -378,6 +566,18 @@ func (pdb *DB) insertUnits(ctx context.Context, tx *database.DB,
 			return nil, nil, fmt.Errorf("no entry in paths table for %q; should be impossible", u.Path)
 		}
 		pathIDToPath[pathID] = u.Path
+		var numExported, numDocumented any // NULL for directories
+		if u.IsPackage() {
+			numExported = u.DocStats.NumExported
+			numDocumented = u.DocStats.NumDocumented
+		}
+		var examples any // NULL if there are none
+		if u.Examples != nil {
+			examples, err = json.Marshal(u.Examples)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 		unitValues = append(unitValues,
 			pathID,
 			moduleID,
@@ -386,6 +586,14 @@ func (pdb *DB) insertUnits(ctx context.Context, tx *database.DB,
 			pq.Array(licenseTypes),
 			pq.Array(licensePaths),
 			u.IsRedistributable,
+			numExported,
+			numDocumented,
+			u.ExamplesValid,
+			u.NumTestFiles,
+			examples,
+			pq.Array(u.BuildErrors),
+			u.PackageDeprecated,
+			u.PackageDeprecationComment,
 		)
 		if u.Readme != nil {
 			pathToReadme[u.Path] = u.Readme
@@ -459,6 +667,14 @@ func insertUnits(ctx context.Context, db *database.DB, unitValues []any) (pathID
 		"license_types",
 		"license_paths",
 		"redistributable",
+		"num_exported_symbols",
+		"num_documented_symbols",
+		"examples_valid",
+		"num_test_files",
+		"examples",
+		"build_errors",
+		"deprecated",
+		"deprecation_comment",
 	}
 	uniqueUnitCols := []string{"path_id", "module_id"}
 	returningUnitCols := []string{"id", "path_id"}
@@ -506,7 +722,18 @@ func insertDocs(ctx context.Context, db *database.DB,
 					if doc.GOOS == "" || doc.GOARCH == "" {
 						ch <- database.RowItem{Err: errors.New("empty GOOS or GOARCH")}
 					}
-					ch <- database.RowItem{Values: []any{unitID, doc.GOOS, doc.GOARCH, doc.Synopsis, doc.Source}}
+					var usage any // NULL if not a command
+					if doc.Usage != "" {
+						usage = doc.Usage
+					}
+					var html, text any // NULL if not rendered
+					if doc.HTML != "" {
+						html = doc.HTML
+					}
+					if doc.Text != "" {
+						text = doc.Text
+					}
+					ch <- database.RowItem{Values: []any{unitID, doc.GOOS, doc.GOARCH, doc.Synopsis, usage, doc.Source, html, text}}
 				}
 			}
 			close(ch)
@@ -515,7 +742,7 @@ func insertDocs(ctx context.Context, db *database.DB,
 	}
 
 	uniqueCols := []string{"unit_id", "goos", "goarch"}
-	docCols := append(uniqueCols, "synopsis", "source")
+	docCols := append(uniqueCols, "synopsis", "usage", "source", "html", "text")
 	return db.CopyUpsert(ctx, "documentation",
 		docCols, database.CopyFromChan(generateRows()), uniqueCols, "id")
 }
@@ -599,28 +826,41 @@ func insertImports(ctx context.Context, tx *database.DB,
 	return tx.BulkUpsert(ctx, "imports", importCols, importValues, importCols)
 }
 
+// insertReadmes stores the READMEs of the units at paths. Since units belong
+// to a single module version, so do their READMEs. A README stored by an
+// earlier fetch of the same version is deleted if the unit no longer has one.
 func insertReadmes(ctx context.Context, db *database.DB,
 	paths []string,
 	pathToUnitID map[string]int,
 	pathToReadme map[string]*internal.Readme) (err error) {
 	defer derrors.WrapStack(&err, "insertReadmes")
 
-	var readmeValues []any
+	var (
+		readmeValues []any
+		noReadmeIDs  []int
+	)
 	for _, path := range paths {
+		unitID := pathToUnitID[path]
 		readme, ok := pathToReadme[path]
 		if !ok {
+			noReadmeIDs = append(noReadmeIDs, unitID)
 			continue
 		}
 
 		// Do not add a readme with empty or zero contents.
 		readmeContents := makeValidUnicode(readme.Contents)
 		if len(readmeContents) == 0 {
+			noReadmeIDs = append(noReadmeIDs, unitID)
 			continue
 		}
 
-		unitID := pathToUnitID[path]
 		readmeValues = append(readmeValues, unitID, readme.Filepath, readmeContents)
 	}
+	if len(noReadmeIDs) > 0 {
+		if _, err := db.Exec(ctx, `DELETE FROM readmes WHERE unit_id = ANY($1)`, pq.Array(noReadmeIDs)); err != nil {
+			return err
+		}
+	}
 	readmeCols := []string{"unit_id", "file_path", "contents"}
 	return db.BulkUpsert(ctx, "readmes", readmeCols, readmeValues, []string{"unit_id"})
 }
@@ -214,6 +214,32 @@ func TestUpsertModule(t *testing.T) {
 	checkModule(ctx, t, testDB, m)
 }
 
+func TestUpsertModuleRemovedReadme(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module("readme.org", "v1.2.3", "p")
+	MustInsertModule(ctx, t, testDB, m)
+	// Refetching the same version without a README removes the stored one.
+	m.Units[0].Readme = nil
+	MustInsertModule(ctx, t, testDB, m)
+
+	um, err := testDB.GetUnitMeta(ctx, m.ModulePath, m.ModulePath, m.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Readme != nil {
+		t.Errorf("got README %+v, want none", u.Readme)
+	}
+}
+
 func TestInsertModuleErrors(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
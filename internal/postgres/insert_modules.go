@@ -0,0 +1,179 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// DefaultInsertModulesBatchSize is the number of modules InsertModules
+// inserts in each transaction, unless changed with SetInsertModulesBatchSize.
+const DefaultInsertModulesBatchSize = 50
+
+// SetInsertModulesBatchSize sets the number of modules InsertModules inserts
+// in each transaction. It is not safe to call concurrently with
+// InsertModules.
+func (db *DB) SetInsertModulesBatchSize(n int) {
+	db.insertModulesBatchSize = n
+}
+
+// An InsertModuleResult is the outcome of inserting one module with
+// InsertModules.
+type InsertModuleResult struct {
+	ModulePath string
+	Version    string
+	// IsLatest reports whether the module was the latest version for its
+	// module path, as for InsertModule.
+	IsLatest bool
+	// Err is the reason the module was not inserted, or nil if it was.
+	Err error
+}
+
+// InsertModules inserts many modules, for example when seeding a new database
+// from an export of another one. It does the same work as calling
+// InsertModule on each module, including updating the search index and
+// calling insert-module hooks, but in fewer transactions.
+//
+// The latest-version information for each module is read from the
+// database, so it should be populated beforehand for accurate search
+// results.
+//
+// InsertModules returns one result per module, in the same order. A module
+// that fails validation is skipped. If a batch of modules fails to be
+// inserted, its modules are retried one at a time so that failures are
+// reported for the modules that caused them. The returned error is non-nil
+// only if the insertion could not proceed at all.
+func (db *DB) InsertModules(ctx context.Context, modules []*internal.Module) (_ []*InsertModuleResult, err error) {
+	defer derrors.WrapStack(&err, "DB.InsertModules(ctx, %d modules)", len(modules))
+
+	results := make([]*InsertModuleResult, len(modules))
+	var toInsert []int // indexes of modules that can be inserted
+	for i, m := range modules {
+		r := &InsertModuleResult{}
+		results[i] = r
+		if m == nil {
+			r.Err = fmt.Errorf("nil module: %w", derrors.InvalidArgument)
+			continue
+		}
+		r.ModulePath = m.ModulePath
+		r.Version = m.Version
+		if r.Err = db.prepareModule(ctx, m); r.Err != nil {
+			continue
+		}
+		toInsert = append(toInsert, i)
+	}
+
+	size := db.insertModulesBatchSize
+	if size <= 0 {
+		size = DefaultInsertModulesBatchSize
+	}
+	for len(toInsert) > 0 {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		n := size
+		if n > len(toInsert) {
+			n = len(toInsert)
+		}
+		batch := toInsert[:n]
+		toInsert = toInsert[n:]
+		if err := db.saveModuleBatch(ctx, modules, batch, results); err != nil {
+			log.Infof(ctx, "InsertModules: batch of %d failed, inserting one at a time: %v", len(batch), err)
+			for _, i := range batch {
+				results[i].IsLatest, results[i].Err = db.saveModuleWithLatest(ctx, modules[i])
+			}
+		}
+		for _, i := range batch {
+			if results[i].Err == nil {
+				db.runInsertModuleHooks(ctx, modules[i])
+			}
+		}
+	}
+	return results, nil
+}
+
+// saveModuleWithLatest saves m like saveModule, with the latest-version
+// information for its module path read from the database.
+func (db *DB) saveModuleWithLatest(ctx context.Context, m *internal.Module) (isLatest bool, err error) {
+	lmv, err := db.GetLatestModuleVersions(ctx, m.ModulePath)
+	if err != nil {
+		return false, err
+	}
+	return db.saveModule(ctx, m, lmv)
+}
+
+// saveModuleBatch saves the modules at the given indexes in two
+// transactions: one for their paths, and one for everything else, like
+// saveModule. It sets the IsLatest field of their results only if both
+// transactions succeed.
+//
+// The modules are saved in module path order, and the second transaction
+// locks all of their module paths before saving any of them, so that
+// concurrent batches acquire their locks in the same order and cannot
+// deadlock. The latest-version information of each module is read after its
+// module path is locked, so it cannot be changed by another transaction
+// until the module is saved.
+func (db *DB) saveModuleBatch(ctx context.Context, modules []*internal.Module, batch []int, results []*InsertModuleResult) (err error) {
+	defer derrors.WrapStack(&err, "saveModuleBatch(ctx, %d modules)", len(batch))
+
+	batch = append([]int(nil), batch...)
+	sort.SliceStable(batch, func(j, k int) bool {
+		return modules[batch[j]].ModulePath < modules[batch[k]].ModulePath
+	})
+	pathToIDs := make([]map[string]int, len(batch))
+	err = db.db.Transact(ctx, sql.LevelRepeatableRead, func(tx *database.DB) error {
+		for j, i := range batch {
+			var err error
+			pathToIDs[j], err = insertPaths(ctx, tx, modules[i])
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	isLatest := make([]bool, len(batch))
+	err = db.db.Transact(ctx, sql.LevelRepeatableRead, func(tx *database.DB) error {
+		// Advisory locks are reentrant, so saveModuleTx can lock the module
+		// paths again.
+		lmvs := make([]*internal.LatestModuleVersions, len(batch))
+		for j, i := range batch {
+			if err := lock(ctx, tx, modules[i].ModulePath); err != nil {
+				return err
+			}
+			var err error
+			lmvs[j], _, err = getLatestModuleVersions(ctx, tx, modules[i].ModulePath)
+			if err != nil {
+				return err
+			}
+		}
+		for j, i := range batch {
+			var err error
+			isLatest[j], err = db.saveModuleTx(ctx, tx, modules[i], lmvs[j], pathToIDs[j])
+			if err != nil {
+				return fmt.Errorf("%s@%s: %w", modules[i].ModulePath, modules[i].Version, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for j, i := range batch {
+		results[i].IsLatest = isLatest[j]
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+func TestInsertModules(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	defer testDB.SetInsertModulesBatchSize(0)
+	testDB.SetInsertModulesBatchSize(2)
+
+	var hooked []string
+	testDB.AddInsertModuleHook(func(_ context.Context, modulePath, _ string) {
+		hooked = append(hooked, modulePath)
+	})
+	defer func() { testDB.insertModuleHooks = nil }()
+
+	invalid := sample.Module("example.com/invalid", "v1.0.0", "pkg")
+	invalid.Version = ""
+	modules := []*internal.Module{
+		sample.Module("example.com/a", "v1.0.0", "pkg"),
+		invalid,
+		sample.Module("example.com/b", "v1.1.0", "pkg"),
+		sample.Module("example.com/c", "v1.2.0", "pkg"),
+	}
+	results, err := testDB.InsertModules(ctx, modules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(modules) {
+		t.Fatalf("got %d results, want %d", len(results), len(modules))
+	}
+	for i, r := range results {
+		if i == 1 {
+			if !errors.Is(r.Err, derrors.DBModuleInsertInvalid) {
+				t.Errorf("%s: got error %v, want DBModuleInsertInvalid", r.ModulePath, r.Err)
+			}
+			continue
+		}
+		if r.Err != nil {
+			t.Errorf("%s: %v", r.ModulePath, r.Err)
+			continue
+		}
+		if !r.IsLatest {
+			t.Errorf("%s: IsLatest = false, want true", r.ModulePath)
+		}
+		m := modules[i]
+		if _, err := testDB.GetUnitMeta(ctx, m.ModulePath+"/pkg", internal.UnknownModulePath, version.Latest); err != nil {
+			t.Errorf("%s: GetUnitMeta: %v", m.ModulePath, err)
+		}
+		var n int
+		if err := testDB.db.QueryRow(ctx, `SELECT COUNT(*) FROM search_documents WHERE module_path = $1`,
+			m.ModulePath).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n != 1 {
+			t.Errorf("%s: got %d search documents, want 1", m.ModulePath, n)
+		}
+	}
+	if got, want := len(hooked), 3; got != want {
+		t.Errorf("insert-module hooks called %d times, want %d", got, want)
+	}
+}
@@ -100,6 +100,36 @@ func (db *DB) getModuleLicenses(ctx context.Context, moduleID int) (_ []*license
 	return collectLicenses(rows, db.bypassLicenseCheck)
 }
 
+// GetAllModuleLicenses returns all the licenses in the given module version,
+// including those in subdirectories, deepest first. The contents of
+// non-redistributable licenses are removed unless the license check is
+// bypassed. It returns a NotFound error if the module version is not in the
+// database.
+func (db *DB) GetAllModuleLicenses(ctx context.Context, modulePath, version string) (_ []*licenses.License, err error) {
+	defer derrors.WrapStack(&err, "GetAllModuleLicenses(ctx, %q, %q)", modulePath, version)
+	defer middleware.ElapsedStat(ctx, "GetAllModuleLicenses")()
+
+	var moduleID int
+	err = db.db.QueryRow(ctx, `SELECT id FROM modules WHERE module_path = $1 AND version = $2`,
+		modulePath, version).Scan(&moduleID)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	case nil:
+	default:
+		return nil, err
+	}
+	rows, err := db.db.Query(ctx, `
+		SELECT types, file_path, contents, coverage
+		FROM licenses
+		WHERE module_id = $1`, moduleID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return collectLicenses(rows, db.bypassLicenseCheck)
+}
+
 // collectLicenses converts the sql rows to a list of licenses. The columns
 // must be types, file_path and contents, in that order.
 func collectLicenses(rows *sql.Rows, bypassLicenseCheck bool) ([]*licenses.License, error) {
@@ -148,6 +178,37 @@ func mustHaveColumns(rows *sql.Rows, wantColumns ...string) {
 	}
 }
 
+// LicenseDistribution returns the number of modules that have a license of
+// each detected license type. A module is counted once for a type no matter
+// how many of its versions or license files have that type.
+func (db *DB) LicenseDistribution(ctx context.Context) (_ map[string]int, err error) {
+	defer derrors.WrapStack(&err, "LicenseDistribution(ctx)")
+
+	query := `
+		SELECT t.type, COUNT(DISTINCT m.module_path)
+		FROM licenses l
+		INNER JOIN modules m ON m.id = l.module_id
+		CROSS JOIN unnest(l.types) AS t(type)
+		WHERE t.type != ''
+		GROUP BY t.type`
+	dist := map[string]int{}
+	collect := func(rows *sql.Rows) error {
+		var (
+			typ string
+			n   int
+		)
+		if err := rows.Scan(&typ, &n); err != nil {
+			return err
+		}
+		dist[typ] = n
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect); err != nil {
+		return nil, err
+	}
+	return dist, nil
+}
+
 // zipLicenseMetadata constructs licenses.Metadata from the given license types
 // and paths, by zipping and then sorting.
 func zipLicenseMetadata(licenseTypes []string, licensePaths []string) (_ []*licenses.Metadata, err error) {
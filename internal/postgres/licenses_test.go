@@ -7,6 +7,7 @@ package postgres
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"testing"
 
@@ -232,3 +233,38 @@ func makeModuleNonRedistributable(m *internal.Module) {
 		m.Units[i].IsRedistributable = false
 	}
 }
+
+func TestLicenseDistribution(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	insert := func(modulePath, version string, types ...[]string) {
+		t.Helper()
+		m := sample.Module(modulePath, version, "p")
+		m.Licenses = nil
+		for i, ts := range types {
+			m.Licenses = append(m.Licenses, &licenses.License{
+				Metadata: &licenses.Metadata{Types: ts, FilePath: fmt.Sprintf("LICENSE%d", i)},
+				Contents: []byte("license"),
+			})
+		}
+		MustInsertModule(ctx, t, testDB, m)
+	}
+	insert("example.com/mit", "v1.0.0", []string{"MIT"})
+	insert("example.com/mit", "v1.1.0", []string{"MIT"})
+	insert("example.com/apache", "v1.0.0", []string{"Apache-2.0"})
+	insert("example.com/both", "v1.0.0", []string{"MIT"}, []string{"Apache-2.0"})
+	insert("example.com/dual", "v1.0.0", []string{"MIT", "Apache-2.0"})
+
+	got, err := testDB.LicenseDistribution(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"MIT": 3, "Apache-2.0": 3}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -18,10 +18,26 @@ import (
 )
 
 type DB struct {
-	db                 *database.DB
-	bypassLicenseCheck bool
-	expoller           *poller.Poller
-	cancel             func()
+	db                     *database.DB
+	bypassLicenseCheck     bool
+	expoller               *poller.Poller
+	cancel                 func()
+	insertModuleHooks      []InsertModuleHook
+	insertModulesBatchSize int
+}
+
+// An InsertModuleHook is called after a module version has been successfully
+// inserted into the database.
+type InsertModuleHook func(ctx context.Context, modulePath, version string)
+
+// AddInsertModuleHook arranges for h to be called after every successful call
+// to InsertModule, and for every module successfully inserted by
+// InsertModules. It returns a function that removes the hook. Neither is safe
+// to call concurrently with InsertModule; add hooks before using the DB.
+func (db *DB) AddInsertModuleHook(h InsertModuleHook) (remove func()) {
+	i := len(db.insertModuleHooks)
+	db.insertModuleHooks = append(db.insertModuleHooks, h)
+	return func() { db.insertModuleHooks[i] = nil }
 }
 
 // New returns a new postgres DB.
@@ -0,0 +1,53 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// PurgeDocumentation deletes the encoded source of the documentation of the
+// packages of the given module version, which is what documentation is
+// rendered from and most of the space it uses. Unit metadata, synopses,
+// licenses and symbols are kept.
+//
+// GetUnit reports the documentation of a purged unit as missing, with
+// Unit.DocumentationPurged set. The module version's state is marked as
+// purged, so that its next fetch is not skipped as unchanged, which restores
+// the documentation. Purged module versions are not reprocessed in bulk by
+// the UpdateModuleVersionStatesForReprocessing methods.
+//
+// PurgeDocumentation returns an error wrapping derrors.NotFound if the module
+// version is not in the database.
+func (db *DB) PurgeDocumentation(ctx context.Context, modulePath, version string) (err error) {
+	defer derrors.WrapStack(&err, "PurgeDocumentation(ctx, %q, %q)", modulePath, version)
+	return db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		var moduleID int
+		err := tx.QueryRow(ctx, `SELECT id FROM modules WHERE module_path = $1 AND version = $2`,
+			modulePath, version).Scan(&moduleID)
+		if err == sql.ErrNoRows {
+			return derrors.NotFound
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			UPDATE documentation d
+			SET source = NULL
+			FROM units u
+			WHERE d.unit_id = u.id AND u.module_id = $1`, moduleID); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, `
+			UPDATE module_version_states
+			SET documentation_purged = TRUE
+			WHERE module_path = $1 AND version = $2`, modulePath, version)
+		return err
+	})
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestPurgeDocumentation(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, "foo")
+	MustInsertModule(ctx, t, testDB, m)
+	pkgPath := sample.ModulePath + "/foo"
+	const appVersion = "20230101t000000"
+	updateState := func() {
+		t.Helper()
+		must(t, testDB.UpdateModuleVersionState(ctx, &ModuleVersionStateForUpdate{
+			ModulePath: sample.ModulePath,
+			Version:    sample.VersionString,
+			AppVersion: appVersion,
+			Timestamp:  time.Now(),
+			Status:     http.StatusOK,
+			HasGoMod:   true,
+		}))
+	}
+	updateState()
+	getState := func() *internal.ModuleVersionState {
+		t.Helper()
+		vs, err := testDB.GetModuleVersionState(ctx, sample.ModulePath, sample.VersionString)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return vs
+	}
+
+	getUnit := func() (*internal.UnitMeta, *internal.Unit) {
+		t.Helper()
+		um, err := testDB.GetUnitMeta(ctx, pkgPath, sample.ModulePath, sample.VersionString)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return um, u
+	}
+
+	if err := testDB.PurgeDocumentation(ctx, sample.ModulePath, sample.VersionString); err != nil {
+		t.Fatal(err)
+	}
+	um, u := getUnit()
+	if um.Name != "foo" || len(um.Licenses) == 0 || !um.IsRedistributable {
+		t.Errorf("unit metadata after purge: got name %q, %d licenses, redistributable %t; want foo, licenses, true",
+			um.Name, len(um.Licenses), um.IsRedistributable)
+	}
+	if u.Documentation != nil || !u.DocumentationPurged {
+		t.Errorf("after purge: got documentation %v, purged %t; want nil, true", u.Documentation, u.DocumentationPurged)
+	}
+	if vs := getState(); !vs.DocumentationPurged || vs.AppVersion != appVersion {
+		t.Errorf("state after purge: got purged %t, app version %q; want true, %q", vs.DocumentationPurged, vs.AppVersion, appVersion)
+	}
+	// Purged versions are not reprocessed in bulk.
+	must(t, testDB.UpdateModuleVersionStatesWithStatus(ctx, http.StatusOK, "30000101t000000"))
+	must(t, testDB.UpdateModuleVersionStatesForReprocessingReleaseVersionsOnly(ctx, "30000101t000000"))
+	if vs := getState(); vs.Status != http.StatusOK {
+		t.Errorf("state after requeue: got status %d, want %d", vs.Status, http.StatusOK)
+	}
+
+	// Inserting the module again, as a fetch does, restores the documentation.
+	MustInsertModule(ctx, t, testDB, m)
+	updateState()
+	if vs := getState(); vs.DocumentationPurged {
+		t.Error("state after reinsert: still purged")
+	}
+	_, u = getUnit()
+	if len(u.Documentation) == 0 || u.DocumentationPurged {
+		t.Errorf("after reinsert: got %d documentation, purged %t; want documentation, false", len(u.Documentation), u.DocumentationPurged)
+	}
+
+	err := testDB.PurgeDocumentation(ctx, sample.ModulePath, "v9.9.9")
+	if !errors.Is(err, derrors.NotFound) {
+		t.Errorf("purging missing version: got %v, want NotFound", err)
+	}
+}
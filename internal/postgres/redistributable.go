@@ -0,0 +1,212 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"path"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+)
+
+// A LicenseClassifier reports whether a set of license types makes a
+// module or package redistributable. licenses.Redistributable is the policy
+// used when modules are fetched.
+type LicenseClassifier func(licenseTypes []string) bool
+
+// recomputeRedistributabilityBatchSize is the number of modules read at a time
+// by RecomputeRedistributability.
+const recomputeRedistributabilityBatchSize = 1000
+
+// RecomputeRedistributability re-evaluates whether each stored module and
+// unit is redistributable according to classify, using the license metadata
+// stored for the module. It is meant to be run after the license policy
+// changes.
+//
+// A module or unit is redistributable under the same rules as when it is
+// fetched: all of its module's root licenses, and the licenses in the
+// directories between it and the module root, must be redistributable.
+//
+// Units that become non-redistributable have their documentation and READMEs
+// removed, as they would have if fetched under the new policy. Because the
+// content of non-redistributable units is not stored, modules that become
+// redistributable are marked for reprocessing so that it is fetched. If db
+// bypasses the license check, only the flags are changed.
+func (db *DB) RecomputeRedistributability(ctx context.Context, classify LicenseClassifier) (err error) {
+	defer derrors.WrapStack(&err, "RecomputeRedistributability(ctx)")
+
+	lastID := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ids, err := database.Collect1[int](ctx, db.db, `
+			SELECT id FROM modules WHERE id > $1 ORDER BY id LIMIT $2`,
+			lastID, recomputeRedistributabilityBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		for _, id := range ids {
+			if err := db.recomputeModuleRedistributability(ctx, id, classify); err != nil {
+				return err
+			}
+		}
+		lastID = ids[len(ids)-1]
+	}
+}
+
+// recomputeModuleRedistributability updates the redistributable flags for
+// the module with the given ID, and the content of its units that change.
+func (db *DB) recomputeModuleRedistributability(ctx context.Context, moduleID int, classify LicenseClassifier) (err error) {
+	defer derrors.WrapStack(&err, "recomputeModuleRedistributability(ctx, %d)", moduleID)
+
+	return db.db.Transact(ctx, sql.LevelRepeatableRead, func(tx *database.DB) error {
+		var (
+			modulePath, version string
+			wasRedist           bool
+		)
+		if err := tx.QueryRow(ctx, `
+			SELECT module_path, version, redistributable FROM modules WHERE id = $1`,
+			moduleID).Scan(&modulePath, &version, &wasRedist); err != nil {
+			return err
+		}
+
+		// License types by the directory of the license file, relative to
+		// the module root.
+		typesByDir := map[string][]string{}
+		err := tx.RunQuery(ctx, `SELECT file_path, types FROM licenses WHERE module_id = $1`,
+			func(rows *sql.Rows) error {
+				var (
+					filePath string
+					types    []string
+				)
+				if err := rows.Scan(&filePath, pq.Array(&types)); err != nil {
+					return err
+				}
+				dir := path.Dir(filePath)
+				for _, t := range types {
+					if t != "" {
+						typesByDir[dir] = append(typesByDir[dir], t)
+					}
+				}
+				return nil
+			}, moduleID)
+		if err != nil {
+			return err
+		}
+		isRedist := classify(typesByDir["."])
+
+		var becameRedist, becameNonRedist []int // unit IDs
+		err = tx.RunQuery(ctx, `
+			SELECT u.id, p.path, u.redistributable
+			FROM units u
+			INNER JOIN paths p ON p.id = u.path_id
+			WHERE u.module_id = $1`,
+			func(rows *sql.Rows) error {
+				var (
+					id       int
+					unitPath string
+					was      bool
+				)
+				if err := rows.Scan(&id, &unitPath, &was); err != nil {
+					return err
+				}
+				now := isRedist && unitLicensesRedistributable(typesByDir, modulePath, unitPath, classify)
+				switch {
+				case now && !was:
+					becameRedist = append(becameRedist, id)
+				case !now && was:
+					becameNonRedist = append(becameNonRedist, id)
+				}
+				return nil
+			}, moduleID)
+		if err != nil {
+			return err
+		}
+		if isRedist == wasRedist && len(becameRedist) == 0 && len(becameNonRedist) == 0 {
+			return nil
+		}
+		log.Infof(ctx, "RecomputeRedistributability: %s@%s: module redistributable %t => %t; %d units became redistributable, %d became non-redistributable",
+			modulePath, version, wasRedist, isRedist, len(becameRedist), len(becameNonRedist))
+
+		if _, err := tx.Exec(ctx, `UPDATE modules SET redistributable = $2 WHERE id = $1`, moduleID, isRedist); err != nil {
+			return err
+		}
+		for _, u := range []struct {
+			ids    []int
+			redist bool
+		}{{becameRedist, true}, {becameNonRedist, false}} {
+			if len(u.ids) == 0 {
+				continue
+			}
+			if _, err := tx.Exec(ctx, `UPDATE units SET redistributable = $2 WHERE id = ANY($1)`,
+				pq.Array(u.ids), u.redist); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `UPDATE search_documents SET redistributable = $2 WHERE unit_id = ANY($1)`,
+				pq.Array(u.ids), u.redist); err != nil {
+				return err
+			}
+		}
+		if db.bypassLicenseCheck {
+			return nil
+		}
+		if len(becameNonRedist) > 0 {
+			for _, table := range []string{"documentation", "readmes"} {
+				if _, err := tx.Exec(ctx, `DELETE FROM `+table+` WHERE unit_id = ANY($1)`, pq.Array(becameNonRedist)); err != nil {
+					return err
+				}
+			}
+		}
+		if wasRedist && !isRedist {
+			if _, err := tx.Exec(ctx, `DELETE FROM module_changelogs WHERE module_id = $1`, moduleID); err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx, `DELETE FROM module_notices WHERE module_id = $1`, moduleID); err != nil {
+				return err
+			}
+		}
+		if len(becameRedist) > 0 {
+			// The content that is now redistributable was never stored.
+			if _, err := tx.Exec(ctx, `
+				UPDATE module_version_states
+				SET
+					status = (CASE WHEN status=290 THEN 521 ELSE 520 END),
+					next_processed_after = CURRENT_TIMESTAMP,
+					last_processed_at = NULL
+				WHERE module_path = $1 AND version = $2 AND (status = 200 OR status = 290)`,
+				modulePath, version); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// unitLicensesRedistributable reports whether the licenses in the directories
+// from the module root (exclusive) down to the unit at unitPath (inclusive)
+// are redistributable according to classify. They are if there are none.
+func unitLicensesRedistributable(typesByDir map[string][]string, modulePath, unitPath string, classify LicenseClassifier) bool {
+	dir := strings.TrimPrefix(strings.TrimPrefix(unitPath, modulePath), "/")
+	var types []string
+	for d, ts := range typesByDir {
+		if d == "." {
+			continue
+		}
+		// Append a slash so that a/b does not match a/bc.
+		if strings.HasPrefix(dir+"/", d+"/") {
+			types = append(types, ts...)
+		}
+	}
+	return len(types) == 0 || classify(types)
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestRecomputeRedistributability(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// A module whose license is not redistributable under the current policy.
+	const unknownType = "Unknown-Permissive"
+	nonRedist := sample.Module("example.com/nonredist", "v1.0.0", "pkg")
+	nonRedist.IsRedistributable = false
+	for _, l := range nonRedist.Licenses {
+		l.Types = []string{unknownType}
+	}
+	for _, u := range nonRedist.Units {
+		u.IsRedistributable = false
+	}
+	MustInsertModule(ctx, t, testDB, nonRedist)
+	must(t, testDB.UpdateModuleVersionState(ctx, &ModuleVersionStateForUpdate{
+		ModulePath: nonRedist.ModulePath,
+		Version:    nonRedist.Version,
+		AppVersion: "app",
+		Timestamp:  time.Now(),
+		Status:     http.StatusOK,
+		HasGoMod:   true,
+	}))
+	redist := sample.Module("example.com/redist", "v1.0.0", "pkg")
+	MustInsertModule(ctx, t, testDB, redist)
+
+	check := func(modulePath string, want bool) {
+		t.Helper()
+		var got bool
+		if err := testDB.db.QueryRow(ctx, `SELECT redistributable FROM modules WHERE module_path = $1`,
+			modulePath).Scan(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%s: module redistributable = %t, want %t", modulePath, got, want)
+		}
+		var n int
+		if err := testDB.db.QueryRow(ctx, `
+			SELECT COUNT(*) FROM units u INNER JOIN modules m ON m.id = u.module_id
+			WHERE m.module_path = $1 AND u.redistributable != $2`,
+			modulePath, want).Scan(&n); err != nil {
+			t.Fatal(err)
+		}
+		if n != 0 {
+			t.Errorf("%s: %d units with redistributable != %t", modulePath, n, want)
+		}
+	}
+
+	// The new policy also allows the unknown license type.
+	allowUnknown := func(types []string) bool {
+		for _, t := range types {
+			if t != unknownType && !licenses.Redistributable([]string{t}) {
+				return false
+			}
+		}
+		return len(types) > 0
+	}
+	must(t, testDB.RecomputeRedistributability(ctx, allowUnknown))
+	check(nonRedist.ModulePath, true)
+	check(redist.ModulePath, true)
+	// The module content was never stored, so it must be fetched again.
+	mvs, err := testDB.GetModuleVersionState(ctx, nonRedist.ModulePath, nonRedist.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := mvs.Status, 520; got != want {
+		t.Errorf("module version state status: got %d, want %d", got, want)
+	}
+
+	// A policy that allows nothing removes stored documentation.
+	must(t, testDB.RecomputeRedistributability(ctx, func([]string) bool { return false }))
+	check(nonRedist.ModulePath, false)
+	check(redist.ModulePath, false)
+	var n int
+	if err := testDB.db.QueryRow(ctx, `
+		SELECT COUNT(*) FROM documentation d
+		INNER JOIN units u ON u.id = d.unit_id
+		INNER JOIN modules m ON m.id = u.module_id
+		WHERE m.module_path = $1`, redist.ModulePath).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("got %d documentation rows for non-redistributable module, want 0", n)
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// repoURLNoise matches the parts of a repository URL that are ignored when
+// comparing URLs: the scheme and any trailing slashes. It must be kept in
+// sync with the regular expression in GetModulesByRepo, and with the
+// expression index idx_modules_repo_url that the query uses.
+var repoURLNoise = regexp.MustCompile(`^[a-z]+://|/+$`)
+
+// GetModulesByRepo returns the paths of all modules, in sorted order, that
+// have some version whose source repository is repoURL. Repository URLs are
+// compared without their schemes and trailing slashes.
+func (db *DB) GetModulesByRepo(ctx context.Context, repoURL string) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "GetModulesByRepo(ctx, %q)", repoURL)
+
+	query := `
+		SELECT DISTINCT module_path
+		FROM modules
+		WHERE regexp_replace(source_info->>'RepoURL', '^[a-z]+://|/+$', '', 'g') = $1
+		ORDER BY module_path`
+	var paths []string
+	collect := func(rows *sql.Rows) error {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return err
+		}
+		paths = append(paths, p)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, repoURLNoise.ReplaceAllString(repoURL, "")); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetModulesByRepo(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for _, m := range []struct {
+		path, version, repo, dir string
+	}{
+		{"github.com/org/repo", "v1.0.0", "https://github.com/org/repo", ""},
+		{"github.com/org/repo", "v1.1.0", "https://github.com/org/repo", ""},
+		{"github.com/org/repo/sub", "v0.1.0", "https://github.com/org/repo/", "sub"},
+		{"github.com/org/other", "v1.0.0", "https://github.com/org/other", ""},
+	} {
+		mod := sample.Module(m.path, m.version, "p")
+		mod.SourceInfo = source.NewGitHubInfo(m.repo, m.dir, m.version)
+		MustInsertModule(ctx, t, testDB, mod)
+	}
+
+	want := []string{"github.com/org/repo", "github.com/org/repo/sub"}
+	for _, repoURL := range []string{
+		"https://github.com/org/repo",
+		"http://github.com/org/repo/",
+		"github.com/org/repo",
+	} {
+		got, err := testDB.GetModulesByRepo(ctx, repoURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", repoURL, diff)
+		}
+	}
+}
@@ -49,6 +49,7 @@ func (db *DB) UpdateModuleVersionStatesForReprocessingReleaseVersionsOnly(ctx co
 			last_processed_at = NULL
 		WHERE
 			app_version < $1
+			AND NOT documentation_purged
 			AND (status = 200 OR status = 290)
 			AND right(sort_version, 1) = '~' -- release versions only
 			AND NOT incompatible;`
@@ -84,6 +85,7 @@ func (db *DB) UpdateModuleVersionStatesForReprocessingLatestOnly(ctx context.Con
 		) latest
 		WHERE
 			app_version < $1
+			AND NOT documentation_purged
 			AND (status = 200 OR status = 290)
 			AND latest.module_path = mvs.module_path
 			AND latest.version = mvs.version;`
@@ -117,6 +119,7 @@ func (db *DB) UpdateModuleVersionStatesForReprocessingSearchDocumentsOnly(ctx co
 		) sd
 		WHERE
 			app_version < $1
+			AND NOT mvs.documentation_purged
 			AND (mvs.status = 200 OR mvs.status = 290)
 			AND mvs.module_path = sd.module_path
 			AND mvs.version = sd.version;`
@@ -136,6 +139,7 @@ func (db *DB) UpdateModuleVersionStatesWithStatus(ctx context.Context, status in
 				last_processed_at = NULL
 			WHERE
 				app_version < $1
+				AND NOT documentation_purged
 				AND status = $3;`
 	affected, err := db.db.Exec(ctx, query, appVersion, derrors.ToReprocessStatus(status), status)
 	if err != nil {
@@ -0,0 +1,153 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// ReqDiff describes how the require directives of a module's go.mod file
+// changed between two versions.
+type ReqDiff struct {
+	// Added holds the requirements present only in the new version.
+	Added []*internal.Requirement
+	// Removed holds the requirements present only in the old version.
+	Removed []*internal.Requirement
+	// Changed holds the requirements present in both versions, but at
+	// different required versions.
+	Changed []*RequirementChange
+}
+
+// RequirementChange describes a required module whose version differs
+// between two versions of the requiring module.
+type RequirementChange struct {
+	ModulePath string
+	OldVersion string
+	NewVersion string
+}
+
+// RequirementsDiff returns the differences between the go.mod require
+// directives of modulePath at oldVersion and at newVersion.
+// It returns a NotFound error if either version is not in the database.
+func (db *DB) RequirementsDiff(ctx context.Context, modulePath, oldVersion, newVersion string) (_ *ReqDiff, err error) {
+	defer derrors.WrapStack(&err, "RequirementsDiff(ctx, %q, %q, %q)", modulePath, oldVersion, newVersion)
+
+	oldReqs, err := db.getStoredRequirements(ctx, modulePath, oldVersion)
+	if err != nil {
+		return nil, err
+	}
+	newReqs, err := db.getStoredRequirements(ctx, modulePath, newVersion)
+	if err != nil {
+		return nil, err
+	}
+	return diffRequirements(oldReqs, newReqs), nil
+}
+
+// GetRequirements returns the require directives of the go.mod file stored
+// for the given module version, in the order they appear in the file. It
+// returns an error with NotFound in its chain if no go.mod file was stored
+// for the module version.
+func (db *DB) GetRequirements(ctx context.Context, modulePath, version string) (_ []*internal.Requirement, err error) {
+	defer derrors.WrapStack(&err, "GetRequirements(ctx, %q, %q)", modulePath, version)
+
+	contents, err := db.GetGoMod(ctx, modulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	mf, err := modfile.ParseLax("go.mod", contents, nil)
+	if err != nil {
+		return nil, err
+	}
+	var reqs []*internal.Requirement
+	for _, r := range mf.Require {
+		reqs = append(reqs, &internal.Requirement{
+			ModulePath: r.Mod.Path,
+			Version:    r.Mod.Version,
+			Indirect:   r.Indirect,
+		})
+	}
+	return reqs, nil
+}
+
+// getStoredRequirements returns the require directives stored in the
+// module_requires table for the given module version, sorted by module path.
+// Unlike GetRequirements, it does not need the go.mod file of the module.
+func (db *DB) getStoredRequirements(ctx context.Context, modulePath, version string) (_ []*internal.Requirement, err error) {
+	defer derrors.WrapStack(&err, "getStoredRequirements(ctx, %q, %q)", modulePath, version)
+
+	var moduleID int
+	err = db.db.QueryRow(ctx, `
+		SELECT id FROM modules WHERE module_path = $1 AND version = $2
+	`, modulePath, version).Scan(&moduleID)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, derrors.NotFound
+	case nil:
+	default:
+		return nil, err
+	}
+
+	var reqs []*internal.Requirement
+	collect := func(rows *sql.Rows) error {
+		var r internal.Requirement
+		if err := rows.Scan(&r.ModulePath, &r.Version, &r.Indirect); err != nil {
+			return err
+		}
+		reqs = append(reqs, &r)
+		return nil
+	}
+	query := `
+		SELECT required_path, required_version, indirect
+		FROM module_requires
+		WHERE module_id = $1
+		ORDER BY required_path`
+	if err := db.db.RunQuery(ctx, query, collect, moduleID); err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// diffRequirements computes the ReqDiff between two lists of requirements.
+// The lists in the result are sorted by module path.
+func diffRequirements(oldReqs, newReqs []*internal.Requirement) *ReqDiff {
+	oldPathToVersion := map[string]string{}
+	for _, r := range oldReqs {
+		oldPathToVersion[r.ModulePath] = r.Version
+	}
+	newPathToVersion := map[string]string{}
+	for _, r := range newReqs {
+		newPathToVersion[r.ModulePath] = r.Version
+	}
+
+	d := &ReqDiff{}
+	for _, r := range newReqs {
+		ov, ok := oldPathToVersion[r.ModulePath]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, r)
+		case ov != r.Version:
+			d.Changed = append(d.Changed, &RequirementChange{
+				ModulePath: r.ModulePath,
+				OldVersion: ov,
+				NewVersion: r.Version,
+			})
+		}
+	}
+	for _, r := range oldReqs {
+		if _, ok := newPathToVersion[r.ModulePath]; !ok {
+			d.Removed = append(d.Removed, r)
+		}
+	}
+	sort.Slice(d.Added, func(i, j int) bool { return d.Added[i].ModulePath < d.Added[j].ModulePath })
+	sort.Slice(d.Removed, func(i, j int) bool { return d.Removed[i].ModulePath < d.Removed[j].ModulePath })
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].ModulePath < d.Changed[j].ModulePath })
+	return d
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestRequirementsDiff(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const modulePath = "example.com/m"
+	m1 := sample.Module(modulePath, "v1.0.0", "a")
+	m1.Requirements = []*internal.Requirement{
+		{ModulePath: "example.com/dep1", Version: "v1.0.0"},
+		{ModulePath: "example.com/dep2", Version: "v0.1.0"},
+		{ModulePath: "example.com/old", Version: "v1.2.3"},
+	}
+	MustInsertModule(ctx, t, testDB, m1)
+
+	m2 := sample.Module(modulePath, "v1.1.0", "a")
+	m2.Requirements = []*internal.Requirement{
+		{ModulePath: "example.com/dep1", Version: "v1.1.0"},
+		{ModulePath: "example.com/dep2", Version: "v0.1.0"},
+		{ModulePath: "example.com/new", Version: "v2.0.0", Indirect: true},
+	}
+	MustInsertModule(ctx, t, testDB, m2)
+
+	got, err := testDB.RequirementsDiff(ctx, modulePath, "v1.0.0", "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ReqDiff{
+		Added:   []*internal.Requirement{{ModulePath: "example.com/new", Version: "v2.0.0", Indirect: true}},
+		Removed: []*internal.Requirement{{ModulePath: "example.com/old", Version: "v1.2.3"}},
+		Changed: []*RequirementChange{{ModulePath: "example.com/dep1", OldVersion: "v1.0.0", NewVersion: "v1.1.0"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	if _, err := testDB.RequirementsDiff(ctx, modulePath, "v1.0.0", "v9.9.9"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got error %v, want NotFound", err)
+	}
+}
+
+func TestGetRequirements(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const modulePath = "example.com/m"
+	m := sample.Module(modulePath, "v1.0.0", "a")
+	m.GoMod = []byte(`module example.com/m
+
+go 1.19
+
+require (
+	example.com/direct v1.0.0
+	example.com/indirect v0.1.0 // indirect
+)
+
+require example.com/other v1.2.3
+`)
+	MustInsertModule(ctx, t, testDB, m)
+
+	got, err := testDB.GetRequirements(ctx, modulePath, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*internal.Requirement{
+		{ModulePath: "example.com/direct", Version: "v1.0.0"},
+		{ModulePath: "example.com/indirect", Version: "v0.1.0", Indirect: true},
+		{ModulePath: "example.com/other", Version: "v1.2.3"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	if _, err := testDB.GetRequirements(ctx, modulePath, "v9.9.9"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("got error %v, want NotFound", err)
+	}
+}
@@ -124,9 +124,13 @@ func (db *DB) SearchSupport() internal.SearchSupport { return internal.FullSearc
 // The gap in this optimization is search terms that are very frequent, but
 // rarely relevant: "int" or "package", for example. In these cases we'll pay
 // the penalty of a deep search that scans nearly every package.
+//
+// Package search honors quoted phrases and the operators AND, OR and NOT in
+// q; see websearchQuery.
 func (db *DB) Search(ctx context.Context, q string, opts SearchOptions) (_ []*SearchResult, err error) {
 	defer derrors.WrapStack(&err, "DB.Search(ctx, %q, %+v)", q, opts)
 	if !opts.SearchSymbols {
+		q = websearchQuery(q)
 		const (
 			limitMultiplier1 = 3
 			limitMultiplier2 = 5
@@ -172,6 +176,12 @@ func (db *DB) search(ctx context.Context, q string, opts SearchOptions, limit in
 			results = append(results, r)
 		}
 	}
+	if opts.ExcludeVulnerable {
+		results, err = db.filterVulnerable(ctx, results)
+		if err != nil {
+			return nil, err
+		}
+	}
 	if !opts.SearchSymbols {
 		results = groupSearchResults(results)
 	}
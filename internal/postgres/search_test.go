@@ -22,8 +22,10 @@ import (
 	"github.com/lib/pq"
 	"go.opencensus.io/stats/view"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/osv"
 	"golang.org/x/pkgsite/internal/stdlib"
 	"golang.org/x/pkgsite/internal/testing/sample"
 )
@@ -823,6 +825,148 @@ func TestExcludedFromSearch(t *testing.T) {
 	}
 }
 
+func TestSearchTextConfigurations(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const (
+		domain = "stem.com"
+		doc    = "Package parse parses the input."
+	)
+	// With the default configuration, a stemmed query matches a related term.
+	sm := sample.Module(domain, "v1.0.0", "parse")
+	sm.Packages()[0].Documentation[0].Synopsis = doc
+	MustInsertModule(ctx, t, testDB, sm)
+	results, err := testDB.Search(ctx, "parsing", SearchOptions{MaxResults: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].PackagePath != domain+"/parse" {
+		t.Errorf("Search(%q): got %v, want one result for %s/parse", "parsing", results, domain)
+	}
+
+	for _, test := range []struct {
+		settings  config.SearchSettings
+		wantStem  bool // whether "parsing" matches "parses"
+		wantStops bool // whether "the" matches
+	}{
+		{config.SearchSettings{}, true, false},
+		{config.SearchSettings{KeepStopWords: true}, true, true},
+		{config.SearchSettings{DisableStemming: true}, false, false},
+		{config.SearchSettings{KeepStopWords: true, DisableStemming: true}, false, true},
+	} {
+		ts := test.settings.TextSearchConfiguration()
+		for _, c := range []struct {
+			query string
+			want  bool
+		}{
+			{"parsing", test.wantStem},
+			{"the", test.wantStops},
+		} {
+			var got bool
+			err := testDB.db.QueryRow(ctx, `
+				SELECT to_tsvector($1::regconfig, $2) @@ websearch_to_tsquery($1::regconfig, $3)`,
+				ts, doc, c.query).Scan(&got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("%s: query %q matches = %t, want %t", ts, c.query, got, c.want)
+			}
+		}
+	}
+}
+
+func TestSearchPhrasesAndOperators(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const domain = "phrase.com"
+	sm := sample.Module(domain, "v1.0.0", "adjacent", "apart")
+	sm.Packages()[0].Documentation[0].Synopsis = "Package adjacent is an HTTP client for the web."
+	sm.Packages()[1].Documentation[0].Synopsis = "Package apart is a client that speaks HTTP."
+	MustInsertModule(ctx, t, testDB, sm)
+
+	for _, test := range []struct {
+		q    string
+		want []string
+	}{
+		{`http client`, []string{"adjacent", "apart"}},
+		{`"http client"`, []string{"adjacent"}},
+		{`client NOT web`, []string{"apart"}},
+		{`client NOT "http client"`, []string{"apart"}},
+		{`web OR speaks`, []string{"adjacent", "apart"}},
+		// Malformed queries degrade gracefully.
+		{`"http client`, []string{"adjacent"}},
+		{`OR client NOT`, []string{"adjacent", "apart"}},
+	} {
+		results, err := testDB.Search(ctx, test.q, SearchOptions{MaxResults: 10})
+		if err != nil {
+			t.Fatalf("Search(%q): %v", test.q, err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Name)
+		}
+		sort.Strings(got)
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("Search(%q) = %v, want %v", test.q, got, test.want)
+		}
+	}
+}
+
+func TestSearchExcludeVulnerable(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const domain = "vulnsearch.com"
+	MustInsertModule(ctx, t, testDB, sample.Module(domain+"/clean", "v1.0.0", "pkg"))
+	MustInsertModule(ctx, t, testDB, sample.Module(domain+"/vulnerable", "v1.0.0", "pkg"))
+	err := testDB.UpsertVulns(ctx, []*osv.Entry{{
+		ID: "GO-2023-0001",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: domain + "/vulnerable"},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeTypeSemver,
+				Events: []osv.RangeEvent{{Introduced: "0"}},
+			}},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		exclude bool
+		want    []string
+	}{
+		{false, []string{domain + "/clean/pkg", domain + "/vulnerable/pkg"}},
+		{true, []string{domain + "/clean/pkg"}},
+	} {
+		rs, err := testDB.Search(ctx, domain, SearchOptions{MaxResults: 10, ExcludeVulnerable: test.exclude})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, r := range rs {
+			got = append(got, r.PackagePath)
+		}
+		sort.Strings(got)
+		if !cmp.Equal(got, test.want) {
+			t.Errorf("ExcludeVulnerable=%t: got %v, want %v", test.exclude, got, test.want)
+		}
+	}
+}
+
 func TestSearchBypass(t *testing.T) {
 	t.Parallel()
 	testDB, release := acquire(t)
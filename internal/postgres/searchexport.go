@@ -0,0 +1,77 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// exportSearchDocumentsBatchSize is the number of documents that
+// ExportSearchDocuments reads from the database at a time.
+const exportSearchDocumentsBatchSize = 1000
+
+// A SearchDoc is the searchable document of a package, as exported by
+// ExportSearchDocuments.
+type SearchDoc struct {
+	PackagePath     string
+	ModulePath      string
+	Version         string
+	Name            string
+	Synopsis        string
+	CommitTime      time.Time
+	LicenseTypes    []string
+	Redistributable bool
+	ImportedByCount int
+	// Symbols are the names of the exported symbols of the package that can
+	// be searched for, sorted. They are empty for commands and
+	// non-redistributable packages.
+	Symbols []string
+}
+
+// ExportSearchDocuments calls fn with the search document of every package in
+// the search index, in order of package path, so that the index can be fed to
+// an external search engine. The documents are read from the database in
+// batches, so the index is never held in memory. If fn returns io.EOF,
+// ExportSearchDocuments stops and returns nil; if it returns any other
+// error, ExportSearchDocuments stops and returns that error.
+func (db *DB) ExportSearchDocuments(ctx context.Context, fn func(SearchDoc) error) (err error) {
+	defer derrors.WrapStack(&err, "ExportSearchDocuments(ctx, fn)")
+
+	collect := func(rows *sql.Rows) error {
+		var d SearchDoc
+		if err := rows.Scan(&d.PackagePath, &d.ModulePath, &d.Version, &d.Name, &d.Synopsis,
+			&d.CommitTime, pq.Array(&d.LicenseTypes), &d.Redistributable, &d.ImportedByCount,
+			pq.Array(&d.Symbols)); err != nil {
+			return err
+		}
+		return fn(d)
+	}
+	return db.db.RunQueryIncrementally(ctx, `
+		SELECT
+			sd.package_path,
+			sd.module_path,
+			sd.version,
+			sd.name,
+			sd.synopsis,
+			sd.commit_time,
+			sd.license_types,
+			sd.redistributable,
+			sd.imported_by_count,
+			ARRAY(
+				SELECT s.name
+				FROM symbol_search_documents ssd
+				INNER JOIN symbol_names s ON s.id = ssd.symbol_name_id
+				WHERE ssd.package_path_id = sd.package_path_id
+				ORDER BY s.name
+			)
+		FROM search_documents sd
+		ORDER BY sd.package_path`,
+		exportSearchDocumentsBatchSize, collect)
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestExportSearchDocuments(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	MustInsertModule(ctx, t, testDB, sample.Module("example.com/a", "v1.0.0", "x", "y"))
+	MustInsertModule(ctx, t, testDB, sample.Module("example.com/b", "v1.2.0", "z"))
+	// Only the latest version of a module is indexed.
+	MustInsertModuleNotLatest(ctx, t, testDB, sample.Module("example.com/b", "v1.1.0", "old"))
+
+	var got []string
+	err := testDB.ExportSearchDocuments(ctx, func(d SearchDoc) error {
+		got = append(got, d.PackagePath+"@"+d.Version)
+		if d.Synopsis != sample.Doc.Synopsis {
+			t.Errorf("%s: got synopsis %q, want %q", d.PackagePath, d.Synopsis, sample.Doc.Synopsis)
+		}
+		if !cmp.Equal(d.LicenseTypes, []string{sample.LicenseType}) {
+			t.Errorf("%s: got license types %v, want %v", d.PackagePath, d.LicenseTypes, []string{sample.LicenseType})
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"example.com/a/x@v1.0.0", "example.com/a/y@v1.0.0", "example.com/b/z@v1.2.0"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// An error from fn stops the export.
+	errStop := errors.New("stop")
+	n := 0
+	err = testDB.ExportSearchDocuments(ctx, func(SearchDoc) error {
+		n++
+		return errStop
+	})
+	if !errors.Is(err, errStop) || n != 1 {
+		t.Errorf("got error %v after %d documents, want %v after 1", err, n, errStop)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// SearchFacets holds the number of packages matching a search query in each
+// group of a faceted search, so that a search UI can offer filters with
+// counts.
+type SearchFacets struct {
+	// LicenseTypes maps each license type to the number of matching packages
+	// that have a license of that type.
+	LicenseTypes map[string]int
+	// ModulePaths maps each module path to the number of matching packages
+	// in that module.
+	ModulePaths map[string]int
+	// Keywords maps each module keyword to the number of matching packages
+	// whose module has that keyword.
+	Keywords map[string]int
+}
+
+// maxSearchFacetsDocuments bounds the number of matching packages that
+// GetSearchFacets counts. The facets of queries with more matches are computed
+// from the highest-scoring packages.
+const maxSearchFacetsDocuments = 10000
+
+// GetSearchFacets returns the facets of the packages matching the search
+// query q. The packages are those that Search could return, before grouping:
+// those whose search tokens match q with a score above the same threshold as
+// deep search, less excluded packages, and less vulnerable packages if
+// opts.ExcludeVulnerable is set. Unlike Search, the results are not limited
+// to a page, but at most maxSearchFacetsDocuments packages are counted.
+func (db *DB) GetSearchFacets(ctx context.Context, q string, opts SearchOptions) (_ *SearchFacets, err error) {
+	defer derrors.WrapStack(&err, "GetSearchFacets(ctx, %q, %+v)", q, opts)
+	q = websearchQuery(q)
+
+	type facetDoc struct {
+		result       *SearchResult
+		licenseTypes []string
+		keywords     []string
+	}
+	var docs []*facetDoc
+	collect := func(rows *sql.Rows) error {
+		d := &facetDoc{result: &SearchResult{}}
+		if err := rows.Scan(&d.result.PackagePath, &d.result.Version, &d.result.ModulePath,
+			pq.Array(&d.licenseTypes), pq.Array(&d.keywords)); err != nil {
+			return err
+		}
+		docs = append(docs, d)
+		return nil
+	}
+	query := fmt.Sprintf(`
+		SELECT r.package_path, r.version, r.module_path, r.license_types, COALESCE(m.keywords, '{}')
+		FROM (
+			SELECT package_path, version, module_path, license_types, (%s) AS score
+			FROM search_documents
+			WHERE tsv_search_tokens @@ websearch_to_tsquery($1)
+		) r
+		LEFT JOIN modules m ON m.module_path = r.module_path AND m.version = r.version
+		WHERE r.score > 0.1
+		ORDER BY r.score DESC, r.package_path
+		LIMIT $2`, scoreExpr)
+	if err := db.db.RunQuery(ctx, query, collect, q, maxSearchFacetsDocuments); err != nil {
+		return nil, err
+	}
+
+	var results []*SearchResult
+	resultDocs := map[*SearchResult]*facetDoc{}
+	for _, d := range docs {
+		ex, err := db.IsExcluded(ctx, d.result.PackagePath)
+		if err != nil {
+			return nil, err
+		}
+		if !ex {
+			results = append(results, d.result)
+			resultDocs[d.result] = d
+		}
+	}
+	if opts.ExcludeVulnerable {
+		results, err = db.filterVulnerable(ctx, results)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	facets := &SearchFacets{
+		LicenseTypes: map[string]int{},
+		ModulePaths:  map[string]int{},
+		Keywords:     map[string]int{},
+	}
+	addDistinct := func(m map[string]int, keys []string) {
+		seen := map[string]bool{}
+		for _, k := range keys {
+			if k != "" && !seen[k] {
+				seen[k] = true
+				m[k]++
+			}
+		}
+	}
+	for _, r := range results {
+		d := resultDocs[r]
+		facets.ModulePaths[r.ModulePath]++
+		addDistinct(facets.LicenseTypes, d.licenseTypes)
+		addDistinct(facets.Keywords, d.keywords)
+	}
+	return facets, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/licenses"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetSearchFacets(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const domain = "facets.com"
+	MustInsertModule(ctx, t, testDB, sample.Module(domain+"/mit", "v1.0.0", "x", "y"))
+	apache := &licenses.License{
+		Metadata: &licenses.Metadata{Types: []string{"Apache-2.0"}, FilePath: "LICENSE"},
+		Contents: []byte("Apache"),
+	}
+	m := sample.Module(domain+"/apache", "v1.0.0", "x")
+	m.Licenses = []*licenses.License{apache}
+	m.Keywords = []string{"router", "http"}
+	for _, u := range m.Units {
+		u.Licenses = []*licenses.Metadata{apache.Metadata}
+	}
+	MustInsertModule(ctx, t, testDB, m)
+	MustInsertModule(ctx, t, testDB, sample.Module("elsewhere.com/mit", "v1.0.0", "z"))
+
+	// Excluded packages are not counted.
+	if err := testDB.InsertExcludedPrefix(ctx, domain+"/mit/y", "no user", "no reason"); err != nil {
+		t.Fatal(err)
+	}
+	err := testDB.UpsertVulns(ctx, []*osv.Entry{{
+		ID: "GO-2023-0001",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: domain + "/apache"},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeTypeSemver,
+				Events: []osv.RangeEvent{{Introduced: "0"}},
+			}},
+		}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		excludeVulnerable bool
+		want              *SearchFacets
+	}{
+		{
+			false,
+			&SearchFacets{
+				LicenseTypes: map[string]int{"MIT": 1, "Apache-2.0": 1},
+				ModulePaths:  map[string]int{domain + "/mit": 1, domain + "/apache": 1},
+				Keywords:     map[string]int{"router": 1, "http": 1},
+			},
+		},
+		{
+			true,
+			&SearchFacets{
+				LicenseTypes: map[string]int{"MIT": 1},
+				ModulePaths:  map[string]int{domain + "/mit": 1},
+				Keywords:     map[string]int{},
+			},
+		},
+	} {
+		got, err := testDB.GetSearchFacets(ctx, domain, SearchOptions{ExcludeVulnerable: test.excludeVulnerable})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ExcludeVulnerable=%t: mismatch (-want, +got):\n%s", test.excludeVulnerable, diff)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"strings"
+	"unicode"
+)
+
+// websearchQuery translates the search query q into the syntax of Postgres's
+// websearch_to_tsquery, so that package search honors quoted phrases and the
+// boolean operators AND, OR and NOT, which must be written in upper case:
+//
+//   - A quoted phrase matches only documents in which its terms are adjacent.
+//   - Terms are implicitly combined with AND, so an explicit AND is dropped.
+//   - OR matches documents containing either of the terms around it.
+//   - NOT excludes documents containing the term or phrase that follows it.
+//
+// The translation never fails. An unterminated quote ends the phrase at the
+// end of the query, and operators that have nothing to apply to, such as a
+// leading OR or a trailing NOT, are ignored.
+func websearchQuery(q string) string {
+	var (
+		out    []string
+		negate bool // a NOT applies to the next term
+		or     bool // an OR precedes the next term
+	)
+	for _, t := range splitSearchQuery(q) {
+		if !t.phrase {
+			switch t.text {
+			case "AND":
+				continue
+			case "OR":
+				or = len(out) > 0
+				continue
+			case "NOT":
+				negate = true
+				continue
+			}
+		}
+		s := t.text
+		if t.phrase {
+			s = `"` + s + `"`
+		}
+		if negate {
+			s = "-" + s
+			negate = false
+		}
+		if or {
+			out = append(out, "or")
+			or = false
+		}
+		out = append(out, s)
+	}
+	return strings.Join(out, " ")
+}
+
+// A searchQueryTerm is a word or quoted phrase of a search query.
+type searchQueryTerm struct {
+	text   string
+	phrase bool
+}
+
+// splitSearchQuery splits q into words separated by white space and phrases
+// enclosed in double quotes. Empty phrases are omitted.
+func splitSearchQuery(q string) []searchQueryTerm {
+	var (
+		terms  []searchQueryTerm
+		b      strings.Builder
+		phrase bool
+	)
+	flush := func() {
+		text := b.String()
+		if phrase {
+			text = strings.Join(strings.Fields(text), " ")
+		}
+		if text != "" {
+			terms = append(terms, searchQueryTerm{text: text, phrase: phrase})
+		}
+		b.Reset()
+	}
+	for _, r := range q {
+		switch {
+		case r == '"':
+			flush()
+			phrase = !phrase
+		case unicode.IsSpace(r) && !phrase:
+			flush()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	return terms
+}
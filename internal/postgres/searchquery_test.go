@@ -0,0 +1,40 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import "testing"
+
+func TestWebsearchQuery(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"   ", ""},
+		{"http client", "http client"},
+		{`"http client"`, `"http client"`},
+		{`"http   client" json`, `"http client" json`},
+		{`json"http client"`, `json "http client"`},
+		{"http AND client", "http client"},
+		{"http OR client", "http or client"},
+		{"http NOT client", "http -client"},
+		{`http NOT "web client"`, `http -"web client"`},
+		{"http not client", "http not client"},
+		{`"AND" OR "NOT"`, `"AND" or "NOT"`},
+		// Malformed queries.
+		{`"http client`, `"http client"`},
+		{`""`, ""},
+		{"OR http", "http"},
+		{"http OR", "http"},
+		{"http NOT", "http"},
+		{"AND OR NOT", ""},
+		{"http NOT NOT client", "http -client"},
+		{"http OR OR client", "http or client"},
+		{"http OR NOT client", "http or -client"},
+	} {
+		if got := websearchQuery(test.in); got != test.want {
+			t.Errorf("websearchQuery(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
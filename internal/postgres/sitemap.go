@@ -0,0 +1,85 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware"
+)
+
+// A SitemapEntry is a package path to be listed in the sitemap, together with
+// the commit time of its latest version.
+type SitemapEntry struct {
+	PackagePath  string
+	LastModified time.Time
+}
+
+// GetSitemapPageStarts returns the package paths, in sorted order, that
+// start each page of pageSize sitemap entries. They can be passed to
+// GetSitemapEntries to read the pages.
+func (db *DB) GetSitemapPageStarts(ctx context.Context, pageSize int) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "GetSitemapPageStarts(ctx, %d)", pageSize)
+	defer middleware.ElapsedStat(ctx, "GetSitemapPageStarts")()
+
+	if pageSize <= 0 {
+		return nil, fmt.Errorf("pageSize must be positive: %w", derrors.InvalidArgument)
+	}
+	query := `
+		SELECT package_path
+		FROM (
+			SELECT package_path, row_number() OVER (ORDER BY package_path) AS n
+			FROM search_documents
+		) s
+		WHERE (n - 1) % $1 = 0
+		ORDER BY package_path`
+	var starts []string
+	collect := func(rows *sql.Rows) error {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return err
+		}
+		starts = append(starts, p)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, pageSize); err != nil {
+		return nil, err
+	}
+	return starts, nil
+}
+
+// GetSitemapEntries returns at most limit sitemap entries, ordered by package
+// path, starting at the first package path that is not before start.
+func (db *DB) GetSitemapEntries(ctx context.Context, start string, limit int) (_ []*SitemapEntry, err error) {
+	defer derrors.WrapStack(&err, "GetSitemapEntries(ctx, %q, %d)", start, limit)
+	defer middleware.ElapsedStat(ctx, "GetSitemapEntries")()
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive: %w", derrors.InvalidArgument)
+	}
+	query := `
+		SELECT package_path, commit_time
+		FROM search_documents
+		WHERE package_path >= $1
+		ORDER BY package_path
+		LIMIT $2`
+	var entries []*SitemapEntry
+	collect := func(rows *sql.Rows) error {
+		var e SitemapEntry
+		if err := rows.Scan(&e.PackagePath, &e.LastModified); err != nil {
+			return err
+		}
+		entries = append(entries, &e)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, start, limit); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware"
+)
+
+// IndexStats holds totals over everything in the database.
+type IndexStats struct {
+	// NumModules is the number of distinct module paths.
+	NumModules int
+	// NumModuleVersions is the number of module versions.
+	NumModuleVersions int
+	// NumPackages is the number of distinct package paths, over all
+	// versions.
+	NumPackages int
+	// DocumentationBytes is the total size of the stored documentation
+	// source, in bytes.
+	DocumentationBytes int64
+}
+
+// Stats returns totals over the contents of the database, for example for
+// an about page. The queries scan whole tables, so callers that serve the
+// result frequently should cache it.
+func (db *DB) Stats(ctx context.Context) (_ *IndexStats, err error) {
+	defer derrors.WrapStack(&err, "Stats(ctx)")
+	defer middleware.ElapsedStat(ctx, "Stats")()
+
+	var s IndexStats
+	err = db.db.QueryRow(ctx, `
+		SELECT
+			(SELECT COUNT(DISTINCT module_path) FROM modules),
+			(SELECT COUNT(*) FROM modules),
+			(SELECT COUNT(DISTINCT path_id) FROM units WHERE name != ''),
+			(SELECT COALESCE(SUM(octet_length(source)), 0) FROM documentation)
+	`).Scan(&s.NumModules, &s.NumModuleVersions, &s.NumPackages, &s.DocumentationBytes)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for _, m := range []struct{ path, version string }{
+		{"example.com/a", "v1.0.0"},
+		{"example.com/a", "v1.1.0"},
+		{"example.com/b", "v1.0.0"},
+	} {
+		MustInsertModule(ctx, t, testDB, sample.Module(m.path, m.version, "p", "q"))
+	}
+
+	got, err := testDB.Stats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.NumModules != 2 {
+		t.Errorf("NumModules = %d, want 2", got.NumModules)
+	}
+	if got.NumModuleVersions != 3 {
+		t.Errorf("NumModuleVersions = %d, want 3", got.NumModuleVersions)
+	}
+	// example.com/{a,b}/{p,q}
+	if got.NumPackages != 4 {
+		t.Errorf("NumPackages = %d, want 4", got.NumPackages)
+	}
+	if got.DocumentationBytes <= 0 {
+		t.Errorf("DocumentationBytes = %d, want positive", got.DocumentationBytes)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+const (
+	// maxSuggestionCandidates bounds the number of module paths that
+	// SuggestModulePaths compares with the requested path.
+	maxSuggestionCandidates = 10000
+
+	// maxSuggestionDistance is the largest edit distance of a suggestion.
+	maxSuggestionDistance = 3
+)
+
+// SuggestModulePaths returns up to n known module paths that are closest to
+// path by edit distance, closest first, for a "did you mean" message after
+// GetUnitMeta fails to find path. Each module path is compared with the
+// leading elements of path that it has the same number of elements as, so
+// path may be that of a package in the module.
+//
+// To keep the candidate set small, only module paths that share the host
+// and the first character after it with path are considered, so typos in
+// those are not corrected.
+func (db *DB) SuggestModulePaths(ctx context.Context, path string, n int) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "SuggestModulePaths(ctx, %q, %d)", path, n)
+
+	prefix := path
+	if i := strings.IndexByte(path, '/'); i >= 0 && i+2 <= len(path) {
+		prefix = path[:i+2]
+	}
+	// The edit distance between two strings is at least the difference of
+	// their lengths, so ordering the candidates by that difference keeps the
+	// closest ones when there are more than maxSuggestionCandidates.
+	// prefixLens[k-1] is the length of the first k elements of path, which
+	// is what a module path with k elements is compared with.
+	var prefixLens []int
+	for k := 1; k <= strings.Count(path, "/")+1; k++ {
+		prefixLens = append(prefixLens, len(leadingElements(path, k)))
+	}
+	query := `
+		SELECT module_path
+		FROM (
+			SELECT DISTINCT
+				module_path,
+				abs(octet_length(module_path) - ($2::int[])[least(
+					octet_length(module_path) - octet_length(replace(module_path, '/', '')) + 1,
+					cardinality($2::int[]))]) AS length_diff
+			FROM modules
+			WHERE module_path LIKE $1 || '%'
+		) c
+		WHERE length_diff <= $3
+		ORDER BY length_diff, module_path
+		LIMIT $4`
+	type suggestion struct {
+		path string
+		dist int
+	}
+	var suggestions []suggestion
+	collect := func(rows *sql.Rows) error {
+		var mp string
+		if err := rows.Scan(&mp); err != nil {
+			return err
+		}
+		d := editDistance(mp, leadingElements(path, strings.Count(mp, "/")+1))
+		if d <= maxSuggestionDistance {
+			suggestions = append(suggestions, suggestion{mp, d})
+		}
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, escapeLike(prefix), pq.Array(prefixLens), maxSuggestionDistance, maxSuggestionCandidates); err != nil {
+		return nil, err
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].dist != suggestions[j].dist {
+			return suggestions[i].dist < suggestions[j].dist
+		}
+		return suggestions[i].path < suggestions[j].path
+	})
+	var paths []string
+	for i := 0; i < len(suggestions) && i < n; i++ {
+		paths = append(paths, suggestions[i].path)
+	}
+	return paths, nil
+}
+
+// escapeLike escapes s so that it matches itself in a LIKE pattern that uses
+// the default escape character.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// leadingElements returns the first n slash-separated elements of path.
+func leadingElements(path string, n int) string {
+	i := 0
+	for ; n > 0 && i < len(path); n-- {
+		j := strings.IndexByte(path[i:], '/')
+		if j < 0 {
+			return path
+		}
+		i += j + 1
+	}
+	if i == 0 {
+		return ""
+	}
+	return path[:i-1]
+}
+
+// editDistance returns the Levenshtein distance between the bytes of a
+// and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
+func minInt(x int, ys ...int) int {
+	for _, y := range ys {
+		if y < x {
+			x = y
+		}
+	}
+	return x
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestSuggestModulePaths(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for _, mp := range []string{
+		"github.com/gorilla/mux",
+		"github.com/gorilla/websocket",
+		"github.com/gorilla/muxy",
+		"gitlab.com/gorilla/mux",
+	} {
+		MustInsertModule(ctx, t, testDB, sample.Module(mp, sample.VersionString, "p"))
+	}
+	for _, test := range []struct {
+		path string
+		want []string
+	}{
+		{"github.com/gorila/mux", []string{"github.com/gorilla/mux", "github.com/gorilla/muxy"}},
+		{"github.com/gorila/mux/p", []string{"github.com/gorilla/mux", "github.com/gorilla/muxy"}},
+		{"github.com/gorilla/websockt", []string{"github.com/gorilla/websocket"}},
+		{"github.com/nothing/like/it", nil},
+		// LIKE metacharacters in the path match only themselves.
+		{"github.com/_orilla/mux", nil},
+		{"github.com/%orilla/mux", nil},
+	} {
+		got, err := testDB.SuggestModulePaths(ctx, test.path, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", test.path, diff)
+		}
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	for _, test := range []struct {
+		in, want string
+	}{
+		{"github.com/g", "github.com/g"},
+		{"github.com/_", `github.com/\_`},
+		{"a%b", `a\%b`},
+		{`a\b`, `a\\b`},
+	} {
+		if got := escapeLike(test.in); got != test.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", test.in, got, test.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"gorilla", "gorila", 1},
+		{"kitten", "sitting", 3},
+	} {
+		if got := editDistance(test.a, test.b); got != test.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
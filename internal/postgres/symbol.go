@@ -83,15 +83,22 @@ type packageSymbol struct {
 	parentName string
 }
 
+// A symbolDeprecation is the deprecation of a symbol in a documentation.
+type symbolDeprecation struct {
+	deprecated bool
+	comment    string
+}
+
 func upsertDocumentationSymbols(ctx context.Context, db *database.DB,
 	pathToPkgsymID map[string]map[packageSymbol]int,
 	pathToDocIDToDoc map[string]map[int]*internal.Documentation) (err error) {
 	defer derrors.WrapStack(&err, "upsertDocumentationSymbols(ctx, db, pathToPkgsymID, pathToDocIDToDoc)")
 
-	// Create a map of documentation_id TO package_symbol_id set.
+	// Create a map of documentation_id TO package_symbol_id TO the
+	// deprecation of the symbol.
 	// This will be used to verify that all package_symbols for the unit have
 	// been inserted.
-	docIDToPkgsymIDs := map[int]map[int]bool{}
+	docIDToPkgsymIDs := map[int]map[int]symbolDeprecation{}
 	for path, docIDToDoc := range pathToDocIDToDoc {
 		for docID, doc := range docIDToDoc {
 			err := updateSymbols(doc.API, func(sm *internal.SymbolMeta) error {
@@ -106,9 +113,9 @@ func upsertDocumentationSymbols(ctx context.Context, db *database.DB,
 				}
 				_, ok = docIDToPkgsymIDs[docID]
 				if !ok {
-					docIDToPkgsymIDs[docID] = map[int]bool{}
+					docIDToPkgsymIDs[docID] = map[int]symbolDeprecation{}
 				}
-				docIDToPkgsymIDs[docID][pkgsymID] = true
+				docIDToPkgsymIDs[docID][pkgsymID] = symbolDeprecation{sm.Deprecated, sm.DeprecationComment}
 				return nil
 			})
 			if err != nil {
@@ -120,26 +127,39 @@ func upsertDocumentationSymbols(ctx context.Context, db *database.DB,
 	// Fetch all existing rows in documentation_symbols for this unit using the
 	// documentation IDs.
 	// Keep track of which rows already exist in documentation_symbols using
-	// gotDocIDToPkgsymIDs.
+	// gotDocIDToPkgsymIDs, and which are no longer wanted using staleIDs, so
+	// that only the difference is written when a version is fetched again.
 	var documentationIDs []any
 	for docID := range docIDToPkgsymIDs {
 		documentationIDs = append(documentationIDs, docID)
 	}
 	gotDocIDToPkgsymIDs := map[int]map[int]bool{}
+	var staleIDs []int
 	collect := func(rows *sql.Rows) error {
-		var id, docID, pkgsymID int
-		if err := rows.Scan(&id, &docID, &pkgsymID); err != nil {
+		var (
+			id, docID, pkgsymID int
+			got                 symbolDeprecation
+		)
+		if err := rows.Scan(&id, &docID, &pkgsymID, &got.deprecated, &got.comment); err != nil {
 			return fmt.Errorf("row.Scan(): %v", err)
 		}
-		if !docIDToPkgsymIDs[docID][pkgsymID] {
+		want, ok := docIDToPkgsymIDs[docID][pkgsymID]
+		if !ok {
 			// The package_symbol_id in the documentation_symbols table does
-			// not match the one we want to insert. This can happen if we
-			// change the package_symbol_id. In that case, do not add this to
-			// the map, so that we can upsert below.
+			// not match the one we want to insert. This can happen if the
+			// symbol was removed or changed, or if we change the
+			// package_symbol_id. In that case, do not add this to the map, so
+			// that we can upsert below, and delete the row.
 			//
 			// See https://go-review.googlesource.com/c/pkgsite/+/315309
 			// and https://go-review.googlesource.com/c/pkgsite/+/315310
 			// where the package_symbol_id was potentially changed.
+			staleIDs = append(staleIDs, id)
+			return nil
+		}
+		if got != want {
+			// The deprecation of the symbol changed. Leave the row out of
+			// the map, so that it is updated below.
 			return nil
 		}
 		if _, ok := gotDocIDToPkgsymIDs[docID]; !ok {
@@ -152,11 +172,19 @@ func upsertDocumentationSymbols(ctx context.Context, db *database.DB,
         SELECT
             ds.id,
             ds.documentation_id,
-            ds.package_symbol_id
+            ds.package_symbol_id,
+            ds.deprecated,
+            ds.deprecation_comment
         FROM documentation_symbols ds
         WHERE documentation_id = ANY($1);`, collect, pq.Array(documentationIDs)); err != nil {
 		return err
 	}
+	if len(staleIDs) > 0 {
+		sort.Ints(staleIDs)
+		if _, err := db.Exec(ctx, `DELETE FROM documentation_symbols WHERE id = ANY($1)`, pq.Array(staleIDs)); err != nil {
+			return err
+		}
+	}
 
 	// Get the difference between the documentation_symbols for this package,
 	// and the ones that already exist in the documentation_symbols table. Only
@@ -171,22 +199,24 @@ func upsertDocumentationSymbols(ctx context.Context, db *database.DB,
 	var values []any
 	for _, docID := range docIDs {
 		gotSet := gotDocIDToPkgsymIDs[docID]
-		for pkgsymID := range docIDToPkgsymIDs[docID] {
+		for pkgsymID, dep := range docIDToPkgsymIDs[docID] {
 			if !gotSet[pkgsymID] {
-				values = append(values, docID, pkgsymID)
+				values = append(values, docID, pkgsymID, dep.deprecated, dep.comment)
 			}
 		}
 	}
 	// Upsert the rows.
 	// Note that the order of pkgsymcols must match that of the SELECT query in
 	// the collect function.
-	docsymcols := []string{"documentation_id", "package_symbol_id"}
+	docsymcols := []string{"documentation_id", "package_symbol_id", "deprecated", "deprecation_comment"}
 	if err := db.BulkInsert(ctx, "documentation_symbols", docsymcols,
 		values, `
 			ON CONFLICT (documentation_id, package_symbol_id)
 			DO UPDATE SET
 				documentation_id=excluded.documentation_id,
-				package_symbol_id=excluded.package_symbol_id`); err != nil {
+				package_symbol_id=excluded.package_symbol_id,
+				deprecated=excluded.deprecated,
+				deprecation_comment=excluded.deprecation_comment`); err != nil {
 		return err
 	}
 	return nil
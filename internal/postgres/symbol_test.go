@@ -84,6 +84,117 @@ func TestInsertSymbolNamesAndHistory(t *testing.T) {
 	}
 }
 
+func TestInsertSymbols_Refetch(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	mod := sample.DefaultModule()
+	api := []*internal.Symbol{
+		sample.Constant,
+		sample.Variable,
+		sample.Function,
+		sample.Type,
+	}
+	mod.Packages()[0].Documentation[0].API = api
+	MustInsertModule(ctx, t, testDB, mod)
+
+	// rowVersions returns the id and xmin of each row of the symbol tables.
+	// A row's xmin changes whenever the row is rewritten.
+	rowVersions := func() []string {
+		t.Helper()
+		var all []string
+		for _, table := range []string{"symbol_names", "package_symbols", "documentation_symbols", "symbol_history"} {
+			rows, err := database.Collect1[string](ctx, testDB.db,
+				fmt.Sprintf(`SELECT '%s ' || id || ' ' || xmin::text FROM %[1]s ORDER BY id`, table))
+			if err != nil {
+				t.Fatal(err)
+			}
+			all = append(all, rows...)
+		}
+		return all
+	}
+	before := rowVersions()
+	MustInsertModule(ctx, t, testDB, mod)
+	if diff := cmp.Diff(before, rowVersions()); diff != "" {
+		t.Errorf("symbol rows rewritten on refetch of unchanged package (-before, +after):\n%s", diff)
+	}
+
+	// Removing a symbol deletes only its row in documentation_symbols.
+	api = []*internal.Symbol{sample.Constant, sample.Function, sample.Type}
+	mod.Packages()[0].Documentation[0].API = api
+	MustInsertModule(ctx, t, testDB, mod)
+	compareUnitSymbols(ctx, t, testDB, mod.Packages()[0].Path, mod.ModulePath, mod.Version,
+		map[internal.BuildContext][]*internal.Symbol{internal.BuildContextAll: api})
+}
+
+func TestInsertSymbols_Deprecated(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	fn := *sample.Function
+	fn.Deprecated = true
+	fn.DeprecationComment = "Use Other instead."
+	mod := sample.DefaultModule()
+	mod.Packages()[0].Documentation[0].API = []*internal.Symbol{sample.Constant, &fn}
+
+	type deprecation struct {
+		Name       string
+		Deprecated bool
+		Comment    string
+	}
+	check := func(want []deprecation) {
+		t.Helper()
+		var got []deprecation
+		if err := testDB.db.RunQuery(ctx, `
+			SELECT s.name, ds.deprecated, ds.deprecation_comment
+			FROM documentation_symbols ds
+			INNER JOIN package_symbols ps ON ps.id = ds.package_symbol_id
+			INNER JOIN symbol_names s ON s.id = ps.symbol_name_id
+			ORDER BY s.name`, func(rows *sql.Rows) error {
+			var d deprecation
+			if err := rows.Scan(&d.Name, &d.Deprecated, &d.Comment); err != nil {
+				return err
+			}
+			got = append(got, d)
+			return nil
+		}); err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("mismatch (-want, +got):\n%s", diff)
+		}
+	}
+
+	MustInsertModule(ctx, t, testDB, mod)
+	check([]deprecation{
+		{sample.Constant.Name, false, ""},
+		{fn.Name, true, fn.DeprecationComment},
+	})
+
+	// The deprecation is not part of the symbol's history.
+	sh, err := testDB.GetSymbolHistory(ctx, mod.Packages()[0].Path, mod.ModulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(sh.SymbolsAtVersion(mod.Version)[fn.Name]); got != 1 {
+		t.Errorf("got %d entries for %s in symbol history, want 1", got, fn.Name)
+	}
+
+	// A change of deprecation is stored when the module is fetched again.
+	fn.DeprecationComment = "Use Another instead."
+	MustInsertModule(ctx, t, testDB, mod)
+	check([]deprecation{
+		{sample.Constant.Name, false, ""},
+		{fn.Name, true, fn.DeprecationComment},
+	})
+}
+
 func TestInsertSymbolHistory_Basic(t *testing.T) {
 	testDB, release := acquire(t)
 	defer release()
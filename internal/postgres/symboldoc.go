@@ -0,0 +1,42 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/godoc"
+	"golang.org/x/pkgsite/internal/godoc/dochtml"
+)
+
+// GetSymbolDoc returns the rendered documentation of the exported symbol in
+// the package at path and version, which must be resolved. The symbol is a
+// top-level name, or a type name and one of its method names separated by a
+// dot. Only the documentation of that symbol is rendered, not that of the
+// whole package.
+//
+// If the package has documentation for several build contexts, the first one
+// in build-context order is used.
+func (db *DB) GetSymbolDoc(ctx context.Context, path, version, symbol string) (_ *dochtml.SymbolDoc, err error) {
+	defer derrors.WrapStack(&err, "DB.GetSymbolDoc(ctx, %q, %q, %q)", path, version, symbol)
+
+	um, err := db.GetUnitMeta(ctx, path, internal.UnknownModulePath, version)
+	if err != nil {
+		return nil, err
+	}
+	u := &internal.Unit{UnitMeta: *um}
+	if um.IsPackage() {
+		if err := db.getUnitDocSource(ctx, u, internal.BuildContext{}); err != nil {
+			return nil, err
+		}
+	}
+	if len(u.Documentation) == 0 {
+		return nil, fmt.Errorf("no documentation for %s@%s: %w", path, version, derrors.NotFound)
+	}
+	return godoc.SymbolDocFromUnit(ctx, u, symbol)
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetSymbolDoc(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module("example.com/m", "v1.2.3", "pkg")
+	m.Packages()[0].Documentation = []*internal.Documentation{sample.Documentation(sample.GOOS, sample.GOARCH, `
+		// Package pkg is for testing.
+		package pkg
+
+		// Hello returns a greeting for name.
+		func Hello(name string) string { return "" }
+	`)}
+	MustInsertModule(ctx, t, testDB, m)
+
+	got, err := testDB.GetSymbolDoc(ctx, "example.com/m/pkg", "v1.2.3", "Hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "func Hello(name string) string"; got.Synopsis != want {
+		t.Errorf("got synopsis %q, want %q", got.Synopsis, want)
+	}
+	if want := "Hello returns a greeting for name."; !strings.Contains(got.Doc.String(), want) {
+		t.Errorf("doc %q does not contain %q", got.Doc, want)
+	}
+	if !strings.HasPrefix(got.SourceURL, "https://example.com/m/") {
+		t.Errorf("got source URL %q", got.SourceURL)
+	}
+
+	for _, test := range []struct{ path, version, symbol string }{
+		{"example.com/m/pkg", "v1.2.3", "Goodbye"},
+		{"example.com/m/pkg", "v1.0.0", "Hello"},
+		{"example.com/m/other", "v1.2.3", "Hello"},
+	} {
+		_, err := testDB.GetSymbolDoc(ctx, test.path, test.version, test.symbol)
+		if !errors.Is(err, derrors.NotFound) {
+			t.Errorf("%s@%s %s: got %v, want NotFound", test.path, test.version, test.symbol, err)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// GetUndocumentedPackages returns the paths of at most limit packages, in
+// order, that have no package comment, or that have exported symbols none of
+// which are documented. Only the latest versions of redistributable packages
+// are considered, since documentation is not stored for the others.
+func (db *DB) GetUndocumentedPackages(ctx context.Context, limit int) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "GetUndocumentedPackages(ctx, %d)", limit)
+
+	if limit <= 0 {
+		return nil, fmt.Errorf("limit must be positive: %w", derrors.InvalidArgument)
+	}
+	query := `
+		SELECT sd.package_path
+		FROM search_documents sd
+		INNER JOIN units u ON u.id = sd.unit_id
+		WHERE sd.redistributable
+			AND (COALESCE(sd.synopsis, '') = ''
+				OR (u.num_exported_symbols > 0 AND u.num_documented_symbols = 0))
+		ORDER BY sd.package_path
+		LIMIT $1`
+	return database.Collect1[string](ctx, db.db, query, limit)
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetUndocumentedPackages(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	documented := sample.Module("example.com/documented", sample.VersionString, "p")
+	documented.Packages()[0].DocStats = internal.DocStats{NumExported: 2, NumDocumented: 1}
+	noSynopsis := sample.Module("example.com/nosynopsis", sample.VersionString, "p")
+	noSynopsis.Packages()[0].Documentation[0].Synopsis = ""
+	noDocs := sample.Module("example.com/nodocs", sample.VersionString, "p")
+	noDocs.Packages()[0].DocStats = internal.DocStats{NumExported: 2, NumDocumented: 0}
+	for _, m := range []*internal.Module{documented, noSynopsis, noDocs} {
+		MustInsertModule(ctx, t, testDB, m)
+	}
+
+	got, err := testDB.GetUndocumentedPackages(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"example.com/nodocs/p", "example.com/nosynopsis/p"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	got, err = testDB.GetUndocumentedPackages(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want[:1], got); diff != "" {
+		t.Errorf("limit 1: mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -62,12 +62,20 @@ func (db *DB) getUnitMetaWithKnownVersion(ctx context.Context, fullPath, moduleP
 		"m.version",
 		"m.commit_time",
 		"m.source_info",
+		"m.commit_info",
 		"m.has_go_mod",
 		"m.redistributable",
+		"m.updated_at",
 		"u.name",
 		"u.redistributable",
 		"u.license_types",
-		"u.license_paths").
+		"u.license_paths",
+		"COALESCE(u.num_exported_symbols, 0)",
+		"COALESCE(u.num_documented_symbols, 0)",
+		"COALESCE(u.examples_valid, true)",
+		"COALESCE(u.num_test_files, 0)",
+		"COALESCE(u.deprecated, false)",
+		"COALESCE(u.deprecation_comment, '')").
 		From("modules m").
 		Join("units u on u.module_id = m.id").
 		Join("paths p ON p.id = u.path_id").Where(squirrel.Eq{"p.path": fullPath}).
@@ -101,12 +109,20 @@ func (db *DB) getUnitMetaWithKnownVersion(ctx context.Context, fullPath, moduleP
 		&um.Version,
 		&um.CommitTime,
 		jsonbScanner{&um.SourceInfo},
+		jsonbScanner{&um.Commit},
 		&um.HasGoMod,
 		&um.ModuleInfo.IsRedistributable,
+		&um.ProcessedAt,
 		&um.Name,
 		&um.IsRedistributable,
 		pq.Array(&licenseTypes),
-		pq.Array(&licensePaths))
+		pq.Array(&licensePaths),
+		&um.DocStats.NumExported,
+		&um.DocStats.NumDocumented,
+		&um.ExamplesValid,
+		&um.NumTestFiles,
+		&um.PackageDeprecated,
+		&um.PackageDeprecationComment)
 	if err == sql.ErrNoRows {
 		return nil, derrors.NotFound
 	}
@@ -254,6 +270,29 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 			return nil, err
 		}
 	}
+	if fields&internal.WithSymbolHistory != 0 && fields&internal.WithMain == 0 &&
+		um.IsPackage() && !um.IsCommand() {
+		u.SymbolHistory, err = db.getUnitSymbolHistory(ctx, um, bc)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if fields&internal.WithVulns != 0 {
+		u.Vulns, err = db.getUnitVulns(ctx, um)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if fields&internal.WithDocSource != 0 && um.IsPackage() {
+		if err := db.getUnitDocSource(ctx, u, bc); err != nil {
+			return nil, err
+		}
+	}
+	if fields&(internal.WithDocHTML|internal.WithDocText) != 0 && um.IsPackage() {
+		if err := db.getUnitDocFormats(ctx, u, fields, bc); err != nil {
+			return nil, err
+		}
+	}
 	if fields&internal.WithImports == 0 &&
 		fields&internal.WithLicenses == 0 {
 		return u, nil
@@ -281,6 +320,10 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 			return nil, err
 		}
 		u.LicenseContents = lics
+		u.Notice, err = getNotice(ctx, db.db, unitID)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if db.bypassLicenseCheck {
 		u.IsRedistributable = true
@@ -290,6 +333,22 @@ func (db *DB) GetUnit(ctx context.Context, um *internal.UnitMeta, fields interna
 	return u, nil
 }
 
+// getUnitSymbolHistory returns the symbol history of the package um for the
+// build context bc. Like GetSymbolHistoryForBuildContext, it uses the
+// history for linux/amd64 if bc is BuildContextAll, and also if bc is empty.
+func (db *DB) getUnitSymbolHistory(ctx context.Context, um *internal.UnitMeta, bc internal.BuildContext) (_ map[string]string, err error) {
+	defer derrors.WrapStack(&err, "getUnitSymbolHistory(ctx, %q, %q, %v)", um.Path, um.ModulePath, bc)
+
+	pathID, err := GetPathID(ctx, db.db, um.Path)
+	if err != nil {
+		return nil, err
+	}
+	if bc == (internal.BuildContext{}) {
+		bc = internal.BuildContextAll
+	}
+	return GetSymbolHistoryForBuildContext(ctx, db.db, pathID, um.ModulePath, bc)
+}
+
 func (db *DB) getUnitID(ctx context.Context, fullPath, modulePath, resolvedVersion string) (_ int, err error) {
 	defer derrors.WrapStack(&err, "getUnitID(ctx, %q, %q, %q)", fullPath, modulePath, resolvedVersion)
 	defer middleware.ElapsedStat(ctx, "getUnitID")()
@@ -472,7 +531,10 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 			r.file_path,
 			r.contents,
 			d.synopsis,
+			d.usage,
 			d.source,
+			u.examples,
+			u.build_errors,
 			COALESCE((
 				SELECT COUNT(unit_id)
 				FROM imports
@@ -491,7 +553,7 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 		ON r.unit_id = u.id
 
 		LEFT JOIN (
-			SELECT synopsis, source, goos, goarch, unit_id
+			SELECT synopsis, usage, source, goos, goarch, unit_id
 			FROM documentation d
 			WHERE d.GOOS = $3 AND d.GOARCH = $4
         ) d
@@ -514,7 +576,10 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 		database.NullIsEmpty(&r.Filepath),
 		database.NullIsEmpty(&r.Contents),
 		database.NullIsEmpty(&doc.Synopsis),
+		database.NullIsEmpty(&doc.Usage),
 		&doc.Source,
+		jsonbScanner{&u.Examples},
+		pq.Array(&u.BuildErrors),
 		&u.NumImports,
 		&u.NumImportedBy,
 	)
@@ -525,7 +590,11 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 		if r.Filepath != "" && um.ModulePath != stdlib.ModulePath {
 			u.Readme = &r
 		}
-		if doc.GOOS != "" {
+		switch {
+		case doc.GOOS != "" && doc.Source == nil:
+			// The documentation was purged by PurgeDocumentation.
+			u.DocumentationPurged = true
+		case doc.GOOS != "":
 			u.Documentation = []*internal.Documentation{doc}
 		}
 	default:
@@ -540,6 +609,13 @@ func (db *DB) getUnitWithAllFields(ctx context.Context, um *internal.UnitMeta, b
 	u.Subdirectories = pkgs
 	u.UnitMeta = *um
 
+	if um.IsModule() {
+		u.Changelog, err = getChangelog(ctx, db.db, moduleID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if um.IsPackage() && !um.IsCommand() && doc.Source != nil {
 		u.SymbolHistory, err = GetSymbolHistoryForBuildContext(ctx, db.db, pathID, um.ModulePath, bcMatched)
 		if err != nil {
@@ -631,3 +707,42 @@ func getModuleReadme(ctx context.Context, db *database.DB, modulePath, resolvedV
 		return nil, err
 	}
 }
+
+// getNotice returns the NOTICE file of the module containing the unit with
+// the given ID, or nil if it has none.
+func getNotice(ctx context.Context, db *database.DB, unitID int) (_ *internal.Notice, err error) {
+	defer derrors.WrapStack(&err, "getNotice(ctx, %d)", unitID)
+	var n internal.Notice
+	err = db.QueryRow(ctx, `
+		SELECT n.file_path, n.contents
+		FROM module_notices n
+		INNER JOIN units u ON u.module_id = n.module_id
+		WHERE u.id = $1`, unitID).Scan(&n.Filepath, &n.Contents)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, nil
+	case nil:
+		return &n, nil
+	default:
+		return nil, err
+	}
+}
+
+// getChangelog returns the changelog of the module with the given ID, or nil
+// if it has none.
+func getChangelog(ctx context.Context, db *database.DB, moduleID int) (_ *internal.Changelog, err error) {
+	defer derrors.WrapStack(&err, "getChangelog(ctx, %d)", moduleID)
+	var c internal.Changelog
+	err = db.QueryRow(ctx, `
+		SELECT file_path, contents
+		FROM module_changelogs
+		WHERE module_id = $1`, moduleID).Scan(&c.Filepath, &c.Contents)
+	switch err {
+	case sql.ErrNoRows:
+		return nil, nil
+	case nil:
+		return &c, nil
+	default:
+		return nil, err
+	}
+}
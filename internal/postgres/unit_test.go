@@ -79,7 +79,7 @@ func testGetUnitMeta(t *testing.T, ctx context.Context) {
 		opts := []cmp.Option{
 			cmpopts.EquateEmpty(),
 			cmpopts.IgnoreFields(licenses.Metadata{}, "Coverage"),
-			cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod"),
+			cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod", "ProcessedAt"),
 			cmp.AllowUnexported(source.Info{}, safehtml.HTML{}),
 		}
 		if diff := cmp.Diff(test.want, got, opts...); diff != "" {
@@ -356,7 +356,7 @@ func TestGetUnitMetaBypass(t *testing.T) {
 				opts := []cmp.Option{
 					cmpopts.EquateEmpty(),
 					cmpopts.IgnoreFields(licenses.Metadata{}, "Coverage"),
-					cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod"),
+					cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod", "ProcessedAt"),
 					cmp.AllowUnexported(source.Info{}, safehtml.HTML{}),
 				}
 				if diff := cmp.Diff(test.want, got, opts...); diff != "" {
@@ -782,6 +782,218 @@ func TestGetUnit_SubdirectoriesShowNonRedistPackages(t *testing.T) {
 	MustInsertModule(ctx, t, testDB, m)
 }
 
+func TestGetUnitChangelog(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, "foo")
+	m.Changelog = &internal.Changelog{Filepath: "CHANGELOG.md", Contents: "# v1.0.0\n\nFirst release."}
+	MustInsertModule(ctx, t, testDB, m)
+
+	for _, test := range []struct {
+		path string
+		want *internal.Changelog
+	}{
+		{sample.ModulePath, m.Changelog},
+		// Only the module root has the changelog.
+		{sample.ModulePath + "/foo", nil},
+	} {
+		um, err := testDB.GetUnitMeta(ctx, test.path, sample.ModulePath, sample.VersionString)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, u.Changelog); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", test.path, diff)
+		}
+	}
+
+	// Reinserting without a changelog removes it.
+	m.Changelog = nil
+	MustInsertModule(ctx, t, testDB, m)
+	um, err := testDB.GetUnitMeta(ctx, sample.ModulePath, sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Changelog != nil {
+		t.Errorf("after reinsert: got changelog %+v, want nil", u.Changelog)
+	}
+}
+
+func TestGetUnitMetaCommit(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, "foo")
+	m.Commit = &internal.Commit{
+		Hash:           "0123456789abcdef0123456789abcdef01234567",
+		AuthorName:     "Author",
+		AuthorEmail:    "author@example.com",
+		CommitterName:  "Committer",
+		CommitterEmail: "committer@example.com",
+	}
+	MustInsertModule(ctx, t, testDB, m)
+	MustInsertModule(ctx, t, testDB, sample.Module(sample.ModulePath, "v1.1.0", "foo"))
+
+	um, err := testDB.GetUnitMeta(ctx, sample.ModulePath+"/foo", sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.Commit, um.Commit); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	um, err = testDB.GetUnitMeta(ctx, sample.ModulePath+"/foo", sample.ModulePath, "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if um.Commit != nil {
+		t.Errorf("v1.1.0: got commit %+v, want nil", um.Commit)
+	}
+
+	// Fetching the module again without commit information keeps the
+	// information it had.
+	m2 := sample.Module(sample.ModulePath, sample.VersionString, "foo")
+	MustInsertModule(ctx, t, testDB, m2)
+	um, err = testDB.GetUnitMeta(ctx, sample.ModulePath+"/foo", sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(m.Commit, um.Commit); diff != "" {
+		t.Errorf("after refetch: mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGetUnitExamples(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, "foo", "bar")
+	want := &internal.Examples{
+		Package: []*internal.Example{{Doc: "Package example.\n", Output: "hello\n"}},
+		Symbol:  []*internal.Example{{Symbol: "T.M", Suffix: "suffix"}},
+	}
+	for _, u := range m.Units {
+		if u.Path == sample.ModulePath+"/foo" {
+			u.Examples = want
+		}
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	for _, test := range []struct {
+		path string
+		want *internal.Examples
+	}{
+		{sample.ModulePath + "/foo", want},
+		{sample.ModulePath + "/bar", nil},
+	} {
+		um, err := testDB.GetUnitMeta(ctx, test.path, sample.ModulePath, sample.VersionString)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, u.Examples); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", test.path, diff)
+		}
+	}
+}
+
+func TestGetUnitBuildErrors(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, "foo", "bar")
+	want := []string{`no required module provides package "example.com/unknown"`}
+	for _, u := range m.Units {
+		if u.Path == sample.ModulePath+"/foo" {
+			u.BuildErrors = want
+		}
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	for _, test := range []struct {
+		path string
+		want []string
+	}{
+		{sample.ModulePath + "/foo", want},
+		{sample.ModulePath + "/bar", nil},
+	} {
+		um, err := testDB.GetUnitMeta(ctx, test.path, sample.ModulePath, sample.VersionString)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, u.BuildErrors); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", test.path, diff)
+		}
+	}
+}
+
+func TestGetUnitNotice(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, "foo")
+	m.Notice = &internal.Notice{Filepath: "NOTICE", Contents: "Copyright Example Corp."}
+	MustInsertModule(ctx, t, testDB, m)
+
+	for _, path := range []string{sample.ModulePath, sample.ModulePath + "/foo"} {
+		um, err := testDB.GetUnitMeta(ctx, path, sample.ModulePath, sample.VersionString)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := testDB.GetUnit(ctx, um, internal.WithLicenses, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(m.Notice, u.Notice); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", path, diff)
+		}
+	}
+
+	// Reinserting without a notice removes it.
+	m.Notice = nil
+	MustInsertModule(ctx, t, testDB, m)
+	um, err := testDB.GetUnitMeta(ctx, sample.ModulePath, sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := testDB.GetUnit(ctx, um, internal.WithLicenses, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Notice != nil {
+		t.Errorf("after reinsert: got notice %+v, want nil", u.Notice)
+	}
+}
+
 func TestGetUnitFieldSet(t *testing.T) {
 	t.Parallel()
 	testDB, release := acquire(t)
@@ -1027,3 +1239,75 @@ func newUnitMeta(path, modulePath, version string) *internal.UnitMeta {
 		},
 	}
 }
+
+func TestGetUnitMetaDocStats(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	m := sample.Module(sample.ModulePath, sample.VersionString, "foo")
+	want := internal.DocStats{NumExported: 4, NumDocumented: 3}
+	for _, u := range m.Units {
+		if u.Path == sample.ModulePath+"/foo" {
+			u.DocStats = want
+		}
+	}
+	MustInsertModule(ctx, t, testDB, m)
+
+	um, err := testDB.GetUnitMeta(ctx, sample.ModulePath+"/foo", sample.ModulePath, sample.VersionString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if um.DocStats != want {
+		t.Errorf("got %+v, want %+v", um.DocStats, want)
+	}
+}
+
+func TestGetUnitSymbolHistory(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for _, v := range []struct {
+		version string
+		api     []*internal.Symbol
+	}{
+		{"v1.15.0", []*internal.Symbol{sample.Constant}},
+		{"v1.16.0", []*internal.Symbol{sample.Constant, sample.Function}},
+	} {
+		m := sample.Module(stdlib.ModulePath, v.version, "context")
+		m.Packages()[0].Documentation[0].API = v.api
+		MustInsertModule(ctx, t, testDB, m)
+	}
+
+	um, err := testDB.GetUnitMeta(ctx, "context", stdlib.ModulePath, "v1.16.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := testDB.GetUnit(ctx, um, internal.WithSymbolHistory, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		sample.Constant.Name: "go1.15",
+		sample.Function.Name: "go1.16",
+	}
+	got := map[string]string{}
+	for name, v := range u.SymbolHistory {
+		tag, err := stdlib.TagForVersion(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[name] = tag
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	if u.Documentation != nil {
+		t.Errorf("got documentation with only WithSymbolHistory")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/Masterminds/squirrel"
@@ -283,6 +284,51 @@ func (db *DB) getLatestMajorVersion(ctx context.Context, fullPath, modulePath st
 	}
 }
 
+// A MajorVersion is the latest version of one major version of a module.
+type MajorVersion struct {
+	ModulePath string
+	// Major is the major version of ModulePath, which is 1 for module paths
+	// without a major version suffix.
+	Major   int
+	Version string
+}
+
+// GetLatestMajorVersions returns the latest good version of each module path
+// in the series of modulePath, which can be any of them, in increasing order
+// of major version. Module paths without a good version are omitted.
+func (db *DB) GetLatestMajorVersions(ctx context.Context, modulePath string) (_ []*MajorVersion, err error) {
+	defer derrors.WrapStack(&err, "DB.GetLatestMajorVersions(ctx, %q)", modulePath)
+	defer middleware.ElapsedStat(ctx, "DB.GetLatestMajorVersions")()
+
+	var mvs []*MajorVersion
+	collect := func(rows *sql.Rows) error {
+		var mv MajorVersion
+		if err := rows.Scan(&mv.ModulePath, &mv.Version); err != nil {
+			return err
+		}
+		_, mv.Major = internal.SeriesPathAndMajorVersion(mv.ModulePath)
+		mvs = append(mvs, &mv)
+		return nil
+	}
+	err = db.db.RunQuery(ctx, `
+		SELECT p.path, l.good_version
+		FROM latest_module_versions l
+		INNER JOIN paths p ON p.id = l.module_path_id
+		WHERE l.series_path = $1 AND l.good_version != ''`,
+		collect, internal.SeriesPathForModule(modulePath))
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(mvs, func(i, j int) bool {
+		if mvs[i].Major != mvs[j].Major {
+			return mvs[i].Major < mvs[j].Major
+		}
+		// Break ties deterministically.
+		return mvs[i].ModulePath < mvs[j].ModulePath
+	})
+	return mvs, nil
+}
+
 // unitExistsAtLatest reports whether unitPath exists at the latest version of modulePath.
 func (db *DB) unitExistsAtLatest(ctx context.Context, unitPath, modulePath string) (unitExists bool, err error) {
 	defer derrors.WrapStack(&err, "DB.unitExistsAtLatest(ctx, %q, %q)", unitPath, modulePath)
@@ -418,6 +464,38 @@ func (db *DB) GetLatestModuleVersions(ctx context.Context, modulePath string) (_
 	return lmv, err
 }
 
+// GetRetractedVersions returns the versions of modulePath in the modules table
+// that are retracted by the go.mod file of its latest version, sorted in
+// descending semver order. If there is no latest-version information for the
+// module, it returns nil.
+func (db *DB) GetRetractedVersions(ctx context.Context, modulePath string) (_ []string, err error) {
+	defer derrors.WrapStack(&err, "GetRetractedVersions(%q)", modulePath)
+
+	lmv, err := db.GetLatestModuleVersions(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	if lmv == nil || lmv.GoModFile == nil || len(lmv.GoModFile.Retract) == 0 {
+		return nil, nil
+	}
+	vs, err := database.Collect1[string](ctx, db.db, `
+		SELECT version
+		FROM modules
+		WHERE module_path = $1
+		ORDER BY sort_version DESC`,
+		modulePath)
+	if err != nil {
+		return nil, err
+	}
+	var retracted []string
+	for _, v := range vs {
+		if lmv.IsRetracted(v) {
+			retracted = append(retracted, v)
+		}
+	}
+	return retracted, nil
+}
+
 func getLatestModuleVersions(ctx context.Context, db *database.DB, modulePath string) (_ *internal.LatestModuleVersions, id int, err error) {
 	derrors.WrapStack(&err, "getLatestModuleVersions(%q)", modulePath)
 
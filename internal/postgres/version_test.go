@@ -662,3 +662,80 @@ func TestLatestModuleVersionsGood(t *testing.T) {
 	`, modulePath, v2))
 	check(v1)
 }
+
+func TestGetRetractedVersions(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx := context.Background()
+
+	const (
+		modulePath = "example.com/m"
+		modFile    = `
+			module example.com/m
+			retract (
+				v1.5.0 // bad release
+				[v1.1.0, v1.2.5]
+			)
+		`
+	)
+	for _, v := range []string{"v1.0.0", "v1.1.0", "v1.2.0", "v1.2.5", "v1.3.0", "v1.5.0", "v1.6.0"} {
+		MustInsertModule(ctx, t, testDB, sample.Module(modulePath, v, "pkg"))
+	}
+
+	got, err := testDB.GetRetractedVersions(ctx, modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("without latest-version info: got %v, want nil", got)
+	}
+
+	lmv, err := internal.NewLatestModuleVersions(modulePath, "v1.6.0", "v1.6.0", "", []byte(modFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := testDB.UpdateLatestModuleVersions(ctx, lmv); err != nil {
+		t.Fatal(err)
+	}
+	got, err = testDB.GetRetractedVersions(ctx, modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"v1.5.0", "v1.2.5", "v1.2.0", "v1.1.0"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestGetLatestMajorVersions(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	for _, m := range []struct{ modulePath, version string }{
+		{"example.com/m", "v1.0.0"},
+		{"example.com/m", "v1.1.0"},
+		{"example.com/m/v2", "v2.0.0"},
+		{"example.com/m/v2", "v2.1.0"},
+		{"example.com/other", "v1.2.0"},
+	} {
+		MustInsertModule(ctx, t, testDB, sample.Module(m.modulePath, m.version, "pkg"))
+	}
+
+	want := []*MajorVersion{
+		{ModulePath: "example.com/m", Major: 1, Version: "v1.1.0"},
+		{ModulePath: "example.com/m/v2", Major: 2, Version: "v2.1.0"},
+	}
+	for _, modulePath := range []string{"example.com/m", "example.com/m/v2"} {
+		got, err := testDB.GetLatestMajorVersions(ctx, modulePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", modulePath, diff)
+		}
+	}
+}
@@ -80,7 +80,7 @@ func insertIndexVersions(ctx context.Context, ddb *database.DB, versions []*inte
 				}
 				// Update a module's status to 0 if it wasn't found previously.
 				// See https://golang.org/issue/46117.
-				if status == http.StatusNotFound {
+				if status == http.StatusNotFound || status == http.StatusGone {
 					updates = append(updates, [2]string{mod, ver})
 				}
 				return nil
@@ -125,9 +125,9 @@ func (db *DB) UpdateModuleVersionState(ctx context.Context, mvs *ModuleVersionSt
 	defer span.End()
 
 	var numPackages *int
-	if !(mvs.Status >= http.StatusBadRequest && mvs.Status <= http.StatusNotFound) {
-		// If a module was fetched a 40x error in this range, we won't know how
-		// many packages it has.
+	if !(mvs.Status >= http.StatusBadRequest && mvs.Status <= http.StatusNotFound) && mvs.Status != http.StatusGone {
+		// If a module was fetched a 40x error in this range, or was gone, we
+		// won't know how many packages it has.
 		n := len(mvs.PackageVersionStates)
 		numPackages = &n
 	}
@@ -166,6 +166,8 @@ func updateModuleVersionState(ctx context.Context, db *database.DB, numPackages
 			num_packages=$6,
 			try_count=try_count+1,
 			last_processed_at=CURRENT_TIMESTAMP,
+			fetch_started_at=NULL,
+			documentation_purged=FALSE,
 			-- back off exponentially until 1 hour, then at constant 1-hour intervals
 			next_processed_after=CASE
 				WHEN last_processed_at IS NULL THEN
@@ -216,6 +218,56 @@ func updateModulesStatus(ctx context.Context, db *database.DB, modulePath, resol
 	return nil
 }
 
+// StartModuleFetch records that a fetch of the module version has started.
+// The mark is cleared by UpdateModuleVersionState when the result of the
+// fetch is recorded. See GetStuckModules.
+func (db *DB) StartModuleFetch(ctx context.Context, modulePath, version string) (err error) {
+	defer derrors.WrapStack(&err, "StartModuleFetch(%q, %q)", modulePath, version)
+
+	_, err = db.db.Exec(ctx, `
+		UPDATE module_version_states
+		SET fetch_started_at = CURRENT_TIMESTAMP
+		WHERE module_path = $1 AND version = $2`,
+		modulePath, version)
+	return err
+}
+
+// ModuleFetchState describes a module version whose fetch has started.
+type ModuleFetchState struct {
+	ModulePath string
+	Version    string
+	// Status is the status of the previous fetch, or zero if there was none.
+	Status         int
+	AppVersion     string
+	FetchStartedAt time.Time
+}
+
+// GetStuckModules returns the module versions whose fetch started more than
+// since ago, and whose result was never recorded. That usually means the
+// worker fetching them crashed. The oldest fetches are first.
+func (db *DB) GetStuckModules(ctx context.Context, since time.Duration) (_ []ModuleFetchState, err error) {
+	defer derrors.WrapStack(&err, "GetStuckModules(ctx, %s)", since)
+
+	query := `
+		SELECT module_path, version, status, app_version, fetch_started_at
+		FROM module_version_states
+		WHERE fetch_started_at < CURRENT_TIMESTAMP - make_interval(secs => $1)
+		ORDER BY fetch_started_at, module_path, version`
+	var mfs []ModuleFetchState
+	err = db.db.RunQuery(ctx, query, func(rows *sql.Rows) error {
+		var s ModuleFetchState
+		if err := rows.Scan(&s.ModulePath, &s.Version, &s.Status, &s.AppVersion, &s.FetchStartedAt); err != nil {
+			return err
+		}
+		mfs = append(mfs, s)
+		return nil
+	}, since.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	return mfs, nil
+}
+
 // UpdateModuleVersionStatus updates the status and error fields of a module version.
 func (db *DB) UpdateModuleVersionStatus(ctx context.Context, modulePath, version string, status int, error string) (err error) {
 	defer derrors.WrapStack(&err, "UpdateModuleVersionStatus(%q, %q, %d)", modulePath, version, status)
@@ -295,7 +347,8 @@ const moduleVersionStateColumns = `
 			app_version,
 			has_go_mod,
 			go_mod_path,
-			num_packages`
+			num_packages,
+			documentation_purged`
 
 // scanModuleVersionState constructs an *internal.ModuleModuleVersionState from the given
 // scanner. It expects columns to be in the order of moduleVersionStateColumns.
@@ -309,7 +362,7 @@ func scanModuleVersionState(scan func(dest ...any) error) (*internal.ModuleVersi
 	)
 	if err := scan(&v.ModulePath, &v.Version, &indexTimestamp, &v.CreatedAt, &v.Status, &v.Error,
 		&v.TryCount, &v.LastProcessedAt, &v.NextProcessedAfter, &v.AppVersion, &hasGoMod, &v.GoModPath,
-		&numPackages); err != nil {
+		&numPackages, &v.DocumentationPurged); err != nil {
 		return nil, err
 	}
 	if indexTimestamp.Valid {
@@ -364,6 +364,46 @@ func TestUpdateModuleVersionStatus(t *testing.T) {
 	}
 }
 
+func TestGetStuckModules(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	testDB, release := acquire(t)
+	defer release()
+
+	var versions []*internal.IndexVersion
+	for _, p := range []string{"stuck.com", "recent.com", "done.com", "never.com"} {
+		versions = append(versions, &internal.IndexVersion{Path: p, Version: "v1.0.0", Timestamp: time.Now()})
+	}
+	must(t, testDB.InsertIndexVersions(ctx, versions))
+	for _, p := range []string{"stuck.com", "recent.com", "done.com"} {
+		must(t, testDB.StartModuleFetch(ctx, p, "v1.0.0"))
+	}
+	// Make the in-progress markers of stuck.com and done.com stale, then
+	// record a result for done.com.
+	if _, err := testDB.db.Exec(ctx, `
+		UPDATE module_version_states
+		SET fetch_started_at = CURRENT_TIMESTAMP - INTERVAL '2 hours'
+		WHERE module_path IN ('stuck.com', 'done.com')`); err != nil {
+		t.Fatal(err)
+	}
+	must(t, testDB.UpdateModuleVersionState(ctx, &ModuleVersionStateForUpdate{
+		ModulePath: "done.com",
+		Version:    "v1.0.0",
+		Status:     200,
+	}))
+
+	got, err := testDB.GetStuckModules(ctx, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].ModulePath != "stuck.com" || got[0].Version != "v1.0.0" {
+		t.Fatalf("got %+v, want only stuck.com@v1.0.0", got)
+	}
+	if age := time.Since(got[0].FetchStartedAt); age < time.Hour {
+		t.Errorf("FetchStartedAt is %s ago, want more than an hour", age)
+	}
+}
+
 func TestHasGoMod(t *testing.T) {
 	ptr := func(b bool) *bool { return &b }
 
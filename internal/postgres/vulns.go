@@ -0,0 +1,365 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/vuln"
+)
+
+// ReplaceVulns replaces the stored vulnerability entries with entries, which
+// should be the full contents of a vulnerability database. Entries that are
+// no longer in the database are removed, and the record of withdrawn entries
+// is cleared.
+func (db *DB) ReplaceVulns(ctx context.Context, entries []*osv.Entry) (err error) {
+	defer derrors.WrapStack(&err, "ReplaceVulns(ctx, %d entries)", len(entries))
+
+	values, err := vulnValues(entries)
+	if err != nil {
+		return err
+	}
+	return db.db.Transact(ctx, sql.LevelRepeatableRead, func(tx *database.DB) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM vulns`); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `DELETE FROM withdrawn_vulns`); err != nil {
+			return err
+		}
+		return tx.BulkInsert(ctx, "vulns", vulnColumns, values, "")
+	})
+}
+
+// UpsertVulns stores entries, replacing any stored entries with the same IDs.
+// Entries that were recorded as withdrawn are no longer.
+func (db *DB) UpsertVulns(ctx context.Context, entries []*osv.Entry) (err error) {
+	defer derrors.WrapStack(&err, "UpsertVulns(ctx, %d entries)", len(entries))
+
+	values, err := vulnValues(entries)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+	}
+	return db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM withdrawn_vulns WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+			return err
+		}
+		return tx.BulkUpsert(ctx, "vulns", vulnColumns, values, []string{"id"})
+	})
+}
+
+// WithdrawVulns removes the stored entries with the IDs of entries, which
+// should be withdrawn, and records their IDs and modification times, so that
+// they can be skipped until they are modified again.
+func (db *DB) WithdrawVulns(ctx context.Context, entries []*osv.Entry) (err error) {
+	defer derrors.WrapStack(&err, "WithdrawVulns(ctx, %d entries)", len(entries))
+
+	var (
+		ids    []string
+		values []any
+	)
+	for _, e := range entries {
+		ids = append(ids, e.ID)
+		values = append(values, e.ID, e.Modified)
+	}
+	return db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM vulns WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+			return err
+		}
+		return tx.BulkUpsert(ctx, "withdrawn_vulns", []string{"id", "modified"}, values, []string{"id"})
+	})
+}
+
+// DeleteVulns removes the stored entries with the given IDs, and any record
+// that they were withdrawn. IDs that are not stored are ignored.
+func (db *DB) DeleteVulns(ctx context.Context, ids []string) (err error) {
+	defer derrors.WrapStack(&err, "DeleteVulns(ctx, %d IDs)", len(ids))
+
+	return db.db.Transact(ctx, sql.LevelDefault, func(tx *database.DB) error {
+		if _, err := tx.Exec(ctx, `DELETE FROM vulns WHERE id = ANY($1)`, pq.Array(ids)); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM withdrawn_vulns WHERE id = ANY($1)`, pq.Array(ids))
+		return err
+	})
+}
+
+// GetVulnModified returns the modification time of each stored entry, keyed
+// by ID.
+func (db *DB) GetVulnModified(ctx context.Context) (_ map[string]time.Time, err error) {
+	defer derrors.WrapStack(&err, "GetVulnModified(ctx)")
+	return db.getVulnModified(ctx, `SELECT id, modified FROM vulns`)
+}
+
+// GetWithdrawnVulnModified returns the modification time of each entry that
+// was recorded as withdrawn by WithdrawVulns, keyed by ID.
+func (db *DB) GetWithdrawnVulnModified(ctx context.Context) (_ map[string]time.Time, err error) {
+	defer derrors.WrapStack(&err, "GetWithdrawnVulnModified(ctx)")
+	return db.getVulnModified(ctx, `SELECT id, modified FROM withdrawn_vulns`)
+}
+
+// getVulnModified returns the modification times selected by query, which
+// must select an ID and a time, keyed by the ID.
+func (db *DB) getVulnModified(ctx context.Context, query string) (map[string]time.Time, error) {
+	modified := map[string]time.Time{}
+	collect := func(rows *sql.Rows) error {
+		var (
+			id string
+			t  time.Time
+		)
+		if err := rows.Scan(&id, &t); err != nil {
+			return err
+		}
+		modified[id] = t
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect); err != nil {
+		return nil, err
+	}
+	return modified, nil
+}
+
+var vulnColumns = []string{"id", "modified", "module_paths", "entry"}
+
+// vulnValues returns the values of vulnColumns for entries, for bulk
+// insertion.
+func vulnValues(entries []*osv.Entry) ([]any, error) {
+	var values []any
+	for _, e := range entries {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		paths := map[string]bool{}
+		var modulePaths []string
+		for _, a := range e.Affected {
+			if !paths[a.Module.Path] {
+				paths[a.Module.Path] = true
+				modulePaths = append(modulePaths, a.Module.Path)
+			}
+		}
+		values = append(values, e.ID, e.Modified, pq.Array(modulePaths), data)
+	}
+	return values, nil
+}
+
+// getUnitVulns returns the stored vulnerabilities that affect the unit um.
+func (db *DB) getUnitVulns(ctx context.Context, um *internal.UnitMeta) (_ []*internal.UnitVuln, err error) {
+	defer derrors.WrapStack(&err, "getUnitVulns(ctx, %q, %q, %q)", um.Path, um.ModulePath, um.Version)
+	defer middleware.ElapsedStat(ctx, "getUnitVulns")()
+
+	var unitPath string
+	if um.IsPackage() {
+		unitPath = um.Path
+	}
+	osvPath := vuln.OSVModulePath(um.ModulePath, um.Version, unitPath)
+	if osvPath == "" {
+		return nil, nil
+	}
+	entries, err := db.getVulnEntries(ctx, `SELECT entry FROM vulns WHERE $1 = ANY(module_paths)`, osvPath)
+	if err != nil {
+		return nil, err
+	}
+	uvs := vuln.UnitVulns(entries, um.ModulePath, um.Version, unitPath)
+	if len(uvs) == 0 {
+		return nil, nil
+	}
+	versions, err := database.Collect1[string](ctx, db.db, `SELECT version FROM modules WHERE module_path = $1`, um.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+	byID := map[string]*osv.Entry{}
+	for _, e := range entries {
+		byID[e.ID] = e
+	}
+	for _, uv := range uvs {
+		uv.FixedVersion = vuln.FixedVersion(byID[uv.ID], um.ModulePath, um.Version, unitPath, versions)
+	}
+	return uvs, nil
+}
+
+// filterVulnerable returns the results whose versions are not affected by
+// any stored vulnerability.
+func (db *DB) filterVulnerable(ctx context.Context, results []*SearchResult) (_ []*SearchResult, err error) {
+	defer derrors.WrapStack(&err, "filterVulnerable(ctx, %d results)", len(results))
+	defer middleware.ElapsedStat(ctx, "filterVulnerable")()
+
+	seen := map[string]bool{}
+	var osvPaths []string
+	for _, r := range results {
+		p := vuln.OSVModulePath(r.ModulePath, r.Version, r.PackagePath)
+		if p != "" && !seen[p] {
+			seen[p] = true
+			osvPaths = append(osvPaths, p)
+		}
+	}
+	if len(osvPaths) == 0 {
+		return results, nil
+	}
+	entries, err := db.getVulnEntries(ctx, `SELECT entry FROM vulns WHERE module_paths && $1`, pq.Array(osvPaths))
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return results, nil
+	}
+	var filtered []*SearchResult
+	for _, r := range results {
+		if len(vuln.UnitVulns(entries, r.ModulePath, r.Version, r.PackagePath)) == 0 {
+			filtered = append(filtered, r)
+		}
+	}
+	return filtered, nil
+}
+
+// getVulnEntries returns the stored entries selected by query, which must
+// select only the entry column.
+func (db *DB) getVulnEntries(ctx context.Context, query string, args ...any) ([]*osv.Entry, error) {
+	var entries []*osv.Entry
+	collect := func(rows *sql.Rows) error {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		var e osv.Entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			return err
+		}
+		entries = append(entries, &e)
+		return nil
+	}
+	if err := db.db.RunQuery(ctx, query, collect, args...); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// A VulnImporter is a module whose latest version imports packages that are
+// affected by stored vulnerabilities.
+type VulnImporter struct {
+	ModulePath string
+	// Packages are the vulnerable packages that the module imports, sorted.
+	Packages []string
+	// IDs are the IDs of the vulnerabilities affecting those packages,
+	// sorted.
+	IDs []string
+}
+
+// GetVulnImporters returns the modules whose latest versions import a
+// package that is affected by some stored vulnerability, sorted by module
+// path. A vulnerability that does not list the affected packages of a module
+// affects all of its packages. Imports between the packages of an affected
+// module are ignored.
+//
+// The versions of the modules that are imported are not known, so the
+// importers are not necessarily affected themselves.
+func (db *DB) GetVulnImporters(ctx context.Context) (_ []*VulnImporter, err error) {
+	defer derrors.WrapStack(&err, "GetVulnImporters(ctx)")
+	defer middleware.ElapsedStat(ctx, "GetVulnImporters")()
+
+	entries, err := db.getVulnEntries(ctx, `SELECT entry FROM vulns`)
+	if err != nil {
+		return nil, err
+	}
+	type affected struct{ id, modulePath string }
+	var (
+		byPackage = map[string][]affected{} // affected packages
+		byModule  = map[string][]affected{} // modules with all packages affected
+		pkgPaths  []string
+		patterns  []string
+	)
+	escape := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	for _, e := range entries {
+		for _, a := range e.Affected {
+			mp := a.Module.Path
+			if len(a.EcosystemSpecific.Packages) == 0 {
+				if mp == osv.GoStdModulePath || mp == osv.GoCmdModulePath {
+					continue
+				}
+				if byModule[mp] == nil {
+					pkgPaths = append(pkgPaths, mp)
+					patterns = append(patterns, escape.Replace(mp)+"/%")
+				}
+				byModule[mp] = append(byModule[mp], affected{e.ID, mp})
+				continue
+			}
+			for _, p := range a.EcosystemSpecific.Packages {
+				if byPackage[p.Path] == nil {
+					pkgPaths = append(pkgPaths, p.Path)
+				}
+				byPackage[p.Path] = append(byPackage[p.Path], affected{e.ID, mp})
+			}
+		}
+	}
+	if len(pkgPaths) == 0 {
+		return nil, nil
+	}
+
+	importers := map[string]*VulnImporter{}
+	seen := map[string]bool{} // module path, package path and ID, joined
+	add := func(modulePath, pkgPath string, as []affected) {
+		for _, a := range as {
+			if a.modulePath == modulePath {
+				continue
+			}
+			vi := importers[modulePath]
+			if vi == nil {
+				vi = &VulnImporter{ModulePath: modulePath}
+				importers[modulePath] = vi
+			}
+			if k := modulePath + " " + pkgPath; !seen[k] {
+				seen[k] = true
+				vi.Packages = append(vi.Packages, pkgPath)
+			}
+			if k := modulePath + " " + a.id; !seen[k] {
+				seen[k] = true
+				vi.IDs = append(vi.IDs, a.id)
+			}
+		}
+	}
+	collect := func(rows *sql.Rows) error {
+		var fromModulePath, toPath string
+		if err := rows.Scan(&fromModulePath, &toPath); err != nil {
+			return err
+		}
+		add(fromModulePath, toPath, byPackage[toPath])
+		for mp, as := range byModule {
+			if toPath == mp || strings.HasPrefix(toPath, mp+"/") {
+				add(fromModulePath, toPath, as)
+			}
+		}
+		return nil
+	}
+	err = db.db.RunQuery(ctx, `
+		SELECT DISTINCT from_module_path, to_path
+		FROM imports_unique
+		WHERE to_path = ANY($1) OR to_path LIKE ANY($2)`,
+		collect, pq.Array(pkgPaths), pq.Array(patterns))
+	if err != nil {
+		return nil, err
+	}
+	var vis []*VulnImporter
+	for _, vi := range importers {
+		sort.Strings(vi.Packages)
+		sort.Strings(vi.IDs)
+		vis = append(vis, vi)
+	}
+	sort.Slice(vis, func(i, j int) bool { return vis[i].ModulePath < vis[j].ModulePath })
+	return vis, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetUnitVulns(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const modulePath = "example.com/vuln"
+	for _, v := range []string{"v1.0.0", "v1.1.0"} {
+		MustInsertModule(ctx, t, testDB, sample.Module(modulePath, v, "p"))
+	}
+	entries := []*osv.Entry{{
+		ID: "GO-2023-0001",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: modulePath},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeTypeSemver,
+				Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.1.0"}},
+			}},
+			EcosystemSpecific: osv.EcosystemSpecific{
+				Packages: []osv.Package{{Path: modulePath + "/p", Symbols: []string{"F"}}},
+			},
+		}},
+	}}
+	if err := testDB.ReplaceVulns(ctx, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	getVulns := func(version string) []*internal.UnitVuln {
+		t.Helper()
+		um, err := testDB.GetUnitMeta(ctx, modulePath+"/p", modulePath, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := testDB.GetUnit(ctx, um, internal.WithVulns, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u.Vulns
+	}
+	want := []*internal.UnitVuln{{ID: "GO-2023-0001", Versions: "before v1.1.0", Symbols: []string{"F"}, FixedVersion: "v1.1.0"}}
+	if diff := cmp.Diff(want, getVulns("v1.0.0")); diff != "" {
+		t.Errorf("v1.0.0: mismatch (-want, +got):\n%s", diff)
+	}
+	if got := getVulns("v1.1.0"); len(got) != 0 {
+		t.Errorf("v1.1.0: got %v, want no vulns", got)
+	}
+
+	// Replacing the entries removes the old ones.
+	if err := testDB.ReplaceVulns(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := getVulns("v1.0.0"); len(got) != 0 {
+		t.Errorf("after replace: got %v, want no vulns", got)
+	}
+}
+
+func TestGetVulnImporters(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const vulnPath = "example.com/vuln"
+	insert := func(modulePath, suffix string, imports ...string) {
+		t.Helper()
+		m := sample.Module(modulePath, "v1.0.0", suffix)
+		for _, u := range m.Units {
+			if u.IsPackage() {
+				u.Imports = imports
+				u.NumImports = len(imports)
+			}
+		}
+		MustInsertModule(ctx, t, testDB, m)
+	}
+	// The vulnerable module imports its own vulnerable package, which is
+	// not counted.
+	insert(vulnPath, "q", vulnPath+"/p")
+	insert("example.com/importer", "a", "fmt", vulnPath+"/p")
+	insert("example.com/nonimporter", "b", "fmt", vulnPath+"/q")
+
+	entries := []*osv.Entry{{
+		ID: "GO-2023-0001",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: vulnPath},
+			EcosystemSpecific: osv.EcosystemSpecific{
+				Packages: []osv.Package{{Path: vulnPath + "/p"}},
+			},
+		}},
+	}}
+	if err := testDB.ReplaceVulns(ctx, entries); err != nil {
+		t.Fatal(err)
+	}
+	got, err := testDB.GetVulnImporters(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*VulnImporter{{
+		ModulePath: "example.com/importer",
+		Packages:   []string{vulnPath + "/p"},
+		IDs:        []string{"GO-2023-0001"},
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// GetWorkspace returns the go.work information stored for the given module
+// version, or nil if the module version has no go.work file at its root.
+func (db *DB) GetWorkspace(ctx context.Context, modulePath, version string) (_ *internal.Workspace, err error) {
+	defer derrors.WrapStack(&err, "GetWorkspace(ctx, %q, %q)", modulePath, version)
+
+	var w internal.Workspace
+	err = db.db.QueryRow(ctx, `
+		SELECT w.go_version, w.dirs
+		FROM module_workspaces w
+		INNER JOIN modules m ON m.id = w.module_id
+		WHERE m.module_path = $1 AND m.version = $2`,
+		modulePath, version).Scan(&w.GoVersion, pq.Array(&w.Dirs))
+	switch err {
+	case sql.ErrNoRows:
+		return nil, nil
+	case nil:
+		return &w, nil
+	default:
+		return nil, err
+	}
+}
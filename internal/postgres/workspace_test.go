@@ -0,0 +1,43 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestGetWorkspace(t *testing.T) {
+	t.Parallel()
+	testDB, release := acquire(t)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	want := &internal.Workspace{GoVersion: "1.19", Dirs: []string{".", "api"}}
+	m := sample.Module("example.com/ws", "v1.0.0", "p")
+	m.Workspace = want
+	MustInsertModule(ctx, t, testDB, m)
+	MustInsertModule(ctx, t, testDB, sample.Module("example.com/ws", "v1.1.0", "p"))
+
+	got, err := testDB.GetWorkspace(ctx, "example.com/ws", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	got, err = testDB.GetWorkspace(ctx, "example.com/ws", "v1.1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != nil {
+		t.Errorf("v1.1.0: got %+v, want nil", got)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxy
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// Credentials authenticate requests to a proxy.
+//
+// Their String method does not reveal them, so they are safe to print by
+// accident, but they should never be logged deliberately.
+type Credentials struct {
+	// Token, if non-empty, is sent as a bearer token.
+	Token string
+	// Username and Password are sent with HTTP basic authentication if Token
+	// is empty.
+	Username, Password string
+}
+
+// String implements fmt.Stringer without revealing the credentials.
+func (c Credentials) String() string {
+	return "proxy.Credentials{REDACTED}"
+}
+
+// GoString implements fmt.GoStringer without revealing the credentials.
+func (c Credentials) GoString() string {
+	return c.String()
+}
+
+func (c Credentials) apply(req *http.Request) {
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	} else {
+		req.SetBasicAuth(c.Username, c.Password)
+	}
+}
+
+// WithCredentials returns a new client that authenticates its requests with
+// the credentials for their host. Hosts are keys of creds, either with or
+// without a port. Requests to other hosts are not authenticated.
+func (c *Client) WithCredentials(creds map[string]Credentials) *Client {
+	c2 := *c
+	c2.credentials = creds
+	return &c2
+}
+
+// authenticate adds the client's credentials for the host of req to req, if
+// there are any.
+func (c *Client) authenticate(req *http.Request) {
+	if cr, ok := c.credentials[req.URL.Host]; ok {
+		cr.apply(req)
+	} else if cr, ok := c.credentials[req.URL.Hostname()]; ok {
+		cr.apply(req)
+	}
+}
+
+// NewFromGOPROXYWithNetrc returns a client for goproxy, as NewFromGOPROXY
+// does, that authenticates its requests with the credentials in the netrc
+// file at netrcFile; see ReadNetrc. If netrcFile is empty, requests are not
+// authenticated.
+func NewFromGOPROXYWithNetrc(goproxy, netrcFile string) (*Client, error) {
+	c, err := NewFromGOPROXY(goproxy)
+	if err != nil {
+		return nil, err
+	}
+	if netrcFile == "" {
+		return c, nil
+	}
+	creds, err := ReadNetrc(netrcFile)
+	if err != nil {
+		return nil, err
+	}
+	return c.WithCredentials(creds), nil
+}
+
+// ReadNetrc reads credentials from the netrc file at path, in the format
+// used by the go command and curl. If path is empty, it reads the file named
+// by the NETRC environment variable, or else the .netrc file in the user's
+// home directory (_netrc on Windows). The "machine" entries of the file become
+// keys of the returned map. Their logins and passwords are used for basic
+// authentication.
+func ReadNetrc(path string) (_ map[string]Credentials, err error) {
+	// Don't include the file contents in errors.
+	defer derrors.Wrap(&err, "ReadNetrc(%q)", path)
+
+	if path == "" {
+		path, err = netrcPath()
+		if err != nil {
+			return nil, err
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseNetrc(string(data)), nil
+}
+
+func netrcPath() (string, error) {
+	if p := os.Getenv("NETRC"); p != "" {
+		return p, nil
+	}
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	name := ".netrc"
+	if runtime.GOOS == "windows" {
+		name = "_netrc"
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// parseNetrc parses netrc data, as in cmd/go/internal/auth.
+func parseNetrc(data string) map[string]Credentials {
+	creds := map[string]Credentials{}
+	var (
+		machine string
+		cred    Credentials
+		inMacro bool
+	)
+	add := func() {
+		if machine != "" && cred.Username != "" && cred.Password != "" {
+			if _, ok := creds[machine]; !ok { // the first entry for a machine wins
+				creds[machine] = cred
+			}
+		}
+		machine, cred = "", Credentials{}
+	}
+	for _, line := range strings.Split(data, "\n") {
+		if inMacro {
+			if line == "" {
+				inMacro = false
+			}
+			continue
+		}
+		f := strings.Fields(line)
+		for i := 0; i < len(f); i++ {
+			if f[i] == "default" {
+				// Credentials for any machine would be sent to every host,
+				// so ignore them.
+				add()
+				continue
+			}
+			if i+1 == len(f) {
+				break
+			}
+			key, value := f[i], f[i+1]
+			i++
+			switch key {
+			case "machine":
+				add()
+				machine = value
+			case "login":
+				cred.Username = value
+			case "password":
+				cred.Password = value
+			case "macdef":
+				// Macro definitions end with a blank line.
+				inMacro = true
+			}
+		}
+	}
+	add()
+	return creds
+}
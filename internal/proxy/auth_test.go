@@ -0,0 +1,116 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxy_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/proxy"
+	"golang.org/x/pkgsite/internal/testing/sample"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+)
+
+func TestCredentials(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.Header.Get("Authorization") == "Bearer secret-token"
+		user, password, ok := r.BasicAuth()
+		basic := ok && user == "gopher" && password == "secret-password"
+		if !token && !basic {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"Version": %q}`, sample.VersionString)
+	})
+	httpClient, _, teardown := testhelper.SetupTestClientAndServer(handler)
+	defer teardown()
+
+	netrc := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(netrc, []byte("machine proxy.test login gopher password secret-password\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fromNetrc, err := proxy.ReadNetrc(netrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		creds   map[string]proxy.Credentials
+		wantErr bool
+	}{
+		{"none", nil, true},
+		{"token", map[string]proxy.Credentials{"proxy.test": {Token: "secret-token"}}, false},
+		{"netrc", fromNetrc, false},
+		{"wrong token", map[string]proxy.Credentials{"proxy.test": {Token: "guess"}}, true},
+		{"other host", map[string]proxy.Credentials{"other.test": {Token: "secret-token"}}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := proxy.New("https://proxy.test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.HTTPClient = httpClient
+			client = client.WithCredentials(test.creds)
+			_, err = client.Info(ctx, sample.ModulePath, sample.VersionString)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("Info: got error %v, want error: %t", err, test.wantErr)
+			}
+		})
+	}
+
+	client, err := proxy.NewFromGOPROXYWithNetrc("https://proxy.test", netrc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HTTPClient = httpClient
+	if _, err := client.Info(ctx, sample.ModulePath, sample.VersionString); err != nil {
+		t.Errorf("NewFromGOPROXYWithNetrc: Info: %v", err)
+	}
+}
+
+func TestReadNetrc(t *testing.T) {
+	const data = `
+machine proxy.test login gopher password p1
+machine other.test:8080
+	login other
+	password p2
+macdef init
+machine macro.test login m password p3
+
+machine proxy.test login second password p4
+default login anyone password p5
+machine nopassword.test login x
+`
+	file := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(file, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	got, err := proxy.ReadNetrc(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]proxy.Credentials{
+		"proxy.test":      {Username: "gopher", Password: "p1"},
+		"other.test:8080": {Username: "other", Password: "p2"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	for _, s := range []string{fmt.Sprint(got), fmt.Sprintf("%+v", got), fmt.Sprintf("%#v", got)} {
+		if strings.Contains(s, "p1") || strings.Contains(s, "gopher") {
+			t.Errorf("printed credentials are not redacted: %s", s)
+		}
+	}
+}
@@ -11,7 +11,7 @@ import (
 	"golang.org/x/pkgsite/internal"
 )
 
-// cache caches proxy info, mod and zip calls.
+// cache caches proxy info, mod, zip and list calls.
 type cache struct {
 	mu sync.Mutex
 
@@ -10,19 +10,26 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"strings"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru"
 	"go.opencensus.io/plugin/ochttp"
 	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
 	"golang.org/x/net/context/ctxhttp"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/tlsconfig"
 	"golang.org/x/pkgsite/internal/version"
+	"golang.org/x/sync/errgroup"
 )
 
 // A Client is used by the fetch service to communicate with a module
@@ -31,6 +38,13 @@ type Client struct {
 	// URL of the module proxy web server
 	url string
 
+	// Proxies to try, in order, when a request to the one before fails.
+	fallbacks []fallback
+
+	// If non-nil, direct reports whether a request that failed on all
+	// proxies should be retried directly from the module's repository.
+	direct func(err error) bool
+
 	// Client used for HTTP requests. It is mutable for testing purposes.
 	HTTPClient *http.Client
 
@@ -38,12 +52,33 @@ type Client struct {
 	disableFetch bool
 
 	cache *cache
+
+	// The responses to list requests, with their ETags, by versionListKey.
+	// Unlike cache, it lives as long as the Client and is shared by all
+	// copies of it.
+	versionLists *lru.Cache
+
+	// Credentials for requests, by host.
+	credentials map[string]Credentials
 }
 
 // A VersionInfo contains metadata about a given version of a module.
 type VersionInfo struct {
 	Version string
 	Time    time.Time
+	// Origin describes the repository that the proxy got the module version
+	// from. It is nil if the proxy does not say.
+	Origin *Origin `json:",omitempty"`
+}
+
+// Origin describes the version-control origin of a module version, as
+// reported by the go command and by proxies that serve its output.
+type Origin struct {
+	VCS    string `json:",omitempty"` // version control system, like "git"
+	URL    string `json:",omitempty"` // URL of the repository
+	Subdir string `json:",omitempty"` // subdirectory of the module in the repository
+	Ref    string `json:",omitempty"` // tag or branch that was resolved, if any
+	Hash   string `json:",omitempty"` // commit hash
 }
 
 // Setting this header to true prevents the proxy from fetching uncached
@@ -54,13 +89,61 @@ const DisableFetchHeader = "Disable-Module-Fetch"
 // be an absolute URI that can be directly passed to http.Get.
 func New(u string) (_ *Client, err error) {
 	defer derrors.WrapStack(&err, "proxy.New(%q)", u)
+	versionLists, err := lru.New(maxVersionLists)
+	if err != nil {
+		return nil, err
+	}
 	return &Client{
 		url:          strings.TrimRight(u, "/"),
 		HTTPClient:   &http.Client{Transport: &ochttp.Transport{}},
 		disableFetch: false,
+		versionLists: versionLists,
 	}, nil
 }
 
+// maxVersionLists is the number of version lists that a Client caches.
+const maxVersionLists = 10000
+
+// NewClientWithFallback constructs a *Client like New, except that if a
+// request made by Info, Mod, Zip, ZipSize or Versions to the primary proxy
+// fails with a 5xx status or times out, the request is retried on the
+// secondary proxy. Other errors, like 404 Not Found, are treated as
+// authoritative and returned without trying the secondary.
+func NewClientWithFallback(primary, secondary string) (_ *Client, err error) {
+	defer derrors.WrapStack(&err, "proxy.NewClientWithFallback(%q, %q)", primary, secondary)
+	c, err := New(primary)
+	if err != nil {
+		return nil, err
+	}
+	c.fallbacks = []fallback{{
+		url:       strings.TrimRight(secondary, "/"),
+		shouldTry: isServerErrorOrTimeout,
+	}}
+	return c, nil
+}
+
+// A fallback is a proxy to try after a request to the previous one fails.
+type fallback struct {
+	url string
+	// shouldTry reports whether the request should be retried on this proxy
+	// after failing on the previous one with err.
+	shouldTry func(err error) bool
+}
+
+// tryProxies calls request with the URL of each of c's proxies in turn, until
+// it succeeds or a fallback proxy should not be tried for the error.
+// Nothing is retried once ctx is done.
+func (c *Client) tryProxies(ctx context.Context, request func(base string) error) error {
+	err := request(c.url)
+	for _, f := range c.fallbacks {
+		if err == nil || ctx.Err() != nil || !f.shouldTry(err) {
+			break
+		}
+		err = request(f.url)
+	}
+	return err
+}
+
 // WithFetchDisabled returns a new client that sets the Disable-Module-Fetch
 // header so that the proxy does not fetch a module it doesn't already know
 // about.
@@ -75,6 +158,16 @@ func (c *Client) FetchDisabled() bool {
 	return c.disableFetch
 }
 
+// WithTLSConfig returns a new client that uses the TLS configuration in
+// configs for requests to the hosts that are its keys, as described by
+// tlsconfig.Client. The new client's HTTPClient is a copy of the client's,
+// with the same timeout and a transport that wraps the client's.
+func (c *Client) WithTLSConfig(configs map[string]*tls.Config) *Client {
+	c2 := *c
+	c2.HTTPClient = tlsconfig.Client(c.HTTPClient, configs)
+	return &c2
+}
+
 // WithCache returns a new client that caches some RPCs.
 func (c *Client) WithCache() *Client {
 	c2 := *c
@@ -113,6 +206,26 @@ func (c *Client) Info(ctx context.Context, modulePath, requestedVersion string)
 	return &v, nil
 }
 
+// ResolveBranch returns the version that the proxy resolves the given branch
+// of modulePath to. That is a pseudo-version for the commit at the head of the
+// branch, unless the commit is tagged with a release version. The version can
+// then be fetched like any other.
+//
+// It returns an error wrapping derrors.InvalidArgument if branch is a
+// semantic version or "latest".
+func (c *Client) ResolveBranch(ctx context.Context, modulePath, branch string) (_ string, err error) {
+	defer derrors.Wrap(&err, "proxy.Client.ResolveBranch(%q, %q)", modulePath, branch)
+
+	if branch == "" || branch == version.Latest || semver.IsValid(branch) {
+		return "", fmt.Errorf("%q is not a branch: %w", branch, derrors.InvalidArgument)
+	}
+	info, err := c.Info(ctx, modulePath, branch)
+	if err != nil {
+		return "", err
+	}
+	return info.Version, nil
+}
+
 // Mod makes a request to $GOPROXY/<module>/@v/<resolvedVersion>.mod and returns the raw data.
 func (c *Client) Mod(ctx context.Context, modulePath, resolvedVersion string) (_ []byte, err error) {
 	defer derrors.WrapStack(&err, "proxy.Client.Mod(%q, %q)", modulePath, resolvedVersion)
@@ -156,12 +269,40 @@ func (c *Client) Zip(ctx context.Context, modulePath, resolvedVersion string) (_
 func (c *Client) ZipSize(ctx context.Context, modulePath, resolvedVersion string) (_ int64, err error) {
 	defer derrors.WrapStack(&err, "proxy.Client.ZipSize(ctx, %q, %q)", modulePath, resolvedVersion)
 
-	url, err := c.EscapedURL(modulePath, resolvedVersion, "zip")
+	var size int64
+	err = c.tryProxies(ctx, func(base string) error {
+		url, err := escapedURL(base, modulePath, resolvedVersion, "zip")
+		if err != nil {
+			return err
+		}
+		size, err = c.headContentLength(ctx, url)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
+
+// headContentLength makes a HEAD request for url and returns the length of
+// the content.
+func (c *Client) headContentLength(ctx context.Context, url string) (_ int64, err error) {
+	defer func() {
+		if ctx.Err() != nil {
+			err = fmt.Errorf("%v: %w", err, derrors.ProxyTimedOut)
+		}
+	}()
+
+	req, err := http.NewRequest("HEAD", url, nil)
 	if err != nil {
 		return 0, err
 	}
-	res, err := ctxhttp.Head(ctx, c.HTTPClient, url)
+	c.authenticate(req)
+	res, err := ctxhttp.Do(ctx, c.HTTPClient, req)
 	if err != nil {
+		if os.IsTimeout(err) && ctx.Err() == nil {
+			return 0, fmt.Errorf("ctxhttp.Head(ctx, client, %q): %v: %w", url, err, derrors.ProxyTimedOut)
+		}
 		return 0, fmt.Errorf("ctxhttp.Head(ctx, client, %q): %v", url, err)
 	}
 	defer res.Body.Close()
@@ -176,7 +317,12 @@ func (c *Client) ZipSize(ctx context.Context, modulePath, resolvedVersion string
 
 func (c *Client) EscapedURL(modulePath, requestedVersion, suffix string) (_ string, err error) {
 	defer derrors.WrapStack(&err, "Client.escapedURL(%q, %q, %q)", modulePath, requestedVersion, suffix)
+	return escapedURL(c.url, modulePath, requestedVersion, suffix)
+}
 
+// escapedURL returns the URL on the proxy at base for the given module
+// version and suffix.
+func escapedURL(base, modulePath, requestedVersion, suffix string) (string, error) {
 	if suffix != "info" && suffix != "mod" && suffix != "zip" {
 		return "", errors.New(`suffix must be "info", "mod" or "zip"`)
 	}
@@ -188,27 +334,29 @@ func (c *Client) EscapedURL(modulePath, requestedVersion, suffix string) (_ stri
 		if suffix != "info" {
 			return "", fmt.Errorf("cannot ask for latest with suffix %q", suffix)
 		}
-		return fmt.Sprintf("%s/%s/@latest", c.url, escapedPath), nil
+		return fmt.Sprintf("%s/%s/@latest", base, escapedPath), nil
 	}
 	escapedVersion, err := module.EscapeVersion(requestedVersion)
 	if err != nil {
 		return "", fmt.Errorf("version: %v: %w", err, derrors.InvalidArgument)
 	}
-	return fmt.Sprintf("%s/%s/@v/%s.%s", c.url, escapedPath, escapedVersion, suffix), nil
+	return fmt.Sprintf("%s/%s/@v/%s.%s", base, escapedPath, escapedVersion, suffix), nil
 }
 
 func (c *Client) readBody(ctx context.Context, modulePath, requestedVersion, suffix string) (_ []byte, err error) {
 	defer derrors.WrapStack(&err, "Client.readBody(%q, %q, %q)", modulePath, requestedVersion, suffix)
 
-	u, err := c.EscapedURL(modulePath, requestedVersion, suffix)
-	if err != nil {
-		return nil, err
-	}
 	var data []byte
-	err = c.executeRequest(ctx, u, func(body io.Reader) error {
-		var err error
-		data, err = io.ReadAll(body)
-		return err
+	err = c.tryProxies(ctx, func(base string) error {
+		u, err := escapedURL(base, modulePath, requestedVersion, suffix)
+		if err != nil {
+			return err
+		}
+		return c.executeRequest(ctx, u, func(body io.Reader) error {
+			var err error
+			data, err = io.ReadAll(body)
+			return err
+		})
 	})
 	if err != nil {
 		return nil, err
@@ -216,32 +364,132 @@ func (c *Client) readBody(ctx context.Context, modulePath, requestedVersion, suf
 	return data, nil
 }
 
+// isServerErrorOrTimeout reports whether err is a 5xx response from a proxy
+// or a timeout. Other errors, like 404 Not Found, are authoritative.
+func isServerErrorOrTimeout(err error) bool {
+	return errors.Is(err, derrors.ProxyError) || errors.Is(err, derrors.ProxyTimedOut)
+}
+
 // Versions makes a request to $GOPROXY/<path>/@v/list and returns the
 // resulting version strings.
+//
+// The list from each proxy is cached along with its ETag, and later requests
+// to the same proxy for it are conditional. When the proxy responds that the
+// list has not changed, the cached list is returned.
 func (c *Client) Versions(ctx context.Context, modulePath string) (_ []string, err error) {
 	defer derrors.Wrap(&err, "Versions(ctx, %q)", modulePath)
 	escapedPath, err := module.EscapePath(modulePath)
 	if err != nil {
 		return nil, fmt.Errorf("module.EscapePath(%q): %w", modulePath, derrors.InvalidArgument)
 	}
-	u := fmt.Sprintf("%s/%s/@v/list", c.url, escapedPath)
 	var versions []string
-	collect := func(body io.Reader) error {
-		scanner := bufio.NewScanner(body)
-		for scanner.Scan() {
-			versions = append(versions, scanner.Text())
+	err = c.tryProxies(ctx, func(base string) error {
+		key := versionListKey{base, modulePath}
+		var cached *versionList
+		header := http.Header{}
+		if v, ok := c.versionLists.Get(key); ok {
+			cached = v.(*versionList)
+			header.Set("If-None-Match", cached.etag)
 		}
-		return scanner.Err()
-	}
-	if err := c.executeRequest(ctx, u, collect); err != nil {
+		err := c.executeRequestWithHeader(ctx, fmt.Sprintf("%s/%s/@v/list", base, escapedPath), header, func(r *http.Response) error {
+			versions = nil
+			scanner := bufio.NewScanner(r.Body)
+			for scanner.Scan() {
+				versions = append(versions, scanner.Text())
+			}
+			if err := scanner.Err(); err != nil {
+				return err
+			}
+			if etag := r.Header.Get("ETag"); etag != "" {
+				// Cache a copy, so that callers can't modify the cached list.
+				c.versionLists.Add(key, &versionList{etag: etag, versions: append([]string(nil), versions...)})
+			}
+			return nil
+		})
+		if cached != nil && errors.Is(err, errNotModified) {
+			versions = append([]string(nil), cached.versions...)
+			return nil
+		}
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
 	return versions, nil
 }
 
+// A versionListKey identifies the list of versions of a module on a proxy.
+type versionListKey struct {
+	proxyURL   string
+	modulePath string
+}
+
+// A versionList is the response to a list request, along with its ETag.
+type versionList struct {
+	etag     string
+	versions []string
+}
+
+// maxConcurrentInfoRequests is the maximum number of info requests that
+// VersionsPublishedBetween makes at once.
+const maxConcurrentInfoRequests = 10
+
+// VersionsPublishedBetween returns the versions of the module that were
+// published at or after start and before end, according to the Time of
+// their info, in the order of Versions. A zero end means no upper bound.
+// It lets a backfill ingest only recent releases of a module.
+//
+// The info of the versions is requested concurrently. A version whose info
+// can't be retrieved is logged and skipped, so that one bad version doesn't
+// prevent the others from being ingested.
+func (c *Client) VersionsPublishedBetween(ctx context.Context, modulePath string, start, end time.Time) (_ []string, err error) {
+	defer derrors.Wrap(&err, "VersionsPublishedBetween(ctx, %q, %s, %s)", modulePath, start, end)
+
+	versions, err := c.Versions(ctx, modulePath)
+	if err != nil {
+		return nil, err
+	}
+	// Each goroutine writes only its own element, so no locking is needed.
+	published := make([]bool, len(versions))
+	var g errgroup.Group
+	g.SetLimit(maxConcurrentInfoRequests)
+	for i, v := range versions {
+		i, v := i, v
+		g.Go(func() error {
+			info, err := c.Info(ctx, modulePath, v)
+			if err != nil {
+				log.Warningf(ctx, "VersionsPublishedBetween: skipping %s@%s: %v", modulePath, v, err)
+				return nil
+			}
+			published[i] = !info.Time.Before(start) && (end.IsZero() || info.Time.Before(end))
+			return nil
+		})
+	}
+	_ = g.Wait() // the goroutines never return an error
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var inWindow []string
+	for i, v := range versions {
+		if published[i] {
+			inWindow = append(inWindow, v)
+		}
+	}
+	return inWindow, nil
+}
+
 // executeRequest executes an HTTP GET request for u, then calls the bodyFunc
 // on the response body, if no error occurred.
-func (c *Client) executeRequest(ctx context.Context, u string, bodyFunc func(body io.Reader) error) (err error) {
+func (c *Client) executeRequest(ctx context.Context, u string, bodyFunc func(body io.Reader) error) error {
+	return c.executeRequestWithHeader(ctx, u, nil, func(r *http.Response) error {
+		return bodyFunc(r.Body)
+	})
+}
+
+// executeRequestWithHeader executes an HTTP GET request for u with the
+// given additional header, then calls responseFunc on the response, if no
+// error occurred.
+func (c *Client) executeRequestWithHeader(ctx context.Context, u string, header http.Header, responseFunc func(*http.Response) error) (err error) {
 	defer func() {
 		if ctx.Err() != nil {
 			err = fmt.Errorf("%v: %w", err, derrors.ProxyTimedOut)
@@ -253,31 +501,46 @@ func (c *Client) executeRequest(ctx context.Context, u string, bodyFunc func(bod
 	if err != nil {
 		return err
 	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
 	if c.disableFetch {
 		req.Header.Set(DisableFetchHeader, "true")
 	}
+	c.authenticate(req)
 	r, err := ctxhttp.Do(ctx, c.HTTPClient, req)
 	if err != nil {
+		if os.IsTimeout(err) && ctx.Err() == nil {
+			// The HTTP client's own timeout expired.
+			return fmt.Errorf("ctxhttp.Do(ctx, client, %q): %v: %w", u, err, derrors.ProxyTimedOut)
+		}
 		return fmt.Errorf("ctxhttp.Do(ctx, client, %q): %v", u, err)
 	}
 	defer r.Body.Close()
 	if err := responseError(r, c.disableFetch); err != nil {
 		return err
 	}
-	return bodyFunc(r.Body)
+	return responseFunc(r)
 }
 
+// errNotModified is returned by executeRequestWithHeader when the proxy
+// responds to a conditional request with 304 Not Modified.
+var errNotModified = errors.New("not modified")
+
 // responseError translates the response status code to an appropriate error.
 func responseError(r *http.Response, fetchDisabled bool) error {
 	switch {
 	case 200 <= r.StatusCode && r.StatusCode < 300:
 		return nil
+	case r.StatusCode == http.StatusNotModified:
+		return errNotModified
 	case 500 <= r.StatusCode:
 		return derrors.ProxyError
 	case r.StatusCode == http.StatusNotFound,
 		r.StatusCode == http.StatusGone:
 		// Treat both 404 Not Found and 410 Gone responses
-		// from the proxy as a "not found" error category.
+		// from the proxy as a "not found" error category; the error of a
+		// 410 is ProxyGone, which wraps NotFound.
 		// If the response body contains "fetch timed out", treat this
 		// as a 504 response so that we retry fetching the module version again
 		// later.
@@ -294,6 +557,8 @@ func responseError(r *http.Response, fetchDisabled bool) error {
 			err = derrors.ProxyTimedOut
 		case fetchDisabled:
 			err = derrors.NotFetched
+		case r.StatusCode == http.StatusGone:
+			err = derrors.ProxyGone
 		default:
 			err = derrors.NotFound
 		}
@@ -6,9 +6,13 @@ package proxy_test
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -128,6 +132,143 @@ func TestListVersions(t *testing.T) {
 	}
 }
 
+func TestListVersionsETag(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const etag = `"v1"`
+	var requests, notModified int
+	proxyServer := proxytest.NewServer(nil)
+	proxyServer.AddRoute("/example.com/etag/@v/list", func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		fmt.Fprint(w, "v1.0.0\nv1.1.0\n")
+	})
+	client, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+
+	want := []string{"v1.0.0", "v1.1.0"}
+	for i := 0; i < 2; i++ {
+		// The list is cached by the client, not by the per-fetch copies
+		// that WithCache returns.
+		got, err := client.WithCache().Versions(ctx, "example.com/etag")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Versions #%d mismatch (-want, +got):\n%s", i+1, diff)
+		}
+		// Modifying the result must not change the cached list.
+		got[0] = "modified"
+	}
+	if requests != 2 || notModified != 1 {
+		t.Errorf("got %d requests with %d not modified, want 2 with 1", requests, notModified)
+	}
+}
+
+func TestListVersionsETagFallback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// The primary proxy serves the list once, then fails. The ETag it
+	// returned must not be sent to the secondary.
+	var primaryRequests int
+	proxyServer := proxytest.NewServer(nil)
+	proxyServer.AddRoute("/primary/example.com/etag/@v/list", func(w http.ResponseWriter, r *http.Request) {
+		primaryRequests++
+		if primaryRequests > 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("ETag", `"primary"`)
+		fmt.Fprint(w, "v1.0.0\n")
+	})
+	proxyServer.AddRoute("/example.com/etag/@v/list", func(w http.ResponseWriter, r *http.Request) {
+		if inm := r.Header.Get("If-None-Match"); inm != "" {
+			t.Errorf("secondary got If-None-Match %s", inm)
+		}
+		fmt.Fprint(w, "v1.0.0\nv1.1.0\n")
+	})
+	testClient, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+	client, err := proxy.NewClientWithFallback("https://proxy.test/primary", "https://proxy.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.HTTPClient = testClient.HTTPClient
+
+	for i, want := range [][]string{{"v1.0.0"}, {"v1.0.0", "v1.1.0"}} {
+		got, err := client.Versions(ctx, "example.com/etag")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("Versions #%d mismatch (-want, +got):\n%s", i+1, diff)
+		}
+	}
+}
+
+func TestVersionsPublishedBetween(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const modulePath = "example.com/releases"
+	day := func(d int) time.Time { return time.Date(2023, time.March, d, 0, 0, 0, 0, time.UTC) }
+	published := map[string]time.Time{
+		"v1.0.0": day(1),
+		"v1.1.0": day(10),
+		"v1.2.0": day(15),
+		"v1.3.0": day(20),
+	}
+	proxyServer := proxytest.NewServer(nil)
+	proxyServer.AddRoute("/"+modulePath+"/@v/list", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "v1.0.0\nv1.1.0\nv1.1.1\nv1.2.0\nv1.3.0\n")
+	})
+	// The info of v1.1.1 can't be retrieved, so it is skipped.
+	proxyServer.AddRoute("/"+modulePath+"/@v/v1.1.1.info", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal error", http.StatusInternalServerError)
+	})
+	for v, tm := range published {
+		v, tm := v, tm
+		proxyServer.AddRoute(fmt.Sprintf("/%s/@v/%s.info", modulePath, v), func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"Version": %q, "Time": %q}`, v, tm.Format(time.RFC3339))
+		})
+	}
+	client, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+
+	for _, test := range []struct {
+		start, end time.Time
+		want       []string
+	}{
+		{day(10), day(20), []string{"v1.1.0", "v1.2.0"}},
+		{day(2), time.Time{}, []string{"v1.1.0", "v1.2.0", "v1.3.0"}},
+		{day(21), day(30), nil},
+	} {
+		got, err := client.VersionsPublishedBetween(ctx, modulePath, test.start, test.end)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("[%s, %s) mismatch (-want, +got):\n%s", test.start, test.end, diff)
+		}
+	}
+}
+
 func TestInfo(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
 	defer cancel()
@@ -162,6 +303,36 @@ func TestInfo(t *testing.T) {
 	}
 }
 
+func TestResolveBranch(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	const pseudo = "v0.0.0-20230102030405-0123456789ab"
+	client, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{{
+		ModulePath: "example.com/branch",
+		Version:    pseudo,
+		Files:      map[string]string{"a.go": "package a"},
+	}})
+	defer teardownProxy()
+
+	got, err := client.ResolveBranch(ctx, "example.com/branch", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != pseudo {
+		t.Errorf("got %q, want %q", got, pseudo)
+	}
+	if _, err := client.Zip(ctx, "example.com/branch", got); err != nil {
+		t.Errorf("fetching resolved version: %v", err)
+	}
+
+	for _, branch := range []string{"", version.Latest, "v1.0.0"} {
+		if _, err := client.ResolveBranch(ctx, "example.com/branch", branch); !errors.Is(err, derrors.InvalidArgument) {
+			t.Errorf("ResolveBranch(%q): got %v, want InvalidArgument", branch, err)
+		}
+	}
+}
+
 func TestInfo_Errors(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
 	defer cancel()
@@ -195,6 +366,83 @@ func TestInfo_Errors(t *testing.T) {
 	}
 }
 
+func TestClientWithFallback(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// The test HTTP client sends every request to the same server, so the
+	// primary and secondary proxies are distinguished by path prefix.
+	proxyServer := proxytest.NewServer([]*proxytest.Module{testModule})
+	proxyServer.AddRoute("/unavailable/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	proxyServer.AddRoute("/notfound/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	testClient, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+	newClient := func(primary string) *proxy.Client {
+		t.Helper()
+		c, err := proxy.NewClientWithFallback("https://proxy.test/"+primary, "https://proxy.test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.HTTPClient = testClient.HTTPClient
+		return c
+	}
+
+	client := newClient("unavailable")
+	if _, err := client.Info(ctx, sample.ModulePath, sample.VersionString); err != nil {
+		t.Errorf("Info: %v", err)
+	}
+	if _, err := client.Mod(ctx, sample.ModulePath, sample.VersionString); err != nil {
+		t.Errorf("Mod: %v", err)
+	}
+	if _, err := client.Zip(ctx, sample.ModulePath, sample.VersionString); err != nil {
+		t.Errorf("Zip: %v", err)
+	}
+	if _, err := client.ZipSize(ctx, sample.ModulePath, sample.VersionString); err != nil {
+		t.Errorf("ZipSize: %v", err)
+	}
+
+	// A 4xx response from the primary is authoritative.
+	client = newClient("notfound")
+	if _, err := client.Info(ctx, sample.ModulePath, sample.VersionString); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("Info from primary returning 404: got %v, want NotFound", err)
+	}
+}
+
+func TestClientWithTLSConfig(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"Version": %q}`, sample.VersionString)
+	}))
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(s.Certificate())
+
+	client, err := proxy.New(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Info(ctx, sample.ModulePath, sample.VersionString); err == nil {
+		t.Error("Info without the server's CA: got nil error")
+	}
+	client = client.WithTLSConfig(map[string]*tls.Config{u.Host: {RootCAs: pool}})
+	if _, err := client.Info(ctx, sample.ModulePath, sample.VersionString); err != nil {
+		t.Errorf("Info with the server's CA: %v", err)
+	}
+}
+
 func TestMod(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
 	defer cancel()
@@ -0,0 +1,101 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxy
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// NewFromGOPROXY constructs a *Client from a list of proxy URLs in the format
+// of the GOPROXY environment variable, as described by "go help
+// module-auth". The proxies are tried in order. After a proxy URL followed by
+// a comma, the next one is tried only if the request fails with 404 Not Found
+// or 410 Gone. After a proxy URL followed by a pipe, the next one is tried
+// after any error.
+//
+// The keyword "direct" may end the list, after at least one proxy URL. A
+// Client can only talk to proxies, so it doesn't fetch modules directly
+// itself, but FallsBackToDirect reports whether a request that failed on all
+// the proxies should be retried directly from the module's repository, as by
+// a fetch.ModuleGetter for version control systems. The keyword "off" is
+// rejected.
+func NewFromGOPROXY(goproxy string) (_ *Client, err error) {
+	defer derrors.WrapStack(&err, "proxy.NewFromGOPROXY(%q)", goproxy)
+
+	var (
+		urls      []string
+		fallsBack []func(error) bool // when to try urls[i+1] after urls[i] fails
+		direct    bool
+	)
+	for goproxy != "" {
+		u, sep := goproxy, byte(0)
+		if i := strings.IndexAny(goproxy, ",|"); i >= 0 {
+			u, sep = goproxy[:i], goproxy[i]
+			goproxy = goproxy[i+1:]
+		} else {
+			goproxy = ""
+		}
+		u = strings.TrimSpace(u)
+		if u == "" {
+			continue
+		}
+		if direct {
+			return nil, fmt.Errorf("%q follows direct: %w", u, derrors.InvalidArgument)
+		}
+		switch u {
+		case "off":
+			return nil, fmt.Errorf("%q is not supported: %w", u, derrors.InvalidArgument)
+		case "direct":
+			if len(urls) == 0 {
+				return nil, fmt.Errorf("direct without a proxy is not supported: %w", derrors.InvalidArgument)
+			}
+			direct = true
+			continue
+		}
+		urls = append(urls, u)
+		if sep == '|' {
+			fallsBack = append(fallsBack, isAnyError)
+		} else {
+			fallsBack = append(fallsBack, isNotFound)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, fmt.Errorf("no proxy URLs: %w", derrors.InvalidArgument)
+	}
+	c, err := New(urls[0])
+	if err != nil {
+		return nil, err
+	}
+	for i, u := range urls[1:] {
+		c.fallbacks = append(c.fallbacks, fallback{
+			url:       strings.TrimRight(u, "/"),
+			shouldTry: fallsBack[i],
+		})
+	}
+	if direct {
+		c.direct = fallsBack[len(urls)-1]
+	}
+	return c, nil
+}
+
+// FallsBackToDirect reports whether a request that failed on all of c's
+// proxies with err should be retried directly from the module's repository,
+// because the list of proxies that c was constructed from ended with
+// "direct".
+func (c *Client) FallsBackToDirect(err error) bool {
+	return c.direct != nil && err != nil && c.direct(err)
+}
+
+// isNotFound reports whether err is a 404 Not Found or 410 Gone response from
+// a proxy.
+func isNotFound(err error) bool {
+	return errors.Is(err, derrors.NotFound) || errors.Is(err, derrors.ProxyGone) || errors.Is(err, derrors.NotFetched)
+}
+
+func isAnyError(error) bool { return true }
@@ -0,0 +1,114 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package proxy_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/proxy"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/testing/sample"
+)
+
+func TestNewFromGOPROXY(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	// The test HTTP client sends every request to the same server, so the
+	// proxies are distinguished by path prefix.
+	proxyServer := proxytest.NewServer([]*proxytest.Module{testModule})
+	proxyServer.AddRoute("/unavailable/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+	})
+	proxyServer.AddRoute("/notfound/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+	proxyServer.AddRoute("/gone/", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	})
+	testClient, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+
+	for _, test := range []struct {
+		goproxy string
+		want    error // nil means success
+	}{
+		{"https://proxy.test", nil},
+		{"https://proxy.test/notfound,https://proxy.test", nil},
+		{"https://proxy.test/notfound,https://proxy.test/gone,https://proxy.test", nil},
+		{"https://proxy.test/unavailable,https://proxy.test", derrors.ProxyError},
+		{"https://proxy.test/unavailable|https://proxy.test", nil},
+		{"https://proxy.test/notfound|https://proxy.test", nil},
+		{"https://proxy.test/notfound,https://proxy.test/unavailable|https://proxy.test", nil},
+		{"https://proxy.test/notfound,https://proxy.test/unavailable,https://proxy.test", derrors.ProxyError},
+		{"https://proxy.test/notfound,https://proxy.test/gone", derrors.NotFound},
+		{"https://proxy.test/gone", derrors.ProxyGone},
+	} {
+		t.Run(test.goproxy, func(t *testing.T) {
+			client, err := proxy.NewFromGOPROXY(test.goproxy)
+			if err != nil {
+				t.Fatal(err)
+			}
+			client.HTTPClient = testClient.HTTPClient
+			_, err = client.Info(ctx, sample.ModulePath, sample.VersionString)
+			if test.want == nil {
+				if err != nil {
+					t.Errorf("Info: %v", err)
+				}
+			} else if !errors.Is(err, test.want) {
+				t.Errorf("Info: got %v, want %v", err, test.want)
+			}
+			if test.want == nil {
+				if _, err := client.Zip(ctx, sample.ModulePath, sample.VersionString); err != nil {
+					t.Errorf("Zip: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestNewFromGOPROXYErrors(t *testing.T) {
+	for _, goproxy := range []string{
+		"",
+		",|",
+		"direct",
+		"off",
+		"direct,https://proxy.golang.org",
+		"https://proxy.golang.org,direct,https://proxy.test",
+	} {
+		if _, err := proxy.NewFromGOPROXY(goproxy); !errors.Is(err, derrors.InvalidArgument) {
+			t.Errorf("NewFromGOPROXY(%q): got %v, want InvalidArgument", goproxy, err)
+		}
+	}
+}
+
+func TestFallsBackToDirect(t *testing.T) {
+	for _, test := range []struct {
+		goproxy string
+		err     error
+		want    bool
+	}{
+		{"https://proxy.golang.org", derrors.NotFound, false},
+		{"https://proxy.golang.org,direct", derrors.NotFound, true},
+		{"https://proxy.golang.org,direct", derrors.ProxyError, false},
+		{"https://proxy.golang.org|direct", derrors.ProxyError, true},
+		{"https://proxy.golang.org|direct", nil, false},
+	} {
+		client, err := proxy.NewFromGOPROXY(test.goproxy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := client.FallsBackToDirect(test.err); got != test.want {
+			t.Errorf("NewFromGOPROXY(%q).FallsBackToDirect(%v) = %t, want %t", test.goproxy, test.err, got, test.want)
+		}
+	}
+}
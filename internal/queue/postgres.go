@@ -0,0 +1,110 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/postgres"
+)
+
+const (
+	// postgresLease is how long a worker of a Postgres queue leases a
+	// version. It is longer than the timeout of a fetch, so that a version
+	// is only handed out again if the worker that leased it went away.
+	postgresLease = 10 * time.Minute
+
+	// postgresPollInterval is how long a worker of a Postgres queue waits
+	// before looking for work again when the queue is empty.
+	postgresPollInterval = 5 * time.Second
+
+	// postgresMaxAttempts is the number of times a version is leased before
+	// it is dead-lettered, if its fetches fail with a server error or never
+	// finish.
+	postgresMaxAttempts = 5
+)
+
+// Postgres is a Queue implementation backed by the fetch_queue table of the
+// database. Unlike InMemory, queued fetches survive restarts, and fetches
+// that fail with a server error are retried, up to postgresMaxAttempts
+// times.
+type Postgres struct {
+	db *postgres.DB
+}
+
+// NewPostgres creates a new Postgres queue that stores fetches in db. It
+// starts workerCount workers that lease fetches from the queue and process
+// them with processFunc until ctx is done.
+func NewPostgres(ctx context.Context, db *postgres.DB, workerCount int, experiments []string, processFunc inMemoryProcessFunc) *Postgres {
+	q := &Postgres{db: db}
+	for i := 0; i < workerCount; i++ {
+		go q.work(ctx, experiments, processFunc)
+	}
+	return q
+}
+
+// ScheduleFetch adds a fetch of the given module version to the queue.
+// Fetches requested by the frontend are processed before those requested by
+// the worker. It returns false if the version was already queued.
+func (q *Postgres) ScheduleFetch(ctx context.Context, modulePath, version string, opts *Options) (_ bool, err error) {
+	defer derrors.WrapStack(&err, "queue.Postgres.ScheduleFetch(%q, %q, %v)", modulePath, version, opts)
+
+	priority := postgres.FetchPriorityBackfill
+	if opts != nil && opts.Source == SourceFrontendValue {
+		priority = postgres.FetchPriorityUser
+	}
+	return q.db.EnqueueFetch(ctx, modulePath, version, priority)
+}
+
+// work processes fetches from the queue until ctx is done.
+func (q *Postgres) work(ctx context.Context, experiments []string, processFunc inMemoryProcessFunc) {
+	for ctx.Err() == nil {
+		lease, err := q.db.DequeueFetch(ctx, postgresLease, postgresMaxAttempts)
+		if err != nil {
+			if !errors.Is(err, derrors.NotFound) {
+				log.Error(ctx, err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(postgresPollInterval):
+			}
+			continue
+		}
+		q.process(ctx, lease, experiments, processFunc)
+	}
+}
+
+// process runs processFunc for the leased version, and records the result in
+// the queue.
+func (q *Postgres) process(ctx context.Context, lease *postgres.FetchLease, experiments []string, processFunc inMemoryProcessFunc) {
+	log.Infof(ctx, "Fetch requested: %s", lease.Modver)
+
+	fetchCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	fetchCtx = experiment.NewContext(fetchCtx, experiments...)
+	defer cancel()
+
+	code, err := processFunc(fetchCtx, lease.Path, lease.Version)
+	if code < http.StatusInternalServerError {
+		if err := q.db.MarkFetchDone(ctx, lease); err != nil {
+			log.Error(ctx, err)
+		}
+		return
+	}
+	errMsg := fmt.Sprintf("fetch returned status %d", code)
+	if err != nil {
+		errMsg = err.Error()
+	}
+	log.Errorf(fetchCtx, "%s: %s", lease.Modver, errMsg)
+	if _, err := q.db.MarkFetchFailed(ctx, lease, errMsg, postgresMaxAttempts); err != nil {
+		log.Error(ctx, err)
+	}
+}
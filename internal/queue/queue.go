@@ -23,6 +23,7 @@ import (
 	"golang.org/x/pkgsite/internal/experiment"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/middleware"
+	"golang.org/x/pkgsite/internal/postgres"
 	taskspb "google.golang.org/genproto/googleapis/cloud/tasks/v2"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -35,9 +36,11 @@ type Queue interface {
 }
 
 // New creates a new Queue with name queueName based on the configuration
-// in cfg. When running locally, Queue uses numWorkers concurrent workers.
-func New(ctx context.Context, cfg *config.Config, queueName string, numWorkers int, expGetter middleware.ExperimentGetter, processFunc inMemoryProcessFunc) (Queue, error) {
-	if !cfg.OnGCP() {
+// in cfg. If cfg.QueueType is config.QueueTypePostgres, the queue is stored
+// in db. When running locally or with a Postgres queue, Queue uses
+// numWorkers concurrent workers.
+func New(ctx context.Context, cfg *config.Config, queueName string, numWorkers int, expGetter middleware.ExperimentGetter, db *postgres.DB, processFunc inMemoryProcessFunc) (Queue, error) {
+	if cfg.QueueType == config.QueueTypePostgres || !cfg.OnGCP() {
 		experiments, err := expGetter(ctx)
 		if err != nil {
 			return nil, err
@@ -48,6 +51,12 @@ func New(ctx context.Context, cfg *config.Config, queueName string, numWorkers i
 				names = append(names, e.Name)
 			}
 		}
+		if cfg.QueueType == config.QueueTypePostgres {
+			if db == nil {
+				return nil, errors.New("postgres queue requires a database")
+			}
+			return NewPostgres(ctx, db, numWorkers, names, processFunc), nil
+		}
 		return NewInMemory(ctx, numWorkers, names, processFunc), nil
 	}
 
@@ -22,6 +22,7 @@ package source
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -37,6 +38,7 @@ import (
 	"golang.org/x/pkgsite/internal/derrors"
 	"golang.org/x/pkgsite/internal/log"
 	"golang.org/x/pkgsite/internal/stdlib"
+	"golang.org/x/pkgsite/internal/tlsconfig"
 	"golang.org/x/pkgsite/internal/version"
 )
 
@@ -63,6 +65,15 @@ func (i *Info) RepoURL() string {
 	})
 }
 
+// ModuleDir returns the directory of the module relative to the repository
+// root.
+func (i *Info) ModuleDir() string {
+	if i == nil {
+		return ""
+	}
+	return i.moduleDir
+}
+
 // ModuleURL returns a URL for the home page of the module.
 func (i *Info) ModuleURL() string {
 	return i.DirectoryURL("")
@@ -215,6 +226,14 @@ func NewClient(timeout time.Duration) *Client {
 	}
 }
 
+// WithTLSConfig returns a new client that uses the TLS configuration in
+// configs for requests to the hosts that are its keys, as described by
+// tlsconfig.Client. The new client keeps the timeout and transport of c,
+// wrapping the transport.
+func (c *Client) WithTLSConfig(configs map[string]*tls.Config) *Client {
+	return &Client{httpClient: tlsconfig.Client(c.httpClient, configs)}
+}
+
 // NewClientForTesting returns a Client suitable for testing. It returns the
 // same results as an ordinary client for statically recognizable paths, but
 // always returns a nil *Info for dynamic paths (those requiring HTTP requests).
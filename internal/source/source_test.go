@@ -1045,3 +1045,51 @@ func TestFilesInfo(t *testing.T) {
 	check(info.ModuleURL(), "/files/Users/bob/")
 	check(info.FileURL("dir/a.go"), "/files/Users/bob/dir/a.go")
 }
+
+func TestNewStdlibInfo(t *testing.T) {
+	const file = "errors/errors.go"
+	for _, test := range []struct {
+		version           string
+		wantFile, wantRaw string
+	}{
+		{
+			"v1.21.3",
+			"https://cs.opensource.google/go/go/+/go1.21.3:src/errors/errors.go;l=3",
+			"https://github.com/golang/go/raw/go1.21.3/README.md",
+		},
+		{
+			"v1.21.0-rc.1",
+			"https://cs.opensource.google/go/go/+/go1.21rc1:src/errors/errors.go;l=3",
+			"https://github.com/golang/go/raw/go1.21rc1/README.md",
+		},
+		{
+			// Before Go 1.4, packages were under src/pkg.
+			"v1.3.2",
+			"https://cs.opensource.google/go/go/+/go1.3.2:src/pkg/errors/errors.go;l=3",
+			"https://github.com/golang/go/raw/go1.3.2/README.md",
+		},
+		{
+			"master",
+			"https://cs.opensource.google/go/go/+/master:src/errors/errors.go;l=3",
+			"https://github.com/golang/go/raw/master/README.md",
+		},
+	} {
+		t.Run(test.version, func(t *testing.T) {
+			info, err := newStdlibInfo(test.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := info.LineURL(file, 3); got != test.wantFile {
+				t.Errorf("LineURL: got %q, want %q", got, test.wantFile)
+			}
+			// Raw URLs are relative to the repo root, not src.
+			if got := info.RawURL("README.md"); got != test.wantRaw {
+				t.Errorf("RawURL: got %q, want %q", got, test.wantRaw)
+			}
+		})
+	}
+
+	if _, err := newStdlibInfo("v1.21.0-rc1"); err == nil {
+		t.Error("got nil error for prerelease without a period, want error")
+	}
+}
@@ -74,6 +74,46 @@ type SymbolMeta struct {
 	// the empty string. For example, the parent type for
 	// net/http.FileServer is Handler.
 	ParentName string
+
+	// Deprecated reports whether the symbol's doc comment has a paragraph
+	// beginning with "Deprecated:". It is stored with the documentation of
+	// the symbol, and is not part of the symbol's history: SymbolHistory
+	// drops it.
+	Deprecated bool
+
+	// DeprecationComment is the text of that paragraph, after
+	// "Deprecated:", on a single line.
+	DeprecationComment string
+}
+
+// A DocTree is the documentation of a package as a tree of symbols, for
+// clients that present documentation themselves instead of using the
+// rendered HTML.
+type DocTree struct {
+	// Doc is the text of the package doc comment.
+	Doc string
+	// Symbols are the top-level symbols of the package. The symbols
+	// associated with a type are its children.
+	Symbols []*DocNode
+}
+
+// A DocNode is a symbol in a DocTree. It has the same SymbolMeta as the
+// symbol in Documentation.API.
+type DocNode struct {
+	SymbolMeta
+
+	// Doc is the text of the symbol's doc comment. For a constant or
+	// variable, it is the comment of its declaration group.
+	Doc string
+
+	// Filename is the name of the file containing the symbol, relative to
+	// the package directory, and Line is the line of its declaration.
+	Filename string
+	Line     int
+
+	// Children are the constants, variables, functions, fields and methods
+	// of a type, in that order.
+	Children []*DocNode
 }
 
 // SymbolHistory represents the history for when a symbol name was first added
@@ -140,6 +180,9 @@ func (sh *SymbolHistory) GetSymbol(name, v string, build BuildContext) (_ *Symbo
 
 // AddSymbol adds the given symbol to SymbolHistory.
 func (sh *SymbolHistory) AddSymbol(sm SymbolMeta, v string, build BuildContext) {
+	// The history is of the API of the package, so a symbol that was only
+	// deprecated is the same symbol.
+	sm.Deprecated, sm.DeprecationComment = false, ""
 	sav, ok := sh.m[v]
 	if !ok {
 		sav = map[string]map[SymbolMeta]*SymbolBuildContexts{}
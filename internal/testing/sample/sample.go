@@ -189,6 +189,7 @@ func Module(modulePath, version string, suffixes ...string) *internal.Module {
 			m.Units[0].Name = u.Name
 		}
 	}
+	m.HasPackages = len(suffixes) > 0
 	if modulePath == stdlib.ModulePath {
 		m.Units[0].Readme = nil
 	}
@@ -238,6 +239,7 @@ func AddPackage(m *internal.Module, pkg *internal.Unit) *internal.Module {
 			pkg.Path, m.ModulePath))
 	}
 	AddUnit(m, UnitForPackage(pkg.Path, m.ModulePath, m.Version, pkg.Name, pkg.IsRedistributable))
+	m.HasPackages = true
 	minLen := len(m.ModulePath)
 	if m.ModulePath == stdlib.ModulePath {
 		minLen = 1
@@ -17,6 +17,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"runtime"
 	"testing"
@@ -200,3 +201,39 @@ func readGolden(t *testing.T, name string) string {
 	}
 	return string(data)
 }
+
+// ServeGitRepo creates a git repository from the txtar contents, tags its
+// commit with tag, and serves it over git's "dumb" HTTP protocol. It returns
+// the URL of the repository. It skips the test if git is not installed.
+func ServeGitRepo(t *testing.T, contents, tag string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not found")
+	}
+	work, _ := WriteTxtarToTempDir(t, contents)
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=gopher", "GIT_AUTHOR_EMAIL=gopher@example.com",
+			"GIT_COMMITTER_NAME=gopher", "GIT_COMMITTER_EMAIL=gopher@example.com",
+			"GIT_AUTHOR_DATE=2023-01-02T03:04:05Z", "GIT_COMMITTER_DATE=2023-01-02T03:04:05Z")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	git(work, "init", "--quiet")
+	git(work, "add", "-A")
+	git(work, "commit", "--quiet", "-m", "initial commit")
+	git(work, "tag", tag)
+
+	root := t.TempDir()
+	repo := filepath.Join(root, "direct.git")
+	git(root, "clone", "--quiet", "--bare", work, repo)
+	git(repo, "update-server-info")
+
+	s := httptest.NewServer(http.FileServer(http.Dir(root)))
+	t.Cleanup(s.Close)
+	return s.URL + "/direct.git"
+}
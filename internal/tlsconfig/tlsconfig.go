@@ -0,0 +1,116 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package tlsconfig provides HTTP transports that use different TLS
+// configurations for different hosts, for talking to servers with internal
+// certificate authorities or that require client certificates.
+//
+// The commands in this repository are not configured with per-host TLS
+// settings; the package is for programs that build proxy and source clients
+// themselves, with their WithTLSConfig methods.
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"os"
+
+	"go.opencensus.io/plugin/ochttp"
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+// Load returns a TLS configuration that trusts the certificate authorities in
+// the PEM file caFile in addition to the system roots, and presents the client
+// certificate in the PEM files certFile and keyFile. Any of the files may be
+// empty to omit that part of the configuration.
+func Load(caFile, certFile, keyFile string) (_ *tls.Config, err error) {
+	defer derrors.Wrap(&err, "tlsconfig.Load(%q, %q, %q)", caFile, certFile, keyFile)
+
+	cfg := &tls.Config{}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificates found")
+		}
+		cfg.RootCAs = pool
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// Transport returns an http.RoundTripper that makes requests to the hosts
+// that are keys of configs with the corresponding TLS configuration. Hosts may
+// be given with or without a port. Requests to other hosts use base, or
+// http.DefaultTransport if base is nil.
+//
+// The transports for the configured hosts are clones of base if it is an
+// *http.Transport, so they keep its settings, such as its timeouts and
+// proxy; otherwise they are clones of http.DefaultTransport.
+func Transport(base http.RoundTripper, configs map[string]*tls.Config) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	template, ok := base.(*http.Transport)
+	if !ok {
+		template = http.DefaultTransport.(*http.Transport)
+	}
+	t := &transport{base: base, byHost: map[string]*http.Transport{}}
+	for host, cfg := range configs {
+		ht := template.Clone()
+		ht.TLSClientConfig = cfg.Clone()
+		t.byHost[host] = ht
+	}
+	return t
+}
+
+// Client returns a copy of c whose transport uses the TLS configurations in
+// configs, as described by Transport. The copy keeps the other settings of c,
+// such as its timeout. If the transport of c is an *ochttp.Transport, its base
+// is wrapped instead, so requests are still traced. A nil c is treated as an
+// empty http.Client.
+func Client(c *http.Client, configs map[string]*tls.Config) *http.Client {
+	var c2 http.Client
+	if c != nil {
+		c2 = *c
+	}
+	if t, ok := c2.Transport.(*ochttp.Transport); ok {
+		t2 := *t
+		t2.Base = Transport(t.Base, configs)
+		c2.Transport = &t2
+	} else {
+		c2.Transport = Transport(c2.Transport, configs)
+	}
+	return &c2
+}
+
+type transport struct {
+	base   http.RoundTripper
+	byHost map[string]*http.Transport
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if ht, ok := t.byHost[req.URL.Host]; ok {
+		return ht.RoundTrip(req)
+	}
+	if ht, ok := t.byHost[req.URL.Hostname()]; ok {
+		return ht.RoundTrip(req)
+	}
+	return t.base.RoundTrip(req)
+}
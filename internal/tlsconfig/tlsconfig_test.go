@@ -0,0 +1,105 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.opencensus.io/plugin/ochttp"
+)
+
+func TestTransport(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Write the server's self-signed certificate as a CA file.
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw})
+	if err := os.WriteFile(caFile, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(caFile, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name    string
+		configs map[string]*tls.Config
+		wantErr bool
+	}{
+		{"not configured", nil, true},
+		{"host and port", map[string]*tls.Config{u.Host: cfg}, false},
+		{"host", map[string]*tls.Config{u.Hostname(): cfg}, false},
+		{"other host", map[string]*tls.Config{"other.test": cfg}, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			c := &http.Client{Transport: Transport(nil, test.configs)}
+			res, err := c.Get(s.URL)
+			if err == nil {
+				res.Body.Close()
+			}
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("got error %v, want error: %t", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient(t *testing.T) {
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer s.Close()
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &tls.Config{RootCAs: s.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs}
+
+	orig := &http.Client{Transport: &ochttp.Transport{}, Timeout: time.Minute}
+	c := Client(orig, map[string]*tls.Config{u.Host: cfg})
+	if c.Timeout != orig.Timeout {
+		t.Errorf("got timeout %s, want %s", c.Timeout, orig.Timeout)
+	}
+	ot, ok := c.Transport.(*ochttp.Transport)
+	if !ok {
+		t.Fatalf("got transport %T, want *ochttp.Transport", c.Transport)
+	}
+	if ot == orig.Transport {
+		t.Error("original transport was reused")
+	}
+	if orig.Transport.(*ochttp.Transport).Base != nil {
+		t.Error("original transport was modified")
+	}
+	res, err := c.Get(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+}
+
+func TestLoadErrors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(empty, "", ""); err == nil {
+		t.Error("Load with a CA file without certificates: got nil error")
+	}
+	if _, err := Load("", empty, empty); err == nil {
+		t.Error("Load with an invalid client certificate: got nil error")
+	}
+}
@@ -5,6 +5,8 @@
 package internal
 
 import (
+	"time"
+
 	"golang.org/x/pkgsite/internal/licenses"
 )
 
@@ -16,11 +18,47 @@ type UnitMeta struct {
 	Name              string
 	IsRedistributable bool
 	Licenses          []*licenses.Metadata
+	DocStats          DocStats
+	// ExamplesValid reports whether the package's examples with output
+	// comments refer only to defined identifiers. It is true if there are
+	// no such examples, and only meaningful for packages.
+	ExamplesValid bool
+	// NumTestFiles is the number of _test.go files in the package's
+	// directory. It is only meaningful for packages.
+	NumTestFiles int
+	// PackageDeprecated reports whether the package is marked as deprecated
+	// by its doc comment. It is separate from ModuleInfo.Deprecated, which
+	// comes from the go.mod file of the module's latest version.
+	PackageDeprecated         bool
+	PackageDeprecationComment string
 
 	// Module level information
 	// Note: IsRedistributable (above) applies to the unit;
 	// ModuleInfo.IsRedistributable applies to the module.
 	ModuleInfo
+
+	// ProcessedAt is when the module version was last inserted or updated
+	// in the database, as when it is reprocessed. It is zero for units that
+	// were not read from the database.
+	ProcessedAt time.Time
+}
+
+// DocStats describes how much of a package's exported API is documented.
+type DocStats struct {
+	// NumExported is the number of exported constants, variables, functions,
+	// types and methods. Struct fields and interface methods are not counted.
+	NumExported int
+	// NumDocumented is the number of exported symbols with a doc comment.
+	NumDocumented int
+}
+
+// Ratio returns the fraction of exported symbols that are documented. A
+// package with no exported symbols is considered fully documented.
+func (s DocStats) Ratio() float64 {
+	if s.NumExported == 0 {
+		return 1
+	}
+	return float64(s.NumDocumented) / float64(s.NumExported)
 }
 
 // IsPackage reports whether the path represents a package path.
@@ -28,7 +66,14 @@ func (um *UnitMeta) IsPackage() bool {
 	return um.Name != ""
 }
 
-// IsCommand reports whether the path represents a package path.
+// HasTests reports whether the package's directory has any _test.go files.
+func (um *UnitMeta) HasTests() bool {
+	return um.NumTestFiles > 0
+}
+
+// IsCommand reports whether the path represents a command, that is, a
+// package named main. It is determined by the package clause of the files
+// read when the package is fetched.
 func (um *UnitMeta) IsCommand() bool {
 	return um.IsPackage() && um.Name == "main"
 }
@@ -45,6 +90,8 @@ func (um *UnitMeta) IsModule() bool {
 type Unit struct {
 	UnitMeta
 	Readme          *Readme
+	Changelog       *Changelog // only for the module root
+	Notice          *Notice    // the module's NOTICE file, read with WithLicenses
 	BuildContexts   []BuildContext
 	Documentation   []*Documentation // at most one on read
 	Subdirectories  []*PackageMeta
@@ -53,9 +100,23 @@ type Unit struct {
 	Symbols         map[BuildContext][]*Symbol
 	NumImports      int
 	NumImportedBy   int
+	Vulns           []*UnitVuln // read with WithVulns
+	Examples        *Examples   // nil if the package has no examples
+
+	// DocumentationPurged reports whether the documentation of the package
+	// was purged to save space, in which case Documentation is empty until
+	// the module is fetched again.
+	DocumentationPurged bool
+
+	// BuildErrors describes problems that would prevent the package from
+	// building, such as imports that cannot be resolved. Its documentation
+	// may be incomplete if there are any.
+	BuildErrors []string
 
 	// SymbolHistory is a map of symbolName to the version when the symbol was
-	// first added to the package.
+	// first added to the package. For the standard library, the versions are
+	// semantic versions; use stdlib.TagForVersion to get the Go release
+	// (for example, "go1.16").
 	SymbolHistory map[string]string
 }
 
@@ -67,8 +128,40 @@ type Documentation struct {
 	GOOS     string
 	GOARCH   string
 	Synopsis string
-	Source   []byte // encoded ast.Files; see godoc.Package.Encode
-	API      []*Symbol
+	// Usage is the usage synopsis from the doc comment of a command, if any.
+	Usage  string
+	Source []byte // encoded ast.Files; see godoc.Package.Encode
+	API    []*Symbol
+	// HTML and Text are the documentation rendered when the package was
+	// fetched: the HTML of the documentation body, and plain text. See
+	// godoc.RenderFormats. They are read only with WithDocHTML and
+	// WithDocText, and may be empty if the package was fetched before they
+	// were stored.
+	HTML string
+	Text string
+}
+
+// Examples holds the examples of a package, classified by what they
+// illustrate.
+type Examples struct {
+	// Package holds the examples of the package as a whole, declared as
+	// func Example or func Example_suffix. They are presented at the top of
+	// the documentation.
+	Package []*Example
+	// Symbol holds the examples of the package's functions, types and
+	// methods.
+	Symbol []*Example
+}
+
+// Example is an example function of a package.
+type Example struct {
+	// Symbol is the name of the symbol that the example is for, such as "F"
+	// or "T.M". It is empty for a package example.
+	Symbol string
+	// Suffix is the suffix of the example's name, as in ExampleF_suffix.
+	Suffix string
+	Doc    string
+	Output string
 }
 
 // Readme is a README at the specified filepath.
@@ -77,6 +170,38 @@ type Readme struct {
 	Contents string
 }
 
+// Changelog is a CHANGELOG or CHANGES file at the specified filepath,
+// relative to the module root.
+type Changelog struct {
+	Filepath string
+	Contents string
+}
+
+// Notice is a NOTICE file at the specified filepath, relative to the module
+// root. Some licenses, like Apache-2.0, require that it be preserved and
+// displayed with attributions.
+type Notice struct {
+	Filepath string
+	Contents string
+}
+
+// A UnitVuln is a known vulnerability that affects a unit at its version.
+type UnitVuln struct {
+	// ID is the ID of the vulnerability, like GO-2023-0001.
+	ID string
+	// Versions describes the affected version ranges, for example
+	// "from v1.2.0 before v1.3.1".
+	Versions string
+	// Symbols are the affected exported symbols of the package. If empty,
+	// the whole package is affected. It is always empty for units that are
+	// not packages.
+	Symbols []string
+	// FixedVersion is the lowest stored version later than the unit's that
+	// is not affected, which is the version to upgrade to. It is empty if no
+	// such version has been stored.
+	FixedVersion string
+}
+
 // PackageMeta represents the metadata of a package in a module version.
 type PackageMeta struct {
 	Path              string
@@ -113,4 +238,19 @@ const (
 	WithMain FieldSet = 1 << iota
 	WithImports
 	WithLicenses
+	// WithSymbolHistory reads only the SymbolHistory of a package, which
+	// WithMain also reads.
+	WithSymbolHistory
+	// WithVulns reads the vulnerabilities that affect the unit.
+	WithVulns
+	// WithDocSource reads only the encoded source of the documentation of a
+	// package into Documentation.Source, which WithMain also reads. Use
+	// godoc.DocTreeFromUnit or godoc.SymbolDocFromUnit to render it.
+	WithDocSource
+	// WithDocHTML reads the HTML of the documentation stored when the
+	// package was fetched into Documentation.HTML.
+	WithDocHTML
+	// WithDocText reads the plain-text documentation stored when the
+	// package was fetched into Documentation.Text.
+	WithDocText
 )
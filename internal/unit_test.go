@@ -0,0 +1,22 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import "testing"
+
+func TestDocStatsRatio(t *testing.T) {
+	for _, test := range []struct {
+		stats DocStats
+		want  float64
+	}{
+		{DocStats{NumExported: 4, NumDocumented: 3}, 0.75},
+		{DocStats{NumExported: 2, NumDocumented: 0}, 0},
+		{DocStats{}, 1},
+	} {
+		if got := test.stats.Ratio(); got != test.want {
+			t.Errorf("%+v.Ratio() = %g, want %g", test.stats, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// ResolveVersionQuery returns the version in available that the version
+// query resolves to, following the rules of the go command described at
+// https://go.dev/ref/mod#version-queries:
+//
+//   - "latest" is the latest version, as defined by version.LatestOf.
+//   - A full version such as "v1.2.3" is that version, if it is available.
+//   - A version prefix such as "v1" or "v1.2" is the latest version with
+//     that prefix.
+//   - A comparison such as "<v1.2.3" or ">=v1.2.3" is the closest version to
+//     the one given: the latest version for < and <=, and the earliest for >
+//     and >=, preferring release versions to pre-release versions.
+//
+// Branch names and revisions are not supported, since they can't be resolved
+// without the module's repository. Invalid versions in available are
+// ignored.
+//
+// ResolveVersionQuery returns an error wrapping derrors.InvalidArgument if
+// the query is invalid, and one wrapping derrors.NotFound if no version
+// matches it.
+func ResolveVersionQuery(query string, available []string) (_ string, err error) {
+	defer derrors.Wrap(&err, "ResolveVersionQuery(%q)", query)
+
+	var versions []string
+	for _, v := range available {
+		if semver.IsValid(v) {
+			versions = append(versions, v)
+		}
+	}
+	if strings.HasPrefix(query, "<") || strings.HasPrefix(query, ">") {
+		if bound := strings.TrimLeft(query, "<>="); !semver.IsValid(bound) || isVersionPrefix(bound) {
+			return "", fmt.Errorf("invalid version in comparison: %w", derrors.InvalidArgument)
+		}
+	}
+	var v string
+	switch {
+	case query == version.Latest:
+		v = version.LatestOf(versions)
+	case strings.HasPrefix(query, "<="):
+		v = latestMatching(versions, query[2:], func(c int) bool { return c <= 0 })
+	case strings.HasPrefix(query, ">="):
+		v = earliestMatching(versions, query[2:], func(c int) bool { return c >= 0 })
+	case strings.HasPrefix(query, "<"):
+		v = latestMatching(versions, query[1:], func(c int) bool { return c < 0 })
+	case strings.HasPrefix(query, ">"):
+		v = earliestMatching(versions, query[1:], func(c int) bool { return c > 0 })
+	case isVersionPrefix(query):
+		var matches []string
+		for _, w := range versions {
+			if strings.HasPrefix(w, query+".") {
+				matches = append(matches, w)
+			}
+		}
+		v = version.LatestOf(matches)
+	case semver.IsValid(query):
+		for _, w := range versions {
+			if w == query {
+				v = w
+			}
+		}
+	default:
+		return "", fmt.Errorf("invalid version query: %w", derrors.InvalidArgument)
+	}
+	if v == "" {
+		return "", fmt.Errorf("no matching version: %w", derrors.NotFound)
+	}
+	return v, nil
+}
+
+// isVersionPrefix reports whether v is a valid version with fewer than three
+// numeric components, like "v1" or "v1.2".
+func isVersionPrefix(v string) bool {
+	return semver.IsValid(v) && semver.Prerelease(v) == "" && semver.Build(v) == "" && strings.Count(v, ".") < 2
+}
+
+// latestMatching returns the latest of versions whose comparison with bound
+// satisfies ok, or the empty string if there is none.
+func latestMatching(versions []string, bound string, ok func(c int) bool) string {
+	return version.LatestOf(version.RemoveIf(versions, func(v string) bool {
+		return !ok(semver.Compare(v, bound))
+	}))
+}
+
+// earliestMatching returns the earliest of versions whose comparison with
+// bound satisfies ok, preferring release versions to pre-release versions,
+// and both to pseudo-versions. It returns the empty string if there is none.
+func earliestMatching(versions []string, bound string, ok func(c int) bool) string {
+	earliest := ""
+	for _, v := range versions {
+		if !ok(semver.Compare(v, bound)) {
+			continue
+		}
+		if earliest == "" || rank(v) < rank(earliest) ||
+			(rank(v) == rank(earliest) && semver.Compare(v, earliest) < 0) {
+			earliest = v
+		}
+	}
+	return earliest
+}
+
+// rank orders versions by preference: release versions, then pre-release
+// versions, then pseudo-versions.
+func rank(v string) int {
+	switch {
+	case version.IsPseudo(v):
+		return 2
+	case semver.Prerelease(v) != "":
+		return 1
+	default:
+		return 0
+	}
+}
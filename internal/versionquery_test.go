@@ -0,0 +1,73 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/pkgsite/internal/derrors"
+)
+
+func TestResolveVersionQuery(t *testing.T) {
+	available := []string{
+		"v0.9.0",
+		"v1.0.0",
+		"v1.1.0",
+		"v1.2.0",
+		"v1.2.1",
+		"v1.3.0-beta.1",
+		"v2.0.0+incompatible",
+		"v2.1.0-pre",
+		"v0.0.0-20200101000000-000000000000",
+	}
+	for _, test := range []struct {
+		query, want string
+	}{
+		{"latest", "v2.0.0+incompatible"},
+		{"v1", "v1.2.1"},
+		{"v1.2", "v1.2.1"},
+		{"v1.3", "v1.3.0-beta.1"},
+		{"v1.1.0", "v1.1.0"},
+		{"v1.3.0-beta.1", "v1.3.0-beta.1"},
+		{"<v1.2.0", "v1.1.0"},
+		{"<=v1.2.0", "v1.2.0"},
+		{">v1.2.0", "v1.2.1"},
+		{">=v1.2.2", "v2.0.0+incompatible"},
+		{">v2.0.0", "v2.1.0-pre"},
+	} {
+		got, err := ResolveVersionQuery(test.query, available)
+		if err != nil {
+			t.Errorf("ResolveVersionQuery(%q): %v", test.query, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ResolveVersionQuery(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+}
+
+func TestResolveVersionQueryErrors(t *testing.T) {
+	available := []string{"v1.0.0", "v1.1.0"}
+	for _, test := range []struct {
+		query   string
+		wantErr error
+	}{
+		{"master", derrors.InvalidArgument},
+		{"<v1", derrors.InvalidArgument},
+		{">=", derrors.InvalidArgument},
+		{"v1.2.0", derrors.NotFound},
+		{"v2", derrors.NotFound},
+		{">v1.1.0", derrors.NotFound},
+	} {
+		_, err := ResolveVersionQuery(test.query, available)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("ResolveVersionQuery(%q): got error %v, want %v", test.query, err, test.wantErr)
+		}
+	}
+	if _, err := ResolveVersionQuery("latest", nil); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("ResolveVersionQuery(latest) with no versions: got error %v, want NotFound", err)
+	}
+}
@@ -345,6 +345,22 @@ func (c *Client) byIDs(ctx context.Context, ids []string) (_ []*osv.Entry, err e
 
 // IDs returns a list of the IDs of all the entries in the database.
 func (c *Client) IDs(ctx context.Context) (_ []string, err error) {
+	metas, err := c.VulnMetas(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, v := range metas {
+		ids = append(ids, v.ID)
+	}
+	return ids, nil
+}
+
+// VulnMetas returns the metadata of all the entries in the database, which
+// includes when each was last modified.
+func (c *Client) VulnMetas(ctx context.Context) (_ []*VulnMeta, err error) {
+	defer derrors.Wrap(&err, "VulnMetas()")
+
 	b, err := c.vulns(ctx)
 	if err != nil {
 		return nil, err
@@ -355,17 +371,24 @@ func (c *Client) IDs(ctx context.Context) (_ []string, err error) {
 		return nil, err
 	}
 
-	var ids []string
+	var metas []*VulnMeta
 	for dec.More() {
 		var v VulnMeta
 		err := dec.Decode(&v)
 		if err != nil {
 			return nil, err
 		}
-		ids = append(ids, v.ID)
+		metas = append(metas, &v)
 	}
 
-	return ids, nil
+	return metas, nil
+}
+
+// ByIDs returns the OSV entries with the given IDs, in the same order.
+func (c *Client) ByIDs(ctx context.Context, ids []string) (_ []*osv.Entry, err error) {
+	defer derrors.Wrap(&err, "ByIDs(%d IDs)", len(ids))
+
+	return c.byIDs(ctx, ids)
 }
 
 // newStreamDecoder returns a decoder that can be used
@@ -9,8 +9,11 @@ import (
 	"context"
 	"fmt"
 	"go/token"
+	"sort"
 	"strings"
 
+	"golang.org/x/mod/semver"
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/osv"
 	"golang.org/x/pkgsite/internal/stdlib"
 	vers "golang.org/x/pkgsite/internal/version"
@@ -32,21 +35,9 @@ func VulnsForPackage(ctx context.Context, modulePath, version, packagePath strin
 		return nil
 	}
 
-	// Handle special module paths.
-	if modulePath == stdlib.ModulePath {
-		// Stdlib pages requested at master will map to a pseudo version
-		// that puts all vulns in range.
-		// We can't really tell you're at master so version.IsPseudo
-		// is the best we can do. The result is vulns won't be reported for a
-		// pseudoversion that refers to a commit that is in a vulnerable range.
-		switch {
-		case vers.IsPseudo(version):
-			return nil
-		case strings.HasPrefix(packagePath, "cmd/"):
-			modulePath = osv.GoCmdModulePath
-		default:
-			modulePath = osv.GoStdModulePath
-		}
+	modulePath = OSVModulePath(modulePath, version, packagePath)
+	if modulePath == "" {
+		return nil
 	}
 
 	// Get all the vulns for this package/version.
@@ -58,6 +49,29 @@ func VulnsForPackage(ctx context.Context, modulePath, version, packagePath strin
 	return toVulns(entries)
 }
 
+// OSVModulePath returns the module path that vulnerability entries use for
+// the package at packagePath in the given module version. It differs from
+// modulePath only for the standard library. It returns the empty string if
+// vulnerabilities can't be reported for the version.
+func OSVModulePath(modulePath, version, packagePath string) string {
+	if modulePath != stdlib.ModulePath {
+		return modulePath
+	}
+	// Stdlib pages requested at master will map to a pseudo version
+	// that puts all vulns in range.
+	// We can't really tell you're at master so version.IsPseudo
+	// is the best we can do. The result is vulns won't be reported for a
+	// pseudoversion that refers to a commit that is in a vulnerable range.
+	switch {
+	case vers.IsPseudo(version):
+		return ""
+	case strings.HasPrefix(packagePath, "cmd/"):
+		return osv.GoCmdModulePath
+	default:
+		return osv.GoStdModulePath
+	}
+}
+
 func toVulns(entries []*osv.Entry) []Vuln {
 	if len(entries) == 0 {
 		return nil
@@ -134,27 +148,11 @@ func collectRangePairs(a osv.Affected) []pair {
 func AffectedPackages(e *osv.Entry) []*AffectedPackage {
 	var affs []*AffectedPackage
 	for _, a := range e.Affected {
-		pairs := collectRangePairs(a)
-		var vs []string
-		for _, p := range pairs {
-			var s string
-			if p.intro == "" && p.fixed == "" {
-				// If neither field is set, the vuln applies to all versions.
-				// Leave it blank, the template will render it properly.
-				s = ""
-			} else if p.intro == "" {
-				s = "before " + p.fixed
-			} else if p.fixed == "" {
-				s = p.intro + " and later"
-			} else {
-				s = "from " + p.intro + " before " + p.fixed
-			}
-			vs = append(vs, s)
-		}
+		versions := affectedVersions(a)
 		for _, p := range a.EcosystemSpecific.Packages {
 			affs = append(affs, &AffectedPackage{
 				PackagePath: p.Path,
-				Versions:    strings.Join(vs, ", "),
+				Versions:    versions,
 				Symbols:     exportedSymbols(p.Symbols),
 				// TODO(hyangah): where to place GOOS/GOARCH info
 			})
@@ -163,6 +161,28 @@ func AffectedPackages(e *osv.Entry) []*AffectedPackage {
 	return affs
 }
 
+// affectedVersions describes the version ranges of a, for example
+// "from v1.2.0 before v1.3.1".
+func affectedVersions(a osv.Affected) string {
+	var vs []string
+	for _, p := range collectRangePairs(a) {
+		var s string
+		if p.intro == "" && p.fixed == "" {
+			// If neither field is set, the vuln applies to all versions.
+			// Leave it blank, the template will render it properly.
+			s = ""
+		} else if p.intro == "" {
+			s = "before " + p.fixed
+		} else if p.fixed == "" {
+			s = p.intro + " and later"
+		} else {
+			s = "from " + p.intro + " before " + p.fixed
+		}
+		vs = append(vs, s)
+	}
+	return strings.Join(vs, ", ")
+}
+
 func exportedSymbols(in []string) []string {
 	var out []string
 	for _, s := range in {
@@ -178,3 +198,70 @@ func exportedSymbols(in []string) []string {
 	}
 	return out
 }
+
+// UnitVulns returns the vulnerabilities among entries that affect the unit at
+// unitPath in the given module version. If the unit is not a package,
+// unitPath should be empty, and every vulnerability affecting the module
+// version is returned.
+func UnitVulns(entries []*osv.Entry, modulePath, version, unitPath string) []*internal.UnitVuln {
+	osvPath := OSVModulePath(modulePath, version, unitPath)
+	if osvPath == "" {
+		return nil
+	}
+	req := &PackageRequest{Module: osvPath, Package: unitPath, Version: version}
+	var uvs []*internal.UnitVuln
+	for _, e := range entries {
+		if !isAffected(e, req) {
+			continue
+		}
+		uv := &internal.UnitVuln{ID: e.ID}
+		for _, a := range e.Affected {
+			if a.Module.Path != osvPath || !osv.AffectsSemver(a.Ranges, version) {
+				continue
+			}
+			uv.Versions = affectedVersions(a)
+			for _, p := range a.EcosystemSpecific.Packages {
+				if unitPath != "" && p.Path == unitPath {
+					uv.Symbols = exportedSymbols(p.Symbols)
+				}
+			}
+			break
+		}
+		uvs = append(uvs, uv)
+	}
+	sort.Slice(uvs, func(i, j int) bool { return uvs[i].ID < uvs[j].ID })
+	return uvs
+}
+
+// FixedVersion returns the lowest of versions that is later than version and
+// is not affected by e, for the package at packagePath in the module at
+// modulePath. Prerelease and pseudo-versions are never returned. FixedVersion
+// returns the empty string if there is no such version, for example because
+// no fix has been released or it has not been stored yet.
+func FixedVersion(e *osv.Entry, modulePath, version, packagePath string, versions []string) string {
+	osvPath := OSVModulePath(modulePath, version, packagePath)
+	if osvPath == "" {
+		return ""
+	}
+	affected := func(v string) bool {
+		for _, a := range e.Affected {
+			if a.Module.Path == osvPath && osv.AffectsSemver(a.Ranges, v) {
+				return true
+			}
+		}
+		return false
+	}
+	var fixed string
+	for _, v := range versions {
+		if semver.Compare(v, version) <= 0 || semver.Prerelease(v) != "" {
+			continue
+		}
+		if fixed != "" && semver.Compare(v, fixed) >= 0 {
+			continue
+		}
+		if !affected(v) {
+			fixed = v
+		}
+	}
+	return fixed
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"golang.org/x/pkgsite/internal"
 	"golang.org/x/pkgsite/internal/osv"
 )
 
@@ -345,3 +346,76 @@ func TestAffectedPackagesPackagesSymbols(t *testing.T) {
 		})
 	}
 }
+
+func TestUnitVulns(t *testing.T) {
+	e := &osv.Entry{
+		ID: "GO-2023-0001",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "example.com/mod"},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeTypeSemver,
+				Events: []osv.RangeEvent{{Introduced: "1.1.0"}, {Fixed: "1.2.0"}},
+			}},
+			EcosystemSpecific: osv.EcosystemSpecific{
+				Packages: []osv.Package{{
+					Path:    "example.com/mod/p",
+					Symbols: []string{"F", "T.M", "unexported"},
+				}},
+			},
+		}},
+	}
+	for _, test := range []struct {
+		version, unitPath string
+		want              []*internal.UnitVuln
+	}{
+		{"v1.0.0", "example.com/mod/p", nil},
+		{"v1.1.0", "example.com/mod/p", []*internal.UnitVuln{{
+			ID:       "GO-2023-0001",
+			Versions: "from v1.1.0 before v1.2.0",
+			Symbols:  []string{"F", "T.M"},
+		}}},
+		{"v1.1.0", "example.com/mod/q", nil},
+		// Module-level units have every vulnerability of the module.
+		{"v1.1.0", "", []*internal.UnitVuln{{
+			ID:       "GO-2023-0001",
+			Versions: "from v1.1.0 before v1.2.0",
+		}}},
+		{"v1.2.0", "example.com/mod/p", nil},
+	} {
+		got := UnitVulns([]*osv.Entry{e}, "example.com/mod", test.version, test.unitPath)
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("%s@%s: mismatch (-want, +got):\n%s", test.unitPath, test.version, diff)
+		}
+	}
+}
+
+func TestFixedVersion(t *testing.T) {
+	e := &osv.Entry{
+		ID: "GO-2023-0001",
+		Affected: []osv.Affected{{
+			Module: osv.Module{Path: "example.com/mod"},
+			Ranges: []osv.Range{{
+				Type:   osv.RangeTypeSemver,
+				Events: []osv.RangeEvent{{Introduced: "1.0.0"}, {Fixed: "1.2.1"}},
+			}},
+		}},
+	}
+	for _, test := range []struct {
+		name     string
+		version  string
+		versions []string
+		want     string
+	}{
+		{"fixed", "v1.0.0", []string{"v0.9.0", "v1.0.0", "v1.2.0", "v1.4.0", "v1.3.0"}, "v1.3.0"},
+		{"prerelease ignored", "v1.0.0", []string{"v1.3.0-pre", "v1.3.0"}, "v1.3.0"},
+		{"no fix", "v1.0.0", []string{"v0.9.0", "v1.0.0", "v1.2.0"}, ""},
+		{"no versions", "v1.0.0", nil, ""},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := FixedVersion(e, "example.com/mod", test.version, "example.com/mod/p", test.versions)
+			if got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
@@ -100,6 +100,14 @@ type Fetcher struct {
 	Cache        *cache.Cache
 	loadShedder  *loadShedder
 	Source       string
+	// Metrics, if non-nil, receives measurements of each fetch.
+	Metrics FetchMetrics
+	// Tracer, if non-nil, creates spans for the phases of each fetch.
+	Tracer Tracer
+	// DirectGetter, if non-nil, fetches modules directly from their
+	// repositories when ProxyClient falls back to direct fetching. See
+	// proxy.Client.FallsBackToDirect.
+	DirectGetter fetch.ModuleGetter
 }
 
 // FetchAndUpdateState fetches and processes a module version, and then updates
@@ -115,8 +123,11 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 	start := time.Now()
 	var nPackages int64
 	defer func() {
-		latency := float64(time.Since(start).Seconds())
+		elapsed := time.Since(start)
+		latency := float64(elapsed.Seconds())
 		dcensus.RecordWithTag(ctx, dcensus.KeyStatus, strconv.Itoa(status), fetchLatency.M(latency))
+		f.metrics().ObserveFetchDuration(elapsed)
+		f.metrics().IncFetchResult(status)
 		if status < 300 {
 			stats.Record(ctx, fetchedPackages.M(nPackages))
 		}
@@ -128,9 +139,15 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 	if modulePath == internal.UnknownModulePath {
 		return http.StatusInternalServerError, "", errors.New("called with internal.UnknownModulePath")
 	}
+	modulePath, err = internal.CleanImportPath(modulePath)
+	if err != nil {
+		return http.StatusBadRequest, "", err
+	}
 	if !utf8.ValidString(requestedVersion) {
 		log.Errorf(ctx, "requested version %q is not valid UTF-8", requestedVersion)
 	}
+	ctx, fspan := f.startSpan(ctx, "FetchAndUpdateState", modulePath, requestedVersion)
+	defer fspan.End()
 	span.AddAttributes(
 		trace.StringAttribute("modulePath", modulePath),
 		trace.StringAttribute("version", requestedVersion))
@@ -143,8 +160,22 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 	//
 	// Don't fail on a non-nil error. If we return here, we won't record
 	// the error state in the DB.
-	info, err := getInfo(ctx, modulePath, requestedVersion, f.ProxyClient)
+	ictx, ispan := f.startSpan(ctx, "proxy.Info", modulePath, requestedVersion)
+	info, err := getInfo(ictx, modulePath, requestedVersion, f.ProxyClient)
+	ispan.End()
+	// The state of the earlier fetch of the module version, if it need not be
+	// processed again.
+	var unchanged *internal.ModuleVersionState
 	if err == nil {
+		unchanged, err = f.unchangedSinceLastFetch(ctx, modulePath, info, appVersionLabel)
+		if err != nil {
+			return derrors.ToStatus(err), "", err
+		}
+	}
+	if unchanged != nil {
+		log.Infof(ctx, "%s@%s: unchanged since last fetch with app version %s; not processing it again",
+			modulePath, info.Version, appVersionLabel)
+	} else if err == nil {
 		// If we're overloaded, shed load by not processing this module.
 		// The zip endpoint requires a resolved version.
 		deferFunc, zipSize, err := f.maybeShed(ctx, modulePath, info.Version)
@@ -152,6 +183,9 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 		if err != nil {
 			return derrors.ToStatus(err), "", err
 		}
+		if zipSize > 0 {
+			f.metrics().ObserveZipSize(zipSize)
+		}
 
 		fi := &FetchInfo{
 			ModulePath: modulePath,
@@ -175,17 +209,35 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 		if err := f.DB.InsertNewModuleVersionFromFrontendFetch(ctx, modulePath, info.Version); err != nil {
 			return derrors.ToStatus(err), "", err
 		}
+		// Mark the fetch as started, so that it can be found if we crash
+		// before recording its result.
+		if err := f.DB.StartModuleFetch(ctx, modulePath, info.Version); err != nil {
+			return derrors.ToStatus(err), "", err
+		}
 	}
 
 	// Get the latest-version information first, and update the DB. We'll need
 	// it to determine if the current module version is the latest good one for
 	// its path.
-	lmv, err := f.FetchAndUpdateLatest(ctx, modulePath)
+	lctx, lspan := f.startSpan(ctx, "FetchAndUpdateLatest", modulePath, "")
+	lmv, err := f.FetchAndUpdateLatest(lctx, modulePath)
+	lspan.End()
 	// The only errors possible here should be DB failures.
 	if err != nil {
 		return derrors.ToStatus(err), "", err
 	}
-	ft := f.fetchAndInsertModule(ctx, modulePath, requestedVersion, lmv)
+	var ft *fetchTask
+	if unchanged != nil {
+		// Skip downloading and processing the module, but record the result
+		// below as for a fetch, so that the version map, latest-version
+		// information and search data are up to date.
+		ft, err = f.unchangedFetchTask(ctx, modulePath, requestedVersion, unchanged)
+		if err != nil {
+			return derrors.ToStatus(err), "", err
+		}
+	} else {
+		ft = f.fetchAndInsertModule(ctx, modulePath, requestedVersion, lmv)
+	}
 	nPackages = int64(len(ft.PackageVersionStates))
 	span.AddAttributes(trace.Int64Attribute("numPackages", nPackages))
 
@@ -201,7 +253,10 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 	}
 	// Regardless of what the status code is, insert the result into
 	// version_map, so that a response can be returned for frontend_fetch.
-	if err := updateVersionMap(ctx, f.DB, ft); err != nil {
+	vctx, vspan := f.startSpan(ctx, "updateVersionMap", modulePath, requestedVersion)
+	err = updateVersionMap(vctx, f.DB, ft)
+	vspan.End()
+	if err != nil {
 		log.Error(ctx, err)
 		if ft.Status != http.StatusInternalServerError {
 			ft.Error = err
@@ -270,6 +325,71 @@ func (f *Fetcher) FetchAndUpdateState(ctx context.Context, modulePath, requested
 	return ft.Status, ft.ResolvedVersion, ft.Error
 }
 
+// unchangedSinceLastFetch returns the state of the module version described
+// by info if it was already processed successfully by this app version or a
+// newer one, and the proxy still reports the commit time that was stored
+// then. In that case, fetching it again would produce the same data, so the
+// zip need not be downloaded. Otherwise it returns nil.
+//
+// A newer app version always processes the module again, so that changes to
+// rendering logic take effect. App versions are compared as strings, as in
+// the requeue queries of internal/postgres. A module version whose
+// documentation was purged is also processed again, to restore it.
+func (f *Fetcher) unchangedSinceLastFetch(ctx context.Context, modulePath string, info *proxy.VersionInfo, appVersionLabel string) (_ *internal.ModuleVersionState, err error) {
+	defer derrors.Wrap(&err, "unchangedSinceLastFetch(%q, %q, %q)", modulePath, info.Version, appVersionLabel)
+
+	if appVersionLabel == "" || info.Time.IsZero() {
+		return nil, nil
+	}
+	vs, err := f.DB.GetModuleVersionState(ctx, modulePath, info.Version)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if vs.Status != http.StatusOK && vs.Status != derrors.ToStatus(derrors.HasIncompletePackages) {
+		return nil, nil
+	}
+	if vs.AppVersion < appVersionLabel || vs.DocumentationPurged {
+		return nil, nil
+	}
+	mi, err := f.DB.GetModuleInfo(ctx, modulePath, info.Version)
+	if err != nil {
+		if errors.Is(err, derrors.NotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !mi.CommitTime.Equal(info.Time) {
+		return nil, nil
+	}
+	return vs, nil
+}
+
+// unchangedFetchTask returns a fetchTask for a module version that need not
+// be processed again, with the results of its earlier fetch described by vs.
+func (f *Fetcher) unchangedFetchTask(ctx context.Context, modulePath, requestedVersion string, vs *internal.ModuleVersionState) (_ *fetchTask, err error) {
+	defer derrors.Wrap(&err, "unchangedFetchTask(%q, %q)", modulePath, vs.Version)
+
+	pvs, err := f.DB.GetPackageVersionStatesForModule(ctx, modulePath, vs.Version)
+	if err != nil {
+		return nil, err
+	}
+	return &fetchTask{
+		FetchResult: fetch.FetchResult{
+			ModulePath:           modulePath,
+			RequestedVersion:     requestedVersion,
+			ResolvedVersion:      vs.Version,
+			Status:               vs.Status,
+			HasGoMod:             vs.HasGoMod,
+			GoModPath:            vs.GoModPath,
+			PackageVersionStates: pvs,
+		},
+		timings: map[string]time.Duration{},
+	}, nil
+}
+
 func getInfo(ctx context.Context, modulePath, requestedVersion string, prox *proxy.Client) (_ *proxy.VersionInfo, err error) {
 	if modulePath == stdlib.ModulePath {
 		var resolvedVersion string
@@ -282,8 +402,9 @@ func getInfo(ctx context.Context, modulePath, requestedVersion string, prox *pro
 	return prox.Info(ctx, modulePath, requestedVersion)
 }
 
-// fetchAndInsertModule fetches the given module version from the module proxy
-// or (in the case of the standard library) from the Go repo and writes the
+// fetchAndInsertModule fetches the given module version from the module proxy,
+// directly from its repository if the proxy falls back to f.DirectGetter, or
+// (in the case of the standard library) from the Go repo and writes the
 // resulting data to the database.
 func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requestedVersion string, lmv *internal.LatestModuleVersions) *fetchTask {
 	ft := &fetchTask{
@@ -293,6 +414,8 @@ func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requeste
 		},
 		timings: map[string]time.Duration{},
 	}
+	ctx, span := f.startSpan(ctx, "fetchAndInsertModule", modulePath, requestedVersion)
+	defer span.End()
 	defer func() {
 		derrors.Wrap(&ft.Error, "fetchAndInsertModule(%q, %q)", modulePath, requestedVersion)
 		if ft.Error != nil {
@@ -320,7 +443,13 @@ func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requeste
 	go func() {
 		defer wg.Done()
 		start := time.Now()
-		fr := fetch.FetchModule(ctx, modulePath, requestedVersion, proxyGetter)
+		ctx, span := f.startSpan(ctx, "fetch.FetchModule", modulePath, requestedVersion)
+		defer span.End()
+		getter := proxyGetter
+		if f.DirectGetter != nil {
+			getter = fetch.NewProxyModuleGetterWithDirect(f.ProxyClient, f.SourceClient, f.DirectGetter)
+		}
+		fr := fetch.FetchModule(ctx, modulePath, requestedVersion, getter)
 		if fr == nil {
 			panic("fetch.FetchModule should never return a nil FetchResult")
 		}
@@ -360,11 +489,18 @@ func (f *Fetcher) fetchAndInsertModule(ctx context.Context, modulePath, requeste
 
 	// The module was successfully fetched.
 	log.Debugf(ctx, "fetch.FetchModule succeeded for %s@%s", ft.ModulePath, ft.RequestedVersion)
+	if ft.ValidationReport != nil {
+		for _, e := range ft.ValidationReport.Entries {
+			log.Infof(ctx, "%s@%s: %s: %s", ft.ModulePath, ft.ResolvedVersion, e.Kind, e.Message)
+		}
+	}
 
 	// Determine the current latest-version information for this module.
 
 	start := time.Now()
-	isLatest, err := f.DB.InsertModule(ctx, ft.Module, lmv)
+	ictx, ispan := f.startSpan(ctx, "db.InsertModule", ft.ModulePath, ft.ResolvedVersion)
+	isLatest, err := f.DB.InsertModule(ictx, ft.Module, lmv)
+	ispan.End()
 	ft.timings["db.InsertModule"] = time.Since(start)
 	if err != nil {
 		ft.Status = derrors.ToStatus(err)
@@ -0,0 +1,63 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/fetch"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+// FetchLocalModule fetches the module in the local directory dir, which must
+// contain a go.mod file, and inserts it into the database. It lets custom
+// pkgsite deployments be developed and tested without a proxy.
+//
+// The module is given a pseudo-version for the current time, which becomes
+// its latest version. FetchLocalModule returns the module path and that
+// version.
+func (f *Fetcher) FetchLocalModule(ctx context.Context, dir string) (modulePath, resolvedVersion string, err error) {
+	defer derrors.Wrap(&err, "FetchLocalModule(%q)", dir)
+
+	g, err := fetch.NewDevDirectoryModuleGetter(dir, time.Now())
+	if err != nil {
+		return "", "", err
+	}
+	ft := &fetchTask{timings: map[string]time.Duration{}}
+	start := time.Now()
+	ft.FetchResult = *fetch.FetchModule(ctx, g.ModulePath(), version.Latest, g)
+	ft.timings["fetch.FetchModule"] = time.Since(start)
+	if ft.Error != nil {
+		return "", "", ft.Error
+	}
+
+	goMod, err := g.Mod(ctx, ft.ModulePath, ft.ResolvedVersion)
+	if err != nil {
+		return "", "", err
+	}
+	lmv, err := internal.NewLatestModuleVersions(ft.ModulePath, ft.ResolvedVersion, ft.ResolvedVersion, "", goMod)
+	if err != nil {
+		return "", "", err
+	}
+	lmv, err = f.DB.UpdateLatestModuleVersions(ctx, lmv)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := f.DB.InsertModule(ctx, ft.Module, lmv); err != nil {
+		return "", "", err
+	}
+	if err := updateVersionMap(ctx, f.DB, ft); err != nil {
+		return "", "", err
+	}
+	if err := f.invalidateCache(ctx, ft.ModulePath); err != nil {
+		log.Errorf(ctx, "failed to invalidate cache for %s: %v", ft.ModulePath, err)
+	}
+	logTaskResult(ctx, ft, "Local fetch")
+	return ft.ModulePath, ft.ResolvedVersion, nil
+}
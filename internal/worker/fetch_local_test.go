@@ -0,0 +1,70 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/source"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
+	"golang.org/x/pkgsite/internal/version"
+)
+
+func TestFetchLocalModule(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	dir := t.TempDir()
+	for name, contents := range map[string]string{
+		"go.mod":     "module example.com/local\n\ngo 1.19\n",
+		"LICENSE":    testhelper.MITLicense,
+		"foo/foo.go": "// Package foo is local.\npackage foo\n\nconst Foo = 1\n",
+	} {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// No proxy is needed.
+	f := &Fetcher{nil, source.NewClient(sourceTimeout), testDB, nil, nil, "", nil, nil, nil}
+	modulePath, resolvedVersion, err := f.FetchLocalModule(ctx, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "example.com/local"; modulePath != want {
+		t.Errorf("got module path %q, want %q", modulePath, want)
+	}
+	if !version.IsPseudo(resolvedVersion) {
+		t.Errorf("got version %q, want a pseudo-version", resolvedVersion)
+	}
+
+	// The module is the latest, so it is served without a version.
+	um, err := testDB.GetUnitMeta(ctx, "example.com/local/foo", internal.UnknownModulePath, version.Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if um.ModulePath != modulePath || um.Version != resolvedVersion {
+		t.Errorf("got %s@%s, want %s@%s", um.ModulePath, um.Version, modulePath, resolvedVersion)
+	}
+	vm, err := testDB.GetVersionMap(ctx, modulePath, version.Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.Status != http.StatusOK || vm.ResolvedVersion != resolvedVersion {
+		t.Errorf("got version map status %d, version %q; want %d, %q",
+			vm.Status, vm.ResolvedVersion, http.StatusOK, resolvedVersion)
+	}
+}
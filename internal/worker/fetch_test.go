@@ -6,21 +6,29 @@ package worker
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/experiment"
+	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/godoc"
 	"golang.org/x/pkgsite/internal/licenses"
 	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/proxy"
 	"golang.org/x/pkgsite/internal/proxy/proxytest"
 	"golang.org/x/pkgsite/internal/source"
 	"golang.org/x/pkgsite/internal/stdlib"
 	"golang.org/x/pkgsite/internal/testing/sample"
+	"golang.org/x/pkgsite/internal/testing/testhelper"
 	"golang.org/x/pkgsite/internal/version"
 )
 
@@ -217,6 +225,37 @@ func TestFetchAndUpdateState(t *testing.T) {
 				}},
 			},
 			wantDoc: []string{"int64 is the set of all signed 64-bit integers."},
+		}, {
+			modulePath: "std",
+			version:    "v1.12.5",
+			pkg:        "errors",
+			want: &internal.Unit{
+				UnitMeta: internal.UnitMeta{
+					ModuleInfo: internal.ModuleInfo{
+						ModulePath:        "std",
+						Version:           "v1.12.5",
+						HasGoMod:          true,
+						CommitTime:        stdlib.TestCommitTime,
+						SourceInfo:        source.NewStdlibInfo("v1.12.5"),
+						IsRedistributable: true,
+					},
+					IsRedistributable: true,
+					Path:              "errors",
+					Name:              "errors",
+					Licenses: []*licenses.Metadata{
+						{
+							Types:    []string{"BSD-3-Clause"},
+							FilePath: "LICENSE",
+						},
+					},
+				},
+				Documentation: []*internal.Documentation{{
+					Synopsis: "Package errors implements functions to manipulate errors.",
+					GOOS:     "linux",
+					GOARCH:   "amd64",
+				}},
+			},
+			wantDoc: []string{"New returns an error that formats as the given text."},
 		}, {
 			modulePath: "std",
 			version:    "v1.12.5",
@@ -319,7 +358,9 @@ func TestFetchAndUpdateState(t *testing.T) {
 			sort.Slice(got.Licenses, func(i, j int) bool {
 				return got.Licenses[i].FilePath < got.Licenses[j].FilePath
 			})
-			if diff := cmp.Diff(test.want.UnitMeta, *got, cmpopts.EquateEmpty(), cmp.AllowUnexported(source.Info{})); diff != "" {
+			// DocStats is tested in package fetch.
+			if diff := cmp.Diff(test.want.UnitMeta, *got, cmpopts.EquateEmpty(), cmp.AllowUnexported(source.Info{}),
+				cmpopts.IgnoreFields(internal.UnitMeta{}, "DocStats", "ProcessedAt")); diff != "" {
 				t.Fatalf("testDB.GetUnitMeta(ctx, %q, %q) mismatch (-want +got):\n%s", test.modulePath, test.version, diff)
 			}
 
@@ -333,6 +374,7 @@ func TestFetchAndUpdateState(t *testing.T) {
 				cmp.AllowUnexported(source.Info{}),
 				cmpopts.IgnoreFields(internal.Unit{}, "Documentation", "BuildContexts"),
 				cmpopts.IgnoreFields(internal.Unit{}, "SymbolHistory"),
+				cmpopts.IgnoreFields(internal.UnitMeta{}, "DocStats", "ProcessedAt"),
 				cmpopts.IgnoreFields(internal.Unit{}, "Subdirectories")); diff != "" {
 				t.Errorf("mismatch on readme (-want +got):\n%s", diff)
 			}
@@ -401,7 +443,7 @@ func TestFetchAndUpdateStateCacheZip(t *testing.T) {
 	defer teardownProxy()
 
 	// With a plain proxy, we download the zip twice.
-	f := &Fetcher{proxyClient, source.NewClient(sourceTimeout), testDB, nil, nil, ""}
+	f := &Fetcher{proxyClient, source.NewClient(sourceTimeout), testDB, nil, nil, "", nil, nil, nil}
 	if _, _, err := f.FetchAndUpdateState(ctx, "m.com", "v1.0.0", testAppVersion); err != nil {
 		t.Fatal(err)
 	}
@@ -422,6 +464,204 @@ func TestFetchAndUpdateStateCacheZip(t *testing.T) {
 
 }
 
+func TestFetchAndUpdateStateSkipUnchanged(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+	proxyServer := proxytest.NewServer([]*proxytest.Module{
+		{
+			ModulePath: "m.com",
+			Version:    "v1.0.0",
+			Files:      map[string]string{"a.go": "package a"},
+		},
+	})
+	proxyClient, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+
+	f := &Fetcher{proxyClient, source.NewClient(sourceTimeout), testDB, nil, nil, "", nil, nil, nil}
+	fetch := func(requestedVersion, appVersion string, wantZipRequests int) {
+		t.Helper()
+		status, resolved, err := f.FetchAndUpdateState(ctx, "m.com", requestedVersion, appVersion)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != http.StatusOK || resolved != "v1.0.0" {
+			t.Errorf("%s, app version %q: got (%d, %q), want (200, v1.0.0)", requestedVersion, appVersion, status, resolved)
+		}
+		if got := proxyServer.ZipRequests(); got != wantZipRequests {
+			t.Errorf("%s, app version %q: got %d downloads, want %d", requestedVersion, appVersion, got, wantZipRequests)
+		}
+	}
+	fetch("v1.0.0", testAppVersion, 1)
+	// The proxy info and app version are the same, so the zip isn't downloaded.
+	fetch("v1.0.0", testAppVersion, 1)
+	// A request for latest resolves to the unchanged version. It isn't
+	// downloaded either, but the version map is updated so that a frontend
+	// fetch of latest finds the result.
+	fetch(version.Latest, testAppVersion, 1)
+	vm, err := testDB.GetVersionMap(ctx, "m.com", version.Latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vm.ResolvedVersion != "v1.0.0" || vm.Status != http.StatusOK {
+		t.Errorf("version map for latest: got (%q, %d), want (v1.0.0, 200)", vm.ResolvedVersion, vm.Status)
+	}
+	// A different app version processes the module again.
+	fetch("v1.0.0", testAppVersion+"2", 2)
+	// So does the same app version after the documentation is purged.
+	if err := testDB.PurgeDocumentation(ctx, "m.com", "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	fetch("v1.0.0", testAppVersion+"2", 3)
+	// After that fetch, the module is unchanged again.
+	fetch("v1.0.0", testAppVersion+"2", 3)
+}
+
+func TestFetchAndUpdateStateDirect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	const modulePath = "example.com/direct"
+	repoURL := testhelper.ServeGitRepo(t, `
+-- go.mod --
+module example.com/direct
+
+go 1.19
+-- LICENSE --
+`+testhelper.MITLicense+`
+-- pkg/pkg.go --
+// Package pkg is fetched directly.
+package pkg
+`, "v1.2.3")
+	direct, err := fetch.NewVCSModuleGetterForTesting(t.TempDir(), repoURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The proxy doesn't have the module, and falls back to direct.
+	proxyServer := proxytest.NewServer(nil)
+	testClient, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+	proxyClient, err := proxy.NewFromGOPROXY("https://proxy.test,direct")
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyClient.HTTPClient = testClient.HTTPClient
+
+	f := &Fetcher{proxyClient, source.NewClient(sourceTimeout), testDB, nil, nil, "", nil, nil, direct}
+	status, resolved, err := f.FetchAndUpdateState(ctx, modulePath, "v1.2.3", testAppVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != http.StatusOK || resolved != "v1.2.3" {
+		t.Errorf("got (%d, %q), want (200, v1.2.3)", status, resolved)
+	}
+	// The module was stored from its repository: the commit, which the
+	// proxy doesn't know, is recorded.
+	um, err := testDB.GetUnitMeta(ctx, modulePath+"/pkg", modulePath, "v1.2.3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := um.Commit; c == nil || len(c.Hash) != 40 || c.AuthorName != "gopher" {
+		t.Errorf("commit: got %+v, want gopher's", c)
+	}
+	if got := proxyServer.ZipRequests(); got != 0 {
+		t.Errorf("got %d zip downloads from the proxy, want 0", got)
+	}
+}
+
+func TestFetchAndUpdateStateGoMod(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+
+	// Comments and unusual formatting must be preserved.
+	const goMod = "// A comment.\nmodule m.com\n\ngo   1.19\n\nrequire (\n\texample.com/dep v1.0.0 // indirect\n)\n"
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{
+			ModulePath: "m.com",
+			Version:    "v1.0.0",
+			Files: map[string]string{
+				"go.mod": goMod,
+				"a.go":   "package a",
+			},
+		},
+	})
+	defer teardownProxy()
+
+	f := &Fetcher{proxyClient, source.NewClient(sourceTimeout), testDB, nil, nil, "", nil, nil, nil}
+	if _, _, err := f.FetchAndUpdateState(ctx, "m.com", "v1.0.0", testAppVersion); err != nil {
+		t.Fatal(err)
+	}
+	got, err := testDB.GetGoMod(ctx, "m.com", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != goMod {
+		t.Errorf("got go.mod %q, want %q", got, goMod)
+	}
+}
+
+func TestFetchAndUpdateStateRerenderNewAppVersion(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+	const (
+		modulePath = "m.com"
+		version    = "v1.0.0"
+		synopsis   = "Package a does things."
+	)
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{
+			ModulePath: modulePath,
+			Version:    version,
+			Files:      map[string]string{"a.go": "// " + synopsis + "\npackage a"},
+		},
+	})
+	defer teardownProxy()
+	f := &Fetcher{proxyClient, source.NewClient(sourceTimeout), testDB, nil, nil, "", nil, nil, nil}
+
+	// fetchWithStaleDoc replaces the stored documentation with a stale
+	// synopsis, fetches with appVersion, and checks the synopsis afterwards.
+	fetchWithStaleDoc := func(appVersion, wantSynopsis string) {
+		t.Helper()
+		if _, err := testDB.Underlying().Exec(ctx, `UPDATE documentation SET synopsis = 'stale'`); err != nil {
+			t.Fatal(err)
+		}
+		if _, _, err := f.FetchAndUpdateState(ctx, modulePath, version, appVersion); err != nil {
+			t.Fatal(err)
+		}
+		um, err := testDB.GetUnitMeta(ctx, modulePath, modulePath, version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(u.Documentation) == 0 {
+			t.Fatal("no documentation")
+		}
+		if got := u.Documentation[0].Synopsis; got != wantSynopsis {
+			t.Errorf("app version %q: got synopsis %q, want %q", appVersion, got, wantSynopsis)
+		}
+	}
+
+	const appVersion = "20230102t000000"
+	if _, _, err := f.FetchAndUpdateState(ctx, modulePath, version, appVersion); err != nil {
+		t.Fatal(err)
+	}
+	// The same or an older app version doesn't render the documentation
+	// again.
+	fetchWithStaleDoc(appVersion, "stale")
+	fetchWithStaleDoc("20230101t000000", "stale")
+	// A newer one does.
+	fetchWithStaleDoc("20230103t000000", synopsis)
+}
+
 func TestFetchAndUpdateLatest(t *testing.T) {
 	ctx := context.Background()
 	prox, teardown := proxytest.SetupTestClient(t, testModules)
@@ -447,3 +687,342 @@ func TestFetchAndUpdateLatest(t *testing.T) {
 			modulePath, wantRaw, wantCooked)
 	}
 }
+
+func TestFetchAndUpdateStateMajorSubdirectory(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+
+	// The repository of the module uses the major-subdirectory layout: v2 of
+	// the module is in its v2 directory, and is tagged with the same
+	// versions. The proxy serves each major version as its own module.
+	const modulePath = "example.com/subdir"
+	proxyClient, teardown := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{
+			ModulePath: modulePath,
+			Version:    "v1.0.0",
+			Files: map[string]string{
+				"go.mod":        "module " + modulePath,
+				"LICENSE":       testhelper.MITLicense,
+				"foo/foo.go":    "package foo",
+				"v2/go.mod":     "module " + modulePath + "/v2",
+				"v2/foo/foo.go": "package foo",
+			},
+		},
+		{
+			ModulePath: modulePath + "/v2",
+			Version:    "v2.0.0",
+			Files: map[string]string{
+				"go.mod":     "module " + modulePath + "/v2",
+				"LICENSE":    testhelper.MITLicense,
+				"foo/foo.go": "package foo",
+			},
+		},
+	})
+	defer teardown()
+
+	f := &Fetcher{proxyClient, source.NewClient(sourceTimeout), testDB, nil, nil, "", nil, nil, nil}
+	for _, mp := range []string{modulePath, modulePath + "/v2"} {
+		if status, _, err := f.FetchAndUpdateState(ctx, mp, version.Latest, testAppVersion); err != nil || status != http.StatusOK {
+			t.Fatalf("FetchAndUpdateState(%q): got (%d, %v), want (200, nil)", mp, status, err)
+		}
+	}
+
+	lmv, err := f.FetchAndUpdateLatest(ctx, modulePath+"/v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lmv.RawVersion != "v2.0.0" {
+		t.Errorf("latest version of v2: got %q, want v2.0.0", lmv.RawVersion)
+	}
+	// The v2 directory is not part of v1 of the module.
+	if _, err := testDB.GetUnitMeta(ctx, modulePath+"/v2/foo", modulePath, "v1.0.0"); !errors.Is(err, derrors.NotFound) {
+		t.Errorf("v2 package in v1 of the module: got %v, want NotFound", err)
+	}
+	// v2 is the latest major version of the module.
+	mvs, err := testDB.GetLatestMajorVersions(ctx, modulePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, mv := range mvs {
+		got = append(got, mv.ModulePath+"@"+mv.Version)
+	}
+	want := []string{modulePath + "@v1.0.0", modulePath + "/v2@v2.0.0"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("GetLatestMajorVersions mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+// fakeFetchMetrics is a FetchMetrics that records its measurements.
+type fakeFetchMetrics struct {
+	mu        sync.Mutex
+	durations []time.Duration
+	statuses  []int
+	zipSizes  []int64
+}
+
+func (m *fakeFetchMetrics) ObserveFetchDuration(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.durations = append(m.durations, d)
+}
+
+func (m *fakeFetchMetrics) IncFetchResult(status int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.statuses = append(m.statuses, status)
+}
+
+func (m *fakeFetchMetrics) ObserveZipSize(bytes int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.zipSizes = append(m.zipSizes, bytes)
+}
+
+func TestFetchAndUpdateStateMetrics(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{
+			ModulePath: "m.com",
+			Version:    "v1.0.0",
+			Files:      map[string]string{"a.go": "package a"},
+		},
+	})
+	defer teardownProxy()
+
+	metrics := &fakeFetchMetrics{}
+	f := &Fetcher{
+		ProxyClient:  proxyClient,
+		SourceClient: source.NewClient(sourceTimeout),
+		DB:           testDB,
+		loadShedder:  &loadShedder{maxSizeInFlight: 100 * mib},
+		Metrics:      metrics,
+	}
+	if _, _, err := f.FetchAndUpdateState(ctx, "m.com", "v1.0.0", testAppVersion); err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics.durations) != 1 || metrics.durations[0] <= 0 {
+		t.Errorf("got durations %v, want one positive duration", metrics.durations)
+	}
+	if diff := cmp.Diff([]int{http.StatusOK}, metrics.statuses); diff != "" {
+		t.Errorf("statuses mismatch (-want, +got):\n%s", diff)
+	}
+	if len(metrics.zipSizes) != 1 || metrics.zipSizes[0] <= 0 {
+		t.Errorf("got zip sizes %v, want one positive size", metrics.zipSizes)
+	}
+}
+
+func TestFetchAndUpdateStateDocFormats(t *testing.T) {
+	ctx := experiment.NewContext(context.Background(), internal.ExperimentStoreDocFormats)
+	defer postgres.ResetTestDB(testDB, t)
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{
+			ModulePath: "m.com",
+			Version:    "v1.0.0",
+			Files:      map[string]string{"a/a.go": "// Package a is documented.\npackage a\n\n// F is a function.\nfunc F() {}"},
+		},
+	})
+	defer teardownProxy()
+
+	f := &Fetcher{
+		ProxyClient:  proxyClient,
+		SourceClient: source.NewClient(sourceTimeout),
+		DB:           testDB,
+		loadShedder:  &loadShedder{maxSizeInFlight: 100 * mib},
+	}
+	if _, _, err := f.FetchAndUpdateState(ctx, "m.com", "v1.0.0", testAppVersion); err != nil {
+		t.Fatal(err)
+	}
+	um, err := testDB.GetUnitMeta(ctx, "m.com/a", "m.com", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Both formats are stored by the one fetch, and can be read with or
+	// without the rest of the unit.
+	for _, fields := range []internal.FieldSet{
+		internal.WithDocHTML | internal.WithDocText,
+		internal.WithMain | internal.WithDocHTML | internal.WithDocText,
+	} {
+		u, err := testDB.GetUnit(ctx, um, fields, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(u.Documentation) != 1 {
+			t.Fatalf("fields %d: got %d documentations, want 1", fields, len(u.Documentation))
+		}
+		doc := u.Documentation[0]
+		if !strings.Contains(doc.HTML, `id="F"`) {
+			t.Errorf("fields %d: HTML does not document F:\n%s", fields, doc.HTML)
+		}
+		if !strings.Contains(doc.Text, "F is a function.") {
+			t.Errorf("fields %d: Text does not document F:\n%s", fields, doc.Text)
+		}
+	}
+}
+
+func TestFetchAndUpdateStateDocHTMLNoLinks(t *testing.T) {
+	ctx := experiment.NewContext(context.Background(), internal.ExperimentStoreDocFormats)
+	defer postgres.ResetTestDB(testDB, t)
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{
+			ModulePath: "m.com",
+			Version:    "v1.0.0",
+			Files: map[string]string{"a/a.go": `// Package a is documented.
+package a
+
+import "strings"
+
+// F returns a new [strings.Builder]. See https://go.dev.
+func F() *strings.Builder { return nil }`},
+		},
+	})
+	defer teardownProxy()
+
+	f := &Fetcher{
+		ProxyClient:  proxyClient,
+		SourceClient: source.NewClient(sourceTimeout),
+		DB:           testDB,
+		loadShedder:  &loadShedder{maxSizeInFlight: 100 * mib},
+	}
+	if _, _, err := f.FetchAndUpdateState(ctx, "m.com", "v1.0.0", testAppVersion); err != nil {
+		t.Fatal(err)
+	}
+	um, err := testDB.GetUnitMeta(ctx, "m.com/a", "m.com", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	getHTML := func(fields internal.FieldSet) string {
+		t.Helper()
+		u, err := testDB.GetUnit(ctx, um, fields, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(u.Documentation) != 1 {
+			t.Fatalf("fields %d: got %d documentations, want 1", fields, len(u.Documentation))
+		}
+		return u.Documentation[0].HTML
+	}
+	links := []string{`href="/strings#Builder"`, `href="https://go.dev"`}
+	withLinks := getHTML(internal.WithDocHTML)
+	noLinks, err := godoc.StripExternalLinks(withLinks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, link := range links {
+		if !strings.Contains(withLinks, link) {
+			t.Errorf("WithDocHTML: HTML does not contain %s:\n%s", link, withLinks)
+		}
+		if strings.Contains(noLinks, link) {
+			t.Errorf("StripExternalLinks: HTML contains %s:\n%s", link, noLinks)
+		}
+	}
+	if want := "F returns a new strings.Builder."; !strings.Contains(noLinks, want) {
+		t.Errorf("StripExternalLinks: HTML does not contain %q:\n%s", want, noLinks)
+	}
+	if want := `href="#F"`; !strings.Contains(noLinks, want) {
+		t.Errorf("StripExternalLinks: HTML does not contain %s:\n%s", want, noLinks)
+	}
+}
+
+func TestFetchAndUpdateStateReadmePerVersion(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+	readmes := map[string]string{
+		"v1.0.0": "README A",
+		"v1.1.0": "README B",
+	}
+	var mods []*proxytest.Module
+	for v, readme := range readmes {
+		mods = append(mods, &proxytest.Module{
+			ModulePath: "m.com",
+			Version:    v,
+			Files: map[string]string{
+				"README.md": readme,
+				"a/a.go":    "package a",
+			},
+		})
+	}
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, mods)
+	defer teardownProxy()
+
+	f := &Fetcher{
+		ProxyClient:  proxyClient,
+		SourceClient: source.NewClient(sourceTimeout),
+		DB:           testDB,
+		loadShedder:  &loadShedder{maxSizeInFlight: 100 * mib},
+	}
+	for v := range readmes {
+		if _, _, err := f.FetchAndUpdateState(ctx, "m.com", v, testAppVersion); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Each version has its own README, not that of the latest version.
+	for v, want := range readmes {
+		um, err := testDB.GetUnitMeta(ctx, "m.com", "m.com", v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		u, err := testDB.GetUnit(ctx, um, internal.WithMain, internal.BuildContext{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if u.Readme == nil || u.Readme.Contents != want {
+			t.Errorf("%s: got README %+v, want contents %q", v, u.Readme, want)
+		}
+	}
+}
+
+func TestFetchAndUpdateStateNoPackages(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+	const modulePath = "no.go/files"
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{
+			ModulePath: modulePath,
+			Version:    "v1.0.0",
+			Files: map[string]string{
+				"go.mod":    "module " + modulePath,
+				"LICENSE":   testhelper.MITLicense,
+				"README.md": "This module has no packages.",
+			},
+		},
+	})
+	defer teardownProxy()
+
+	f := &Fetcher{
+		ProxyClient:  proxyClient,
+		SourceClient: source.NewClient(sourceTimeout),
+		DB:           testDB,
+		loadShedder:  &loadShedder{maxSizeInFlight: 100 * mib},
+	}
+	code, _, err := f.FetchAndUpdateState(ctx, modulePath, "v1.0.0", testAppVersion)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != http.StatusOK {
+		t.Fatalf("got code %d, want %d", code, http.StatusOK)
+	}
+	has, err := testDB.HasPackages(ctx, modulePath, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if has {
+		t.Error("got HasPackages = true, want false")
+	}
+	// The module's README and licenses are stored.
+	um, err := testDB.GetUnitMeta(ctx, modulePath, modulePath, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, err := testDB.GetUnit(ctx, um, internal.WithMain|internal.WithLicenses, internal.BuildContext{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Readme == nil || u.Readme.Contents != "This module has no packages." {
+		t.Errorf("got README %+v, want the module's README", u.Readme)
+	}
+	if len(u.LicenseContents) != 1 {
+		t.Errorf("got %d licenses, want 1", len(u.LicenseContents))
+	}
+}
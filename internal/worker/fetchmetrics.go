@@ -0,0 +1,38 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import "time"
+
+// FetchMetrics receives measurements of the fetches of a Fetcher. It lets a
+// deployment export them to a metrics system of its choice, in addition to
+// the OpenCensus views of this package. Implementations must be safe for
+// concurrent use.
+type FetchMetrics interface {
+	// ObserveFetchDuration is called with the time a fetch took, whatever
+	// its result.
+	ObserveFetchDuration(d time.Duration)
+	// IncFetchResult is called with the HTTP status of each fetch.
+	IncFetchResult(status int)
+	// ObserveZipSize is called with the size of the zip of a module version
+	// that is about to be processed, when the size is known.
+	ObserveZipSize(bytes int64)
+}
+
+// noFetchMetrics is a FetchMetrics that discards its measurements.
+type noFetchMetrics struct{}
+
+func (noFetchMetrics) ObserveFetchDuration(time.Duration) {}
+func (noFetchMetrics) IncFetchResult(int)                 {}
+func (noFetchMetrics) ObserveZipSize(int64)               {}
+
+// metrics returns f.Metrics, or a FetchMetrics that does nothing if it is
+// nil.
+func (f *Fetcher) metrics() FetchMetrics {
+	if f.Metrics == nil {
+		return noFetchMetrics{}
+	}
+	return f.Metrics
+}
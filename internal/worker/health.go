@@ -0,0 +1,56 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/proxy"
+)
+
+// DefaultHealthCheckModule is the module version whose info HealthCheck
+// requests from the proxy, unless the server is configured with another. It
+// should be one that every proxy has cached.
+var DefaultHealthCheckModule = internal.Modver{Path: "golang.org/x/mod", Version: "v0.6.0"}
+
+// A HealthError describes the dependencies of the worker that failed a
+// HealthCheck.
+type HealthError struct {
+	Proxy error // error from the proxy round-trip, or nil
+	DB    error // error from pinging the database, or nil
+}
+
+func (e *HealthError) Error() string {
+	var msgs []string
+	if e.Proxy != nil {
+		msgs = append(msgs, fmt.Sprintf("proxy: %v", e.Proxy))
+	}
+	if e.DB != nil {
+		msgs = append(msgs, fmt.Sprintf("DB: %v", e.DB))
+	}
+	return "health check failed: " + strings.Join(msgs, "; ")
+}
+
+// HealthCheck reports whether the worker can reach the proxy and the
+// database, for readiness probes. It requests the info of the module version
+// probe, which should be cached by the proxy, from proxyClient and pings db.
+// If either fails, it returns a *HealthError describing the failures.
+func HealthCheck(ctx context.Context, proxyClient *proxy.Client, db *postgres.DB, probe internal.Modver) error {
+	var herr HealthError
+	if _, err := proxyClient.Info(ctx, probe.Path, probe.Version); err != nil {
+		herr.Proxy = err
+	}
+	if err := db.Underlying().PingContext(ctx); err != nil {
+		herr.DB = err
+	}
+	if herr.Proxy != nil || herr.DB != nil {
+		return &herr
+	}
+	return nil
+}
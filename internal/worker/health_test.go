@@ -0,0 +1,64 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/database"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+)
+
+func TestHealthCheck(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	probe := internal.Modver{Path: "example.com/probe", Version: "v1.0.0"}
+	proxyServer := proxytest.NewServer([]*proxytest.Module{{
+		ModulePath: probe.Path,
+		Version:    probe.Version,
+		Files:      map[string]string{"a.go": "package a"},
+	}})
+	proxyClient, teardownProxy, err := proxytest.NewClientForServer(proxyServer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardownProxy()
+
+	if err := HealthCheck(ctx, proxyClient, testDB, probe); err != nil {
+		t.Fatalf("healthy: got %v, want nil", err)
+	}
+
+	sqlDB, err := sql.Open("postgres", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.Close()
+	closedDB := postgres.New(database.New(sqlDB, "test"))
+	defer closedDB.Close()
+
+	// A module version the proxy doesn't have is a proxy failure.
+	err = HealthCheck(ctx, proxyClient, testDB, internal.Modver{Path: "example.com/missing", Version: "v1.0.0"})
+	var herr *HealthError
+	if !errors.As(err, &herr) {
+		t.Fatalf("missing probe: got %v, want a *HealthError", err)
+	}
+	if herr.Proxy == nil || herr.DB != nil {
+		t.Errorf("missing probe: got DB error %v, proxy error %v; want only a proxy error", herr.DB, herr.Proxy)
+	}
+
+	err = HealthCheck(ctx, proxyClient, closedDB, probe)
+	if !errors.As(err, &herr) {
+		t.Fatalf("closed DB: got %v, want a *HealthError", err)
+	}
+	if herr.DB == nil || herr.Proxy != nil {
+		t.Errorf("closed DB: got DB error %v, proxy error %v; want only a DB error", herr.DB, herr.Proxy)
+	}
+}
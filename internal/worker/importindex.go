@@ -0,0 +1,142 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/index"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/queue"
+)
+
+// An indexCursor records how far the module index has been imported.
+// It is implemented by *postgres.DB, whose cursor is the latest index
+// timestamp in module_version_states.
+type indexCursor interface {
+	LatestIndexTimestamp(ctx context.Context) (time.Time, error)
+	InsertIndexVersions(ctx context.Context, versions []*internal.IndexVersion) error
+}
+
+// An indexPosition is a position in the module index. Versions are ordered
+// by timestamp, and versions with the same timestamp by path and version.
+type indexPosition struct {
+	timestamp     time.Time
+	path, version string
+}
+
+func positionOf(v *internal.IndexVersion) indexPosition {
+	return indexPosition{v.Timestamp, v.Path, v.Version}
+}
+
+// before reports whether p is before q.
+func (p indexPosition) before(q indexPosition) bool {
+	if !p.timestamp.Equal(q.timestamp) {
+		return p.timestamp.Before(q.timestamp)
+	}
+	if p.path != q.path {
+		return p.path < q.path
+	}
+	return p.version < q.version
+}
+
+// importIndex reads the module index from the position of cur, a page of
+// pageSize versions at a time, and enqueues the versions it has not seen
+// before on q. After each page it advances cur past the page, so an import
+// that fails part way can be resumed. It stops after maxPages pages, or when
+// the index has no more versions. It returns the number of versions
+// enqueued.
+//
+// The index returns versions published at or after a given time, so each
+// page starts with versions that were already read. importIndex keeps its
+// position as a timestamp, path and version, and skips the versions at or
+// before it. A full page may end part way through the versions with its
+// last timestamp, which are only imported from a later page that holds all
+// of them. If a full page holds nothing new because all of its versions
+// share one timestamp, the page is read again with twice the size, so that
+// the import gets past them instead of stalling.
+//
+// A resumed import starts with the versions at the timestamp of cur.
+// They may have been enqueued by the previous import, but the queue's task
+// de-duplication prevents them from being fetched twice.
+func importIndex(ctx context.Context, ic *index.Client, cur indexCursor, q queue.Queue, pageSize, maxPages int) (n int, err error) {
+	defer derrors.Wrap(&err, "importIndex(%d, %d)", pageSize, maxPages)
+
+	since, err := cur.LatestIndexTimestamp(ctx)
+	if err != nil {
+		return n, err
+	}
+	pos := indexPosition{timestamp: since}
+	limit := pageSize
+	for page := 0; page < maxPages; page++ {
+		versions, err := ic.GetVersions(ctx, pos.timestamp, limit)
+		if err != nil {
+			return n, err
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return positionOf(versions[i]).before(positionOf(versions[j]))
+		})
+		complete := versions
+		atEnd := len(versions) < limit
+		if !atEnd {
+			// The versions with the last timestamp may continue on the next
+			// page.
+			last := versions[len(versions)-1].Timestamp
+			for len(complete) > 0 && complete[len(complete)-1].Timestamp.Equal(last) {
+				complete = complete[:len(complete)-1]
+			}
+		}
+		var newVersions []*internal.IndexVersion
+		for _, v := range complete {
+			if pos.before(positionOf(v)) {
+				newVersions = append(newVersions, v)
+			}
+		}
+		if len(newVersions) > 0 {
+			if err := cur.InsertIndexVersions(ctx, newVersions); err != nil {
+				return n, err
+			}
+		}
+		for _, v := range newVersions {
+			if _, err := q.ScheduleFetch(ctx, v.Path, v.Version, &queue.Options{Source: queue.SourceWorkerValue}); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if atEnd {
+			break
+		}
+		if len(newVersions) == 0 {
+			limit *= 2
+			continue
+		}
+		pos = positionOf(newVersions[len(newVersions)-1])
+		limit = pageSize
+	}
+	return n, nil
+}
+
+// handleImportIndex imports new versions from the module index and enqueues
+// them for fetching. The "limit" query parameter is the page size, and
+// "pages" is the maximum number of pages to read.
+func (s *Server) handleImportIndex(w http.ResponseWriter, r *http.Request) (err error) {
+	defer derrors.Wrap(&err, "handleImportIndex(%q)", r.URL.Path)
+	ctx := r.Context()
+	limit := parseLimitParam(r, 100)
+	pages := parseIntParam(r, "pages", 10)
+	n, err := importIndex(ctx, s.indexClient, s.db, s.queue, limit, pages)
+	log.Infof(ctx, "Enqueued %d modules from the index", n)
+	if err != nil {
+		return err
+	}
+	s.computeProcessingLag(ctx)
+	s.computeUnprocessedModules(ctx)
+	return nil
+}
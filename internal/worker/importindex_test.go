@@ -0,0 +1,130 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal"
+	"golang.org/x/pkgsite/internal/index"
+	"golang.org/x/pkgsite/internal/queue"
+)
+
+// fakeIndexCursor is an indexCursor that keeps the inserted versions in
+// memory.
+type fakeIndexCursor struct {
+	inserted []*internal.IndexVersion
+}
+
+func (c *fakeIndexCursor) LatestIndexTimestamp(context.Context) (time.Time, error) {
+	var latest time.Time
+	for _, v := range c.inserted {
+		if v.Timestamp.After(latest) {
+			latest = v.Timestamp
+		}
+	}
+	return latest, nil
+}
+
+func (c *fakeIndexCursor) InsertIndexVersions(_ context.Context, versions []*internal.IndexVersion) error {
+	c.inserted = append(c.inserted, versions...)
+	return nil
+}
+
+// recordingQueue is a queue.Queue that records the scheduled fetches.
+type recordingQueue struct {
+	scheduled []string
+}
+
+func (q *recordingQueue) ScheduleFetch(_ context.Context, modulePath, version string, _ *queue.Options) (bool, error) {
+	q.scheduled = append(q.scheduled, modulePath+"@"+version)
+	return true, nil
+}
+
+func TestImportIndex(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions := []*internal.IndexVersion{
+		{Path: "example.com/a", Version: "v1.0.0", Timestamp: t0},
+		{Path: "example.com/b", Version: "v1.0.0", Timestamp: t0.Add(time.Minute)},
+		{Path: "example.com/a", Version: "v1.1.0", Timestamp: t0.Add(2 * time.Minute)},
+		{Path: "example.com/c", Version: "v0.1.0", Timestamp: t0.Add(3 * time.Minute)},
+		{Path: "example.com/b", Version: "v1.1.0", Timestamp: t0.Add(4 * time.Minute)},
+	}
+	ic, teardown := index.SetupTestIndex(t, versions)
+	defer teardown()
+	cur := &fakeIndexCursor{}
+
+	check := func(q *recordingQueue, wantScheduled []string, wantCursor time.Time) {
+		t.Helper()
+		if diff := cmp.Diff(wantScheduled, q.scheduled); diff != "" {
+			t.Errorf("scheduled mismatch (-want, +got):\n%s", diff)
+		}
+		got, err := cur.LatestIndexTimestamp(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(wantCursor) {
+			t.Errorf("cursor = %s, want %s", got, wantCursor)
+		}
+	}
+
+	// Read three pages of two versions. The first page ends with the only
+	// version at t0+1m, which may continue on the next page, so only a@v1.0.0
+	// is imported. The second page holds nothing new, so the third is read
+	// with twice the size.
+	q := &recordingQueue{}
+	n, err := importIndex(ctx, ic, cur, q, 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 3 {
+		t.Errorf("got %d versions enqueued, want 3", n)
+	}
+	check(q, []string{"example.com/a@v1.0.0", "example.com/b@v1.0.0", "example.com/a@v1.1.0"}, t0.Add(2*time.Minute))
+
+	// Resume from the cursor and read to the end of the index. The version
+	// at the cursor is read again.
+	q = &recordingQueue{}
+	if _, err := importIndex(ctx, ic, cur, q, 2, 10); err != nil {
+		t.Fatal(err)
+	}
+	check(q, []string{"example.com/a@v1.1.0", "example.com/c@v0.1.0", "example.com/b@v1.1.0"}, t0.Add(4*time.Minute))
+
+	// Nothing new has been published, so only the version at the cursor is
+	// read again.
+	q = &recordingQueue{}
+	if _, err := importIndex(ctx, ic, cur, q, 2, 10); err != nil {
+		t.Fatal(err)
+	}
+	check(q, []string{"example.com/b@v1.1.0"}, t0.Add(4*time.Minute))
+}
+
+// Verify that importIndex gets past more versions with the same timestamp
+// than fit on a page.
+func TestImportIndexSameTimestamp(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	versions := []*internal.IndexVersion{
+		{Path: "example.com/a", Version: "v1.0.0", Timestamp: t0},
+		{Path: "example.com/b", Version: "v1.0.0", Timestamp: t0},
+		{Path: "example.com/c", Version: "v1.0.0", Timestamp: t0},
+		{Path: "example.com/d", Version: "v1.0.0", Timestamp: t0.Add(time.Minute)},
+	}
+	ic, teardown := index.SetupTestIndex(t, versions)
+	defer teardown()
+
+	q := &recordingQueue{}
+	if _, err := importIndex(ctx, ic, &fakeIndexCursor{}, q, 2, 10); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"example.com/a@v1.0.0", "example.com/b@v1.0.0", "example.com/c@v1.0.0", "example.com/d@v1.0.0"}
+	if diff := cmp.Diff(want, q.scheduled); diff != "" {
+		t.Errorf("scheduled mismatch (-want, +got):\n%s", diff)
+	}
+}
@@ -60,7 +60,7 @@ func TestReFetch(t *testing.T) {
 	})
 	defer teardownProxy()
 	sourceClient := source.NewClient(sourceTimeout)
-	f := &Fetcher{proxyClient, sourceClient, testDB, nil, nil, ""}
+	f := &Fetcher{proxyClient, sourceClient, testDB, nil, nil, "", nil, nil, nil}
 	if _, _, err := f.FetchAndUpdateState(ctx, sample.ModulePath, version, testAppVersion); err != nil {
 		t.Fatalf("FetchAndUpdateState(%q, %q): %v", sample.ModulePath, version, err)
 	}
@@ -69,7 +69,9 @@ func TestReFetch(t *testing.T) {
 		t.Error(err)
 	}
 
-	// Now re-fetch and verify that contents were overwritten.
+	// Now re-fetch and verify that contents were overwritten. Use a new app
+	// version, because a version that the proxy reports as unchanged is not
+	// processed again by the same app version.
 	proxyClient, teardownProxy = proxytest.SetupTestClient(t, []*proxytest.Module{
 		{
 			ModulePath: sample.ModulePath,
@@ -79,8 +81,8 @@ func TestReFetch(t *testing.T) {
 	})
 	defer teardownProxy()
 
-	f = &Fetcher{proxyClient, sourceClient, testDB, nil, nil, ""}
-	if _, _, err := f.FetchAndUpdateState(ctx, sample.ModulePath, version, testAppVersion); err != nil {
+	f = &Fetcher{proxyClient, sourceClient, testDB, nil, nil, "", nil, nil, nil}
+	if _, _, err := f.FetchAndUpdateState(ctx, sample.ModulePath, version, testAppVersion+"2"); err != nil {
 		t.Fatalf("FetchAndUpdateState(%q, %q): %v", modulePath, version, err)
 	}
 	want := &internal.Unit{
@@ -98,6 +100,7 @@ func TestReFetch(t *testing.T) {
 			Licenses: []*licenses.Metadata{
 				{Types: []string{"MIT"}, FilePath: "LICENSE"},
 			},
+			DocStats: internal.DocStats{NumExported: 1},
 		},
 		Readme: &internal.Readme{
 			Filepath: "bar/README.md",
@@ -126,7 +129,7 @@ func TestReFetch(t *testing.T) {
 	if diff := cmp.Diff(want.UnitMeta, *got,
 		cmp.AllowUnexported(source.Info{}),
 		cmpopts.IgnoreFields(licenses.Metadata{}, "Coverage"),
-		cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod")); diff != "" {
+		cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod", "ProcessedAt")); diff != "" {
 		t.Fatalf("testDB.GetUnitMeta(ctx, %q, %q) mismatch (-want +got):\n%s", want.ModulePath, want.Version, diff)
 	}
 
@@ -138,7 +141,7 @@ func TestReFetch(t *testing.T) {
 		cmp.AllowUnexported(source.Info{}),
 		cmpopts.IgnoreFields(internal.Unit{}, "Documentation", "BuildContexts"),
 		cmpopts.IgnoreFields(licenses.Metadata{}, "Coverage"),
-		cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod")); diff != "" {
+		cmpopts.IgnoreFields(internal.UnitMeta{}, "HasGoMod", "ProcessedAt")); diff != "" {
 		t.Errorf("mismatch on readme (-want +got):\n%s", diff)
 	}
 	if got, want := gotPkg.Documentation, want.Documentation; got == nil || want == nil {
@@ -157,8 +160,8 @@ func TestReFetch(t *testing.T) {
 		},
 	})
 	defer teardownProxy()
-	f = &Fetcher{proxyClient, sourceClient, testDB, nil, nil, ""}
-	if _, _, err := f.FetchAndUpdateState(ctx, modulePath, version, testAppVersion); !errors.Is(err, derrors.DBModuleInsertInvalid) {
+	f = &Fetcher{proxyClient, sourceClient, testDB, nil, nil, "", nil, nil, nil}
+	if _, _, err := f.FetchAndUpdateState(ctx, modulePath, version, testAppVersion+"3"); !errors.Is(err, derrors.DBModuleInsertInvalid) {
 		t.Fatalf("FetchAndUpdateState(%q, %q): %v", modulePath, version, err)
 	}
 }
@@ -28,6 +28,7 @@ import (
 	"golang.org/x/pkgsite/internal/cache"
 	"golang.org/x/pkgsite/internal/config"
 	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/fetch"
 	"golang.org/x/pkgsite/internal/godoc/dochtml"
 	"golang.org/x/pkgsite/internal/index"
 	"golang.org/x/pkgsite/internal/log"
@@ -47,6 +48,7 @@ type Server struct {
 	indexClient     *index.Client
 	proxyClient     *proxy.Client
 	sourceClient    *source.Client
+	directGetter    fetch.ModuleGetter
 	cache           *cache.Cache
 	betaCache       *cache.Cache
 	db              *postgres.DB
@@ -57,20 +59,29 @@ type Server struct {
 	getExperiments  func() []*internal.Experiment
 	workerDBInfo    func() *postgres.UserInfo
 	loadShedder     *loadShedder
+	// healthCheckModule is the module version requested from the proxy by
+	// the readiness check.
+	healthCheckModule internal.Modver
 }
 
 // ServerConfig contains everything needed by a Server.
 type ServerConfig struct {
-	DB                   *postgres.DB
-	IndexClient          *index.Client
-	ProxyClient          *proxy.Client
-	SourceClient         *source.Client
+	DB           *postgres.DB
+	IndexClient  *index.Client
+	ProxyClient  *proxy.Client
+	SourceClient *source.Client
+	// DirectGetter, if non-nil, fetches modules directly from their
+	// repositories when ProxyClient falls back to direct fetching.
+	DirectGetter         fetch.ModuleGetter
 	RedisCacheClient     *redis.Client
 	RedisBetaCacheClient *redis.Client
 	Queue                queue.Queue
 	ReportingClient      *errorreporting.Client
 	StaticPath           template.TrustedSource
 	GetExperiments       func() []*internal.Experiment
+	// HealthCheckModule is the module version requested from the proxy by
+	// the readiness check. If zero, DefaultHealthCheckModule is used.
+	HealthCheckModule internal.Modver
 }
 
 const (
@@ -117,6 +128,7 @@ func NewServer(cfg *config.Config, scfg ServerConfig) (_ *Server, err error) {
 		indexClient:     scfg.IndexClient,
 		proxyClient:     scfg.ProxyClient,
 		sourceClient:    scfg.SourceClient,
+		directGetter:    scfg.DirectGetter,
 		cache:           c,
 		betaCache:       bc,
 		queue:           scfg.Queue,
@@ -125,6 +137,11 @@ func NewServer(cfg *config.Config, scfg ServerConfig) (_ *Server, err error) {
 		staticPath:      scfg.StaticPath,
 		getExperiments:  scfg.GetExperiments,
 		workerDBInfo:    func() *postgres.UserInfo { return p.Current().(*postgres.UserInfo) },
+
+		healthCheckModule: scfg.HealthCheckModule,
+	}
+	if s.healthCheckModule == (internal.Modver{}) {
+		s.healthCheckModule = DefaultHealthCheckModule
 	}
 	s.setLoadShedder(context.Background())
 	return s, nil
@@ -158,12 +175,24 @@ func (s *Server) Install(handle func(string, http.Handler)) {
 	// See the note about duplicate tasks for "/enqueue" below.
 	handle("/poll", rmw(s.errorHandler(s.handlePollIndex)))
 
+	// scheduled: import-index reads the module versions published since the
+	// last import from the module index, inserts them into
+	// module_version_states, and enqueues them for processing without
+	// waiting for "/enqueue". It reads at most "pages" pages of "limit"
+	// versions each.
+	handle("/import-index", rmw(s.errorHandler(s.handleImportIndex)))
+
 	// scheduled: update-imported-by-count update the imported_by_count for
 	// packages in search_documents where imported_by_count_updated_at is null
 	// or imported_by_count_updated_at < version_updated_at.
 	// This endpoint is intended to be invoked periodically by a scheduler.
 	handle("/update-imported-by-count", rmw(s.errorHandler(s.handleUpdateImportedByCount)))
 
+	// scheduled: update-vulns replaces the stored vulnerability entries with
+	// the contents of the vulnerability database.
+	// This endpoint is intended to be invoked periodically by a scheduler.
+	handle("/update-vulns", rmw(s.errorHandler(s.handleUpdateVulns)))
+
 	// task-queue: fetch fetches a module version from the Module Mirror, and
 	// processes the contents, and inserts it into the database. If a fetch
 	// request fails for any reason other than an http.StatusInternalServerError,
@@ -244,6 +273,9 @@ func (s *Server) Install(handle func(string, http.Handler)) {
 	// Health check.
 	handle("/healthz", http.HandlerFunc(s.handleHealthCheck))
 
+	// Readiness check: the proxy and the DB are reachable.
+	handle("/readyz", http.HandlerFunc(s.handleReadinessCheck))
+
 	handle("/favicon.ico", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, "static/worker/favicon.ico")
 	}))
@@ -262,6 +294,19 @@ func (s *Server) handleUpdateImportedByCount(w http.ResponseWriter, r *http.Requ
 	return nil
 }
 
+// handleUpdateVulns updates the stored entries of the vulnerability
+// database, so that they can be read with units.
+func (s *Server) handleUpdateVulns(w http.ResponseWriter, r *http.Request) error {
+	if s.cfg.VulnDB == "" {
+		return &serverError{http.StatusNotImplemented, errors.New("no vulnerability database configured")}
+	}
+	if err := RefreshVulnDB(r.Context(), s.cfg.VulnDB, s.db); err != nil {
+		return err
+	}
+	fmt.Fprint(w, "updated vulnerabilities")
+	return nil
+}
+
 // handleRepopulateSearchDocuments repopulates every row in the search_documents table
 // that was last updated before the given time.
 func (s *Server) handleRepopulateSearchDocuments(w http.ResponseWriter, r *http.Request) error {
@@ -354,6 +399,7 @@ func (s *Server) doFetch(w http.ResponseWriter, r *http.Request) (string, int) {
 		DB:           s.db,
 		Cache:        s.cache,
 		loadShedder:  s.loadShedder,
+		DirectGetter: s.directGetter,
 	}
 	if r.FormValue(queue.DisableProxyFetchParam) == queue.DisableProxyFetchValue {
 		f.ProxyClient = f.ProxyClient.WithFetchDisabled()
@@ -729,13 +775,21 @@ func (s *Server) handleClean(w http.ResponseWriter, r *http.Request) (err error)
 }
 
 func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
-	if err := s.db.Underlying().Ping(); err != nil {
+	if err := s.db.Underlying().PingContext(r.Context()); err != nil {
 		http.Error(w, fmt.Sprintf("DB ping failed: %v", err), http.StatusInternalServerError)
 		return
 	}
 	fmt.Fprintln(w, "OK")
 }
 
+func (s *Server) handleReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	if err := HealthCheck(r.Context(), s.proxyClient, s.db, s.healthCheckModule); err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	fmt.Fprintln(w, "OK")
+}
+
 // Parse the template for the status page.
 func parseTemplate(staticPath, filename template.TrustedSource) (*template.Template, error) {
 	if staticPath.String() == "" {
@@ -815,7 +869,12 @@ func toUint64(n any) uint64 {
 // parameter is missing or there is a parse error, it is logged and the default
 // value is returned.
 func parseLimitParam(r *http.Request, defaultValue int) int {
-	const name = "limit"
+	return parseIntParam(r, "limit", defaultValue)
+}
+
+// parseIntParam parses the integer query parameter with the given name,
+// returning defaultValue if it is missing or invalid.
+func parseIntParam(r *http.Request, name string, defaultValue int) int {
 	param := r.FormValue(name)
 	if param == "" {
 		return defaultValue
@@ -0,0 +1,65 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import "context"
+
+// A Tracer creates spans for the phases of the fetches of a Fetcher, so
+// that a deployment can see where fetch time goes in a tracing system of its
+// choice, in addition to the OpenCensus spans of this package.
+//
+// The spans of a fetch form a tree rooted at the "FetchAndUpdateState" span.
+// Its children include "proxy.Info", "FetchAndUpdateLatest",
+// "fetchAndInsertModule" and "updateVersionMap". The children of
+// "fetchAndInsertModule" are "fetch.FetchModule", which downloads and
+// processes the module, including rendering its documentation, and
+// "db.InsertModule".
+type Tracer interface {
+	// StartSpan starts a span with the given name and attributes, as a child
+	// of the span in ctx, if any. It returns a context holding the new span.
+	StartSpan(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span)
+}
+
+// A Span is a timed phase of a fetch.
+type Span interface {
+	// End ends the span.
+	End()
+}
+
+// A SpanAttribute is a key-value pair describing a span.
+type SpanAttribute struct {
+	Key, Value string
+}
+
+// Keys of span attributes.
+const (
+	SpanKeyModulePath = "module_path"
+	SpanKeyVersion    = "version"
+)
+
+// noTracer is a Tracer whose spans do nothing.
+type noTracer struct{}
+
+func (noTracer) StartSpan(ctx context.Context, _ string, _ ...SpanAttribute) (context.Context, Span) {
+	return ctx, noSpan{}
+}
+
+type noSpan struct{}
+
+func (noSpan) End() {}
+
+// startSpan starts a span for modulePath at version using f.Tracer. The
+// version attribute is omitted if version is empty.
+func (f *Fetcher) startSpan(ctx context.Context, name, modulePath, version string) (context.Context, Span) {
+	var t Tracer = noTracer{}
+	if f.Tracer != nil {
+		t = f.Tracer
+	}
+	attrs := []SpanAttribute{{SpanKeyModulePath, modulePath}}
+	if version != "" {
+		attrs = append(attrs, SpanAttribute{SpanKeyVersion, version})
+	}
+	return t.StartSpan(ctx, name, attrs...)
+}
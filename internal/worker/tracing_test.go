@@ -0,0 +1,99 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/proxy/proxytest"
+	"golang.org/x/pkgsite/internal/source"
+)
+
+// fakeTracer is a Tracer that records its spans.
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+type fakeSpan struct {
+	name, parent string
+	attrs        map[string]string
+	ended        bool
+}
+
+func (s *fakeSpan) End() { s.ended = true }
+
+type fakeSpanKey struct{}
+
+func (t *fakeTracer) StartSpan(ctx context.Context, name string, attrs ...SpanAttribute) (context.Context, Span) {
+	s := &fakeSpan{name: name, attrs: map[string]string{}}
+	if parent, ok := ctx.Value(fakeSpanKey{}).(*fakeSpan); ok {
+		s.parent = parent.name
+	}
+	for _, a := range attrs {
+		s.attrs[a.Key] = a.Value
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = append(t.spans, s)
+	return context.WithValue(ctx, fakeSpanKey{}, s), s
+}
+
+func TestFetchAndUpdateStateSpans(t *testing.T) {
+	ctx := context.Background()
+	defer postgres.ResetTestDB(testDB, t)
+	proxyClient, teardownProxy := proxytest.SetupTestClient(t, []*proxytest.Module{
+		{
+			ModulePath: "m.com",
+			Version:    "v1.0.0",
+			Files:      map[string]string{"a.go": "package a"},
+		},
+	})
+	defer teardownProxy()
+
+	tracer := &fakeTracer{}
+	f := &Fetcher{
+		ProxyClient:  proxyClient,
+		SourceClient: source.NewClient(sourceTimeout),
+		DB:           testDB,
+		Tracer:       tracer,
+	}
+	if _, _, err := f.FetchAndUpdateState(ctx, "m.com", "v1.0.0", testAppVersion); err != nil {
+		t.Fatal(err)
+	}
+
+	// The name of each span, followed by that of its parent.
+	want := [][2]string{
+		{"FetchAndUpdateLatest", "FetchAndUpdateState"},
+		{"FetchAndUpdateState", ""},
+		{"db.InsertModule", "fetchAndInsertModule"},
+		{"fetch.FetchModule", "fetchAndInsertModule"},
+		{"fetchAndInsertModule", "FetchAndUpdateState"},
+		{"proxy.Info", "FetchAndUpdateState"},
+		{"updateVersionMap", "FetchAndUpdateState"},
+	}
+	var got [][2]string
+	for _, s := range tracer.spans {
+		got = append(got, [2]string{s.name, s.parent})
+		if !s.ended {
+			t.Errorf("span %q was not ended", s.name)
+		}
+		if s.attrs[SpanKeyModulePath] != "m.com" {
+			t.Errorf("span %q: got module path %q, want %q", s.name, s.attrs[SpanKeyModulePath], "m.com")
+		}
+		if v, ok := s.attrs[SpanKeyVersion]; ok && v != "v1.0.0" {
+			t.Errorf("span %q: got version %q, want %q", s.name, v, "v1.0.0")
+		}
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i][0] < got[j][0] })
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("spans mismatch (-want, +got):\n%s", diff)
+	}
+}
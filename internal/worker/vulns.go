@@ -0,0 +1,104 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/pkgsite/internal/derrors"
+	"golang.org/x/pkgsite/internal/log"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/vuln"
+)
+
+// RefreshVulnDB updates the vulnerability entries stored in db from the
+// vulnerability database at feedURL, so that the vulnerabilities of units
+// stay current without refetching modules.
+//
+// Only entries that are new or modified since they were stored or withdrawn
+// are downloaded. Entries that have been withdrawn or are no longer in the
+// database are removed; the IDs of withdrawn entries are recorded, since the
+// index doesn't say which entries are withdrawn.
+func RefreshVulnDB(ctx context.Context, feedURL string, db *postgres.DB) (err error) {
+	defer derrors.Wrap(&err, "RefreshVulnDB(ctx, %q)", feedURL)
+
+	client, err := vuln.NewClient(feedURL)
+	if err != nil {
+		return err
+	}
+	metas, err := client.VulnMetas(ctx)
+	if err != nil {
+		return err
+	}
+	stored, err := db.GetVulnModified(ctx)
+	if err != nil {
+		return err
+	}
+	withdrawn, err := db.GetWithdrawnVulnModified(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		changed []string
+		deleted []string
+		inFeed  = map[string]bool{}
+	)
+	for _, m := range metas {
+		inFeed[m.ID] = true
+		if t, ok := withdrawn[m.ID]; ok && !m.Modified.After(t) {
+			continue
+		}
+		if t, ok := stored[m.ID]; !ok || m.Modified.After(t) {
+			changed = append(changed, m.ID)
+		}
+	}
+	for _, ids := range []map[string]time.Time{stored, withdrawn} {
+		for id := range ids {
+			if !inFeed[id] {
+				deleted = append(deleted, id)
+			}
+		}
+	}
+
+	entries, err := client.ByIDs(ctx, changed)
+	if err != nil {
+		return err
+	}
+	var upserts, withdrawals []*osv.Entry
+	for i, e := range entries {
+		switch {
+		case e == nil:
+			// The entry is in the index but could not be read; try again
+			// on the next refresh.
+			log.Warningf(ctx, "RefreshVulnDB: missing entry for %s", changed[i])
+		case e.Withdrawn != nil:
+			withdrawals = append(withdrawals, e)
+		default:
+			upserts = append(upserts, e)
+		}
+	}
+
+	if len(upserts) > 0 {
+		if err := db.UpsertVulns(ctx, upserts); err != nil {
+			return err
+		}
+	}
+	if len(withdrawals) > 0 {
+		if err := db.WithdrawVulns(ctx, withdrawals); err != nil {
+			return err
+		}
+	}
+	if len(deleted) > 0 {
+		if err := db.DeleteVulns(ctx, deleted); err != nil {
+			return err
+		}
+	}
+	log.Infof(ctx, "RefreshVulnDB: %d entries in feed, %d stored or updated, %d withdrawn, %d removed",
+		len(metas), len(upserts), len(withdrawals), len(deleted))
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/pkgsite/internal/osv"
+	"golang.org/x/pkgsite/internal/postgres"
+	"golang.org/x/pkgsite/internal/vuln"
+)
+
+func TestRefreshVulnDB(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+	defer postgres.ResetTestDB(testDB, t)
+
+	dir := t.TempDir()
+	feedURL := "file://" + dir
+	t1 := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	t2 := t1.Add(24 * time.Hour)
+
+	entry := func(id string, modified time.Time, withdrawn bool) *osv.Entry {
+		e := &osv.Entry{
+			ID:       id,
+			Modified: modified,
+			Affected: []osv.Affected{{
+				Module: osv.Module{Path: "example.com/" + id},
+				Ranges: []osv.Range{{
+					Type:   osv.RangeTypeSemver,
+					Events: []osv.RangeEvent{{Introduced: "0"}, {Fixed: "1.1.0"}},
+				}},
+			}},
+		}
+		if withdrawn {
+			e.Withdrawn = &modified
+		}
+		return e
+	}
+	writeFeed := func(entries ...*osv.Entry) {
+		t.Helper()
+		var metas []*vuln.VulnMeta
+		for _, e := range entries {
+			metas = append(metas, &vuln.VulnMeta{ID: e.ID, Modified: e.Modified})
+			writeJSON(t, filepath.Join(dir, "ID", e.ID+".json"), e)
+		}
+		writeJSON(t, filepath.Join(dir, "index", "vulns.json"), metas)
+	}
+	check := func(want map[string]time.Time) {
+		t.Helper()
+		if err := RefreshVulnDB(ctx, feedURL, testDB); err != nil {
+			t.Fatal(err)
+		}
+		got, err := testDB.GetVulnModified(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for id, m := range got {
+			got[id] = m.UTC()
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("stored entries mismatch (-want, +got):\n%s", diff)
+		}
+	}
+
+	writeFeed(entry("GO-1", t1, false), entry("GO-2", t1, false), entry("GO-3", t1, true))
+	check(map[string]time.Time{"GO-1": t1, "GO-2": t1})
+
+	// GO-1 is withdrawn, GO-2 is removed from the feed, GO-4 is new.
+	writeFeed(entry("GO-1", t2, true), entry("GO-3", t1, true), entry("GO-4", t2, false))
+	check(map[string]time.Time{"GO-4": t2})
+
+	// An unchanged feed leaves the stored entries alone.
+	check(map[string]time.Time{"GO-4": t2})
+
+	// Withdrawn entries are recorded, and not downloaded again while they are
+	// unchanged: make them unreadable, so that downloading them fails.
+	got, err := testDB.GetWithdrawnVulnModified(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, m := range got {
+		got[id] = m.UTC()
+	}
+	if diff := cmp.Diff(map[string]time.Time{"GO-1": t2, "GO-3": t1}, got); diff != "" {
+		t.Errorf("withdrawn entries mismatch (-want, +got):\n%s", diff)
+	}
+	for _, id := range []string{"GO-1", "GO-3"} {
+		if err := os.WriteFile(filepath.Join(dir, "ID", id+".json"), []byte("not JSON"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check(map[string]time.Time{"GO-4": t2})
+
+	// A withdrawn entry that is modified is downloaded again.
+	writeFeed(entry("GO-1", t2.Add(time.Hour), false), entry("GO-3", t1, true), entry("GO-4", t2, false))
+	check(map[string]time.Time{"GO-1": t2.Add(time.Hour), "GO-4": t2})
+}
+
+func writeJSON(t *testing.T, filename string, v any) {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,17 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE module_requires (
+    module_id INTEGER NOT NULL REFERENCES modules(id) ON DELETE CASCADE,
+    required_path TEXT NOT NULL,
+    required_version TEXT NOT NULL,
+    PRIMARY KEY (module_id, required_path)
+);
+
+COMMENT ON TABLE module_requires IS
+'TABLE module_requires contains the require directives from the go.mod file of a module version.';
+
+END;
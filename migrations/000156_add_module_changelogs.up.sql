@@ -0,0 +1,16 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE module_changelogs (
+    module_id INTEGER NOT NULL PRIMARY KEY REFERENCES modules(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    contents TEXT NOT NULL
+);
+
+COMMENT ON TABLE module_changelogs IS
+'TABLE module_changelogs contains the CHANGELOG or CHANGES file at the root of a module version.';
+
+END;
@@ -0,0 +1,17 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE units
+	ADD COLUMN num_exported_symbols INTEGER,
+	ADD COLUMN num_documented_symbols INTEGER;
+
+COMMENT ON COLUMN units.num_exported_symbols IS
+'COLUMN num_exported_symbols is the number of exported symbols in the package. It is NULL for directories that are not packages.';
+
+COMMENT ON COLUMN units.num_documented_symbols IS
+'COLUMN num_documented_symbols is the number of exported symbols in the package that have a doc comment.';
+
+END;
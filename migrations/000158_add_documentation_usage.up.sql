@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE documentation ADD COLUMN usage TEXT;
+
+COMMENT ON COLUMN documentation.usage IS
+'COLUMN usage is the usage synopsis extracted from the doc comment of a command. It is NULL for other packages.';
+
+END;
@@ -0,0 +1,17 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE units
+	ADD COLUMN deprecated BOOLEAN,
+	ADD COLUMN deprecation_comment TEXT;
+
+COMMENT ON COLUMN units.deprecated IS
+'COLUMN deprecated reports whether the package doc comment marks the package as deprecated. It is independent of module deprecation, which is in latest_module_versions.';
+
+COMMENT ON COLUMN units.deprecation_comment IS
+'COLUMN deprecation_comment is the text of the "Deprecated:" paragraph of the package doc comment.';
+
+END;
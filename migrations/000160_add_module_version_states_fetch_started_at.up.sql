@@ -0,0 +1,13 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE module_version_states
+	ADD COLUMN fetch_started_at TIMESTAMP WITH TIME ZONE;
+
+COMMENT ON COLUMN module_version_states.fetch_started_at IS
+'COLUMN fetch_started_at is the time a worker started fetching the module version. It is cleared when the fetch result is recorded, so a fetch that started long ago and is still set may belong to a worker that crashed.';
+
+END;
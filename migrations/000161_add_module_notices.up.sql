@@ -0,0 +1,16 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE module_notices (
+    module_id INTEGER NOT NULL PRIMARY KEY REFERENCES modules(id) ON DELETE CASCADE,
+    file_path TEXT NOT NULL,
+    contents TEXT NOT NULL
+);
+
+COMMENT ON TABLE module_notices IS
+'TABLE module_notices contains the NOTICE file at the root of a module version, which some licenses require to be preserved with attributions.';
+
+END;
@@ -0,0 +1,22 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE vulns (
+    id TEXT NOT NULL PRIMARY KEY,
+    modified TIMESTAMP WITH TIME ZONE NOT NULL,
+    module_paths TEXT[] NOT NULL,
+    entry JSONB NOT NULL
+);
+
+COMMENT ON TABLE vulns IS
+'TABLE vulns contains the entries of the Go vulnerability database, in OSV format.';
+
+COMMENT ON COLUMN vulns.module_paths IS
+'COLUMN module_paths holds the paths of the modules affected by the vulnerability, as they appear in the entry. The standard library is stdlib or toolchain.';
+
+CREATE INDEX idx_vulns_module_paths ON vulns USING GIN (module_paths);
+
+END;
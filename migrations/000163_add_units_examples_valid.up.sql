@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE units ADD COLUMN examples_valid BOOLEAN;
+
+COMMENT ON COLUMN units.examples_valid IS
+'COLUMN examples_valid reports whether the examples of the package with output comments refer only to defined identifiers. It is NULL for units inserted before the column was added.';
+
+END;
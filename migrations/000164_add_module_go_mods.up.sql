@@ -0,0 +1,15 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE module_go_mods (
+    module_id INTEGER NOT NULL PRIMARY KEY REFERENCES modules(id) ON DELETE CASCADE,
+    contents BYTEA NOT NULL
+);
+
+COMMENT ON TABLE module_go_mods IS
+'TABLE module_go_mods contains the go.mod file of a module version, as served by the module proxy.';
+
+END;
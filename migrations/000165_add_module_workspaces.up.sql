@@ -0,0 +1,16 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE module_workspaces (
+    module_id INTEGER NOT NULL PRIMARY KEY REFERENCES modules(id) ON DELETE CASCADE,
+    go_version TEXT NOT NULL,
+    dirs TEXT[] NOT NULL
+);
+
+COMMENT ON TABLE module_workspaces IS
+'TABLE module_workspaces describes the go.work file at the root of a module version: its go directive and the directories of its use directives.';
+
+END;
@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE units ADD COLUMN num_test_files INTEGER;
+
+COMMENT ON COLUMN units.num_test_files IS
+'COLUMN num_test_files is the number of _test.go files in the directory of the package. It is NULL for units inserted before the column was added.';
+
+END;
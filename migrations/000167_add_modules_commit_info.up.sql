@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE modules ADD COLUMN commit_info JSONB;
+
+COMMENT ON COLUMN modules.commit_info IS
+'COLUMN commit_info holds the hash, author and committer of the commit the module version was built from, when the module was fetched from its repository.';
+
+END;
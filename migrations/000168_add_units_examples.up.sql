@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE units ADD COLUMN examples JSONB;
+
+COMMENT ON COLUMN units.examples IS
+'COLUMN examples holds the examples of the package, with package examples separate from the examples of its symbols. It is NULL if the package has no examples or was inserted before the column was added.';
+
+END;
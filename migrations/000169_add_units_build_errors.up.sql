@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE units ADD COLUMN build_errors TEXT[];
+
+COMMENT ON COLUMN units.build_errors IS
+'COLUMN build_errors describes problems that would prevent the package from building, such as unresolved imports. It is NULL if there are none or the unit was inserted before the column was added.';
+
+END;
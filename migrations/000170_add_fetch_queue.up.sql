@@ -0,0 +1,27 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE fetch_queue (
+    module_path TEXT NOT NULL,
+    version TEXT NOT NULL,
+    enqueued_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    leased_until TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (module_path, version)
+);
+
+COMMENT ON TABLE fetch_queue IS
+'TABLE fetch_queue holds the module versions waiting to be fetched by a worker. A row is removed when its fetch is done.';
+
+COMMENT ON COLUMN fetch_queue.leased_until IS
+'COLUMN leased_until is the time until which the row is leased to the worker that dequeued it, or NULL if it has not been dequeued. A row whose lease has expired can be dequeued again.';
+
+COMMENT ON COLUMN fetch_queue.attempts IS
+'COLUMN attempts is the number of times the row has been dequeued.';
+
+CREATE INDEX idx_fetch_queue_enqueued_at ON fetch_queue (enqueued_at);
+
+END;
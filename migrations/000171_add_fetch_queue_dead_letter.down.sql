@@ -0,0 +1,11 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE fetch_queue
+    DROP COLUMN dead_lettered,
+    DROP COLUMN last_error;
+
+END;
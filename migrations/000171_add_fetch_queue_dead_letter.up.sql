@@ -0,0 +1,17 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE fetch_queue
+    ADD COLUMN dead_lettered BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN last_error TEXT;
+
+COMMENT ON COLUMN fetch_queue.dead_lettered IS
+'COLUMN dead_lettered is true if the fetch of the row failed too many times, so that it is no longer dequeued until it is requeued.';
+
+COMMENT ON COLUMN fetch_queue.last_error IS
+'COLUMN last_error is the error of the last failed fetch of the row, or NULL if no fetch has failed.';
+
+END;
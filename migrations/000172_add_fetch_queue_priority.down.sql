@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+DROP INDEX idx_fetch_queue_priority_enqueued_at;
+CREATE INDEX idx_fetch_queue_enqueued_at ON fetch_queue (enqueued_at);
+
+ALTER TABLE fetch_queue DROP COLUMN priority;
+
+END;
@@ -0,0 +1,15 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE fetch_queue ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
+
+COMMENT ON COLUMN fetch_queue.priority IS
+'COLUMN priority orders the rows for dequeuing: rows with higher priority are dequeued first, and rows of equal priority in the order they were enqueued.';
+
+DROP INDEX idx_fetch_queue_enqueued_at;
+CREATE INDEX idx_fetch_queue_priority_enqueued_at ON fetch_queue (priority DESC, enqueued_at);
+
+END;
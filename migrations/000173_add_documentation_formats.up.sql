@@ -0,0 +1,15 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE documentation ADD COLUMN html TEXT;
+ALTER TABLE documentation ADD COLUMN text TEXT;
+
+COMMENT ON COLUMN documentation.html IS
+'COLUMN html contains the HTML of the documentation body, rendered at fetch time. It is NULL if it was not rendered.';
+COMMENT ON COLUMN documentation.text IS
+'COLUMN text contains the documentation rendered as plain text at fetch time. It is NULL if it was not rendered.';
+
+END;
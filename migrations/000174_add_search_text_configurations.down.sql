@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+DROP TEXT SEARCH CONFIGURATION english_nostem;
+DROP TEXT SEARCH CONFIGURATION english_keep_stopwords;
+DROP TEXT SEARCH DICTIONARY english_nostem_dict;
+DROP TEXT SEARCH DICTIONARY english_stem_all;
+
+END;
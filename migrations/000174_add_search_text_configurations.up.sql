@@ -0,0 +1,41 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- The text search configurations selected by config.SearchSettings, other
+-- than pg_catalog.english and pg_catalog.simple.
+
+CREATE TEXT SEARCH DICTIONARY english_stem_all (
+    TEMPLATE = pg_catalog.snowball,
+    LANGUAGE = english
+);
+
+CREATE TEXT SEARCH DICTIONARY english_nostem_dict (
+    TEMPLATE = pg_catalog.simple,
+    STOPWORDS = english
+);
+
+CREATE TEXT SEARCH CONFIGURATION english_keep_stopwords (COPY = pg_catalog.english);
+
+ALTER TEXT SEARCH CONFIGURATION english_keep_stopwords
+    ALTER MAPPING FOR asciiword, asciihword, hword_asciipart, word, hword, hword_part
+    WITH english_stem_all;
+
+CREATE TEXT SEARCH CONFIGURATION english_nostem (COPY = pg_catalog.english);
+
+ALTER TEXT SEARCH CONFIGURATION english_nostem
+    ALTER MAPPING FOR asciiword, asciihword, hword_asciipart, word, hword, hword_part
+    WITH english_nostem_dict;
+
+COMMENT ON TEXT SEARCH DICTIONARY english_stem_all IS
+'TEXT SEARCH DICTIONARY english_stem_all stems English words like english_stem, but has no stop words.';
+COMMENT ON TEXT SEARCH DICTIONARY english_nostem_dict IS
+'TEXT SEARCH DICTIONARY english_nostem_dict removes English stop words, but does not stem words.';
+COMMENT ON TEXT SEARCH CONFIGURATION english_keep_stopwords IS
+'TEXT SEARCH CONFIGURATION english_keep_stopwords is like english, but keeps stop words. It is used for search when stop words are kept.';
+COMMENT ON TEXT SEARCH CONFIGURATION english_nostem IS
+'TEXT SEARCH CONFIGURATION english_nostem is like english, but does not stem words. It is used for search when stemming is disabled.';
+
+END;
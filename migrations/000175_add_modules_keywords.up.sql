@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE modules ADD COLUMN keywords TEXT[];
+
+COMMENT ON COLUMN modules.keywords IS
+'COLUMN keywords contains the most frequent terms in the README and package synopses of the module, extracted at fetch time. It is used for search facets.';
+
+END;
@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE modules ADD COLUMN has_packages BOOLEAN;
+
+COMMENT ON COLUMN modules.has_packages IS
+'COLUMN has_packages reports whether the module contains any packages. It is NULL for modules fetched before it was added, all of which have packages.';
+
+END;
@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE module_version_states ADD COLUMN documentation_purged BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN module_version_states.documentation_purged IS
+'COLUMN documentation_purged reports whether the documentation of the module version was purged since it was last processed. Purged module versions are not skipped as unchanged when fetched, and are not reprocessed in bulk.';
+
+END;
@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE fetch_queue ADD COLUMN lease_token TEXT;
+
+COMMENT ON COLUMN fetch_queue.lease_token IS
+'COLUMN lease_token identifies the current lease of the row, or is NULL if it has not been dequeued. Only the holder of the lease can mark the fetch of the row as done.';
+
+END;
@@ -0,0 +1,17 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE documentation_symbols
+    ADD COLUMN deprecated BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN deprecation_comment TEXT NOT NULL DEFAULT '';
+
+COMMENT ON COLUMN documentation_symbols.deprecated IS
+'COLUMN deprecated reports whether the doc comment of the symbol in this documentation has a paragraph beginning with "Deprecated:".';
+
+COMMENT ON COLUMN documentation_symbols.deprecation_comment IS
+'COLUMN deprecation_comment is the text of the "Deprecated:" paragraph of the doc comment of the symbol, on a single line.';
+
+END;
@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+ALTER TABLE module_requires ADD COLUMN indirect BOOLEAN NOT NULL DEFAULT FALSE;
+
+COMMENT ON COLUMN module_requires.indirect IS
+'COLUMN indirect reports whether the require directive is marked with an "// indirect" comment.';
+
+END;
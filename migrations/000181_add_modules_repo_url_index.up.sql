@@ -0,0 +1,12 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+-- The expression must match the one in the query of GetModulesByRepo, so
+-- that the planner can use the index.
+CREATE INDEX idx_modules_repo_url ON modules
+    (regexp_replace(source_info->>'RepoURL', '^[a-z]+://|/+$', '', 'g'));
+
+END;
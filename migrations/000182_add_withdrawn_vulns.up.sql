@@ -0,0 +1,15 @@
+-- Copyright 2023 The Go Authors. All rights reserved.
+-- Use of this source code is governed by a BSD-style
+-- license that can be found in the LICENSE file.
+
+BEGIN;
+
+CREATE TABLE withdrawn_vulns (
+    id TEXT NOT NULL PRIMARY KEY,
+    modified TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON TABLE withdrawn_vulns IS
+'TABLE withdrawn_vulns records the entries of the Go vulnerability database that have been withdrawn, so that they are not downloaded again until they are modified.';
+
+END;